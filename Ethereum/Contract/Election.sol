@@ -4,25 +4,79 @@ pragma solidity ^0.8.0;
 contract ElectionFact {
     
     struct ElectionDet {
+        uint256 id;
         address deployedAddress;
+        bytes32 companyId;
         string el_n;
         string el_d;
+        uint256 createdAt;
     }
     
-    mapping(string => ElectionDet[]) public companyEmail;
+    // Global index of every election; an election's id is its position + 1 (0 = unknown).
+    ElectionDet[] private elections;
+    // Companies are identified by an opaque id computed off-chain, never by their email.
+    mapping(bytes32 => uint256[]) private companyElections;
+    mapping(address => uint256) public electionIds;
     
-    function createElection(string memory email, string memory election_name, string memory election_description) public {
+    event ElectionCreated(uint256 indexed id, address indexed election, bytes32 indexed companyId, address authority, string name, string description);
+    
+    function createElection(bytes32 companyId, string memory election_name, string memory election_description) public returns (uint256) {
+        require(companyId != bytes32(0), "company id required");
         address newElection = address(new Election(msg.sender, election_name, election_description));
+        uint256 id = elections.length + 1;
         
-        companyEmail[email].push(ElectionDet({
+        elections.push(ElectionDet({
+            id: id,
             deployedAddress: newElection,
+            companyId: companyId,
             el_n: election_name,
-            el_d: election_description
+            el_d: election_description,
+            createdAt: block.timestamp
         }));
+        companyElections[companyId].push(id);
+        electionIds[newElection] = id;
+        
+        emit ElectionCreated(id, newElection, companyId, msg.sender, election_name, election_description);
+        return id;
+    }
+    
+    function electionCount() public view returns (uint256) {
+        return elections.length;
+    }
+    
+    function companyElectionCount(bytes32 companyId) public view returns (uint256) {
+        return companyElections[companyId].length;
+    }
+    
+    function getElection(uint256 id) public view returns (ElectionDet memory) {
+        require(id > 0 && id <= elections.length, "unknown election id");
+        return elections[id - 1];
+    }
+    
+    // Pages through all elections in creation order. Returns fewer than limit at the end.
+    function getElections(uint256 offset, uint256 limit) public view returns (ElectionDet[] memory page) {
+        uint256 total = elections.length;
+        if (offset >= total) {
+            return new ElectionDet[](0);
+        }
+        uint256 n = total - offset < limit ? total - offset : limit;
+        page = new ElectionDet[](n);
+        for (uint256 i = 0; i < n; i++) {
+            page[i] = elections[offset + i];
+        }
     }
     
-    function getDeployedElections(string memory email) public view returns (ElectionDet[] memory) {
-        return companyEmail[email];
+    // Pages through one company's elections in creation order.
+    function getCompanyElections(bytes32 companyId, uint256 offset, uint256 limit) public view returns (ElectionDet[] memory page) {
+        uint256[] storage ids = companyElections[companyId];
+        if (offset >= ids.length) {
+            return new ElectionDet[](0);
+        }
+        uint256 n = ids.length - offset < limit ? ids.length - offset : limit;
+        page = new ElectionDet[](n);
+        for (uint256 i = 0; i < n; i++) {
+            page[i] = elections[ids[offset + i] - 1];
+        }
     }
 }
 
@@ -30,7 +84,13 @@ contract Election {
     address public election_authority;
     string public election_name;
     string public election_description;
-    bool public status;
+    bool public status; // true until the authority closes the election
+    
+    // Voting window (unix seconds). Ballots are accepted in [startTime, endTime).
+    uint256 public startTime;
+    uint256 public endTime;
+    
+    uint256 constant DEFAULT_DURATION = 7 days;
     
     struct Candidate {
         string candidate_name;
@@ -51,11 +111,43 @@ contract Election {
     uint256 public numCandidates;
     uint256 public numVoters;
     
+    event CandidateAdded(uint256 indexed candidateID, string candidate_name, string email);
+    event VoteCast(uint256 indexed candidateID, string voter, uint256 totalVoters);
+    event ScheduleUpdated(uint256 startTime, uint256 endTime);
+    event ElectionClosed(uint256 closedAt, uint256 totalVoters);
+    event BallotRejected(string voter, uint256 candidateID, string reason);
+    event ApproversSet(bytes32[] approvers, uint256 threshold);
+    event ActionApproved(bytes32 indexed action, bytes32 indexed approver, uint256 approvals);
+    event ElectionPaused(uint256 pausedAt, string reason);
+    event ElectionResumed(uint256 resumedAt, uint256 pausedFor, uint256 endTime);
+    
+    // Circuit breaker: while paused no ballots are accepted. totalPaused sums every pause.
+    bool public paused;
+    uint256 public pausedAt;
+    uint256 public totalPaused;
+    
+    // M-of-N approval for closing and rescheduling. An approver id is either an address
+    // (left-padded to 32 bytes) that signs the action hash with personal_sign, or an admin
+    // member's opaque company id, vouched for by the authority after login. Approvals for
+    // archiving to L1 (kind 3) are recorded here too, but the archive lives on another
+    // chain: the operator checks them before anchoring and nothing consumes them here.
+    uint8 constant ACTION_CLOSE = 1;
+    uint8 constant ACTION_SCHEDULE = 2;
+    
+    bytes32[] private approvers;
+    mapping(bytes32 => bool) public isApprover;
+    uint256 public approvalThreshold; // 0 = the authority acts alone
+    uint256 public actionNonce; // bumped whenever an approved action runs, voiding stale approvals
+    mapping(bytes32 => uint256) public approvalCount;
+    mapping(bytes32 => mapping(bytes32 => bool)) public approvedBy;
+    
     constructor(address authority, string memory name, string memory description) {
         election_authority = authority;
         election_name = name;
         election_description = description;
         status = true;
+        startTime = block.timestamp;
+        endTime = block.timestamp + DEFAULT_DURATION;
     }
     
     modifier owner() {
@@ -63,12 +155,146 @@ contract Election {
         _;
     }
     
+    modifier notClosed() {
+        require(status, "Error: Election is closed");
+        _;
+    }
+    
+    modifier notPaused() {
+        require(!paused, "Error: Election is paused");
+        _;
+    }
+    
+    function setSchedule(uint256 start, uint256 end) public owner notClosed {
+        require(end > start, "Error: End must be after start");
+        require(numVoters == 0 || start <= block.timestamp, "Error: Cannot move start past cast votes");
+        consumeApproval(ACTION_SCHEDULE, start, end);
+        startTime = start;
+        endTime = end;
+        emit ScheduleUpdated(start, end);
+    }
+    
+    // Closing is final: no more votes or candidates, and the window ends now.
+    function closeElection() public owner notClosed {
+        consumeApproval(ACTION_CLOSE, 0, 0);
+        status = false;
+        if (endTime > block.timestamp) {
+            endTime = block.timestamp;
+        }
+        emit ElectionClosed(block.timestamp, numVoters);
+    }
+    
+    // Approvers can be set once, before the election closes, and only by the election
+    // authority; the API relays the call only for the election's owning company.
+    function setApprovers(bytes32[] memory list, uint256 threshold) public notClosed {
+        require(msg.sender == election_authority, "Error: Only the election authority can set approvers");
+        require(approvalThreshold == 0, "Error: Approvers already set");
+        require(threshold > 0 && threshold <= list.length, "Error: Invalid threshold");
+        for (uint256 i = 0; i < list.length; i++) {
+            require(list[i] != bytes32(0) && !isApprover[list[i]], "Error: Invalid or duplicate approver");
+            isApprover[list[i]] = true;
+            approvers.push(list[i]);
+        }
+        approvalThreshold = threshold;
+        emit ApproversSet(list, threshold);
+    }
+    
+    function getApprovers() public view returns (bytes32[] memory, uint256) {
+        return (approvers, approvalThreshold);
+    }
+    
+    // The hash approvers sign for an action; a and b are the new window for ACTION_SCHEDULE.
+    function actionHash(uint8 kind, uint256 a, uint256 b) public view returns (bytes32) {
+        return keccak256(abi.encode(address(this), kind, a, b, actionNonce));
+    }
+    
+    // Relays an address approver's personal_sign signature over the action hash.
+    function approve(bytes32 action, bytes memory signature) public owner {
+        recordApproval(action, bytes32(uint256(uint160(recoverSigner(action, signature)))));
+    }
+    
+    // Records an admin member's approval after the authority has checked their login.
+    function approveMember(bytes32 action, bytes32 member) public owner {
+        recordApproval(action, member);
+    }
+    
+    function isApproved(bytes32 action) public view returns (bool) {
+        return approvalThreshold == 0 || approvalCount[action] >= approvalThreshold;
+    }
+    
+    function recordApproval(bytes32 action, bytes32 approver) internal {
+        require(isApprover[approver], "Error: Not an approver");
+        require(!approvedBy[action][approver], "Error: Already approved");
+        approvedBy[action][approver] = true;
+        approvalCount[action]++;
+        emit ActionApproved(action, approver, approvalCount[action]);
+    }
+    
+    function consumeApproval(uint8 kind, uint256 a, uint256 b) internal {
+        if (approvalThreshold == 0) {
+            return;
+        }
+        require(approvalCount[actionHash(kind, a, b)] >= approvalThreshold, "Error: Not enough approvals");
+        actionNonce++;
+    }
+    
+    function recoverSigner(bytes32 action, bytes memory sig) internal pure returns (address) {
+        require(sig.length == 65, "Error: Bad signature");
+        bytes32 r;
+        bytes32 s;
+        uint8 v;
+        assembly {
+            r := mload(add(sig, 32))
+            s := mload(add(sig, 64))
+            v := byte(0, mload(add(sig, 96)))
+        }
+        if (v < 27) {
+            v += 27;
+        }
+        address signer = ecrecover(keccak256(abi.encodePacked("\x19Ethereum Signed Message:\n32", action)), v, r, s);
+        require(signer != address(0), "Error: Bad signature");
+        return signer;
+    }
+    
+    function pause(string memory reason) public owner notClosed {
+        require(!paused, "Error: Election is already paused");
+        paused = true;
+        pausedAt = block.timestamp;
+        emit ElectionPaused(block.timestamp, reason);
+    }
+    
+    // With extend the window's end moves out by the time spent paused. That is a schedule
+    // change, so it needs ACTION_SCHEDULE approval over (startTime, 0): the end is left
+    // open because it depends on when the resume is mined.
+    function resume(bool extend) public owner notClosed {
+        require(paused, "Error: Election is not paused");
+        if (extend) {
+            consumeApproval(ACTION_SCHEDULE, startTime, 0);
+        }
+        uint256 pausedFor = block.timestamp - pausedAt;
+        paused = false;
+        pausedAt = 0;
+        totalPaused += pausedFor;
+        if (extend) {
+            endTime += pausedFor;
+        }
+        emit ElectionResumed(block.timestamp, pausedFor, endTime);
+    }
+    
+    function isOpen() public view returns (bool) {
+        return status && !paused && block.timestamp >= startTime && block.timestamp < endTime;
+    }
+    
+    function getSchedule() public view returns (uint256, uint256, bool) {
+        return (startTime, endTime, status);
+    }
+    
     function addCandidate(
         string memory candidate_name, 
         string memory candidate_description, 
         string memory imgHash, 
         string memory email
-    ) public owner {
+    ) public owner notClosed {
         uint256 candidateID = numCandidates;
         candidates[candidateID] = Candidate({
             candidate_name: candidate_name,
@@ -78,12 +304,42 @@ contract Election {
             email: email
         });
         numCandidates++;
+        
+        emit CandidateAdded(candidateID, candidate_name, email);
     }
     
-    function vote(uint256 candidateID, string memory e) public owner {
+    function vote(uint256 candidateID, string memory e) public owner notClosed notPaused {
+        requireWindow();
         require(!voters[e].voted, "Error: You cannot double vote");
         require(candidateID < numCandidates, "Error: Invalid candidate ID");
-        
+        recordVote(candidateID, e);
+    }
+    
+    // Casts many ballots in one transaction. An invalid ballot (double vote, unknown
+    // candidate) is skipped with BallotRejected instead of reverting the whole batch.
+    function voteBatch(uint256[] memory candidateIDs, string[] memory emails) public owner notClosed notPaused returns (uint256 accepted) {
+        require(candidateIDs.length == emails.length, "Error: Length mismatch");
+        requireWindow();
+        for (uint256 i = 0; i < emails.length; i++) {
+            if (voters[emails[i]].voted) {
+                emit BallotRejected(emails[i], candidateIDs[i], "double vote");
+                continue;
+            }
+            if (candidateIDs[i] >= numCandidates) {
+                emit BallotRejected(emails[i], candidateIDs[i], "invalid candidate");
+                continue;
+            }
+            recordVote(candidateIDs[i], emails[i]);
+            accepted++;
+        }
+    }
+    
+    function requireWindow() internal view {
+        require(block.timestamp >= startTime, "Error: Election has not started");
+        require(block.timestamp < endTime, "Error: Election has ended");
+    }
+    
+    function recordVote(uint256 candidateID, string memory e) internal {
         voters[e] = Voter({
             candidate_id_voted: candidateID,
             voted: true
@@ -91,6 +347,8 @@ contract Election {
         
         numVoters++;
         candidates[candidateID].voteCount++;
+        
+        emit VoteCast(candidateID, e, numVoters);
     }
     
     function getNumOfCandidates() public view returns(uint256) {
@@ -119,6 +377,14 @@ contract Election {
         );
     }
     
+    // Every candidate in id order, so a ballot is read with one call instead of one per candidate.
+    function getAllCandidates() public view returns (Candidate[] memory list) {
+        list = new Candidate[](numCandidates);
+        for (uint256 i = 0; i < numCandidates; i++) {
+            list[i] = candidates[i];
+        }
+    }
+    
     function winnerCandidate() public view owner returns (uint256) {
         require(numCandidates > 0, "Error: No candidates");
         
@@ -14,6 +14,15 @@ contract ElectionArchive {
 
     mapping(address => FinalResult) public archivedResults;
 
+    event ResultArchived(
+        address indexed electionAddress,
+        string title,
+        string winnerName,
+        uint256 winningVotes,
+        uint256 totalVoters,
+        uint256 timestamp
+    );
+
     modifier onlyAdmin() {
         require(msg.sender == admin, "Only admin can archive results");
         _;
@@ -38,5 +47,7 @@ contract ElectionArchive {
             totalVoters: _totalVoters,
             timestamp: block.timestamp
         });
+
+        emit ResultArchived(_electionAddress, _title, _winnerName, _winningVotes, _totalVoters, block.timestamp);
     }
 }
@@ -31,8 +31,8 @@ var (
 
 // BindingsMetaData contains all meta data concerning the Bindings contract.
 var BindingsMetaData = &bind.MetaData{
-	ABI: "[{\"inputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"inputs\":[],\"name\":\"admin\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_electionAddress\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_title\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"_winnerName\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"_winningVotes\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"_totalVoters\",\"type\":\"uint256\"}],\"name\":\"archiveResult\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"name\":\"archivedResults\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"electionAddress\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"title\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"winnerName\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"winningVotes\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"totalVoters\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"timestamp\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
-	Bin: "0x608060405234801561000f575f80fd5b50335f806101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff160217905550610b168061005c5f395ff3fe608060405234801561000f575f80fd5b506004361061003f575f3560e01c806331bd3af7146100435780635c22ed3a14610078578063f851a44014610094575b5f80fd5b61005d60048036038101906100589190610448565b6100b2565b60405161006f96959493929190610524565b60405180910390f35b610092600480360381019061008d91906106e7565b610215565b005b61009c6103ba565b6040516100a99190610796565b60405180910390f35b6001602052805f5260405f205f91509050805f015f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff16908060010180546100f6906107dc565b80601f0160208091040260200160405190810160405280929190818152602001828054610122906107dc565b801561016d5780601f106101445761010080835404028352916020019161016d565b820191905f5260205f20905b81548152906001019060200180831161015057829003601f168201915b505050505090806002018054610182906107dc565b80601f01602080910402602001604051908101604052809291908181526020018280546101ae906107dc565b80156101f95780601f106101d0576101008083540402835291602001916101f9565b820191905f5260205f20905b8154815290600101906020018083116101dc57829003601f168201915b5050505050908060030154908060040154908060050154905086565b5f8054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff163373ffffffffffffffffffffffffffffffffffffffff16146102a2576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161029990610856565b60405180910390fd5b6040518060c001604052808673ffffffffffffffffffffffffffffffffffffffff1681526020018581526020018481526020018381526020018281526020014281525060015f8773ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f820151815f015f6101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff160217905550602082015181600101908161037b9190610a11565b5060408201518160020190816103919190610a11565b50606082015181600301556080820151816004015560a082015181600501559050505050505050565b5f8054906101000a900473ffffffffffffffffffffffffffffffffffffffff1681565b5f604051905090565b5f80fd5b5f80fd5b5f73ffffffffffffffffffffffffffffffffffffffff82169050919050565b5f610417826103ee565b9050919050565b6104278161040d565b8114610431575f80fd5b50565b5f813590506104428161041e565b92915050565b5f6020828403121561045d5761045c6103e6565b5b5f61046a84828501610434565b91505092915050565b61047c8161040d565b82525050565b5f81519050919050565b5f82825260208201905092915050565b5f5b838110156104b957808201518184015260208101905061049e565b5f8484015250505050565b5f601f19601f8301169050919050565b5f6104de82610482565b6104e8818561048c565b93506104f881856020860161049c565b610501816104c4565b840191505092915050565b5f819050919050565b61051e8161050c565b82525050565b5f60c0820190506105375f830189610473565b818103602083015261054981886104d4565b9050818103604083015261055d81876104d4565b905061056c6060830186610515565b6105796080830185610515565b61058660a0830184610515565b979650505050505050565b5f80fd5b5f80fd5b7f4e487b71000000000000000000000000000000000000000000000000000000005f52604160045260245ffd5b6105cf826104c4565b810181811067ffffffffffffffff821117156105ee576105ed610599565b5b80604052505050565b5f6106006103dd565b905061060c82826105c6565b919050565b5f67ffffffffffffffff82111561062b5761062a610599565b5b610634826104c4565b9050602081019050919050565b828183375f83830152505050565b5f61066161065c84610611565b6105f7565b90508281526020810184848401111561067d5761067c610595565b5b610688848285610641565b509392505050565b5f82601f8301126106a4576106a3610591565b5b81356106b484826020860161064f565b91505092915050565b6106c68161050c565b81146106d0575f80fd5b50565b5f813590506106e1816106bd565b92915050565b5f805f805f60a08688031215610700576106ff6103e6565b5b5f61070d88828901610434565b955050602086013567ffffffffffffffff81111561072e5761072d6103ea565b5b61073a88828901610690565b945050604086013567ffffffffffffffff81111561075b5761075a6103ea565b5b61076788828901610690565b9350506060610778888289016106d3565b9250506080610789888289016106d3565b9150509295509295909350565b5f6020820190506107a95f830184610473565b92915050565b7f4e487b71000000000000000000000000000000000000000000000000000000005f52602260045260245ffd5b5f60028204905060018216806107f357607f821691505b602082108103610806576108056107af565b5b50919050565b7f4f6e6c792061646d696e2063616e206172636869766520726573756c747300005f82015250565b5f610840601e8361048c565b915061084b8261080c565b602082019050919050565b5f6020820190508181035f83015261086d81610834565b9050919050565b5f819050815f5260205f209050919050565b5f6020601f8301049050919050565b5f82821b905092915050565b5f600883026108d07fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff82610895565b6108da8683610895565b95508019841693508086168417925050509392505050565b5f819050919050565b5f61091561091061090b8461050c565b6108f2565b61050c565b9050919050565b5f819050919050565b61092e836108fb565b61094261093a8261091c565b8484546108a1565b825550505050565b5f90565b61095661094a565b610961818484610925565b505050565b5b81811015610984576109795f8261094e565b600181019050610967565b5050565b601f8211156109c95761099a81610874565b6109a384610886565b810160208510156109b2578190505b6109c66109be85610886565b830182610966565b50505b505050565b5f82821c905092915050565b5f6109e95f19846008026109ce565b1980831691505092915050565b5f610a0183836109da565b9150826002028217905092915050565b610a1a82610482565b67ffffffffffffffff811115610a3357610a32610599565b5b610a3d82546107dc565b610a48828285610988565b5f60209050601f831160018114610a79575f8415610a67578287015190505b610a7185826109f6565b865550610ad8565b601f198416610a8786610874565b5f5b82811015610aae57848901518255600182019150602085019450602081019050610a89565b86831015610acb5784890151610ac7601f8916826109da565b8355505b6001600288020188555050505b50505050505056fea264697066735822122073096dab2fb05a2f4b567d68c17d697b9605be2d51c98cdc616df63292fc3df364736f6c63430008140033",
+	ABI: "[{\"inputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"electionAddress\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"title\",\"type\":\"string\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"winnerName\",\"type\":\"string\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"winningVotes\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"totalVoters\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"timestamp\",\"type\":\"uint256\"}],\"name\":\"ResultArchived\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"admin\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_electionAddress\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_title\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"_winnerName\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"_winningVotes\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"_totalVoters\",\"type\":\"uint256\"}],\"name\":\"archiveResult\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"name\":\"archivedResults\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"electionAddress\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"title\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"winnerName\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"winningVotes\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"totalVoters\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"timestamp\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+	Bin: "0x608060405234801561000f575f80fd5b505f80546001600160a01b031916331790556106fa8061002e5f395ff3fe608060405234801561000f575f80fd5b506004361061003f575f3560e01c806331bd3af7146100435780635c22ed3a14610071578063f851a44014610086575b5f80fd5b61005661005136600461036c565b6100b0565b604051610068969594939291906103cf565b60405180910390f35b61008461007f3660046104c0565b6101fb565b005b5f54610098906001600160a01b031681565b6040516001600160a01b039091168152602001610068565b600160208190525f9182526040909120805491810180546001600160a01b03909316926100dc90610540565b80601f016020809104026020016040519081016040528092919081815260200182805461010890610540565b80156101535780601f1061012a57610100808354040283529160200191610153565b820191905f5260205f20905b81548152906001019060200180831161013657829003601f168201915b50505050509080600201805461016890610540565b80601f016020809104026020016040519081016040528092919081815260200182805461019490610540565b80156101df5780601f106101b6576101008083540402835291602001916101df565b820191905f5260205f20905b8154815290600101906020018083116101c257829003601f168201915b5050505050908060030154908060040154908060050154905086565b5f546001600160a01b031633146102585760405162461bcd60e51b815260206004820152601e60248201527f4f6e6c792061646d696e2063616e206172636869766520726573756c74730000604482015260640160405180910390fd5b6040805160c0810182526001600160a01b03878116808352602080840189815284860189905260608501889052608085018790524260a08601525f92835260019182905294909120835181546001600160a01b0319169316929092178255925191929091908201906102ca90826105c6565b50604082015160028201906102df90826105c6565b50606082015181600301556080820151816004015560a08201518160050155905050846001600160a01b03167fc480ccf7ef79cb9424bfe2f7ebdf0a50d62a89ec3c84d0e5138ef1414a0d1e518585858542604051610342959493929190610682565b60405180910390a25050505050565b80356001600160a01b0381168114610367575f80fd5b919050565b5f6020828403121561037c575f80fd5b61038582610351565b9392505050565b5f81518084525f5b818110156103b057602081850181015186830182015201610394565b505f602082860101526020601f19601f83011685010191505092915050565b6001600160a01b038716815260c0602082018190525f906103f29083018861038c565b8281036040840152610404818861038c565b60608401969096525050608081019290925260a0909101529392505050565b634e487b7160e01b5f52604160045260245ffd5b5f82601f830112610446575f80fd5b813567ffffffffffffffff8082111561046157610461610423565b604051601f8301601f19908116603f0116810190828211818310171561048957610489610423565b816040528381528660208588010111156104a1575f80fd5b836020870160208301375f602085830101528094505050505092915050565b5f805f805f60a086880312156104d4575f80fd5b6104dd86610351565b9450602086013567ffffffffffffffff808211156104f9575f80fd5b61050589838a01610437565b9550604088013591508082111561051a575f80fd5b5061052788828901610437565b9598949750949560608101359550608001359392505050565b600181811c9082168061055457607f821691505b60208210810361057257634e487b7160e01b5f52602260045260245ffd5b50919050565b601f8211156105c1575f81815260208120601f850160051c8101602086101561059e5750805b601f850160051c820191505b818110156105bd578281556001016105aa565b5050505b505050565b815167ffffffffffffffff8111156105e0576105e0610423565b6105f4816105ee8454610540565b84610578565b602080601f831160018114610627575f84156106105750858301515b5f19600386901b1c1916600185901b1785556105bd565b5f85815260208120601f198616915b8281101561065557888601518255948401946001909101908401610636565b508582101561067257878501515f19600388901b60f8161c191681555b5050505050600190811b01905550565b60a081525f61069460a083018861038c565b82810360208401526106a6818861038c565b6040840196909652505060608101929092526080909101529291505056fea2646970667358221220f4155302801e0aaf338d6cc35df980e683ec5330294d43f9955a8a4c9a7886b664736f6c63430008150033",
 }
 
 // BindingsABI is the input ABI used to generate the binding from.
@@ -318,3 +318,152 @@ func (_Bindings *BindingsSession) ArchiveResult(_electionAddress common.Address,
 func (_Bindings *BindingsTransactorSession) ArchiveResult(_electionAddress common.Address, _title string, _winnerName string, _winningVotes *big.Int, _totalVoters *big.Int) (*types.Transaction, error) {
 	return _Bindings.Contract.ArchiveResult(&_Bindings.TransactOpts, _electionAddress, _title, _winnerName, _winningVotes, _totalVoters)
 }
+
+// BindingsResultArchivedIterator is returned from FilterResultArchived and is used to iterate over the raw logs and unpacked data for ResultArchived events raised by the Bindings contract.
+type BindingsResultArchivedIterator struct {
+	Event *BindingsResultArchived // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *BindingsResultArchivedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(BindingsResultArchived)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(BindingsResultArchived)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *BindingsResultArchivedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *BindingsResultArchivedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// BindingsResultArchived represents a ResultArchived event raised by the Bindings contract.
+type BindingsResultArchived struct {
+	ElectionAddress common.Address
+	Title           string
+	WinnerName      string
+	WinningVotes    *big.Int
+	TotalVoters     *big.Int
+	Timestamp       *big.Int
+	Raw             types.Log // Blockchain specific contextual infos
+}
+
+// FilterResultArchived is a free log retrieval operation binding the contract event 0xc480ccf7ef79cb9424bfe2f7ebdf0a50d62a89ec3c84d0e5138ef1414a0d1e51.
+//
+// Solidity: event ResultArchived(address indexed electionAddress, string title, string winnerName, uint256 winningVotes, uint256 totalVoters, uint256 timestamp)
+func (_Bindings *BindingsFilterer) FilterResultArchived(opts *bind.FilterOpts, electionAddress []common.Address) (*BindingsResultArchivedIterator, error) {
+
+	var electionAddressRule []interface{}
+	for _, electionAddressItem := range electionAddress {
+		electionAddressRule = append(electionAddressRule, electionAddressItem)
+	}
+
+	logs, sub, err := _Bindings.contract.FilterLogs(opts, "ResultArchived", electionAddressRule)
+	if err != nil {
+		return nil, err
+	}
+	return &BindingsResultArchivedIterator{contract: _Bindings.contract, event: "ResultArchived", logs: logs, sub: sub}, nil
+}
+
+// WatchResultArchived is a free log subscription operation binding the contract event 0xc480ccf7ef79cb9424bfe2f7ebdf0a50d62a89ec3c84d0e5138ef1414a0d1e51.
+//
+// Solidity: event ResultArchived(address indexed electionAddress, string title, string winnerName, uint256 winningVotes, uint256 totalVoters, uint256 timestamp)
+func (_Bindings *BindingsFilterer) WatchResultArchived(opts *bind.WatchOpts, sink chan<- *BindingsResultArchived, electionAddress []common.Address) (event.Subscription, error) {
+
+	var electionAddressRule []interface{}
+	for _, electionAddressItem := range electionAddress {
+		electionAddressRule = append(electionAddressRule, electionAddressItem)
+	}
+
+	logs, sub, err := _Bindings.contract.WatchLogs(opts, "ResultArchived", electionAddressRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(BindingsResultArchived)
+				if err := _Bindings.contract.UnpackLog(event, "ResultArchived", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseResultArchived is a log parse operation binding the contract event 0xc480ccf7ef79cb9424bfe2f7ebdf0a50d62a89ec3c84d0e5138ef1414a0d1e51.
+//
+// Solidity: event ResultArchived(address indexed electionAddress, string title, string winnerName, uint256 winningVotes, uint256 totalVoters, uint256 timestamp)
+func (_Bindings *BindingsFilterer) ParseResultArchived(log types.Log) (*BindingsResultArchived, error) {
+	event := new(BindingsResultArchived)
+	if err := _Bindings.contract.UnpackLog(event, "ResultArchived", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
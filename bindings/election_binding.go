@@ -1,4 +1,4 @@
-﻿// Code generated - DO NOT EDIT.
+// Code generated - DO NOT EDIT.
 // This file is a generated binding and any manual changes will be lost.
 
 package bindings
@@ -29,10 +29,19 @@ var (
 	_ = abi.ConvertType
 )
 
+// ElectionCandidate is an auto generated low-level Go binding around an user-defined struct.
+type ElectionCandidate struct {
+	CandidateName        string
+	CandidateDescription string
+	ImgHash              string
+	VoteCount            *big.Int
+	Email                string
+}
+
 // ElectionMetaData contains all meta data concerning the Election contract.
 var ElectionMetaData = &bind.MetaData{
-	ABI: "[{\"inputs\":[{\"internalType\":\"address\",\"name\":\"authority\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"name\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"description\",\"type\":\"string\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"candidate_name\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"candidate_description\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"imgHash\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"email\",\"type\":\"string\"}],\"name\":\"addCandidate\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"candidates\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"candidate_name\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"candidate_description\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"imgHash\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"voteCount\",\"type\":\"uint256\"},{\"internalType\":\"string\",\"name\":\"email\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"election_authority\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"election_description\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"election_name\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"candidateID\",\"type\":\"uint256\"}],\"name\":\"getCandidate\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getElectionDetails\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getNumOfCandidates\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getNumOfVoters\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"email\",\"type\":\"string\"}],\"name\":\"getVoterDetails\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"email\",\"type\":\"string\"}],\"name\":\"hasVoted\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"numCandidates\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"numVoters\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"status\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"candidateID\",\"type\":\"uint256\"},{\"internalType\":\"string\",\"name\":\"e\",\"type\":\"string\"}],\"name\":\"vote\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"name\":\"voters\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"candidate_id_voted\",\"type\":\"uint256\"},{\"internalType\":\"bool\",\"name\":\"voted\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"winnerCandidate\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
-	Bin: "0x60806040523480156200001157600080fd5b506040516200157738038062001577833981016040819052620000349162000149565b600080546001600160a01b0319166001600160a01b03851617905560016200005d838262000262565b5060026200006c828262000262565b50506003805460ff19166001179055506200032e9050565b634e487b7160e01b600052604160045260246000fd5b600082601f830112620000ac57600080fd5b81516001600160401b0380821115620000c957620000c962000084565b604051601f8301601f19908116603f01168101908282118183101715620000f457620000f462000084565b816040528381526020925086838588010111156200011157600080fd5b600091505b8382101562000135578582018301518183018401529082019062000116565b600093810190920192909252949350505050565b6000806000606084860312156200015f57600080fd5b83516001600160a01b03811681146200017757600080fd5b60208501519093506001600160401b03808211156200019557600080fd5b620001a3878388016200009a565b93506040860151915080821115620001ba57600080fd5b50620001c9868287016200009a565b9150509250925092565b600181811c90821680620001e857607f821691505b6020821081036200020957634e487b7160e01b600052602260045260246000fd5b50919050565b601f8211156200025d57600081815260208120601f850160051c81016020861015620002385750805b601f850160051c820191505b81811015620002595782815560010162000244565b5050505b505050565b81516001600160401b038111156200027e576200027e62000084565b62000296816200028f8454620001d3565b846200020f565b602080601f831160018114620002ce5760008415620002b55750858301515b600019600386901b1c1916600185901b17855562000259565b600085815260208120601f198616915b82811015620002ff57888601518255948401946001909101908401620002de565b50858210156200031e5787850151600019600388901b60f8161c191681555b5050505050600190811b01905550565b611239806200033e6000396000f3fe608060405234801561001057600080fd5b506004361061010b5760003560e01c80635216509a116100a257806382e15fcd1161007157806382e15fcd14610242578063a15148d11461026d578063e8685ba114610275578063ed35a5da1461027d578063ed836bc31461028557600080fd5b80635216509a146101d457806353fa2e64146101dd57806365fc783c1461022757806376874a7d1461022f57600080fd5b806335b8e820116100de57806335b8e8201461018457806339bfeae31461019757806342b03cc9146101aa5780634cbe32b8146101bd57600080fd5b8063044d5a9714610110578063200d2ed21461012e578063241084751461014b5780633477ee2e14610160575b600080fd5b61011861029b565b6040516101259190610db4565b60405180910390f35b60035461013b9060ff1681565b6040519015158152602001610125565b61015e610159366004610e71565b610329565b005b61017361016e366004610eb8565b6104a9565b604051610125959493929190610ed1565b610173610192366004610eb8565b6106f7565b61013b6101a5366004610f30565b6109e5565b61015e6101b8366004610f6d565b610a13565b6101c660075481565b604051908152602001610125565b6101c660065481565b6102126101eb366004610f30565b80516020818301810180516005825292820191909301209152805460019091015460ff1682565b60408051928352901515602083015201610125565b6007546101c6565b61021261023d366004610f30565b610aed565b600054610255906001600160a01b031681565b6040516001600160a01b039091168152602001610125565b6101c6610b38565b6006546101c6565b610118610c30565b61028d610c3d565b60405161012592919061101a565b600280546102a890611048565b80601f01602080910402602001604051908101604052809291908181526020018280546102d490611048565b80156103215780601f106102f657610100808354040283529160200191610321565b820191906000526020600020905b81548152906001019060200180831161030457829003601f168201915b505050505081565b6000546001600160a01b0316331461035c5760405162461bcd60e51b815260040161035390611082565b60405180910390fd5b60058160405161036c91906110b1565b9081526040519081900360200190206001015460ff16156103cf5760405162461bcd60e51b815260206004820152601d60248201527f4572726f723a20596f752063616e6e6f7420646f75626c6520766f74650000006044820152606401610353565b60065482106104205760405162461bcd60e51b815260206004820152601b60248201527f4572726f723a20496e76616c69642063616e64696461746520494400000000006044820152606401610353565b6040805180820182528381526001602082015290516005906104439084906110b1565b90815260405160209181900382019020825181559101516001909101805460ff19169115159190911790556007805490600061047e836110cd565b909155505060008281526004602052604081206003018054916104a0836110cd565b91905055505050565b6004602052600090815260409020805481906104c490611048565b80601f01602080910402602001604051908101604052809291908181526020018280546104f090611048565b801561053d5780601f106105125761010080835404028352916020019161053d565b820191906000526020600020905b81548152906001019060200180831161052057829003601f168201915b50505050509080600101805461055290611048565b80601f016020809104026020016040519081016040528092919081815260200182805461057e90611048565b80156105cb5780601f106105a0576101008083540402835291602001916105cb565b820191906000526020600020905b8154815290600101906020018083116105ae57829003601f168201915b5050505050908060020180546105e090611048565b80601f016020809104026020016040519081016040528092919081815260200182805461060c90611048565b80156106595780601f1061062e57610100808354040283529160200191610659565b820191906000526020600020905b81548152906001019060200180831161063c57829003601f168201915b50505050509080600301549080600401805461067490611048565b80601f01602080910402602001604051908101604052809291908181526020018280546106a090611048565b80156106ed5780601f106106c2576101008083540402835291602001916106ed565b820191906000526020600020905b8154815290600101906020018083116106d057829003601f168201915b5050505050905085565b60608060606000606060065486106107515760405162461bcd60e51b815260206004820152601b60248201527f4572726f723a20496e76616c69642063616e64696461746520494400000000006044820152606401610353565b600086815260046020526040808220815160a0810190925280548290829061077890611048565b80601f01602080910402602001604051908101604052809291908181526020018280546107a490611048565b80156107f15780601f106107c6576101008083540402835291602001916107f1565b820191906000526020600020905b8154815290600101906020018083116107d457829003601f168201915b5050505050815260200160018201805461080a90611048565b80601f016020809104026020016040519081016040528092919081815260200182805461083690611048565b80156108835780601f1061085857610100808354040283529160200191610883565b820191906000526020600020905b81548152906001019060200180831161086657829003601f168201915b5050505050815260200160028201805461089c90611048565b80601f01602080910402602001604051908101604052809291908181526020018280546108c890611048565b80156109155780601f106108ea57610100808354040283529160200191610915565b820191906000526020600020905b8154815290600101906020018083116108f857829003601f168201915b505050505081526020016003820154815260200160048201805461093890611048565b80601f016020809104026020016040519081016040528092919081815260200182805461096490611048565b80156109b15780601f10610986576101008083540402835291602001916109b1565b820191906000526020600020905b81548152906001019060200180831161099457829003601f168201915b5050509190925250508151602083015160408401516060850151608090950151929c919b5099509297509550909350505050565b60006005826040516109f791906110b1565b9081526040519081900360200190206001015460ff1692915050565b6000546001600160a01b03163314610a3d5760405162461bcd60e51b815260040161035390611082565b6006546040805160a08101825286815260208082018790528183018690526000606083018190526080830186905284815260049091529190912081518190610a859082611143565b5060208201516001820190610a9a9082611143565b5060408201516002820190610aaf9082611143565b506060820151600382015560808201516004820190610ace9082611143565b50506006805491506000610ae1836110cd565b91905055505050505050565b6000806000600584604051610b0291906110b1565b9081526040805160209281900383018120818301909252815480825260019092015460ff16151592018290529590945092505050565b600080546001600160a01b03163314610b635760405162461bcd60e51b815260040161035390611082565b600060065411610bac5760405162461bcd60e51b81526020600482015260146024820152734572726f723a204e6f2063616e6469646174657360601b6044820152606401610353565b600080805260046020527f17ef568e3e12ab5b9c7254a8d58478811de00f9e6eb34345acd53bf8fd09d3ef549060015b600654811015610c2957600081815260046020526040902060030154831015610c175760008181526004602052604090206003015492509050805b80610c21816110cd565b915050610bdc565b5091505090565b600180546102a890611048565b60608060016002818054610c5090611048565b80601f0160208091040260200160405190810160405280929190818152602001828054610c7c90611048565b8015610cc95780601f10610c9e57610100808354040283529160200191610cc9565b820191906000526020600020905b815481529060010190602001808311610cac57829003601f168201915b50505050509150808054610cdc90611048565b80601f0160208091040260200160405190810160405280929190818152602001828054610d0890611048565b8015610d555780601f10610d2a57610100808354040283529160200191610d55565b820191906000526020600020905b815481529060010190602001808311610d3857829003601f168201915b50505050509050915091509091565b60005b83811015610d7f578181015183820152602001610d67565b50506000910152565b60008151808452610da0816020860160208601610d64565b601f01601f19169290920160200192915050565b602081526000610dc76020830184610d88565b9392505050565b634e487b7160e01b600052604160045260246000fd5b600082601f830112610df557600080fd5b813567ffffffffffffffff80821115610e1057610e10610dce565b604051601f8301601f19908116603f01168101908282118183101715610e3857610e38610dce565b81604052838152866020858801011115610e5157600080fd5b836020870160208301376000602085830101528094505050505092915050565b60008060408385031215610e8457600080fd5b82359150602083013567ffffffffffffffff811115610ea257600080fd5b610eae85828601610de4565b9150509250929050565b600060208284031215610eca57600080fd5b5035919050565b60a081526000610ee460a0830188610d88565b8281036020840152610ef68188610d88565b90508281036040840152610f0a8187610d88565b90508460608401528281036080840152610f248185610d88565b98975050505050505050565b600060208284031215610f4257600080fd5b813567ffffffffffffffff811115610f5957600080fd5b610f6584828501610de4565b949350505050565b60008060008060808587031215610f8357600080fd5b843567ffffffffffffffff80821115610f9b57600080fd5b610fa788838901610de4565b95506020870135915080821115610fbd57600080fd5b610fc988838901610de4565b94506040870135915080821115610fdf57600080fd5b610feb88838901610de4565b9350606087013591508082111561100157600080fd5b5061100e87828801610de4565b91505092959194509250565b60408152600061102d6040830185610d88565b828103602084015261103f8185610d88565b95945050505050565b600181811c9082168061105c57607f821691505b60208210810361107c57634e487b7160e01b600052602260045260246000fd5b50919050565b60208082526015908201527422b93937b91d1020b1b1b2b9b9902232b734b2b21760591b604082015260600190565b600082516110c3818460208701610d64565b9190910192915050565b6000600182016110ed57634e487b7160e01b600052601160045260246000fd5b5060010190565b601f82111561113e57600081815260208120601f850160051c8101602086101561111b5750805b601f850160051c820191505b8181101561113a57828155600101611127565b5050505b505050565b815167ffffffffffffffff81111561115d5761115d610dce565b6111718161116b8454611048565b846110f4565b602080601f8311600181146111a6576000841561118e5750858301515b600019600386901b1c1916600185901b17855561113a565b600085815260208120601f198616915b828110156111d5578886015182559484019460019091019084016111b6565b50858210156111f35787850151600019600388901b60f8161c191681555b5050505050600190811b0190555056fea2646970667358221220aa1e9f032f28909aeb383b4cc8c8c079b6b944aa40854fe4102d7c7963aa4cf364736f6c63430008130033",
+	ABI: "[{\"inputs\":[{\"internalType\":\"address\",\"name\":\"authority\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"name\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"description\",\"type\":\"string\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"action\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"approver\",\"type\":\"bytes32\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"approvals\",\"type\":\"uint256\"}],\"name\":\"ActionApproved\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"bytes32[]\",\"name\":\"approvers\",\"type\":\"bytes32[]\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"threshold\",\"type\":\"uint256\"}],\"name\":\"ApproversSet\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"string\",\"name\":\"voter\",\"type\":\"string\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"candidateID\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"reason\",\"type\":\"string\"}],\"name\":\"BallotRejected\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"candidateID\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"candidate_name\",\"type\":\"string\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"email\",\"type\":\"string\"}],\"name\":\"CandidateAdded\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"closedAt\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"totalVoters\",\"type\":\"uint256\"}],\"name\":\"ElectionClosed\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"pausedAt\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"reason\",\"type\":\"string\"}],\"name\":\"ElectionPaused\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"resumedAt\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"pausedFor\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"endTime\",\"type\":\"uint256\"}],\"name\":\"ElectionResumed\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"startTime\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"endTime\",\"type\":\"uint256\"}],\"name\":\"ScheduleUpdated\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"candidateID\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"voter\",\"type\":\"string\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"totalVoters\",\"type\":\"uint256\"}],\"name\":\"VoteCast\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"uint8\",\"name\":\"kind\",\"type\":\"uint8\"},{\"internalType\":\"uint256\",\"name\":\"a\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"b\",\"type\":\"uint256\"}],\"name\":\"actionHash\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"actionNonce\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"candidate_name\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"candidate_description\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"imgHash\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"email\",\"type\":\"string\"}],\"name\":\"addCandidate\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"name\":\"approvalCount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"approvalThreshold\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"action\",\"type\":\"bytes32\"},{\"internalType\":\"bytes\",\"name\":\"signature\",\"type\":\"bytes\"}],\"name\":\"approve\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"action\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"member\",\"type\":\"bytes32\"}],\"name\":\"approveMember\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"name\":\"approvedBy\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"candidates\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"candidate_name\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"candidate_description\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"imgHash\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"voteCount\",\"type\":\"uint256\"},{\"internalType\":\"string\",\"name\":\"email\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"closeElection\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"election_authority\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"election_description\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"election_name\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"endTime\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getAllCandidates\",\"outputs\":[{\"components\":[{\"internalType\":\"string\",\"name\":\"candidate_name\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"candidate_description\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"imgHash\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"voteCount\",\"type\":\"uint256\"},{\"internalType\":\"string\",\"name\":\"email\",\"type\":\"string\"}],\"internalType\":\"structElection.Candidate[]\",\"name\":\"list\",\"type\":\"tuple[]\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getApprovers\",\"outputs\":[{\"internalType\":\"bytes32[]\",\"name\":\"\",\"type\":\"bytes32[]\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"candidateID\",\"type\":\"uint256\"}],\"name\":\"getCandidate\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getElectionDetails\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getNumOfCandidates\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getNumOfVoters\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getSchedule\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"email\",\"type\":\"string\"}],\"name\":\"getVoterDetails\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"email\",\"type\":\"string\"}],\"name\":\"hasVoted\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"action\",\"type\":\"bytes32\"}],\"name\":\"isApproved\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"name\":\"isApprover\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"isOpen\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"numCandidates\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"numVoters\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"reason\",\"type\":\"string\"}],\"name\":\"pause\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"paused\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"pausedAt\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bool\",\"name\":\"extend\",\"type\":\"bool\"}],\"name\":\"resume\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32[]\",\"name\":\"list\",\"type\":\"bytes32[]\"},{\"internalType\":\"uint256\",\"name\":\"threshold\",\"type\":\"uint256\"}],\"name\":\"setApprovers\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"start\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"end\",\"type\":\"uint256\"}],\"name\":\"setSchedule\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"startTime\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"status\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"totalPaused\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"candidateID\",\"type\":\"uint256\"},{\"internalType\":\"string\",\"name\":\"e\",\"type\":\"string\"}],\"name\":\"vote\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256[]\",\"name\":\"candidateIDs\",\"type\":\"uint256[]\"},{\"internalType\":\"string[]\",\"name\":\"emails\",\"type\":\"string[]\"}],\"name\":\"voteBatch\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"accepted\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"name\":\"voters\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"candidate_id_voted\",\"type\":\"uint256\"},{\"internalType\":\"bool\",\"name\":\"voted\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"winnerCandidate\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+	Bin: "0x608060405234801562000010575f80fd5b50604051620030cd380380620030cd833981016040819052620000339162000159565b5f80546001600160a01b0319166001600160a01b03851617905560016200005b838262000269565b5060026200006a828262000269565b506003805460ff191660011790554260048190556200008e9062093a809062000331565b6005555062000357915050565b634e487b7160e01b5f52604160045260245ffd5b5f82601f830112620000bf575f80fd5b81516001600160401b0380821115620000dc57620000dc6200009b565b604051601f8301601f19908116603f011681019082821181831017156200010757620001076200009b565b8160405283815260209250868385880101111562000123575f80fd5b5f91505b8382101562000146578582018301518183018401529082019062000127565b5f93810190920192909252949350505050565b5f805f606084860312156200016c575f80fd5b83516001600160a01b038116811462000183575f80fd5b60208501519093506001600160401b0380821115620001a0575f80fd5b620001ae87838801620000af565b93506040860151915080821115620001c4575f80fd5b50620001d386828701620000af565b9150509250925092565b600181811c90821680620001f257607f821691505b6020821081036200021157634e487b7160e01b5f52602260045260245ffd5b50919050565b601f82111562000264575f81815260208120601f850160051c810160208610156200023f5750805b601f850160051c820191505b8181101562000260578281556001016200024b565b5050505b505050565b81516001600160401b038111156200028557620002856200009b565b6200029d81620002968454620001dd565b8462000217565b602080601f831160018114620002d3575f8415620002bb5750858301515b5f19600386901b1c1916600185901b17855562000260565b5f85815260208120601f198616915b828110156200030357888601518255948401946001909101908401620002e2565b50858210156200032157878501515f19600388901b60f8161c191681555b5050505050600190811b01905550565b808201808211156200035157634e487b7160e01b5f52601160045260245ffd5b92915050565b612d6880620003655f395ff3fe608060405234801561000f575f80fd5b5060043610610249575f3560e01c80635c975abb1161014057806382e15fcd116100bf578063d67f133111610084578063d67f13311461051a578063e15ef5d51461052d578063e8685ba114610540578063ed35a5da14610548578063ed836bc314610550578063f540879014610566575f80fd5b806382e15fcd146104a35780638d1ba346146104cd5780639869aca0146104ec578063a15148d1146104ff578063bcb5141314610507575f80fd5b806376874a7d1161010557806376874a7d1461045857806378e979251461046b5780637d0eef61146104745780637fb4c4c21461047d578063819d029714610490575f80fd5b80635c975abb1461041257806365fc783c1461041f5780636c6c32d0146104275780636cb3e8ef1461042f5780636da6635514610445575f80fd5b80633197cbb6116101cc57806347535d7b1161019157806347535d7b1461039b57806348aefc32146103a35780634cbe32b8146103b65780635216509a146103bf57806353fa2e64146103c8575f80fd5b80633197cbb6146103355780633477ee2e1461033e57806335b8e8201461036257806339bfeae31461037557806342b03cc914610388575f80fd5b8063241084751161021257806324108475146102d257806326fadbe2146102e55780632d1623691461030e5780632e55d0f2146103175780632e6997fe14610320575f80fd5b8062eea8a21461024d578063044d5a97146102695780630b36c7641461027e578063200d2ed21461029357806321248228146102b0575b5f80fd5b610256600c5481565b6040519081526020015b60405180910390f35b610271610593565b60405161026091906123d5565b61029161028c3660046123ee565b61061f565b005b6003546102a09060ff1681565b6040519015158152602001610260565b6102a06102be36600461240e565b600e6020525f908152604090205460ff1681565b6102916102e03660046124dd565b61065f565b60045460055460035460ff16604080519384526020840192909252151590820152606001610260565b61025660105481565b610256600b5481565b6103286107cf565b6040516102609190612521565b61025660055481565b61035161034c36600461240e565b610af2565b6040516102609594939291906125e6565b61035161037036600461240e565b610d37565b6102a0610383366004612644565b61101b565b61029161039636600461267e565b611048565b6102a061117b565b6102a06103b136600461240e565b6111b2565b61025660095481565b61025660085481565b6103fd6103d6366004612644565b80516020818301810180516007825292820191909301209152805460019091015460ff1682565b60408051928352901515602083015201610260565b600a546102a09060ff1681565b600954610256565b6102916111d8565b610437611287565b604051610260929190612724565b610291610453366004612644565b6112e7565b6103fd610466366004612644565b6113dd565b61025660045481565b610256600f5481565b61025661048b36600461281a565b611426565b61029161049e3660046128c3565b6116ba565b5f546104b5906001600160a01b031681565b6040516001600160a01b039091168152602001610260565b6102566104db36600461240e565b60116020525f908152604090205481565b6102916104fa3660046123ee565b611985565b610256611ad7565b610256610515366004612955565b611bca565b61029161052836600461298c565b611c17565b61029161053b3660046129d9565b611c5c565b600854610256565b610271611da7565b610558611db4565b6040516102609291906129f8565b6102a06105743660046123ee565b601260209081525f928352604080842090915290825290205460ff1681565b600280546105a090612a25565b80601f01602080910402602001604051908101604052809291908181526020018280546105cc90612a25565b80156106175780601f106105ee57610100808354040283529160200191610617565b820191905f5260205f20905b8154815290600101906020018083116105fa57829003601f168201915b505050505081565b5f546001600160a01b031633146106515760405162461bcd60e51b815260040161064890612a5d565b60405180910390fd5b61065b8282611ed7565b5050565b5f546001600160a01b031633146106885760405162461bcd60e51b815260040161064890612a5d565b60035460ff166106aa5760405162461bcd60e51b815260040161064890612a8c565b600a5460ff16156106f95760405162461bcd60e51b8152602060048201526019602482015278115c9c9bdc8e88115b1958dd1a5bdb881a5cc81c185d5cd959603a1b6044820152606401610648565b610701612024565b6007816040516107119190612ac3565b9081526040519081900360200190206001015460ff16156107745760405162461bcd60e51b815260206004820152601d60248201527f4572726f723a20596f752063616e6e6f7420646f75626c6520766f74650000006044820152606401610648565b60085482106107c55760405162461bcd60e51b815260206004820152601b60248201527f4572726f723a20496e76616c69642063616e64696461746520494400000000006044820152606401610648565b61065b82826120c9565b606060085467ffffffffffffffff8111156107ec576107ec612425565b60405190808252806020026020018201604052801561084e57816020015b61083b6040518060a001604052806060815260200160608152602001606081526020015f8152602001606081525090565b81526020019060019003908161080a5790505b5090505f5b600854811015610aee575f8181526006602052604090819020815160a0810190925280548290829061088490612a25565b80601f01602080910402602001604051908101604052809291908181526020018280546108b090612a25565b80156108fb5780601f106108d2576101008083540402835291602001916108fb565b820191905f5260205f20905b8154815290600101906020018083116108de57829003601f168201915b5050505050815260200160018201805461091490612a25565b80601f016020809104026020016040519081016040528092919081815260200182805461094090612a25565b801561098b5780601f106109625761010080835404028352916020019161098b565b820191905f5260205f20905b81548152906001019060200180831161096e57829003601f168201915b505050505081526020016002820180546109a490612a25565b80601f01602080910402602001604051908101604052809291908181526020018280546109d090612a25565b8015610a1b5780601f106109f257610100808354040283529160200191610a1b565b820191905f5260205f20905b8154815290600101906020018083116109fe57829003601f168201915b5050505050815260200160038201548152602001600482018054610a3e90612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054610a6a90612a25565b8015610ab55780601f10610a8c57610100808354040283529160200191610ab5565b820191905f5260205f20905b815481529060010190602001808311610a9857829003601f168201915b505050505081525050828281518110610ad057610ad0612ade565b60200260200101819052508080610ae690612b06565b915050610853565b5090565b60066020525f9081526040902080548190610b0c90612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054610b3890612a25565b8015610b835780601f10610b5a57610100808354040283529160200191610b83565b820191905f5260205f20905b815481529060010190602001808311610b6657829003601f168201915b505050505090806001018054610b9890612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054610bc490612a25565b8015610c0f5780601f10610be657610100808354040283529160200191610c0f565b820191905f5260205f20905b815481529060010190602001808311610bf257829003601f168201915b505050505090806002018054610c2490612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054610c5090612a25565b8015610c9b5780601f10610c7257610100808354040283529160200191610c9b565b820191905f5260205f20905b815481529060010190602001808311610c7e57829003601f168201915b505050505090806003015490806004018054610cb690612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054610ce290612a25565b8015610d2d5780601f10610d0457610100808354040283529160200191610d2d565b820191905f5260205f20905b815481529060010190602001808311610d1057829003601f168201915b5050505050905085565b60608060605f60606008548610610d905760405162461bcd60e51b815260206004820152601b60248201527f4572726f723a20496e76616c69642063616e64696461746520494400000000006044820152606401610648565b5f86815260066020526040808220815160a08101909252805482908290610db690612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054610de290612a25565b8015610e2d5780601f10610e0457610100808354040283529160200191610e2d565b820191905f5260205f20905b815481529060010190602001808311610e1057829003601f168201915b50505050508152602001600182018054610e4690612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054610e7290612a25565b8015610ebd5780601f10610e9457610100808354040283529160200191610ebd565b820191905f5260205f20905b815481529060010190602001808311610ea057829003601f168201915b50505050508152602001600282018054610ed690612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054610f0290612a25565b8015610f4d5780601f10610f2457610100808354040283529160200191610f4d565b820191905f5260205f20905b815481529060010190602001808311610f3057829003601f168201915b5050505050815260200160038201548152602001600482018054610f7090612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054610f9c90612a25565b8015610fe75780601f10610fbe57610100808354040283529160200191610fe7565b820191905f5260205f20905b815481529060010190602001808311610fca57829003601f168201915b5050509190925250508151602083015160408401516060850151608090950151929c919b5099509297509550909350505050565b5f60078260405161102c9190612ac3565b9081526040519081900360200190206001015460ff1692915050565b5f546001600160a01b031633146110715760405162461bcd60e51b815260040161064890612a5d565b60035460ff166110935760405162461bcd60e51b815260040161064890612a8c565b6008546040805160a08101825286815260208082018790528183018690525f6060830181905260808301869052848152600690915291909120815181906110da9082612b6c565b50602082015160018201906110ef9082612b6c565b50604082015160028201906111049082612b6c565b5060608201516003820155608082015160048201906111239082612b6c565b50506008805491505f61113583612b06565b9190505550807fe64f834082661dd9ed89427b87edb6270fd6dad17198b0938f0edd058914c87f868460405161116c9291906129f8565b60405180910390a25050505050565b6003545f9060ff1680156111925750600a5460ff16155b80156111a057506004544210155b80156111ad575060055442105b905090565b5f600f545f14806111d25750600f545f8381526011602052604090205410155b92915050565b5f546001600160a01b031633146112015760405162461bcd60e51b815260040161064890612a5d565b60035460ff166112235760405162461bcd60e51b815260040161064890612a8c565b61122f60015f8061218c565b6003805460ff1916905560055442101561124857426005555b6009546040805142815260208101929092527fd3461d57d954b89a789e6fbd29ff23c87fef073eae45cc372d7e26cb14594353910160405180910390a1565b60605f600d600f54818054806020026020016040519081016040528092919081815260200182805480156112d857602002820191905f5260205f20905b8154815260200190600101908083116112c4575b50505050509150915091509091565b5f546001600160a01b031633146113105760405162461bcd60e51b815260040161064890612a5d565b60035460ff166113325760405162461bcd60e51b815260040161064890612a8c565b600a5460ff161561138f5760405162461bcd60e51b815260206004820152602160248201527f4572726f723a20456c656374696f6e20697320616c72656164792070617573656044820152601960fa1b6064820152608401610648565b600a805460ff1916600117905542600b8190556040517f6439f95181932fe19c4a4871f266184c145461c7a05604ddd8259256681d2f88916113d2918490612c28565b60405180910390a150565b5f805f6007846040516113f09190612ac3565b9081526040805160209281900383018120818301909252815480825260019092015460ff16151592018290529590945092505050565b5f80546001600160a01b031633146114505760405162461bcd60e51b815260040161064890612a5d565b60035460ff166114725760405162461bcd60e51b815260040161064890612a8c565b600a5460ff16156114c15760405162461bcd60e51b8152602060048201526019602482015278115c9c9bdc8e88115b1958dd1a5bdb881a5cc81c185d5cd959603a1b6044820152606401610648565b815183511461150b5760405162461bcd60e51b815260206004820152601660248201527508ae4e4dee4744098cadccee8d040dad2e6dac2e8c6d60531b6044820152606401610648565b611513612024565b5f5b82518110156116b357600783828151811061153257611532612ade565b60200260200101516040516115479190612ac3565b9081526040519081900360200190206001015460ff16156115d2577f7df1303a567716bd50bac12600fe8ef9931c9a1b1a911f1a65af20b3f9e8ac8283828151811061159557611595612ade565b60200260200101518583815181106115af576115af612ade565b60200260200101516040516115c5929190612c40565b60405180910390a16116a1565b6008548482815181106115e7576115e7612ade565b602002602001015110611657577f7df1303a567716bd50bac12600fe8ef9931c9a1b1a911f1a65af20b3f9e8ac8283828151811061162757611627612ade565b602002602001015185838151811061164157611641612ade565b60200260200101516040516115c5929190612c86565b61169384828151811061166c5761166c612ade565b602002602001015184838151811061168657611686612ade565b60200260200101516120c9565b8161169d81612b06565b9250505b806116ab81612b06565b915050611515565b5092915050565b60035460ff166116dc5760405162461bcd60e51b815260040161064890612a8c565b5f546001600160a01b031633146117525760405162461bcd60e51b815260206004820152603460248201527f4572726f723a204f6e6c792074686520656c656374696f6e20617574686f726960448201527374792063616e2073657420617070726f7665727360601b6064820152608401610648565b600f54156117a25760405162461bcd60e51b815260206004820152601c60248201527f4572726f723a20417070726f7665727320616c726561647920736574000000006044820152606401610648565b5f811180156117b2575081518111155b6117fe5760405162461bcd60e51b815260206004820152601860248201527f4572726f723a20496e76616c6964207468726573686f6c6400000000000000006044820152606401610648565b5f5b8251811015611941575f801b83828151811061181e5761181e612ade565b6020026020010151141580156118635750600e5f84838151811061184457611844612ade565b60209081029190910181015182528101919091526040015f205460ff16155b6118bb5760405162461bcd60e51b8152602060048201526024808201527f4572726f723a20496e76616c6964206f72206475706c6963617465206170707260448201526337bb32b960e11b6064820152608401610648565b6001600e5f8584815181106118d2576118d2612ade565b602002602001015181526020019081526020015f205f6101000a81548160ff021916908315150217905550600d83828151811061191157611911612ade565b60209081029190910181015182546001810184555f9384529190922001558061193981612b06565b915050611800565b50600f8190556040517f681084b7bd331d1786b94cd347fd4045485cebef99891447c87fbd4d23b86f3d906119799084908490612724565b60405180910390a15050565b5f546001600160a01b031633146119ae5760405162461bcd60e51b815260040161064890612a5d565b60035460ff166119d05760405162461bcd60e51b815260040161064890612a8c565b818111611a1f5760405162461bcd60e51b815260206004820152601e60248201527f4572726f723a20456e64206d75737420626520616674657220737461727400006044820152606401610648565b6009541580611a2e5750428211155b611a8b5760405162461bcd60e51b815260206004820152602860248201527f4572726f723a2043616e6e6f74206d6f76652073746172742070617374206361604482015267737420766f74657360c01b6064820152608401610648565b611a976002838361218c565b6004829055600581905560408051838152602081018390527f644a9f205d77531ec9577a8e9e61b6f8737b60425f866c6d371472dd268427599101611979565b5f80546001600160a01b03163314611b015760405162461bcd60e51b815260040161064890612a5d565b5f60085411611b495760405162461bcd60e51b81526020600482015260146024820152734572726f723a204e6f2063616e6469646174657360601b6044820152606401610648565b5f80805260066020527f54cdd369e4e8a8515e52ca72ec816c2101831ad1f18bf44102ed171459c9b4fb549060015b600854811015611bc3575f81815260066020526040902060030154831015611bb1575f8181526006602052604090206003015492509050805b80611bbb81612b06565b915050611b78565b5091505090565b601054604080513060208083019190915260ff96909616818301526060810194909452608084019290925260a0808401919091528151808403909101815260c09092019052805191012090565b5f546001600160a01b03163314611c405760405162461bcd60e51b815260040161064890612a5d565b61065b82611c4e8484612220565b6001600160a01b0316611ed7565b5f546001600160a01b03163314611c855760405162461bcd60e51b815260040161064890612a5d565b60035460ff16611ca75760405162461bcd60e51b815260040161064890612a8c565b600a5460ff16611cf95760405162461bcd60e51b815260206004820152601d60248201527f4572726f723a20456c656374696f6e206973206e6f74207061757365640000006044820152606401610648565b8015611d0d57611d0d60026004545f61218c565b5f600b5442611d1c9190612cd2565b600a805460ff191690555f600b819055600c80549293508392909190611d43908490612ce5565b90915550508115611d65578060055f828254611d5f9190612ce5565b90915550505b6005546040805142815260208101849052908101919091527f46c0215a8a63754bf37d8dddd302bf1a7ec6b540be934338c9bb7a5e967c91c390606001611979565b600180546105a090612a25565b60608060016002818054611dc790612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054611df390612a25565b8015611e3e5780601f10611e1557610100808354040283529160200191611e3e565b820191905f5260205f20905b815481529060010190602001808311611e2157829003601f168201915b50505050509150808054611e5190612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054611e7d90612a25565b8015611ec85780601f10611e9f57610100808354040283529160200191611ec8565b820191905f5260205f20905b815481529060010190602001808311611eab57829003601f168201915b50505050509050915091509091565b5f818152600e602052604090205460ff16611f2d5760405162461bcd60e51b815260206004820152601660248201527522b93937b91d102737ba1030b71030b8383937bb32b960511b6044820152606401610648565b5f82815260126020908152604080832084845290915290205460ff1615611f965760405162461bcd60e51b815260206004820152601760248201527f4572726f723a20416c726561647920617070726f7665640000000000000000006044820152606401610648565b5f8281526012602090815260408083208484528252808320805460ff1916600117905584835260119091528120805491611fcf83612b06565b919050555080827f4ede1c61bc0cef1132846f085639c84569ce72445812e6e0a44d3946f88a18b460115f8681526020019081526020015f205460405161201891815260200190565b60405180910390a35050565b6004544210156120765760405162461bcd60e51b815260206004820152601f60248201527f4572726f723a20456c656374696f6e20686173206e6f742073746172746564006044820152606401610648565b60055442106120c75760405162461bcd60e51b815260206004820152601960248201527f4572726f723a20456c656374696f6e2068617320656e646564000000000000006044820152606401610648565b565b6040805180820182528381526001602082015290516007906120ec908490612ac3565b90815260405160209181900382019020825181559101516001909101805460ff191691151591909117905560098054905f61212683612b06565b90915550505f82815260066020526040812060030180549161214783612b06565b9190505550817f7443492b9be7d0c78915e97bcabcb827676bf0292f1b78d919b33ca1a881a66b82600954604051612180929190612cf8565b60405180910390a25050565b600f545f0361219a57505050565b600f5460115f6121ab868686611bca565b81526020019081526020015f205410156122075760405162461bcd60e51b815260206004820152601b60248201527f4572726f723a204e6f7420656e6f75676820617070726f76616c7300000000006044820152606401610648565b60108054905f61221683612b06565b9190505550505050565b5f81516041146122695760405162461bcd60e51b81526020600482015260146024820152734572726f723a20426164207369676e617475726560601b6044820152606401610648565b6020820151604083015160608401515f1a601b8110156122915761228e601b82612d19565b90505b6040517f19457468657265756d205369676e6564204d6573736167653a0a3332000000006020820152603c81018790525f90600190605c0160408051601f1981840301815282825280516020918201205f84529083018083525260ff851690820152606081018690526080810185905260a0016020604051602081039080840390855afa158015612324573d5f803e3d5ffd5b5050604051601f1901519150506001600160a01b03811661237e5760405162461bcd60e51b81526020600482015260146024820152734572726f723a20426164207369676e617475726560601b6044820152606401610648565b9695505050505050565b5f5b838110156123a257818101518382015260200161238a565b50505f910152565b5f81518084526123c1816020860160208601612388565b601f01601f19169290920160200192915050565b602081525f6123e760208301846123aa565b9392505050565b5f80604083850312156123ff575f80fd5b50508035926020909101359150565b5f6020828403121561241e575f80fd5b5035919050565b634e487b7160e01b5f52604160045260245ffd5b604051601f8201601f1916810167ffffffffffffffff8111828210171561246257612462612425565b604052919050565b5f67ffffffffffffffff83111561248357612483612425565b612496601f8401601f1916602001612439565b90508281528383830111156124a9575f80fd5b828260208301375f602084830101529392505050565b5f82601f8301126124ce575f80fd5b6123e78383356020850161246a565b5f80604083850312156124ee575f80fd5b82359150602083013567ffffffffffffffff81111561250b575f80fd5b612517858286016124bf565b9150509250929050565b5f6020808301818452808551808352604092508286019150828160051b8701018488015f5b838110156125d857603f19898403018552815160a0815181865261256c828701826123aa565b915050888201518582038a87015261258482826123aa565b915050878201518582038987015261259c82826123aa565b9150506060808301518187015250608080830151925085820381870152506125c481836123aa565b968901969450505090860190600101612546565b509098975050505050505050565b60a081525f6125f860a08301886123aa565b828103602084015261260a81886123aa565b9050828103604084015261261e81876123aa565b9050846060840152828103608084015261263881856123aa565b98975050505050505050565b5f60208284031215612654575f80fd5b813567ffffffffffffffff81111561266a575f80fd5b612676848285016124bf565b949350505050565b5f805f8060808587031215612691575f80fd5b843567ffffffffffffffff808211156126a8575f80fd5b6126b4888389016124bf565b955060208701359150808211156126c9575f80fd5b6126d5888389016124bf565b945060408701359150808211156126ea575f80fd5b6126f6888389016124bf565b9350606087013591508082111561270b575f80fd5b50612718878288016124bf565b91505092959194509250565b604080825283519082018190525f906020906060840190828701845b8281101561275c57815184529284019290840190600101612740565b50505092019290925292915050565b5f67ffffffffffffffff82111561278457612784612425565b5060051b60200190565b5f82601f83011261279d575f80fd5b813560206127b26127ad8361276b565b612439565b82815260059290921b840181019181810190868411156127d0575f80fd5b8286015b8481101561280f57803567ffffffffffffffff8111156127f3575f8081fd5b6128018986838b01016124bf565b8452509183019183016127d4565b509695505050505050565b5f806040838503121561282b575f80fd5b823567ffffffffffffffff80821115612842575f80fd5b818501915085601f830112612855575f80fd5b813560206128656127ad8361276b565b82815260059290921b84018101918181019089841115612883575f80fd5b948201945b838610156128a157853582529482019490820190612888565b965050860135925050808211156128b6575f80fd5b506125178582860161278e565b5f80604083850312156128d4575f80fd5b823567ffffffffffffffff8111156128ea575f80fd5b8301601f810185136128fa575f80fd5b8035602061290a6127ad8361276b565b82815260059290921b83018101918181019088841115612928575f80fd5b938201935b838510156129465784358252938201939082019061292d565b98969091013596505050505050565b5f805f60608486031215612967575f80fd5b833560ff81168114612977575f80fd5b95602085013595506040909401359392505050565b5f806040838503121561299d575f80fd5b82359150602083013567ffffffffffffffff8111156129ba575f80fd5b8301601f810185136129ca575f80fd5b6125178582356020840161246a565b5f602082840312156129e9575f80fd5b813580151581146123e7575f80fd5b604081525f612a0a60408301856123aa565b8281036020840152612a1c81856123aa565b95945050505050565b600181811c90821680612a3957607f821691505b602082108103612a5757634e487b7160e01b5f52602260045260245ffd5b50919050565b60208082526015908201527422b93937b91d1020b1b1b2b9b9902232b734b2b21760591b604082015260600190565b60208082526019908201527f4572726f723a20456c656374696f6e20697320636c6f73656400000000000000604082015260600190565b5f8251612ad4818460208701612388565b9190910192915050565b634e487b7160e01b5f52603260045260245ffd5b634e487b7160e01b5f52601160045260245ffd5b5f60018201612b1757612b17612af2565b5060010190565b601f821115612b67575f81815260208120601f850160051c81016020861015612b445750805b601f850160051c820191505b81811015612b6357828155600101612b50565b5050505b505050565b815167ffffffffffffffff811115612b8657612b86612425565b612b9a81612b948454612a25565b84612b1e565b602080601f831160018114612bcd575f8415612bb65750858301515b5f19600386901b1c1916600185901b178555612b63565b5f85815260208120601f198616915b82811015612bfb57888601518255948401946001909101908401612bdc565b5085821015612c1857878501515f19600388901b60f8161c191681555b5050505050600190811b01905550565b828152604060208201525f61267660408301846123aa565b606081525f612c5260608301856123aa565b8360208401528281036040840152600b81526a646f75626c6520766f746560a81b6020820152604081019150509392505050565b606081525f612c9860608301856123aa565b83602084015282810360408401526011815270696e76616c69642063616e64696461746560781b6020820152604081019150509392505050565b818103818111156111d2576111d2612af2565b808201808211156111d2576111d2612af2565b604081525f612d0a60408301856123aa565b90508260208301529392505050565b60ff81811683821601908111156111d2576111d2612af256fea26469706673582212203d647dc3688a57ea591c82383d2bb3e04073875d0fb6915e75c74fbdc6ec64aa64736f6c63430008150033",
 }
 
 // ElectionABI is the input ABI used to generate the binding from.
@@ -202,6 +211,161 @@ func (_Election *ElectionTransactorRaw) Transact(opts *bind.TransactOpts, method
 	return _Election.Contract.contract.Transact(opts, method, params...)
 }
 
+// ActionHash is a free data retrieval call binding the contract method 0xbcb51413.
+//
+// Solidity: function actionHash(uint8 kind, uint256 a, uint256 b) view returns(bytes32)
+func (_Election *ElectionCaller) ActionHash(opts *bind.CallOpts, kind uint8, a *big.Int, b *big.Int) ([32]byte, error) {
+	var out []interface{}
+	err := _Election.contract.Call(opts, &out, "actionHash", kind, a, b)
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// ActionHash is a free data retrieval call binding the contract method 0xbcb51413.
+//
+// Solidity: function actionHash(uint8 kind, uint256 a, uint256 b) view returns(bytes32)
+func (_Election *ElectionSession) ActionHash(kind uint8, a *big.Int, b *big.Int) ([32]byte, error) {
+	return _Election.Contract.ActionHash(&_Election.CallOpts, kind, a, b)
+}
+
+// ActionHash is a free data retrieval call binding the contract method 0xbcb51413.
+//
+// Solidity: function actionHash(uint8 kind, uint256 a, uint256 b) view returns(bytes32)
+func (_Election *ElectionCallerSession) ActionHash(kind uint8, a *big.Int, b *big.Int) ([32]byte, error) {
+	return _Election.Contract.ActionHash(&_Election.CallOpts, kind, a, b)
+}
+
+// ActionNonce is a free data retrieval call binding the contract method 0x2d162369.
+//
+// Solidity: function actionNonce() view returns(uint256)
+func (_Election *ElectionCaller) ActionNonce(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Election.contract.Call(opts, &out, "actionNonce")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// ActionNonce is a free data retrieval call binding the contract method 0x2d162369.
+//
+// Solidity: function actionNonce() view returns(uint256)
+func (_Election *ElectionSession) ActionNonce() (*big.Int, error) {
+	return _Election.Contract.ActionNonce(&_Election.CallOpts)
+}
+
+// ActionNonce is a free data retrieval call binding the contract method 0x2d162369.
+//
+// Solidity: function actionNonce() view returns(uint256)
+func (_Election *ElectionCallerSession) ActionNonce() (*big.Int, error) {
+	return _Election.Contract.ActionNonce(&_Election.CallOpts)
+}
+
+// ApprovalCount is a free data retrieval call binding the contract method 0x8d1ba346.
+//
+// Solidity: function approvalCount(bytes32 ) view returns(uint256)
+func (_Election *ElectionCaller) ApprovalCount(opts *bind.CallOpts, arg0 [32]byte) (*big.Int, error) {
+	var out []interface{}
+	err := _Election.contract.Call(opts, &out, "approvalCount", arg0)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// ApprovalCount is a free data retrieval call binding the contract method 0x8d1ba346.
+//
+// Solidity: function approvalCount(bytes32 ) view returns(uint256)
+func (_Election *ElectionSession) ApprovalCount(arg0 [32]byte) (*big.Int, error) {
+	return _Election.Contract.ApprovalCount(&_Election.CallOpts, arg0)
+}
+
+// ApprovalCount is a free data retrieval call binding the contract method 0x8d1ba346.
+//
+// Solidity: function approvalCount(bytes32 ) view returns(uint256)
+func (_Election *ElectionCallerSession) ApprovalCount(arg0 [32]byte) (*big.Int, error) {
+	return _Election.Contract.ApprovalCount(&_Election.CallOpts, arg0)
+}
+
+// ApprovalThreshold is a free data retrieval call binding the contract method 0x7d0eef61.
+//
+// Solidity: function approvalThreshold() view returns(uint256)
+func (_Election *ElectionCaller) ApprovalThreshold(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Election.contract.Call(opts, &out, "approvalThreshold")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// ApprovalThreshold is a free data retrieval call binding the contract method 0x7d0eef61.
+//
+// Solidity: function approvalThreshold() view returns(uint256)
+func (_Election *ElectionSession) ApprovalThreshold() (*big.Int, error) {
+	return _Election.Contract.ApprovalThreshold(&_Election.CallOpts)
+}
+
+// ApprovalThreshold is a free data retrieval call binding the contract method 0x7d0eef61.
+//
+// Solidity: function approvalThreshold() view returns(uint256)
+func (_Election *ElectionCallerSession) ApprovalThreshold() (*big.Int, error) {
+	return _Election.Contract.ApprovalThreshold(&_Election.CallOpts)
+}
+
+// ApprovedBy is a free data retrieval call binding the contract method 0xf5408790.
+//
+// Solidity: function approvedBy(bytes32 , bytes32 ) view returns(bool)
+func (_Election *ElectionCaller) ApprovedBy(opts *bind.CallOpts, arg0 [32]byte, arg1 [32]byte) (bool, error) {
+	var out []interface{}
+	err := _Election.contract.Call(opts, &out, "approvedBy", arg0, arg1)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// ApprovedBy is a free data retrieval call binding the contract method 0xf5408790.
+//
+// Solidity: function approvedBy(bytes32 , bytes32 ) view returns(bool)
+func (_Election *ElectionSession) ApprovedBy(arg0 [32]byte, arg1 [32]byte) (bool, error) {
+	return _Election.Contract.ApprovedBy(&_Election.CallOpts, arg0, arg1)
+}
+
+// ApprovedBy is a free data retrieval call binding the contract method 0xf5408790.
+//
+// Solidity: function approvedBy(bytes32 , bytes32 ) view returns(bool)
+func (_Election *ElectionCallerSession) ApprovedBy(arg0 [32]byte, arg1 [32]byte) (bool, error) {
+	return _Election.Contract.ApprovedBy(&_Election.CallOpts, arg0, arg1)
+}
+
 // Candidates is a free data retrieval call binding the contract method 0x3477ee2e.
 //
 // Solidity: function candidates(uint256 ) view returns(string candidate_name, string candidate_description, string imgHash, uint256 voteCount, string email)
@@ -355,6 +519,100 @@ func (_Election *ElectionCallerSession) ElectionName() (string, error) {
 	return _Election.Contract.ElectionName(&_Election.CallOpts)
 }
 
+// EndTime is a free data retrieval call binding the contract method 0x3197cbb6.
+//
+// Solidity: function endTime() view returns(uint256)
+func (_Election *ElectionCaller) EndTime(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Election.contract.Call(opts, &out, "endTime")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// EndTime is a free data retrieval call binding the contract method 0x3197cbb6.
+//
+// Solidity: function endTime() view returns(uint256)
+func (_Election *ElectionSession) EndTime() (*big.Int, error) {
+	return _Election.Contract.EndTime(&_Election.CallOpts)
+}
+
+// EndTime is a free data retrieval call binding the contract method 0x3197cbb6.
+//
+// Solidity: function endTime() view returns(uint256)
+func (_Election *ElectionCallerSession) EndTime() (*big.Int, error) {
+	return _Election.Contract.EndTime(&_Election.CallOpts)
+}
+
+// GetAllCandidates is a free data retrieval call binding the contract method 0x2e6997fe.
+//
+// Solidity: function getAllCandidates() view returns((string,string,string,uint256,string)[] list)
+func (_Election *ElectionCaller) GetAllCandidates(opts *bind.CallOpts) ([]ElectionCandidate, error) {
+	var out []interface{}
+	err := _Election.contract.Call(opts, &out, "getAllCandidates")
+
+	if err != nil {
+		return *new([]ElectionCandidate), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([]ElectionCandidate)).(*[]ElectionCandidate)
+
+	return out0, err
+
+}
+
+// GetAllCandidates is a free data retrieval call binding the contract method 0x2e6997fe.
+//
+// Solidity: function getAllCandidates() view returns((string,string,string,uint256,string)[] list)
+func (_Election *ElectionSession) GetAllCandidates() ([]ElectionCandidate, error) {
+	return _Election.Contract.GetAllCandidates(&_Election.CallOpts)
+}
+
+// GetAllCandidates is a free data retrieval call binding the contract method 0x2e6997fe.
+//
+// Solidity: function getAllCandidates() view returns((string,string,string,uint256,string)[] list)
+func (_Election *ElectionCallerSession) GetAllCandidates() ([]ElectionCandidate, error) {
+	return _Election.Contract.GetAllCandidates(&_Election.CallOpts)
+}
+
+// GetApprovers is a free data retrieval call binding the contract method 0x6cb3e8ef.
+//
+// Solidity: function getApprovers() view returns(bytes32[], uint256)
+func (_Election *ElectionCaller) GetApprovers(opts *bind.CallOpts) ([][32]byte, *big.Int, error) {
+	var out []interface{}
+	err := _Election.contract.Call(opts, &out, "getApprovers")
+
+	if err != nil {
+		return *new([][32]byte), *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([][32]byte)).(*[][32]byte)
+	out1 := *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+
+	return out0, out1, err
+
+}
+
+// GetApprovers is a free data retrieval call binding the contract method 0x6cb3e8ef.
+//
+// Solidity: function getApprovers() view returns(bytes32[], uint256)
+func (_Election *ElectionSession) GetApprovers() ([][32]byte, *big.Int, error) {
+	return _Election.Contract.GetApprovers(&_Election.CallOpts)
+}
+
+// GetApprovers is a free data retrieval call binding the contract method 0x6cb3e8ef.
+//
+// Solidity: function getApprovers() view returns(bytes32[], uint256)
+func (_Election *ElectionCallerSession) GetApprovers() ([][32]byte, *big.Int, error) {
+	return _Election.Contract.GetApprovers(&_Election.CallOpts)
+}
+
 // GetCandidate is a free data retrieval call binding the contract method 0x35b8e820.
 //
 // Solidity: function getCandidate(uint256 candidateID) view returns(string, string, string, uint256, string)
@@ -484,6 +742,39 @@ func (_Election *ElectionCallerSession) GetNumOfVoters() (*big.Int, error) {
 	return _Election.Contract.GetNumOfVoters(&_Election.CallOpts)
 }
 
+// GetSchedule is a free data retrieval call binding the contract method 0x26fadbe2.
+//
+// Solidity: function getSchedule() view returns(uint256, uint256, bool)
+func (_Election *ElectionCaller) GetSchedule(opts *bind.CallOpts) (*big.Int, *big.Int, bool, error) {
+	var out []interface{}
+	err := _Election.contract.Call(opts, &out, "getSchedule")
+
+	if err != nil {
+		return *new(*big.Int), *new(*big.Int), *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	out1 := *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	out2 := *abi.ConvertType(out[2], new(bool)).(*bool)
+
+	return out0, out1, out2, err
+
+}
+
+// GetSchedule is a free data retrieval call binding the contract method 0x26fadbe2.
+//
+// Solidity: function getSchedule() view returns(uint256, uint256, bool)
+func (_Election *ElectionSession) GetSchedule() (*big.Int, *big.Int, bool, error) {
+	return _Election.Contract.GetSchedule(&_Election.CallOpts)
+}
+
+// GetSchedule is a free data retrieval call binding the contract method 0x26fadbe2.
+//
+// Solidity: function getSchedule() view returns(uint256, uint256, bool)
+func (_Election *ElectionCallerSession) GetSchedule() (*big.Int, *big.Int, bool, error) {
+	return _Election.Contract.GetSchedule(&_Election.CallOpts)
+}
+
 // GetVoterDetails is a free data retrieval call binding the contract method 0x76874a7d.
 //
 // Solidity: function getVoterDetails(string email) view returns(uint256, bool)
@@ -547,6 +838,99 @@ func (_Election *ElectionCallerSession) HasVoted(email string) (bool, error) {
 	return _Election.Contract.HasVoted(&_Election.CallOpts, email)
 }
 
+// IsApproved is a free data retrieval call binding the contract method 0x48aefc32.
+//
+// Solidity: function isApproved(bytes32 action) view returns(bool)
+func (_Election *ElectionCaller) IsApproved(opts *bind.CallOpts, action [32]byte) (bool, error) {
+	var out []interface{}
+	err := _Election.contract.Call(opts, &out, "isApproved", action)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// IsApproved is a free data retrieval call binding the contract method 0x48aefc32.
+//
+// Solidity: function isApproved(bytes32 action) view returns(bool)
+func (_Election *ElectionSession) IsApproved(action [32]byte) (bool, error) {
+	return _Election.Contract.IsApproved(&_Election.CallOpts, action)
+}
+
+// IsApproved is a free data retrieval call binding the contract method 0x48aefc32.
+//
+// Solidity: function isApproved(bytes32 action) view returns(bool)
+func (_Election *ElectionCallerSession) IsApproved(action [32]byte) (bool, error) {
+	return _Election.Contract.IsApproved(&_Election.CallOpts, action)
+}
+
+// IsApprover is a free data retrieval call binding the contract method 0x21248228.
+//
+// Solidity: function isApprover(bytes32 ) view returns(bool)
+func (_Election *ElectionCaller) IsApprover(opts *bind.CallOpts, arg0 [32]byte) (bool, error) {
+	var out []interface{}
+	err := _Election.contract.Call(opts, &out, "isApprover", arg0)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// IsApprover is a free data retrieval call binding the contract method 0x21248228.
+//
+// Solidity: function isApprover(bytes32 ) view returns(bool)
+func (_Election *ElectionSession) IsApprover(arg0 [32]byte) (bool, error) {
+	return _Election.Contract.IsApprover(&_Election.CallOpts, arg0)
+}
+
+// IsApprover is a free data retrieval call binding the contract method 0x21248228.
+//
+// Solidity: function isApprover(bytes32 ) view returns(bool)
+func (_Election *ElectionCallerSession) IsApprover(arg0 [32]byte) (bool, error) {
+	return _Election.Contract.IsApprover(&_Election.CallOpts, arg0)
+}
+
+// IsOpen is a free data retrieval call binding the contract method 0x47535d7b.
+//
+// Solidity: function isOpen() view returns(bool)
+func (_Election *ElectionCaller) IsOpen(opts *bind.CallOpts) (bool, error) {
+	var out []interface{}
+	err := _Election.contract.Call(opts, &out, "isOpen")
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// IsOpen is a free data retrieval call binding the contract method 0x47535d7b.
+//
+// Solidity: function isOpen() view returns(bool)
+func (_Election *ElectionSession) IsOpen() (bool, error) {
+	return _Election.Contract.IsOpen(&_Election.CallOpts)
+}
+
+// IsOpen is a free data retrieval call binding the contract method 0x47535d7b.
+//
+// Solidity: function isOpen() view returns(bool)
+func (_Election *ElectionCallerSession) IsOpen() (bool, error) {
+	return _Election.Contract.IsOpen(&_Election.CallOpts)
+}
+
 // NumCandidates is a free data retrieval call binding the contract method 0x5216509a.
 //
 // Solidity: function numCandidates() view returns(uint256)
@@ -609,12 +993,12 @@ func (_Election *ElectionCallerSession) NumVoters() (*big.Int, error) {
 	return _Election.Contract.NumVoters(&_Election.CallOpts)
 }
 
-// Status is a free data retrieval call binding the contract method 0x200d2ed2.
+// Paused is a free data retrieval call binding the contract method 0x5c975abb.
 //
-// Solidity: function status() view returns(bool)
-func (_Election *ElectionCaller) Status(opts *bind.CallOpts) (bool, error) {
+// Solidity: function paused() view returns(bool)
+func (_Election *ElectionCaller) Paused(opts *bind.CallOpts) (bool, error) {
 	var out []interface{}
-	err := _Election.contract.Call(opts, &out, "status")
+	err := _Election.contract.Call(opts, &out, "paused")
 
 	if err != nil {
 		return *new(bool), err
@@ -626,42 +1010,166 @@ func (_Election *ElectionCaller) Status(opts *bind.CallOpts) (bool, error) {
 
 }
 
-// Status is a free data retrieval call binding the contract method 0x200d2ed2.
+// Paused is a free data retrieval call binding the contract method 0x5c975abb.
 //
-// Solidity: function status() view returns(bool)
-func (_Election *ElectionSession) Status() (bool, error) {
-	return _Election.Contract.Status(&_Election.CallOpts)
+// Solidity: function paused() view returns(bool)
+func (_Election *ElectionSession) Paused() (bool, error) {
+	return _Election.Contract.Paused(&_Election.CallOpts)
 }
 
-// Status is a free data retrieval call binding the contract method 0x200d2ed2.
+// Paused is a free data retrieval call binding the contract method 0x5c975abb.
 //
-// Solidity: function status() view returns(bool)
-func (_Election *ElectionCallerSession) Status() (bool, error) {
-	return _Election.Contract.Status(&_Election.CallOpts)
+// Solidity: function paused() view returns(bool)
+func (_Election *ElectionCallerSession) Paused() (bool, error) {
+	return _Election.Contract.Paused(&_Election.CallOpts)
 }
 
-// Voters is a free data retrieval call binding the contract method 0x53fa2e64.
+// PausedAt is a free data retrieval call binding the contract method 0x2e55d0f2.
 //
-// Solidity: function voters(string ) view returns(uint256 candidate_id_voted, bool voted)
-func (_Election *ElectionCaller) Voters(opts *bind.CallOpts, arg0 string) (struct {
-	CandidateIdVoted *big.Int
-	Voted            bool
-}, error) {
+// Solidity: function pausedAt() view returns(uint256)
+func (_Election *ElectionCaller) PausedAt(opts *bind.CallOpts) (*big.Int, error) {
 	var out []interface{}
-	err := _Election.contract.Call(opts, &out, "voters", arg0)
+	err := _Election.contract.Call(opts, &out, "pausedAt")
 
-	outstruct := new(struct {
-		CandidateIdVoted *big.Int
-		Voted            bool
-	})
 	if err != nil {
-		return *outstruct, err
+		return *new(*big.Int), err
 	}
 
-	outstruct.CandidateIdVoted = *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
-	outstruct.Voted = *abi.ConvertType(out[1], new(bool)).(*bool)
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
 
-	return *outstruct, err
+	return out0, err
+
+}
+
+// PausedAt is a free data retrieval call binding the contract method 0x2e55d0f2.
+//
+// Solidity: function pausedAt() view returns(uint256)
+func (_Election *ElectionSession) PausedAt() (*big.Int, error) {
+	return _Election.Contract.PausedAt(&_Election.CallOpts)
+}
+
+// PausedAt is a free data retrieval call binding the contract method 0x2e55d0f2.
+//
+// Solidity: function pausedAt() view returns(uint256)
+func (_Election *ElectionCallerSession) PausedAt() (*big.Int, error) {
+	return _Election.Contract.PausedAt(&_Election.CallOpts)
+}
+
+// StartTime is a free data retrieval call binding the contract method 0x78e97925.
+//
+// Solidity: function startTime() view returns(uint256)
+func (_Election *ElectionCaller) StartTime(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Election.contract.Call(opts, &out, "startTime")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// StartTime is a free data retrieval call binding the contract method 0x78e97925.
+//
+// Solidity: function startTime() view returns(uint256)
+func (_Election *ElectionSession) StartTime() (*big.Int, error) {
+	return _Election.Contract.StartTime(&_Election.CallOpts)
+}
+
+// StartTime is a free data retrieval call binding the contract method 0x78e97925.
+//
+// Solidity: function startTime() view returns(uint256)
+func (_Election *ElectionCallerSession) StartTime() (*big.Int, error) {
+	return _Election.Contract.StartTime(&_Election.CallOpts)
+}
+
+// Status is a free data retrieval call binding the contract method 0x200d2ed2.
+//
+// Solidity: function status() view returns(bool)
+func (_Election *ElectionCaller) Status(opts *bind.CallOpts) (bool, error) {
+	var out []interface{}
+	err := _Election.contract.Call(opts, &out, "status")
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// Status is a free data retrieval call binding the contract method 0x200d2ed2.
+//
+// Solidity: function status() view returns(bool)
+func (_Election *ElectionSession) Status() (bool, error) {
+	return _Election.Contract.Status(&_Election.CallOpts)
+}
+
+// Status is a free data retrieval call binding the contract method 0x200d2ed2.
+//
+// Solidity: function status() view returns(bool)
+func (_Election *ElectionCallerSession) Status() (bool, error) {
+	return _Election.Contract.Status(&_Election.CallOpts)
+}
+
+// TotalPaused is a free data retrieval call binding the contract method 0x00eea8a2.
+//
+// Solidity: function totalPaused() view returns(uint256)
+func (_Election *ElectionCaller) TotalPaused(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Election.contract.Call(opts, &out, "totalPaused")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// TotalPaused is a free data retrieval call binding the contract method 0x00eea8a2.
+//
+// Solidity: function totalPaused() view returns(uint256)
+func (_Election *ElectionSession) TotalPaused() (*big.Int, error) {
+	return _Election.Contract.TotalPaused(&_Election.CallOpts)
+}
+
+// TotalPaused is a free data retrieval call binding the contract method 0x00eea8a2.
+//
+// Solidity: function totalPaused() view returns(uint256)
+func (_Election *ElectionCallerSession) TotalPaused() (*big.Int, error) {
+	return _Election.Contract.TotalPaused(&_Election.CallOpts)
+}
+
+// Voters is a free data retrieval call binding the contract method 0x53fa2e64.
+//
+// Solidity: function voters(string ) view returns(uint256 candidate_id_voted, bool voted)
+func (_Election *ElectionCaller) Voters(opts *bind.CallOpts, arg0 string) (struct {
+	CandidateIdVoted *big.Int
+	Voted            bool
+}, error) {
+	var out []interface{}
+	err := _Election.contract.Call(opts, &out, "voters", arg0)
+
+	outstruct := new(struct {
+		CandidateIdVoted *big.Int
+		Voted            bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.CandidateIdVoted = *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	outstruct.Voted = *abi.ConvertType(out[1], new(bool)).(*bool)
+
+	return *outstruct, err
 
 }
 
@@ -737,6 +1245,153 @@ func (_Election *ElectionTransactorSession) AddCandidate(candidate_name string,
 	return _Election.Contract.AddCandidate(&_Election.TransactOpts, candidate_name, candidate_description, imgHash, email)
 }
 
+// Approve is a paid mutator transaction binding the contract method 0xd67f1331.
+//
+// Solidity: function approve(bytes32 action, bytes signature) returns()
+func (_Election *ElectionTransactor) Approve(opts *bind.TransactOpts, action [32]byte, signature []byte) (*types.Transaction, error) {
+	return _Election.contract.Transact(opts, "approve", action, signature)
+}
+
+// Approve is a paid mutator transaction binding the contract method 0xd67f1331.
+//
+// Solidity: function approve(bytes32 action, bytes signature) returns()
+func (_Election *ElectionSession) Approve(action [32]byte, signature []byte) (*types.Transaction, error) {
+	return _Election.Contract.Approve(&_Election.TransactOpts, action, signature)
+}
+
+// Approve is a paid mutator transaction binding the contract method 0xd67f1331.
+//
+// Solidity: function approve(bytes32 action, bytes signature) returns()
+func (_Election *ElectionTransactorSession) Approve(action [32]byte, signature []byte) (*types.Transaction, error) {
+	return _Election.Contract.Approve(&_Election.TransactOpts, action, signature)
+}
+
+// ApproveMember is a paid mutator transaction binding the contract method 0x0b36c764.
+//
+// Solidity: function approveMember(bytes32 action, bytes32 member) returns()
+func (_Election *ElectionTransactor) ApproveMember(opts *bind.TransactOpts, action [32]byte, member [32]byte) (*types.Transaction, error) {
+	return _Election.contract.Transact(opts, "approveMember", action, member)
+}
+
+// ApproveMember is a paid mutator transaction binding the contract method 0x0b36c764.
+//
+// Solidity: function approveMember(bytes32 action, bytes32 member) returns()
+func (_Election *ElectionSession) ApproveMember(action [32]byte, member [32]byte) (*types.Transaction, error) {
+	return _Election.Contract.ApproveMember(&_Election.TransactOpts, action, member)
+}
+
+// ApproveMember is a paid mutator transaction binding the contract method 0x0b36c764.
+//
+// Solidity: function approveMember(bytes32 action, bytes32 member) returns()
+func (_Election *ElectionTransactorSession) ApproveMember(action [32]byte, member [32]byte) (*types.Transaction, error) {
+	return _Election.Contract.ApproveMember(&_Election.TransactOpts, action, member)
+}
+
+// CloseElection is a paid mutator transaction binding the contract method 0x6c6c32d0.
+//
+// Solidity: function closeElection() returns()
+func (_Election *ElectionTransactor) CloseElection(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Election.contract.Transact(opts, "closeElection")
+}
+
+// CloseElection is a paid mutator transaction binding the contract method 0x6c6c32d0.
+//
+// Solidity: function closeElection() returns()
+func (_Election *ElectionSession) CloseElection() (*types.Transaction, error) {
+	return _Election.Contract.CloseElection(&_Election.TransactOpts)
+}
+
+// CloseElection is a paid mutator transaction binding the contract method 0x6c6c32d0.
+//
+// Solidity: function closeElection() returns()
+func (_Election *ElectionTransactorSession) CloseElection() (*types.Transaction, error) {
+	return _Election.Contract.CloseElection(&_Election.TransactOpts)
+}
+
+// Pause is a paid mutator transaction binding the contract method 0x6da66355.
+//
+// Solidity: function pause(string reason) returns()
+func (_Election *ElectionTransactor) Pause(opts *bind.TransactOpts, reason string) (*types.Transaction, error) {
+	return _Election.contract.Transact(opts, "pause", reason)
+}
+
+// Pause is a paid mutator transaction binding the contract method 0x6da66355.
+//
+// Solidity: function pause(string reason) returns()
+func (_Election *ElectionSession) Pause(reason string) (*types.Transaction, error) {
+	return _Election.Contract.Pause(&_Election.TransactOpts, reason)
+}
+
+// Pause is a paid mutator transaction binding the contract method 0x6da66355.
+//
+// Solidity: function pause(string reason) returns()
+func (_Election *ElectionTransactorSession) Pause(reason string) (*types.Transaction, error) {
+	return _Election.Contract.Pause(&_Election.TransactOpts, reason)
+}
+
+// Resume is a paid mutator transaction binding the contract method 0xe15ef5d5.
+//
+// Solidity: function resume(bool extend) returns()
+func (_Election *ElectionTransactor) Resume(opts *bind.TransactOpts, extend bool) (*types.Transaction, error) {
+	return _Election.contract.Transact(opts, "resume", extend)
+}
+
+// Resume is a paid mutator transaction binding the contract method 0xe15ef5d5.
+//
+// Solidity: function resume(bool extend) returns()
+func (_Election *ElectionSession) Resume(extend bool) (*types.Transaction, error) {
+	return _Election.Contract.Resume(&_Election.TransactOpts, extend)
+}
+
+// Resume is a paid mutator transaction binding the contract method 0xe15ef5d5.
+//
+// Solidity: function resume(bool extend) returns()
+func (_Election *ElectionTransactorSession) Resume(extend bool) (*types.Transaction, error) {
+	return _Election.Contract.Resume(&_Election.TransactOpts, extend)
+}
+
+// SetApprovers is a paid mutator transaction binding the contract method 0x819d0297.
+//
+// Solidity: function setApprovers(bytes32[] list, uint256 threshold) returns()
+func (_Election *ElectionTransactor) SetApprovers(opts *bind.TransactOpts, list [][32]byte, threshold *big.Int) (*types.Transaction, error) {
+	return _Election.contract.Transact(opts, "setApprovers", list, threshold)
+}
+
+// SetApprovers is a paid mutator transaction binding the contract method 0x819d0297.
+//
+// Solidity: function setApprovers(bytes32[] list, uint256 threshold) returns()
+func (_Election *ElectionSession) SetApprovers(list [][32]byte, threshold *big.Int) (*types.Transaction, error) {
+	return _Election.Contract.SetApprovers(&_Election.TransactOpts, list, threshold)
+}
+
+// SetApprovers is a paid mutator transaction binding the contract method 0x819d0297.
+//
+// Solidity: function setApprovers(bytes32[] list, uint256 threshold) returns()
+func (_Election *ElectionTransactorSession) SetApprovers(list [][32]byte, threshold *big.Int) (*types.Transaction, error) {
+	return _Election.Contract.SetApprovers(&_Election.TransactOpts, list, threshold)
+}
+
+// SetSchedule is a paid mutator transaction binding the contract method 0x9869aca0.
+//
+// Solidity: function setSchedule(uint256 start, uint256 end) returns()
+func (_Election *ElectionTransactor) SetSchedule(opts *bind.TransactOpts, start *big.Int, end *big.Int) (*types.Transaction, error) {
+	return _Election.contract.Transact(opts, "setSchedule", start, end)
+}
+
+// SetSchedule is a paid mutator transaction binding the contract method 0x9869aca0.
+//
+// Solidity: function setSchedule(uint256 start, uint256 end) returns()
+func (_Election *ElectionSession) SetSchedule(start *big.Int, end *big.Int) (*types.Transaction, error) {
+	return _Election.Contract.SetSchedule(&_Election.TransactOpts, start, end)
+}
+
+// SetSchedule is a paid mutator transaction binding the contract method 0x9869aca0.
+//
+// Solidity: function setSchedule(uint256 start, uint256 end) returns()
+func (_Election *ElectionTransactorSession) SetSchedule(start *big.Int, end *big.Int) (*types.Transaction, error) {
+	return _Election.Contract.SetSchedule(&_Election.TransactOpts, start, end)
+}
+
 // Vote is a paid mutator transaction binding the contract method 0x24108475.
 //
 // Solidity: function vote(uint256 candidateID, string e) returns()
@@ -757,3 +1412,1282 @@ func (_Election *ElectionSession) Vote(candidateID *big.Int, e string) (*types.T
 func (_Election *ElectionTransactorSession) Vote(candidateID *big.Int, e string) (*types.Transaction, error) {
 	return _Election.Contract.Vote(&_Election.TransactOpts, candidateID, e)
 }
+
+// VoteBatch is a paid mutator transaction binding the contract method 0x7fb4c4c2.
+//
+// Solidity: function voteBatch(uint256[] candidateIDs, string[] emails) returns(uint256 accepted)
+func (_Election *ElectionTransactor) VoteBatch(opts *bind.TransactOpts, candidateIDs []*big.Int, emails []string) (*types.Transaction, error) {
+	return _Election.contract.Transact(opts, "voteBatch", candidateIDs, emails)
+}
+
+// VoteBatch is a paid mutator transaction binding the contract method 0x7fb4c4c2.
+//
+// Solidity: function voteBatch(uint256[] candidateIDs, string[] emails) returns(uint256 accepted)
+func (_Election *ElectionSession) VoteBatch(candidateIDs []*big.Int, emails []string) (*types.Transaction, error) {
+	return _Election.Contract.VoteBatch(&_Election.TransactOpts, candidateIDs, emails)
+}
+
+// VoteBatch is a paid mutator transaction binding the contract method 0x7fb4c4c2.
+//
+// Solidity: function voteBatch(uint256[] candidateIDs, string[] emails) returns(uint256 accepted)
+func (_Election *ElectionTransactorSession) VoteBatch(candidateIDs []*big.Int, emails []string) (*types.Transaction, error) {
+	return _Election.Contract.VoteBatch(&_Election.TransactOpts, candidateIDs, emails)
+}
+
+// ElectionActionApprovedIterator is returned from FilterActionApproved and is used to iterate over the raw logs and unpacked data for ActionApproved events raised by the Election contract.
+type ElectionActionApprovedIterator struct {
+	Event *ElectionActionApproved // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ElectionActionApprovedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ElectionActionApproved)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ElectionActionApproved)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ElectionActionApprovedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ElectionActionApprovedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ElectionActionApproved represents a ActionApproved event raised by the Election contract.
+type ElectionActionApproved struct {
+	Action    [32]byte
+	Approver  [32]byte
+	Approvals *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterActionApproved is a free log retrieval operation binding the contract event 0x4ede1c61bc0cef1132846f085639c84569ce72445812e6e0a44d3946f88a18b4.
+//
+// Solidity: event ActionApproved(bytes32 indexed action, bytes32 indexed approver, uint256 approvals)
+func (_Election *ElectionFilterer) FilterActionApproved(opts *bind.FilterOpts, action [][32]byte, approver [][32]byte) (*ElectionActionApprovedIterator, error) {
+
+	var actionRule []interface{}
+	for _, actionItem := range action {
+		actionRule = append(actionRule, actionItem)
+	}
+	var approverRule []interface{}
+	for _, approverItem := range approver {
+		approverRule = append(approverRule, approverItem)
+	}
+
+	logs, sub, err := _Election.contract.FilterLogs(opts, "ActionApproved", actionRule, approverRule)
+	if err != nil {
+		return nil, err
+	}
+	return &ElectionActionApprovedIterator{contract: _Election.contract, event: "ActionApproved", logs: logs, sub: sub}, nil
+}
+
+// WatchActionApproved is a free log subscription operation binding the contract event 0x4ede1c61bc0cef1132846f085639c84569ce72445812e6e0a44d3946f88a18b4.
+//
+// Solidity: event ActionApproved(bytes32 indexed action, bytes32 indexed approver, uint256 approvals)
+func (_Election *ElectionFilterer) WatchActionApproved(opts *bind.WatchOpts, sink chan<- *ElectionActionApproved, action [][32]byte, approver [][32]byte) (event.Subscription, error) {
+
+	var actionRule []interface{}
+	for _, actionItem := range action {
+		actionRule = append(actionRule, actionItem)
+	}
+	var approverRule []interface{}
+	for _, approverItem := range approver {
+		approverRule = append(approverRule, approverItem)
+	}
+
+	logs, sub, err := _Election.contract.WatchLogs(opts, "ActionApproved", actionRule, approverRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ElectionActionApproved)
+				if err := _Election.contract.UnpackLog(event, "ActionApproved", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseActionApproved is a log parse operation binding the contract event 0x4ede1c61bc0cef1132846f085639c84569ce72445812e6e0a44d3946f88a18b4.
+//
+// Solidity: event ActionApproved(bytes32 indexed action, bytes32 indexed approver, uint256 approvals)
+func (_Election *ElectionFilterer) ParseActionApproved(log types.Log) (*ElectionActionApproved, error) {
+	event := new(ElectionActionApproved)
+	if err := _Election.contract.UnpackLog(event, "ActionApproved", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// ElectionApproversSetIterator is returned from FilterApproversSet and is used to iterate over the raw logs and unpacked data for ApproversSet events raised by the Election contract.
+type ElectionApproversSetIterator struct {
+	Event *ElectionApproversSet // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ElectionApproversSetIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ElectionApproversSet)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ElectionApproversSet)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ElectionApproversSetIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ElectionApproversSetIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ElectionApproversSet represents a ApproversSet event raised by the Election contract.
+type ElectionApproversSet struct {
+	Approvers [][32]byte
+	Threshold *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterApproversSet is a free log retrieval operation binding the contract event 0x681084b7bd331d1786b94cd347fd4045485cebef99891447c87fbd4d23b86f3d.
+//
+// Solidity: event ApproversSet(bytes32[] approvers, uint256 threshold)
+func (_Election *ElectionFilterer) FilterApproversSet(opts *bind.FilterOpts) (*ElectionApproversSetIterator, error) {
+
+	logs, sub, err := _Election.contract.FilterLogs(opts, "ApproversSet")
+	if err != nil {
+		return nil, err
+	}
+	return &ElectionApproversSetIterator{contract: _Election.contract, event: "ApproversSet", logs: logs, sub: sub}, nil
+}
+
+// WatchApproversSet is a free log subscription operation binding the contract event 0x681084b7bd331d1786b94cd347fd4045485cebef99891447c87fbd4d23b86f3d.
+//
+// Solidity: event ApproversSet(bytes32[] approvers, uint256 threshold)
+func (_Election *ElectionFilterer) WatchApproversSet(opts *bind.WatchOpts, sink chan<- *ElectionApproversSet) (event.Subscription, error) {
+
+	logs, sub, err := _Election.contract.WatchLogs(opts, "ApproversSet")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ElectionApproversSet)
+				if err := _Election.contract.UnpackLog(event, "ApproversSet", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseApproversSet is a log parse operation binding the contract event 0x681084b7bd331d1786b94cd347fd4045485cebef99891447c87fbd4d23b86f3d.
+//
+// Solidity: event ApproversSet(bytes32[] approvers, uint256 threshold)
+func (_Election *ElectionFilterer) ParseApproversSet(log types.Log) (*ElectionApproversSet, error) {
+	event := new(ElectionApproversSet)
+	if err := _Election.contract.UnpackLog(event, "ApproversSet", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// ElectionBallotRejectedIterator is returned from FilterBallotRejected and is used to iterate over the raw logs and unpacked data for BallotRejected events raised by the Election contract.
+type ElectionBallotRejectedIterator struct {
+	Event *ElectionBallotRejected // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ElectionBallotRejectedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ElectionBallotRejected)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ElectionBallotRejected)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ElectionBallotRejectedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ElectionBallotRejectedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ElectionBallotRejected represents a BallotRejected event raised by the Election contract.
+type ElectionBallotRejected struct {
+	Voter       string
+	CandidateID *big.Int
+	Reason      string
+	Raw         types.Log // Blockchain specific contextual infos
+}
+
+// FilterBallotRejected is a free log retrieval operation binding the contract event 0x7df1303a567716bd50bac12600fe8ef9931c9a1b1a911f1a65af20b3f9e8ac82.
+//
+// Solidity: event BallotRejected(string voter, uint256 candidateID, string reason)
+func (_Election *ElectionFilterer) FilterBallotRejected(opts *bind.FilterOpts) (*ElectionBallotRejectedIterator, error) {
+
+	logs, sub, err := _Election.contract.FilterLogs(opts, "BallotRejected")
+	if err != nil {
+		return nil, err
+	}
+	return &ElectionBallotRejectedIterator{contract: _Election.contract, event: "BallotRejected", logs: logs, sub: sub}, nil
+}
+
+// WatchBallotRejected is a free log subscription operation binding the contract event 0x7df1303a567716bd50bac12600fe8ef9931c9a1b1a911f1a65af20b3f9e8ac82.
+//
+// Solidity: event BallotRejected(string voter, uint256 candidateID, string reason)
+func (_Election *ElectionFilterer) WatchBallotRejected(opts *bind.WatchOpts, sink chan<- *ElectionBallotRejected) (event.Subscription, error) {
+
+	logs, sub, err := _Election.contract.WatchLogs(opts, "BallotRejected")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ElectionBallotRejected)
+				if err := _Election.contract.UnpackLog(event, "BallotRejected", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseBallotRejected is a log parse operation binding the contract event 0x7df1303a567716bd50bac12600fe8ef9931c9a1b1a911f1a65af20b3f9e8ac82.
+//
+// Solidity: event BallotRejected(string voter, uint256 candidateID, string reason)
+func (_Election *ElectionFilterer) ParseBallotRejected(log types.Log) (*ElectionBallotRejected, error) {
+	event := new(ElectionBallotRejected)
+	if err := _Election.contract.UnpackLog(event, "BallotRejected", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// ElectionCandidateAddedIterator is returned from FilterCandidateAdded and is used to iterate over the raw logs and unpacked data for CandidateAdded events raised by the Election contract.
+type ElectionCandidateAddedIterator struct {
+	Event *ElectionCandidateAdded // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ElectionCandidateAddedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ElectionCandidateAdded)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ElectionCandidateAdded)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ElectionCandidateAddedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ElectionCandidateAddedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ElectionCandidateAdded represents a CandidateAdded event raised by the Election contract.
+type ElectionCandidateAdded struct {
+	CandidateID   *big.Int
+	CandidateName string
+	Email         string
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterCandidateAdded is a free log retrieval operation binding the contract event 0xe64f834082661dd9ed89427b87edb6270fd6dad17198b0938f0edd058914c87f.
+//
+// Solidity: event CandidateAdded(uint256 indexed candidateID, string candidate_name, string email)
+func (_Election *ElectionFilterer) FilterCandidateAdded(opts *bind.FilterOpts, candidateID []*big.Int) (*ElectionCandidateAddedIterator, error) {
+
+	var candidateIDRule []interface{}
+	for _, candidateIDItem := range candidateID {
+		candidateIDRule = append(candidateIDRule, candidateIDItem)
+	}
+
+	logs, sub, err := _Election.contract.FilterLogs(opts, "CandidateAdded", candidateIDRule)
+	if err != nil {
+		return nil, err
+	}
+	return &ElectionCandidateAddedIterator{contract: _Election.contract, event: "CandidateAdded", logs: logs, sub: sub}, nil
+}
+
+// WatchCandidateAdded is a free log subscription operation binding the contract event 0xe64f834082661dd9ed89427b87edb6270fd6dad17198b0938f0edd058914c87f.
+//
+// Solidity: event CandidateAdded(uint256 indexed candidateID, string candidate_name, string email)
+func (_Election *ElectionFilterer) WatchCandidateAdded(opts *bind.WatchOpts, sink chan<- *ElectionCandidateAdded, candidateID []*big.Int) (event.Subscription, error) {
+
+	var candidateIDRule []interface{}
+	for _, candidateIDItem := range candidateID {
+		candidateIDRule = append(candidateIDRule, candidateIDItem)
+	}
+
+	logs, sub, err := _Election.contract.WatchLogs(opts, "CandidateAdded", candidateIDRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ElectionCandidateAdded)
+				if err := _Election.contract.UnpackLog(event, "CandidateAdded", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseCandidateAdded is a log parse operation binding the contract event 0xe64f834082661dd9ed89427b87edb6270fd6dad17198b0938f0edd058914c87f.
+//
+// Solidity: event CandidateAdded(uint256 indexed candidateID, string candidate_name, string email)
+func (_Election *ElectionFilterer) ParseCandidateAdded(log types.Log) (*ElectionCandidateAdded, error) {
+	event := new(ElectionCandidateAdded)
+	if err := _Election.contract.UnpackLog(event, "CandidateAdded", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// ElectionElectionClosedIterator is returned from FilterElectionClosed and is used to iterate over the raw logs and unpacked data for ElectionClosed events raised by the Election contract.
+type ElectionElectionClosedIterator struct {
+	Event *ElectionElectionClosed // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ElectionElectionClosedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ElectionElectionClosed)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ElectionElectionClosed)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ElectionElectionClosedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ElectionElectionClosedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ElectionElectionClosed represents a ElectionClosed event raised by the Election contract.
+type ElectionElectionClosed struct {
+	ClosedAt    *big.Int
+	TotalVoters *big.Int
+	Raw         types.Log // Blockchain specific contextual infos
+}
+
+// FilterElectionClosed is a free log retrieval operation binding the contract event 0xd3461d57d954b89a789e6fbd29ff23c87fef073eae45cc372d7e26cb14594353.
+//
+// Solidity: event ElectionClosed(uint256 closedAt, uint256 totalVoters)
+func (_Election *ElectionFilterer) FilterElectionClosed(opts *bind.FilterOpts) (*ElectionElectionClosedIterator, error) {
+
+	logs, sub, err := _Election.contract.FilterLogs(opts, "ElectionClosed")
+	if err != nil {
+		return nil, err
+	}
+	return &ElectionElectionClosedIterator{contract: _Election.contract, event: "ElectionClosed", logs: logs, sub: sub}, nil
+}
+
+// WatchElectionClosed is a free log subscription operation binding the contract event 0xd3461d57d954b89a789e6fbd29ff23c87fef073eae45cc372d7e26cb14594353.
+//
+// Solidity: event ElectionClosed(uint256 closedAt, uint256 totalVoters)
+func (_Election *ElectionFilterer) WatchElectionClosed(opts *bind.WatchOpts, sink chan<- *ElectionElectionClosed) (event.Subscription, error) {
+
+	logs, sub, err := _Election.contract.WatchLogs(opts, "ElectionClosed")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ElectionElectionClosed)
+				if err := _Election.contract.UnpackLog(event, "ElectionClosed", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseElectionClosed is a log parse operation binding the contract event 0xd3461d57d954b89a789e6fbd29ff23c87fef073eae45cc372d7e26cb14594353.
+//
+// Solidity: event ElectionClosed(uint256 closedAt, uint256 totalVoters)
+func (_Election *ElectionFilterer) ParseElectionClosed(log types.Log) (*ElectionElectionClosed, error) {
+	event := new(ElectionElectionClosed)
+	if err := _Election.contract.UnpackLog(event, "ElectionClosed", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// ElectionElectionPausedIterator is returned from FilterElectionPaused and is used to iterate over the raw logs and unpacked data for ElectionPaused events raised by the Election contract.
+type ElectionElectionPausedIterator struct {
+	Event *ElectionElectionPaused // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ElectionElectionPausedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ElectionElectionPaused)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ElectionElectionPaused)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ElectionElectionPausedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ElectionElectionPausedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ElectionElectionPaused represents a ElectionPaused event raised by the Election contract.
+type ElectionElectionPaused struct {
+	PausedAt *big.Int
+	Reason   string
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterElectionPaused is a free log retrieval operation binding the contract event 0x6439f95181932fe19c4a4871f266184c145461c7a05604ddd8259256681d2f88.
+//
+// Solidity: event ElectionPaused(uint256 pausedAt, string reason)
+func (_Election *ElectionFilterer) FilterElectionPaused(opts *bind.FilterOpts) (*ElectionElectionPausedIterator, error) {
+
+	logs, sub, err := _Election.contract.FilterLogs(opts, "ElectionPaused")
+	if err != nil {
+		return nil, err
+	}
+	return &ElectionElectionPausedIterator{contract: _Election.contract, event: "ElectionPaused", logs: logs, sub: sub}, nil
+}
+
+// WatchElectionPaused is a free log subscription operation binding the contract event 0x6439f95181932fe19c4a4871f266184c145461c7a05604ddd8259256681d2f88.
+//
+// Solidity: event ElectionPaused(uint256 pausedAt, string reason)
+func (_Election *ElectionFilterer) WatchElectionPaused(opts *bind.WatchOpts, sink chan<- *ElectionElectionPaused) (event.Subscription, error) {
+
+	logs, sub, err := _Election.contract.WatchLogs(opts, "ElectionPaused")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ElectionElectionPaused)
+				if err := _Election.contract.UnpackLog(event, "ElectionPaused", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseElectionPaused is a log parse operation binding the contract event 0x6439f95181932fe19c4a4871f266184c145461c7a05604ddd8259256681d2f88.
+//
+// Solidity: event ElectionPaused(uint256 pausedAt, string reason)
+func (_Election *ElectionFilterer) ParseElectionPaused(log types.Log) (*ElectionElectionPaused, error) {
+	event := new(ElectionElectionPaused)
+	if err := _Election.contract.UnpackLog(event, "ElectionPaused", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// ElectionElectionResumedIterator is returned from FilterElectionResumed and is used to iterate over the raw logs and unpacked data for ElectionResumed events raised by the Election contract.
+type ElectionElectionResumedIterator struct {
+	Event *ElectionElectionResumed // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ElectionElectionResumedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ElectionElectionResumed)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ElectionElectionResumed)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ElectionElectionResumedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ElectionElectionResumedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ElectionElectionResumed represents a ElectionResumed event raised by the Election contract.
+type ElectionElectionResumed struct {
+	ResumedAt *big.Int
+	PausedFor *big.Int
+	EndTime   *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterElectionResumed is a free log retrieval operation binding the contract event 0x46c0215a8a63754bf37d8dddd302bf1a7ec6b540be934338c9bb7a5e967c91c3.
+//
+// Solidity: event ElectionResumed(uint256 resumedAt, uint256 pausedFor, uint256 endTime)
+func (_Election *ElectionFilterer) FilterElectionResumed(opts *bind.FilterOpts) (*ElectionElectionResumedIterator, error) {
+
+	logs, sub, err := _Election.contract.FilterLogs(opts, "ElectionResumed")
+	if err != nil {
+		return nil, err
+	}
+	return &ElectionElectionResumedIterator{contract: _Election.contract, event: "ElectionResumed", logs: logs, sub: sub}, nil
+}
+
+// WatchElectionResumed is a free log subscription operation binding the contract event 0x46c0215a8a63754bf37d8dddd302bf1a7ec6b540be934338c9bb7a5e967c91c3.
+//
+// Solidity: event ElectionResumed(uint256 resumedAt, uint256 pausedFor, uint256 endTime)
+func (_Election *ElectionFilterer) WatchElectionResumed(opts *bind.WatchOpts, sink chan<- *ElectionElectionResumed) (event.Subscription, error) {
+
+	logs, sub, err := _Election.contract.WatchLogs(opts, "ElectionResumed")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ElectionElectionResumed)
+				if err := _Election.contract.UnpackLog(event, "ElectionResumed", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseElectionResumed is a log parse operation binding the contract event 0x46c0215a8a63754bf37d8dddd302bf1a7ec6b540be934338c9bb7a5e967c91c3.
+//
+// Solidity: event ElectionResumed(uint256 resumedAt, uint256 pausedFor, uint256 endTime)
+func (_Election *ElectionFilterer) ParseElectionResumed(log types.Log) (*ElectionElectionResumed, error) {
+	event := new(ElectionElectionResumed)
+	if err := _Election.contract.UnpackLog(event, "ElectionResumed", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// ElectionScheduleUpdatedIterator is returned from FilterScheduleUpdated and is used to iterate over the raw logs and unpacked data for ScheduleUpdated events raised by the Election contract.
+type ElectionScheduleUpdatedIterator struct {
+	Event *ElectionScheduleUpdated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ElectionScheduleUpdatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ElectionScheduleUpdated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ElectionScheduleUpdated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ElectionScheduleUpdatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ElectionScheduleUpdatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ElectionScheduleUpdated represents a ScheduleUpdated event raised by the Election contract.
+type ElectionScheduleUpdated struct {
+	StartTime *big.Int
+	EndTime   *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterScheduleUpdated is a free log retrieval operation binding the contract event 0x644a9f205d77531ec9577a8e9e61b6f8737b60425f866c6d371472dd26842759.
+//
+// Solidity: event ScheduleUpdated(uint256 startTime, uint256 endTime)
+func (_Election *ElectionFilterer) FilterScheduleUpdated(opts *bind.FilterOpts) (*ElectionScheduleUpdatedIterator, error) {
+
+	logs, sub, err := _Election.contract.FilterLogs(opts, "ScheduleUpdated")
+	if err != nil {
+		return nil, err
+	}
+	return &ElectionScheduleUpdatedIterator{contract: _Election.contract, event: "ScheduleUpdated", logs: logs, sub: sub}, nil
+}
+
+// WatchScheduleUpdated is a free log subscription operation binding the contract event 0x644a9f205d77531ec9577a8e9e61b6f8737b60425f866c6d371472dd26842759.
+//
+// Solidity: event ScheduleUpdated(uint256 startTime, uint256 endTime)
+func (_Election *ElectionFilterer) WatchScheduleUpdated(opts *bind.WatchOpts, sink chan<- *ElectionScheduleUpdated) (event.Subscription, error) {
+
+	logs, sub, err := _Election.contract.WatchLogs(opts, "ScheduleUpdated")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ElectionScheduleUpdated)
+				if err := _Election.contract.UnpackLog(event, "ScheduleUpdated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseScheduleUpdated is a log parse operation binding the contract event 0x644a9f205d77531ec9577a8e9e61b6f8737b60425f866c6d371472dd26842759.
+//
+// Solidity: event ScheduleUpdated(uint256 startTime, uint256 endTime)
+func (_Election *ElectionFilterer) ParseScheduleUpdated(log types.Log) (*ElectionScheduleUpdated, error) {
+	event := new(ElectionScheduleUpdated)
+	if err := _Election.contract.UnpackLog(event, "ScheduleUpdated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// ElectionVoteCastIterator is returned from FilterVoteCast and is used to iterate over the raw logs and unpacked data for VoteCast events raised by the Election contract.
+type ElectionVoteCastIterator struct {
+	Event *ElectionVoteCast // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ElectionVoteCastIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ElectionVoteCast)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ElectionVoteCast)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ElectionVoteCastIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ElectionVoteCastIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ElectionVoteCast represents a VoteCast event raised by the Election contract.
+type ElectionVoteCast struct {
+	CandidateID *big.Int
+	Voter       string
+	TotalVoters *big.Int
+	Raw         types.Log // Blockchain specific contextual infos
+}
+
+// FilterVoteCast is a free log retrieval operation binding the contract event 0x7443492b9be7d0c78915e97bcabcb827676bf0292f1b78d919b33ca1a881a66b.
+//
+// Solidity: event VoteCast(uint256 indexed candidateID, string voter, uint256 totalVoters)
+func (_Election *ElectionFilterer) FilterVoteCast(opts *bind.FilterOpts, candidateID []*big.Int) (*ElectionVoteCastIterator, error) {
+
+	var candidateIDRule []interface{}
+	for _, candidateIDItem := range candidateID {
+		candidateIDRule = append(candidateIDRule, candidateIDItem)
+	}
+
+	logs, sub, err := _Election.contract.FilterLogs(opts, "VoteCast", candidateIDRule)
+	if err != nil {
+		return nil, err
+	}
+	return &ElectionVoteCastIterator{contract: _Election.contract, event: "VoteCast", logs: logs, sub: sub}, nil
+}
+
+// WatchVoteCast is a free log subscription operation binding the contract event 0x7443492b9be7d0c78915e97bcabcb827676bf0292f1b78d919b33ca1a881a66b.
+//
+// Solidity: event VoteCast(uint256 indexed candidateID, string voter, uint256 totalVoters)
+func (_Election *ElectionFilterer) WatchVoteCast(opts *bind.WatchOpts, sink chan<- *ElectionVoteCast, candidateID []*big.Int) (event.Subscription, error) {
+
+	var candidateIDRule []interface{}
+	for _, candidateIDItem := range candidateID {
+		candidateIDRule = append(candidateIDRule, candidateIDItem)
+	}
+
+	logs, sub, err := _Election.contract.WatchLogs(opts, "VoteCast", candidateIDRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ElectionVoteCast)
+				if err := _Election.contract.UnpackLog(event, "VoteCast", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseVoteCast is a log parse operation binding the contract event 0x7443492b9be7d0c78915e97bcabcb827676bf0292f1b78d919b33ca1a881a66b.
+//
+// Solidity: event VoteCast(uint256 indexed candidateID, string voter, uint256 totalVoters)
+func (_Election *ElectionFilterer) ParseVoteCast(log types.Log) (*ElectionVoteCast, error) {
+	event := new(ElectionVoteCast)
+	if err := _Election.contract.UnpackLog(event, "VoteCast", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
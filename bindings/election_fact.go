@@ -31,15 +31,18 @@ var (
 
 // ElectionFactElectionDet is an auto generated low-level Go binding around an user-defined struct.
 type ElectionFactElectionDet struct {
+	Id              *big.Int
 	DeployedAddress common.Address
+	CompanyId       [32]byte
 	ElN             string
 	ElD             string
+	CreatedAt       *big.Int
 }
 
 // ElectionFactMetaData contains all meta data concerning the ElectionFact contract.
 var ElectionFactMetaData = &bind.MetaData{
-	ABI: "[{\"inputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"companyEmail\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"deployedAddress\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"el_n\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"el_d\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"email\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"election_name\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"election_description\",\"type\":\"string\"}],\"name\":\"createElection\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"email\",\"type\":\"string\"}],\"name\":\"getDeployedElections\",\"outputs\":[{\"components\":[{\"internalType\":\"address\",\"name\":\"deployedAddress\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"el_n\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"el_d\",\"type\":\"string\"}],\"internalType\":\"structElectionFact.ElectionDet[]\",\"name\":\"\",\"type\":\"tuple[]\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
-	Bin: "0x608060405234801561001057600080fd5b50611f20806100206000396000f3fe60806040523480156200001157600080fd5b5060043610620000465760003560e01c8063381dde0d146200004b5780634a6cf3a9146200007a578063c55bdbe41462000093575b600080fd5b620000626200005c366004620005a3565b620000bb565b60405162000071919062000638565b60405180910390f35b620000916200008b366004620006da565b62000286565b005b620000aa620000a43660046200076c565b6200036c565b6040516200007193929190620007b5565b6060600082604051620000cf9190620007f9565b9081526020016040518091039020805480602002602001604051908101604052809291908181526020016000905b828210156200027b57600084815260209081902060408051606081019091526003850290910180546001600160a01b031682526001810180549293919291840191620001499062000817565b80601f0160208091040260200160405190810160405280929190818152602001828054620001779062000817565b8015620001c85780601f106200019c57610100808354040283529160200191620001c8565b820191906000526020600020905b815481529060010190602001808311620001aa57829003601f168201915b50505050508152602001600282018054620001e39062000817565b80601f0160208091040260200160405190810160405280929190818152602001828054620002119062000817565b8015620002625780601f10620002365761010080835404028352916020019162000262565b820191906000526020600020905b8154815290600101906020018083116200024457829003601f168201915b50505050508152505081526020019060010190620000fd565b505050509050919050565b60003383836040516200029990620004ea565b620002a793929190620007b5565b604051809103906000f080158015620002c4573d6000803e3d6000fd5b509050600084604051620002d99190620007f9565b90815260408051602092819003830181206060820183526001600160a01b038581168352848301888152938301879052815460018082018455600093845295909220835160039093020180546001600160a01b03191692909116919091178155915190928201906200034c9082620008a6565b5060408201516002820190620003639082620008a6565b50505050505050565b815160208184018101805160008252928201918501919091209190528054829081106200039857600080fd5b6000918252602090912060039091020180546001820180546001600160a01b039092169450919250620003cb9062000817565b80601f0160208091040260200160405190810160405280929190818152602001828054620003f99062000817565b80156200044a5780601f106200041e576101008083540402835291602001916200044a565b820191906000526020600020905b8154815290600101906020018083116200042c57829003601f168201915b505050505090806002018054620004619062000817565b80601f01602080910402602001604051908101604052809291908181526020018280546200048f9062000817565b8015620004e05780601f10620004b457610100808354040283529160200191620004e0565b820191906000526020600020905b815481529060010190602001808311620004c257829003601f168201915b5050505050905083565b611577806200097483390190565b634e487b7160e01b600052604160045260246000fd5b600082601f8301126200052057600080fd5b813567ffffffffffffffff808211156200053e576200053e620004f8565b604051601f8301601f19908116603f01168101908282118183101715620005695762000569620004f8565b816040528381528660208588010111156200058357600080fd5b836020870160208301376000602085830101528094505050505092915050565b600060208284031215620005b657600080fd5b813567ffffffffffffffff811115620005ce57600080fd5b620005dc848285016200050e565b949350505050565b60005b8381101562000601578181015183820152602001620005e7565b50506000910152565b6000815180845262000624816020860160208601620005e4565b601f01601f19169290920160200192915050565b60006020808301818452808551808352604092508286019150828160051b87010184880160005b83811015620006cc57888303603f19018552815180516001600160a01b03168452878101516060898601819052906200069b828701826200060a565b91505087820151915084810388860152620006b781836200060a565b9689019694505050908601906001016200065f565b509098975050505050505050565b600080600060608486031215620006f057600080fd5b833567ffffffffffffffff808211156200070957600080fd5b62000717878388016200050e565b945060208601359150808211156200072e57600080fd5b6200073c878388016200050e565b935060408601359150808211156200075357600080fd5b5062000762868287016200050e565b9150509250925092565b600080604083850312156200078057600080fd5b823567ffffffffffffffff8111156200079857600080fd5b620007a6858286016200050e565b95602094909401359450505050565b6001600160a01b0384168152606060208201819052600090620007db908301856200060a565b8281036040840152620007ef81856200060a565b9695505050505050565b600082516200080d818460208701620005e4565b9190910192915050565b600181811c908216806200082c57607f821691505b6020821081036200084d57634e487b7160e01b600052602260045260246000fd5b50919050565b601f821115620008a157600081815260208120601f850160051c810160208610156200087c5750805b601f850160051c820191505b818110156200089d5782815560010162000888565b5050505b505050565b815167ffffffffffffffff811115620008c357620008c3620004f8565b620008db81620008d4845462000817565b8462000853565b602080601f831160018114620009135760008415620008fa5750858301515b600019600386901b1c1916600185901b1785556200089d565b600085815260208120601f198616915b82811015620009445788860151825594840194600190910190840162000923565b5085821015620009635787850151600019600388901b60f8161c191681555b5050505050600190811b0190555056fe60806040523480156200001157600080fd5b506040516200157738038062001577833981016040819052620000349162000149565b600080546001600160a01b0319166001600160a01b03851617905560016200005d838262000262565b5060026200006c828262000262565b50506003805460ff19166001179055506200032e9050565b634e487b7160e01b600052604160045260246000fd5b600082601f830112620000ac57600080fd5b81516001600160401b0380821115620000c957620000c962000084565b604051601f8301601f19908116603f01168101908282118183101715620000f457620000f462000084565b816040528381526020925086838588010111156200011157600080fd5b600091505b8382101562000135578582018301518183018401529082019062000116565b600093810190920192909252949350505050565b6000806000606084860312156200015f57600080fd5b83516001600160a01b03811681146200017757600080fd5b60208501519093506001600160401b03808211156200019557600080fd5b620001a3878388016200009a565b93506040860151915080821115620001ba57600080fd5b50620001c9868287016200009a565b9150509250925092565b600181811c90821680620001e857607f821691505b6020821081036200020957634e487b7160e01b600052602260045260246000fd5b50919050565b601f8211156200025d57600081815260208120601f850160051c81016020861015620002385750805b601f850160051c820191505b81811015620002595782815560010162000244565b5050505b505050565b81516001600160401b038111156200027e576200027e62000084565b62000296816200028f8454620001d3565b846200020f565b602080601f831160018114620002ce5760008415620002b55750858301515b600019600386901b1c1916600185901b17855562000259565b600085815260208120601f198616915b82811015620002ff57888601518255948401946001909101908401620002de565b50858210156200031e5787850151600019600388901b60f8161c191681555b5050505050600190811b01905550565b611239806200033e6000396000f3fe608060405234801561001057600080fd5b506004361061010b5760003560e01c80635216509a116100a257806382e15fcd1161007157806382e15fcd14610242578063a15148d11461026d578063e8685ba114610275578063ed35a5da1461027d578063ed836bc31461028557600080fd5b80635216509a146101d457806353fa2e64146101dd57806365fc783c1461022757806376874a7d1461022f57600080fd5b806335b8e820116100de57806335b8e8201461018457806339bfeae31461019757806342b03cc9146101aa5780634cbe32b8146101bd57600080fd5b8063044d5a9714610110578063200d2ed21461012e578063241084751461014b5780633477ee2e14610160575b600080fd5b61011861029b565b6040516101259190610db4565b60405180910390f35b60035461013b9060ff1681565b6040519015158152602001610125565b61015e610159366004610e71565b610329565b005b61017361016e366004610eb8565b6104a9565b604051610125959493929190610ed1565b610173610192366004610eb8565b6106f7565b61013b6101a5366004610f30565b6109e5565b61015e6101b8366004610f6d565b610a13565b6101c660075481565b604051908152602001610125565b6101c660065481565b6102126101eb366004610f30565b80516020818301810180516005825292820191909301209152805460019091015460ff1682565b60408051928352901515602083015201610125565b6007546101c6565b61021261023d366004610f30565b610aed565b600054610255906001600160a01b031681565b6040516001600160a01b039091168152602001610125565b6101c6610b38565b6006546101c6565b610118610c30565b61028d610c3d565b60405161012592919061101a565b600280546102a890611048565b80601f01602080910402602001604051908101604052809291908181526020018280546102d490611048565b80156103215780601f106102f657610100808354040283529160200191610321565b820191906000526020600020905b81548152906001019060200180831161030457829003601f168201915b505050505081565b6000546001600160a01b0316331461035c5760405162461bcd60e51b815260040161035390611082565b60405180910390fd5b60058160405161036c91906110b1565b9081526040519081900360200190206001015460ff16156103cf5760405162461bcd60e51b815260206004820152601d60248201527f4572726f723a20596f752063616e6e6f7420646f75626c6520766f74650000006044820152606401610353565b60065482106104205760405162461bcd60e51b815260206004820152601b60248201527f4572726f723a20496e76616c69642063616e64696461746520494400000000006044820152606401610353565b6040805180820182528381526001602082015290516005906104439084906110b1565b90815260405160209181900382019020825181559101516001909101805460ff19169115159190911790556007805490600061047e836110cd565b909155505060008281526004602052604081206003018054916104a0836110cd565b91905055505050565b6004602052600090815260409020805481906104c490611048565b80601f01602080910402602001604051908101604052809291908181526020018280546104f090611048565b801561053d5780601f106105125761010080835404028352916020019161053d565b820191906000526020600020905b81548152906001019060200180831161052057829003601f168201915b50505050509080600101805461055290611048565b80601f016020809104026020016040519081016040528092919081815260200182805461057e90611048565b80156105cb5780601f106105a0576101008083540402835291602001916105cb565b820191906000526020600020905b8154815290600101906020018083116105ae57829003601f168201915b5050505050908060020180546105e090611048565b80601f016020809104026020016040519081016040528092919081815260200182805461060c90611048565b80156106595780601f1061062e57610100808354040283529160200191610659565b820191906000526020600020905b81548152906001019060200180831161063c57829003601f168201915b50505050509080600301549080600401805461067490611048565b80601f01602080910402602001604051908101604052809291908181526020018280546106a090611048565b80156106ed5780601f106106c2576101008083540402835291602001916106ed565b820191906000526020600020905b8154815290600101906020018083116106d057829003601f168201915b5050505050905085565b60608060606000606060065486106107515760405162461bcd60e51b815260206004820152601b60248201527f4572726f723a20496e76616c69642063616e64696461746520494400000000006044820152606401610353565b600086815260046020526040808220815160a0810190925280548290829061077890611048565b80601f01602080910402602001604051908101604052809291908181526020018280546107a490611048565b80156107f15780601f106107c6576101008083540402835291602001916107f1565b820191906000526020600020905b8154815290600101906020018083116107d457829003601f168201915b5050505050815260200160018201805461080a90611048565b80601f016020809104026020016040519081016040528092919081815260200182805461083690611048565b80156108835780601f1061085857610100808354040283529160200191610883565b820191906000526020600020905b81548152906001019060200180831161086657829003601f168201915b5050505050815260200160028201805461089c90611048565b80601f01602080910402602001604051908101604052809291908181526020018280546108c890611048565b80156109155780601f106108ea57610100808354040283529160200191610915565b820191906000526020600020905b8154815290600101906020018083116108f857829003601f168201915b505050505081526020016003820154815260200160048201805461093890611048565b80601f016020809104026020016040519081016040528092919081815260200182805461096490611048565b80156109b15780601f10610986576101008083540402835291602001916109b1565b820191906000526020600020905b81548152906001019060200180831161099457829003601f168201915b5050509190925250508151602083015160408401516060850151608090950151929c919b5099509297509550909350505050565b60006005826040516109f791906110b1565b9081526040519081900360200190206001015460ff1692915050565b6000546001600160a01b03163314610a3d5760405162461bcd60e51b815260040161035390611082565b6006546040805160a08101825286815260208082018790528183018690526000606083018190526080830186905284815260049091529190912081518190610a859082611143565b5060208201516001820190610a9a9082611143565b5060408201516002820190610aaf9082611143565b506060820151600382015560808201516004820190610ace9082611143565b50506006805491506000610ae1836110cd565b91905055505050505050565b6000806000600584604051610b0291906110b1565b9081526040805160209281900383018120818301909252815480825260019092015460ff16151592018290529590945092505050565b600080546001600160a01b03163314610b635760405162461bcd60e51b815260040161035390611082565b600060065411610bac5760405162461bcd60e51b81526020600482015260146024820152734572726f723a204e6f2063616e6469646174657360601b6044820152606401610353565b600080805260046020527f17ef568e3e12ab5b9c7254a8d58478811de00f9e6eb34345acd53bf8fd09d3ef549060015b600654811015610c2957600081815260046020526040902060030154831015610c175760008181526004602052604090206003015492509050805b80610c21816110cd565b915050610bdc565b5091505090565b600180546102a890611048565b60608060016002818054610c5090611048565b80601f0160208091040260200160405190810160405280929190818152602001828054610c7c90611048565b8015610cc95780601f10610c9e57610100808354040283529160200191610cc9565b820191906000526020600020905b815481529060010190602001808311610cac57829003601f168201915b50505050509150808054610cdc90611048565b80601f0160208091040260200160405190810160405280929190818152602001828054610d0890611048565b8015610d555780601f10610d2a57610100808354040283529160200191610d55565b820191906000526020600020905b815481529060010190602001808311610d3857829003601f168201915b50505050509050915091509091565b60005b83811015610d7f578181015183820152602001610d67565b50506000910152565b60008151808452610da0816020860160208601610d64565b601f01601f19169290920160200192915050565b602081526000610dc76020830184610d88565b9392505050565b634e487b7160e01b600052604160045260246000fd5b600082601f830112610df557600080fd5b813567ffffffffffffffff80821115610e1057610e10610dce565b604051601f8301601f19908116603f01168101908282118183101715610e3857610e38610dce565b81604052838152866020858801011115610e5157600080fd5b836020870160208301376000602085830101528094505050505092915050565b60008060408385031215610e8457600080fd5b82359150602083013567ffffffffffffffff811115610ea257600080fd5b610eae85828601610de4565b9150509250929050565b600060208284031215610eca57600080fd5b5035919050565b60a081526000610ee460a0830188610d88565b8281036020840152610ef68188610d88565b90508281036040840152610f0a8187610d88565b90508460608401528281036080840152610f248185610d88565b98975050505050505050565b600060208284031215610f4257600080fd5b813567ffffffffffffffff811115610f5957600080fd5b610f6584828501610de4565b949350505050565b60008060008060808587031215610f8357600080fd5b843567ffffffffffffffff80821115610f9b57600080fd5b610fa788838901610de4565b95506020870135915080821115610fbd57600080fd5b610fc988838901610de4565b94506040870135915080821115610fdf57600080fd5b610feb88838901610de4565b9350606087013591508082111561100157600080fd5b5061100e87828801610de4565b91505092959194509250565b60408152600061102d6040830185610d88565b828103602084015261103f8185610d88565b95945050505050565b600181811c9082168061105c57607f821691505b60208210810361107c57634e487b7160e01b600052602260045260246000fd5b50919050565b60208082526015908201527422b93937b91d1020b1b1b2b9b9902232b734b2b21760591b604082015260600190565b600082516110c3818460208701610d64565b9190910192915050565b6000600182016110ed57634e487b7160e01b600052601160045260246000fd5b5060010190565b601f82111561113e57600081815260208120601f850160051c8101602086101561111b5750805b601f850160051c820191505b8181101561113a57828155600101611127565b5050505b505050565b815167ffffffffffffffff81111561115d5761115d610dce565b6111718161116b8454611048565b846110f4565b602080601f8311600181146111a6576000841561118e5750858301515b600019600386901b1c1916600185901b17855561113a565b600085815260208120601f198616915b828110156111d5578886015182559484019460019091019084016111b6565b50858210156111f35787850151600019600388901b60f8161c191681555b5050505050600190811b0190555056fea2646970667358221220aa1e9f032f28909aeb383b4cc8c8c079b6b944aa40854fe4102d7c7963aa4cf364736f6c63430008130033a264697066735822122078b0aa4cc049a69f343ce85a6ee8520d3f339d75b1c1ae96f2c6ea6a2177f6f864736f6c63430008130033",
+	ABI: "[{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"id\",\"type\":\"uint256\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"election\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"companyId\",\"type\":\"bytes32\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"authority\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"name\",\"type\":\"string\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"description\",\"type\":\"string\"}],\"name\":\"ElectionCreated\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"companyId\",\"type\":\"bytes32\"}],\"name\":\"companyElectionCount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"companyId\",\"type\":\"bytes32\"},{\"internalType\":\"string\",\"name\":\"election_name\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"election_description\",\"type\":\"string\"}],\"name\":\"createElection\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"electionCount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"name\":\"electionIds\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"companyId\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"offset\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"limit\",\"type\":\"uint256\"}],\"name\":\"getCompanyElections\",\"outputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"id\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"deployedAddress\",\"type\":\"address\"},{\"internalType\":\"bytes32\",\"name\":\"companyId\",\"type\":\"bytes32\"},{\"internalType\":\"string\",\"name\":\"el_n\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"el_d\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"createdAt\",\"type\":\"uint256\"}],\"internalType\":\"structElectionFact.ElectionDet[]\",\"name\":\"page\",\"type\":\"tuple[]\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"id\",\"type\":\"uint256\"}],\"name\":\"getElection\",\"outputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"id\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"deployedAddress\",\"type\":\"address\"},{\"internalType\":\"bytes32\",\"name\":\"companyId\",\"type\":\"bytes32\"},{\"internalType\":\"string\",\"name\":\"el_n\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"el_d\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"createdAt\",\"type\":\"uint256\"}],\"internalType\":\"structElectionFact.ElectionDet\",\"name\":\"\",\"type\":\"tuple\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"offset\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"limit\",\"type\":\"uint256\"}],\"name\":\"getElections\",\"outputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"id\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"deployedAddress\",\"type\":\"address\"},{\"internalType\":\"bytes32\",\"name\":\"companyId\",\"type\":\"bytes32\"},{\"internalType\":\"string\",\"name\":\"el_n\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"el_d\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"createdAt\",\"type\":\"uint256\"}],\"internalType\":\"structElectionFact.ElectionDet[]\",\"name\":\"page\",\"type\":\"tuple[]\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+	Bin: "0x608060405234801561000f575f80fd5b506141c28061001d5f395ff3fe608060405234801562000010575f80fd5b506004361062000084575f3560e01c8063bbbe4b79116200005f578063bbbe4b7914620000ef578063be8e8b3c1462000111578063c50955b21462000133578063ce04aa02146200014a575f80fd5b8063338981081462000088578063997d283014620000b75780639d71077714620000c9575b5f80fd5b6200009f6200009936600462000bee565b62000161565b604051620000ae919062000cc2565b60405180910390f35b5f545b604051908152602001620000ae565b620000e0620000da36600462000d26565b6200042e565b604051620000ae919062000d3e565b620000ba6200010036600462000d52565b60026020525f908152604090205481565b620000ba6200012236600462000d26565b5f9081526001602052604090205490565b620000ba6200014436600462000e1f565b6200064b565b6200009f6200015b36600462000e8e565b62000895565b5f54606090808410620001ac57604080515f8082526020820190925290620001a2565b6200018e62000ba2565b815260200190600190039081620001845790505b5091505062000428565b5f83620001ba868462000ecc565b10620001c75783620001d3565b620001d3858362000ecc565b90508067ffffffffffffffff811115620001f157620001f162000d7a565b6040519080825280602002602001820160405280156200022e57816020015b6200021a62000ba2565b815260200190600190039081620002105790505b5092505f5b8181101562000424575f62000249828862000ee2565b815481106200025c576200025c62000ef8565b905f5260205f2090600602016040518060c00160405290815f8201548152602001600182015f9054906101000a90046001600160a01b03166001600160a01b03166001600160a01b0316815260200160028201548152602001600382018054620002c69062000f0c565b80601f0160208091040260200160405190810160405280929190818152602001828054620002f49062000f0c565b8015620003435780601f10620003195761010080835404028352916020019162000343565b820191905f5260205f20905b8154815290600101906020018083116200032557829003601f168201915b505050505081526020016004820180546200035e9062000f0c565b80601f01602080910402602001604051908101604052809291908181526020018280546200038c9062000f0c565b8015620003db5780601f10620003b157610100808354040283529160200191620003db565b820191905f5260205f20905b815481529060010190602001808311620003bd57829003601f168201915b5050505050815260200160058201548152505084828151811062000403576200040362000ef8565b602002602001018190525080806200041b9062000f46565b91505062000233565b5050505b92915050565b6200043862000ba2565b5f821180156200044957505f548211155b620004915760405162461bcd60e51b81526020600482015260136024820152721d5b9adb9bdddb88195b1958dd1a5bdb881a59606a1b60448201526064015b60405180910390fd5b5f6200049f60018462000ecc565b81548110620004b257620004b262000ef8565b905f5260205f2090600602016040518060c00160405290815f8201548152602001600182015f9054906101000a90046001600160a01b03166001600160a01b03166001600160a01b03168152602001600282015481526020016003820180546200051c9062000f0c565b80601f01602080910402602001604051908101604052809291908181526020018280546200054a9062000f0c565b8015620005995780601f106200056f5761010080835404028352916020019162000599565b820191905f5260205f20905b8154815290600101906020018083116200057b57829003601f168201915b50505050508152602001600482018054620005b49062000f0c565b80601f0160208091040260200160405190810160405280929190818152602001828054620005e29062000f0c565b8015620006315780601f10620006075761010080835404028352916020019162000631565b820191905f5260205f20905b8154815290600101906020018083116200061357829003601f168201915b505050505081526020016005820154815250509050919050565b5f83620006915760405162461bcd60e51b815260206004820152601360248201527218dbdb5c185b9e481a59081c995c5d5a5c9959606a1b604482015260640162000488565b5f338484604051620006a39062000be0565b620006b19392919062000f61565b604051809103905ff080158015620006cb573d5f803e3d5ffd5b505f805491925090620006e090600162000ee2565b6040805160c0810182528281526001600160a01b03858116602083019081529282018a8152606083018a8152608084018a90524260a08501525f805460018101825590805284517f290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e563600690920291820190815595517f290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e564820180546001600160a01b031916919095161790935590517f290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e5658301555193945090927f290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e56690910190620007e9908262000ff6565b506080820151600482019062000800908262000ff6565b5060a091909101516005909101555f8681526001602081815260408084208054938401815584528184209092018490556001600160a01b0385168084526002909152918190208390555187919083907f6262ddee9ed9032a157db5ad59fbb0f1264148e5cea485a1771879ebf872d14990620008829033908b908b9062000f61565b60405180910390a49150505b9392505050565b5f8381526001602052604090208054606091908410620008ed57604080515f8082526020820190925290620008e3565b620008cf62000ba2565b815260200190600190039081620008c55790505b509150506200088e565b80545f9084906200090090879062000ecc565b106200090d57836200091c565b81546200091c90869062000ecc565b90508067ffffffffffffffff8111156200093a576200093a62000d7a565b6040519080825280602002602001820160405280156200097757816020015b6200096362000ba2565b815260200190600190039081620009595790505b5092505f5b8181101562000b98575f60018462000995848a62000ee2565b81548110620009a857620009a862000ef8565b905f5260205f200154620009bd919062000ecc565b81548110620009d057620009d062000ef8565b905f5260205f2090600602016040518060c00160405290815f8201548152602001600182015f9054906101000a90046001600160a01b03166001600160a01b03166001600160a01b031681526020016002820154815260200160038201805462000a3a9062000f0c565b80601f016020809104026020016040519081016040528092919081815260200182805462000a689062000f0c565b801562000ab75780601f1062000a8d5761010080835404028352916020019162000ab7565b820191905f5260205f20905b81548152906001019060200180831162000a9957829003601f168201915b5050505050815260200160048201805462000ad29062000f0c565b80601f016020809104026020016040519081016040528092919081815260200182805462000b009062000f0c565b801562000b4f5780601f1062000b255761010080835404028352916020019162000b4f565b820191905f5260205f20905b81548152906001019060200180831162000b3157829003601f168201915b5050505050815260200160058201548152505084828151811062000b775762000b7762000ef8565b6020026020010181905250808062000b8f9062000f46565b9150506200097c565b5050509392505050565b6040518060c001604052805f81526020015f6001600160a01b031681526020015f801916815260200160608152602001606081526020015f81525090565b6130cd80620010c083390190565b5f806040838503121562000c00575f80fd5b50508035926020909101359150565b5f81518084525f5b8181101562000c355760208185018101518683018201520162000c17565b505f602082860101526020601f19601f83011685010191505092915050565b8051825260018060a01b036020820151166020830152604081015160408301525f606082015160c0606085015262000c9060c085018262000c0f565b90506080830151848203608086015262000cab828262000c0f565b91505060a083015160a08501528091505092915050565b5f602080830181845280855180835260408601915060408160051b87010192508387015f5b8281101562000d1957603f1988860301845262000d0685835162000c54565b9450928501929085019060010162000ce7565b5092979650505050505050565b5f6020828403121562000d37575f80fd5b5035919050565b602081525f6200088e602083018462000c54565b5f6020828403121562000d63575f80fd5b81356001600160a01b03811681146200088e575f80fd5b634e487b7160e01b5f52604160045260245ffd5b5f82601f83011262000d9e575f80fd5b813567ffffffffffffffff8082111562000dbc5762000dbc62000d7a565b604051601f8301601f19908116603f0116810190828211818310171562000de75762000de762000d7a565b8160405283815286602085880101111562000e00575f80fd5b836020870160208301375f602085830101528094505050505092915050565b5f805f6060848603121562000e32575f80fd5b83359250602084013567ffffffffffffffff8082111562000e51575f80fd5b62000e5f8783880162000d8e565b9350604086013591508082111562000e75575f80fd5b5062000e848682870162000d8e565b9150509250925092565b5f805f6060848603121562000ea1575f80fd5b505081359360208301359350604090920135919050565b634e487b7160e01b5f52601160045260245ffd5b8181038181111562000428576200042862000eb8565b8082018082111562000428576200042862000eb8565b634e487b7160e01b5f52603260045260245ffd5b600181811c9082168062000f2157607f821691505b60208210810362000f4057634e487b7160e01b5f52602260045260245ffd5b50919050565b5f6001820162000f5a5762000f5a62000eb8565b5060010190565b6001600160a01b03841681526060602082018190525f9062000f869083018562000c0f565b828103604084015262000f9a818562000c0f565b9695505050505050565b601f82111562000ff1575f81815260208120601f850160051c8101602086101562000fcc5750805b601f850160051c820191505b8181101562000fed5782815560010162000fd8565b5050505b505050565b815167ffffffffffffffff81111562001013576200101362000d7a565b6200102b8162001024845462000f0c565b8462000fa4565b602080601f83116001811462001061575f8415620010495750858301515b5f19600386901b1c1916600185901b17855562000fed565b5f85815260208120601f198616915b82811015620010915788860151825594840194600190910190840162001070565b5085821015620010af57878501515f19600388901b60f8161c191681555b5050505050600190811b0190555056fe608060405234801562000010575f80fd5b50604051620030cd380380620030cd833981016040819052620000339162000159565b5f80546001600160a01b0319166001600160a01b03851617905560016200005b838262000269565b5060026200006a828262000269565b506003805460ff191660011790554260048190556200008e9062093a809062000331565b6005555062000357915050565b634e487b7160e01b5f52604160045260245ffd5b5f82601f830112620000bf575f80fd5b81516001600160401b0380821115620000dc57620000dc6200009b565b604051601f8301601f19908116603f011681019082821181831017156200010757620001076200009b565b8160405283815260209250868385880101111562000123575f80fd5b5f91505b8382101562000146578582018301518183018401529082019062000127565b5f93810190920192909252949350505050565b5f805f606084860312156200016c575f80fd5b83516001600160a01b038116811462000183575f80fd5b60208501519093506001600160401b0380821115620001a0575f80fd5b620001ae87838801620000af565b93506040860151915080821115620001c4575f80fd5b50620001d386828701620000af565b9150509250925092565b600181811c90821680620001f257607f821691505b6020821081036200021157634e487b7160e01b5f52602260045260245ffd5b50919050565b601f82111562000264575f81815260208120601f850160051c810160208610156200023f5750805b601f850160051c820191505b8181101562000260578281556001016200024b565b5050505b505050565b81516001600160401b038111156200028557620002856200009b565b6200029d81620002968454620001dd565b8462000217565b602080601f831160018114620002d3575f8415620002bb5750858301515b5f19600386901b1c1916600185901b17855562000260565b5f85815260208120601f198616915b828110156200030357888601518255948401946001909101908401620002e2565b50858210156200032157878501515f19600388901b60f8161c191681555b5050505050600190811b01905550565b808201808211156200035157634e487b7160e01b5f52601160045260245ffd5b92915050565b612d6880620003655f395ff3fe608060405234801561000f575f80fd5b5060043610610249575f3560e01c80635c975abb1161014057806382e15fcd116100bf578063d67f133111610084578063d67f13311461051a578063e15ef5d51461052d578063e8685ba114610540578063ed35a5da14610548578063ed836bc314610550578063f540879014610566575f80fd5b806382e15fcd146104a35780638d1ba346146104cd5780639869aca0146104ec578063a15148d1146104ff578063bcb5141314610507575f80fd5b806376874a7d1161010557806376874a7d1461045857806378e979251461046b5780637d0eef61146104745780637fb4c4c21461047d578063819d029714610490575f80fd5b80635c975abb1461041257806365fc783c1461041f5780636c6c32d0146104275780636cb3e8ef1461042f5780636da6635514610445575f80fd5b80633197cbb6116101cc57806347535d7b1161019157806347535d7b1461039b57806348aefc32146103a35780634cbe32b8146103b65780635216509a146103bf57806353fa2e64146103c8575f80fd5b80633197cbb6146103355780633477ee2e1461033e57806335b8e8201461036257806339bfeae31461037557806342b03cc914610388575f80fd5b8063241084751161021257806324108475146102d257806326fadbe2146102e55780632d1623691461030e5780632e55d0f2146103175780632e6997fe14610320575f80fd5b8062eea8a21461024d578063044d5a97146102695780630b36c7641461027e578063200d2ed21461029357806321248228146102b0575b5f80fd5b610256600c5481565b6040519081526020015b60405180910390f35b610271610593565b60405161026091906123d5565b61029161028c3660046123ee565b61061f565b005b6003546102a09060ff1681565b6040519015158152602001610260565b6102a06102be36600461240e565b600e6020525f908152604090205460ff1681565b6102916102e03660046124dd565b61065f565b60045460055460035460ff16604080519384526020840192909252151590820152606001610260565b61025660105481565b610256600b5481565b6103286107cf565b6040516102609190612521565b61025660055481565b61035161034c36600461240e565b610af2565b6040516102609594939291906125e6565b61035161037036600461240e565b610d37565b6102a0610383366004612644565b61101b565b61029161039636600461267e565b611048565b6102a061117b565b6102a06103b136600461240e565b6111b2565b61025660095481565b61025660085481565b6103fd6103d6366004612644565b80516020818301810180516007825292820191909301209152805460019091015460ff1682565b60408051928352901515602083015201610260565b600a546102a09060ff1681565b600954610256565b6102916111d8565b610437611287565b604051610260929190612724565b610291610453366004612644565b6112e7565b6103fd610466366004612644565b6113dd565b61025660045481565b610256600f5481565b61025661048b36600461281a565b611426565b61029161049e3660046128c3565b6116ba565b5f546104b5906001600160a01b031681565b6040516001600160a01b039091168152602001610260565b6102566104db36600461240e565b60116020525f908152604090205481565b6102916104fa3660046123ee565b611985565b610256611ad7565b610256610515366004612955565b611bca565b61029161052836600461298c565b611c17565b61029161053b3660046129d9565b611c5c565b600854610256565b610271611da7565b610558611db4565b6040516102609291906129f8565b6102a06105743660046123ee565b601260209081525f928352604080842090915290825290205460ff1681565b600280546105a090612a25565b80601f01602080910402602001604051908101604052809291908181526020018280546105cc90612a25565b80156106175780601f106105ee57610100808354040283529160200191610617565b820191905f5260205f20905b8154815290600101906020018083116105fa57829003601f168201915b505050505081565b5f546001600160a01b031633146106515760405162461bcd60e51b815260040161064890612a5d565b60405180910390fd5b61065b8282611ed7565b5050565b5f546001600160a01b031633146106885760405162461bcd60e51b815260040161064890612a5d565b60035460ff166106aa5760405162461bcd60e51b815260040161064890612a8c565b600a5460ff16156106f95760405162461bcd60e51b8152602060048201526019602482015278115c9c9bdc8e88115b1958dd1a5bdb881a5cc81c185d5cd959603a1b6044820152606401610648565b610701612024565b6007816040516107119190612ac3565b9081526040519081900360200190206001015460ff16156107745760405162461bcd60e51b815260206004820152601d60248201527f4572726f723a20596f752063616e6e6f7420646f75626c6520766f74650000006044820152606401610648565b60085482106107c55760405162461bcd60e51b815260206004820152601b60248201527f4572726f723a20496e76616c69642063616e64696461746520494400000000006044820152606401610648565b61065b82826120c9565b606060085467ffffffffffffffff8111156107ec576107ec612425565b60405190808252806020026020018201604052801561084e57816020015b61083b6040518060a001604052806060815260200160608152602001606081526020015f8152602001606081525090565b81526020019060019003908161080a5790505b5090505f5b600854811015610aee575f8181526006602052604090819020815160a0810190925280548290829061088490612a25565b80601f01602080910402602001604051908101604052809291908181526020018280546108b090612a25565b80156108fb5780601f106108d2576101008083540402835291602001916108fb565b820191905f5260205f20905b8154815290600101906020018083116108de57829003601f168201915b5050505050815260200160018201805461091490612a25565b80601f016020809104026020016040519081016040528092919081815260200182805461094090612a25565b801561098b5780601f106109625761010080835404028352916020019161098b565b820191905f5260205f20905b81548152906001019060200180831161096e57829003601f168201915b505050505081526020016002820180546109a490612a25565b80601f01602080910402602001604051908101604052809291908181526020018280546109d090612a25565b8015610a1b5780601f106109f257610100808354040283529160200191610a1b565b820191905f5260205f20905b8154815290600101906020018083116109fe57829003601f168201915b5050505050815260200160038201548152602001600482018054610a3e90612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054610a6a90612a25565b8015610ab55780601f10610a8c57610100808354040283529160200191610ab5565b820191905f5260205f20905b815481529060010190602001808311610a9857829003601f168201915b505050505081525050828281518110610ad057610ad0612ade565b60200260200101819052508080610ae690612b06565b915050610853565b5090565b60066020525f9081526040902080548190610b0c90612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054610b3890612a25565b8015610b835780601f10610b5a57610100808354040283529160200191610b83565b820191905f5260205f20905b815481529060010190602001808311610b6657829003601f168201915b505050505090806001018054610b9890612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054610bc490612a25565b8015610c0f5780601f10610be657610100808354040283529160200191610c0f565b820191905f5260205f20905b815481529060010190602001808311610bf257829003601f168201915b505050505090806002018054610c2490612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054610c5090612a25565b8015610c9b5780601f10610c7257610100808354040283529160200191610c9b565b820191905f5260205f20905b815481529060010190602001808311610c7e57829003601f168201915b505050505090806003015490806004018054610cb690612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054610ce290612a25565b8015610d2d5780601f10610d0457610100808354040283529160200191610d2d565b820191905f5260205f20905b815481529060010190602001808311610d1057829003601f168201915b5050505050905085565b60608060605f60606008548610610d905760405162461bcd60e51b815260206004820152601b60248201527f4572726f723a20496e76616c69642063616e64696461746520494400000000006044820152606401610648565b5f86815260066020526040808220815160a08101909252805482908290610db690612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054610de290612a25565b8015610e2d5780601f10610e0457610100808354040283529160200191610e2d565b820191905f5260205f20905b815481529060010190602001808311610e1057829003601f168201915b50505050508152602001600182018054610e4690612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054610e7290612a25565b8015610ebd5780601f10610e9457610100808354040283529160200191610ebd565b820191905f5260205f20905b815481529060010190602001808311610ea057829003601f168201915b50505050508152602001600282018054610ed690612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054610f0290612a25565b8015610f4d5780601f10610f2457610100808354040283529160200191610f4d565b820191905f5260205f20905b815481529060010190602001808311610f3057829003601f168201915b5050505050815260200160038201548152602001600482018054610f7090612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054610f9c90612a25565b8015610fe75780601f10610fbe57610100808354040283529160200191610fe7565b820191905f5260205f20905b815481529060010190602001808311610fca57829003601f168201915b5050509190925250508151602083015160408401516060850151608090950151929c919b5099509297509550909350505050565b5f60078260405161102c9190612ac3565b9081526040519081900360200190206001015460ff1692915050565b5f546001600160a01b031633146110715760405162461bcd60e51b815260040161064890612a5d565b60035460ff166110935760405162461bcd60e51b815260040161064890612a8c565b6008546040805160a08101825286815260208082018790528183018690525f6060830181905260808301869052848152600690915291909120815181906110da9082612b6c565b50602082015160018201906110ef9082612b6c565b50604082015160028201906111049082612b6c565b5060608201516003820155608082015160048201906111239082612b6c565b50506008805491505f61113583612b06565b9190505550807fe64f834082661dd9ed89427b87edb6270fd6dad17198b0938f0edd058914c87f868460405161116c9291906129f8565b60405180910390a25050505050565b6003545f9060ff1680156111925750600a5460ff16155b80156111a057506004544210155b80156111ad575060055442105b905090565b5f600f545f14806111d25750600f545f8381526011602052604090205410155b92915050565b5f546001600160a01b031633146112015760405162461bcd60e51b815260040161064890612a5d565b60035460ff166112235760405162461bcd60e51b815260040161064890612a8c565b61122f60015f8061218c565b6003805460ff1916905560055442101561124857426005555b6009546040805142815260208101929092527fd3461d57d954b89a789e6fbd29ff23c87fef073eae45cc372d7e26cb14594353910160405180910390a1565b60605f600d600f54818054806020026020016040519081016040528092919081815260200182805480156112d857602002820191905f5260205f20905b8154815260200190600101908083116112c4575b50505050509150915091509091565b5f546001600160a01b031633146113105760405162461bcd60e51b815260040161064890612a5d565b60035460ff166113325760405162461bcd60e51b815260040161064890612a8c565b600a5460ff161561138f5760405162461bcd60e51b815260206004820152602160248201527f4572726f723a20456c656374696f6e20697320616c72656164792070617573656044820152601960fa1b6064820152608401610648565b600a805460ff1916600117905542600b8190556040517f6439f95181932fe19c4a4871f266184c145461c7a05604ddd8259256681d2f88916113d2918490612c28565b60405180910390a150565b5f805f6007846040516113f09190612ac3565b9081526040805160209281900383018120818301909252815480825260019092015460ff16151592018290529590945092505050565b5f80546001600160a01b031633146114505760405162461bcd60e51b815260040161064890612a5d565b60035460ff166114725760405162461bcd60e51b815260040161064890612a8c565b600a5460ff16156114c15760405162461bcd60e51b8152602060048201526019602482015278115c9c9bdc8e88115b1958dd1a5bdb881a5cc81c185d5cd959603a1b6044820152606401610648565b815183511461150b5760405162461bcd60e51b815260206004820152601660248201527508ae4e4dee4744098cadccee8d040dad2e6dac2e8c6d60531b6044820152606401610648565b611513612024565b5f5b82518110156116b357600783828151811061153257611532612ade565b60200260200101516040516115479190612ac3565b9081526040519081900360200190206001015460ff16156115d2577f7df1303a567716bd50bac12600fe8ef9931c9a1b1a911f1a65af20b3f9e8ac8283828151811061159557611595612ade565b60200260200101518583815181106115af576115af612ade565b60200260200101516040516115c5929190612c40565b60405180910390a16116a1565b6008548482815181106115e7576115e7612ade565b602002602001015110611657577f7df1303a567716bd50bac12600fe8ef9931c9a1b1a911f1a65af20b3f9e8ac8283828151811061162757611627612ade565b602002602001015185838151811061164157611641612ade565b60200260200101516040516115c5929190612c86565b61169384828151811061166c5761166c612ade565b602002602001015184838151811061168657611686612ade565b60200260200101516120c9565b8161169d81612b06565b9250505b806116ab81612b06565b915050611515565b5092915050565b60035460ff166116dc5760405162461bcd60e51b815260040161064890612a8c565b5f546001600160a01b031633146117525760405162461bcd60e51b815260206004820152603460248201527f4572726f723a204f6e6c792074686520656c656374696f6e20617574686f726960448201527374792063616e2073657420617070726f7665727360601b6064820152608401610648565b600f54156117a25760405162461bcd60e51b815260206004820152601c60248201527f4572726f723a20417070726f7665727320616c726561647920736574000000006044820152606401610648565b5f811180156117b2575081518111155b6117fe5760405162461bcd60e51b815260206004820152601860248201527f4572726f723a20496e76616c6964207468726573686f6c6400000000000000006044820152606401610648565b5f5b8251811015611941575f801b83828151811061181e5761181e612ade565b6020026020010151141580156118635750600e5f84838151811061184457611844612ade565b60209081029190910181015182528101919091526040015f205460ff16155b6118bb5760405162461bcd60e51b8152602060048201526024808201527f4572726f723a20496e76616c6964206f72206475706c6963617465206170707260448201526337bb32b960e11b6064820152608401610648565b6001600e5f8584815181106118d2576118d2612ade565b602002602001015181526020019081526020015f205f6101000a81548160ff021916908315150217905550600d83828151811061191157611911612ade565b60209081029190910181015182546001810184555f9384529190922001558061193981612b06565b915050611800565b50600f8190556040517f681084b7bd331d1786b94cd347fd4045485cebef99891447c87fbd4d23b86f3d906119799084908490612724565b60405180910390a15050565b5f546001600160a01b031633146119ae5760405162461bcd60e51b815260040161064890612a5d565b60035460ff166119d05760405162461bcd60e51b815260040161064890612a8c565b818111611a1f5760405162461bcd60e51b815260206004820152601e60248201527f4572726f723a20456e64206d75737420626520616674657220737461727400006044820152606401610648565b6009541580611a2e5750428211155b611a8b5760405162461bcd60e51b815260206004820152602860248201527f4572726f723a2043616e6e6f74206d6f76652073746172742070617374206361604482015267737420766f74657360c01b6064820152608401610648565b611a976002838361218c565b6004829055600581905560408051838152602081018390527f644a9f205d77531ec9577a8e9e61b6f8737b60425f866c6d371472dd268427599101611979565b5f80546001600160a01b03163314611b015760405162461bcd60e51b815260040161064890612a5d565b5f60085411611b495760405162461bcd60e51b81526020600482015260146024820152734572726f723a204e6f2063616e6469646174657360601b6044820152606401610648565b5f80805260066020527f54cdd369e4e8a8515e52ca72ec816c2101831ad1f18bf44102ed171459c9b4fb549060015b600854811015611bc3575f81815260066020526040902060030154831015611bb1575f8181526006602052604090206003015492509050805b80611bbb81612b06565b915050611b78565b5091505090565b601054604080513060208083019190915260ff96909616818301526060810194909452608084019290925260a0808401919091528151808403909101815260c09092019052805191012090565b5f546001600160a01b03163314611c405760405162461bcd60e51b815260040161064890612a5d565b61065b82611c4e8484612220565b6001600160a01b0316611ed7565b5f546001600160a01b03163314611c855760405162461bcd60e51b815260040161064890612a5d565b60035460ff16611ca75760405162461bcd60e51b815260040161064890612a8c565b600a5460ff16611cf95760405162461bcd60e51b815260206004820152601d60248201527f4572726f723a20456c656374696f6e206973206e6f74207061757365640000006044820152606401610648565b8015611d0d57611d0d60026004545f61218c565b5f600b5442611d1c9190612cd2565b600a805460ff191690555f600b819055600c80549293508392909190611d43908490612ce5565b90915550508115611d65578060055f828254611d5f9190612ce5565b90915550505b6005546040805142815260208101849052908101919091527f46c0215a8a63754bf37d8dddd302bf1a7ec6b540be934338c9bb7a5e967c91c390606001611979565b600180546105a090612a25565b60608060016002818054611dc790612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054611df390612a25565b8015611e3e5780601f10611e1557610100808354040283529160200191611e3e565b820191905f5260205f20905b815481529060010190602001808311611e2157829003601f168201915b50505050509150808054611e5190612a25565b80601f0160208091040260200160405190810160405280929190818152602001828054611e7d90612a25565b8015611ec85780601f10611e9f57610100808354040283529160200191611ec8565b820191905f5260205f20905b815481529060010190602001808311611eab57829003601f168201915b50505050509050915091509091565b5f818152600e602052604090205460ff16611f2d5760405162461bcd60e51b815260206004820152601660248201527522b93937b91d102737ba1030b71030b8383937bb32b960511b6044820152606401610648565b5f82815260126020908152604080832084845290915290205460ff1615611f965760405162461bcd60e51b815260206004820152601760248201527f4572726f723a20416c726561647920617070726f7665640000000000000000006044820152606401610648565b5f8281526012602090815260408083208484528252808320805460ff1916600117905584835260119091528120805491611fcf83612b06565b919050555080827f4ede1c61bc0cef1132846f085639c84569ce72445812e6e0a44d3946f88a18b460115f8681526020019081526020015f205460405161201891815260200190565b60405180910390a35050565b6004544210156120765760405162461bcd60e51b815260206004820152601f60248201527f4572726f723a20456c656374696f6e20686173206e6f742073746172746564006044820152606401610648565b60055442106120c75760405162461bcd60e51b815260206004820152601960248201527f4572726f723a20456c656374696f6e2068617320656e646564000000000000006044820152606401610648565b565b6040805180820182528381526001602082015290516007906120ec908490612ac3565b90815260405160209181900382019020825181559101516001909101805460ff191691151591909117905560098054905f61212683612b06565b90915550505f82815260066020526040812060030180549161214783612b06565b9190505550817f7443492b9be7d0c78915e97bcabcb827676bf0292f1b78d919b33ca1a881a66b82600954604051612180929190612cf8565b60405180910390a25050565b600f545f0361219a57505050565b600f5460115f6121ab868686611bca565b81526020019081526020015f205410156122075760405162461bcd60e51b815260206004820152601b60248201527f4572726f723a204e6f7420656e6f75676820617070726f76616c7300000000006044820152606401610648565b60108054905f61221683612b06565b9190505550505050565b5f81516041146122695760405162461bcd60e51b81526020600482015260146024820152734572726f723a20426164207369676e617475726560601b6044820152606401610648565b6020820151604083015160608401515f1a601b8110156122915761228e601b82612d19565b90505b6040517f19457468657265756d205369676e6564204d6573736167653a0a3332000000006020820152603c81018790525f90600190605c0160408051601f1981840301815282825280516020918201205f84529083018083525260ff851690820152606081018690526080810185905260a0016020604051602081039080840390855afa158015612324573d5f803e3d5ffd5b5050604051601f1901519150506001600160a01b03811661237e5760405162461bcd60e51b81526020600482015260146024820152734572726f723a20426164207369676e617475726560601b6044820152606401610648565b9695505050505050565b5f5b838110156123a257818101518382015260200161238a565b50505f910152565b5f81518084526123c1816020860160208601612388565b601f01601f19169290920160200192915050565b602081525f6123e760208301846123aa565b9392505050565b5f80604083850312156123ff575f80fd5b50508035926020909101359150565b5f6020828403121561241e575f80fd5b5035919050565b634e487b7160e01b5f52604160045260245ffd5b604051601f8201601f1916810167ffffffffffffffff8111828210171561246257612462612425565b604052919050565b5f67ffffffffffffffff83111561248357612483612425565b612496601f8401601f1916602001612439565b90508281528383830111156124a9575f80fd5b828260208301375f602084830101529392505050565b5f82601f8301126124ce575f80fd5b6123e78383356020850161246a565b5f80604083850312156124ee575f80fd5b82359150602083013567ffffffffffffffff81111561250b575f80fd5b612517858286016124bf565b9150509250929050565b5f6020808301818452808551808352604092508286019150828160051b8701018488015f5b838110156125d857603f19898403018552815160a0815181865261256c828701826123aa565b915050888201518582038a87015261258482826123aa565b915050878201518582038987015261259c82826123aa565b9150506060808301518187015250608080830151925085820381870152506125c481836123aa565b968901969450505090860190600101612546565b509098975050505050505050565b60a081525f6125f860a08301886123aa565b828103602084015261260a81886123aa565b9050828103604084015261261e81876123aa565b9050846060840152828103608084015261263881856123aa565b98975050505050505050565b5f60208284031215612654575f80fd5b813567ffffffffffffffff81111561266a575f80fd5b612676848285016124bf565b949350505050565b5f805f8060808587031215612691575f80fd5b843567ffffffffffffffff808211156126a8575f80fd5b6126b4888389016124bf565b955060208701359150808211156126c9575f80fd5b6126d5888389016124bf565b945060408701359150808211156126ea575f80fd5b6126f6888389016124bf565b9350606087013591508082111561270b575f80fd5b50612718878288016124bf565b91505092959194509250565b604080825283519082018190525f906020906060840190828701845b8281101561275c57815184529284019290840190600101612740565b50505092019290925292915050565b5f67ffffffffffffffff82111561278457612784612425565b5060051b60200190565b5f82601f83011261279d575f80fd5b813560206127b26127ad8361276b565b612439565b82815260059290921b840181019181810190868411156127d0575f80fd5b8286015b8481101561280f57803567ffffffffffffffff8111156127f3575f8081fd5b6128018986838b01016124bf565b8452509183019183016127d4565b509695505050505050565b5f806040838503121561282b575f80fd5b823567ffffffffffffffff80821115612842575f80fd5b818501915085601f830112612855575f80fd5b813560206128656127ad8361276b565b82815260059290921b84018101918181019089841115612883575f80fd5b948201945b838610156128a157853582529482019490820190612888565b965050860135925050808211156128b6575f80fd5b506125178582860161278e565b5f80604083850312156128d4575f80fd5b823567ffffffffffffffff8111156128ea575f80fd5b8301601f810185136128fa575f80fd5b8035602061290a6127ad8361276b565b82815260059290921b83018101918181019088841115612928575f80fd5b938201935b838510156129465784358252938201939082019061292d565b98969091013596505050505050565b5f805f60608486031215612967575f80fd5b833560ff81168114612977575f80fd5b95602085013595506040909401359392505050565b5f806040838503121561299d575f80fd5b82359150602083013567ffffffffffffffff8111156129ba575f80fd5b8301601f810185136129ca575f80fd5b6125178582356020840161246a565b5f602082840312156129e9575f80fd5b813580151581146123e7575f80fd5b604081525f612a0a60408301856123aa565b8281036020840152612a1c81856123aa565b95945050505050565b600181811c90821680612a3957607f821691505b602082108103612a5757634e487b7160e01b5f52602260045260245ffd5b50919050565b60208082526015908201527422b93937b91d1020b1b1b2b9b9902232b734b2b21760591b604082015260600190565b60208082526019908201527f4572726f723a20456c656374696f6e20697320636c6f73656400000000000000604082015260600190565b5f8251612ad4818460208701612388565b9190910192915050565b634e487b7160e01b5f52603260045260245ffd5b634e487b7160e01b5f52601160045260245ffd5b5f60018201612b1757612b17612af2565b5060010190565b601f821115612b67575f81815260208120601f850160051c81016020861015612b445750805b601f850160051c820191505b81811015612b6357828155600101612b50565b5050505b505050565b815167ffffffffffffffff811115612b8657612b86612425565b612b9a81612b948454612a25565b84612b1e565b602080601f831160018114612bcd575f8415612bb65750858301515b5f19600386901b1c1916600185901b178555612b63565b5f85815260208120601f198616915b82811015612bfb57888601518255948401946001909101908401612bdc565b5085821015612c1857878501515f19600388901b60f8161c191681555b5050505050600190811b01905550565b828152604060208201525f61267660408301846123aa565b606081525f612c5260608301856123aa565b8360208401528281036040840152600b81526a646f75626c6520766f746560a81b6020820152604081019150509392505050565b606081525f612c9860608301856123aa565b83602084015282810360408401526011815270696e76616c69642063616e64696461746560781b6020820152604081019150509392505050565b818103818111156111d2576111d2612af2565b808201808211156111d2576111d2612af2565b604081525f612d0a60408301856123aa565b90508260208301529392505050565b60ff81811683821601908111156111d2576111d2612af256fea26469706673582212203d647dc3688a57ea591c82383d2bb3e04073875d0fb6915e75c74fbdc6ec64aa64736f6c63430008150033a264697066735822122074c6184a7d80d8bda77af07ae1b092c47a4eca753683e42afecec947a415aa3064736f6c63430008150033",
 }
 
 // ElectionFactABI is the input ABI used to generate the binding from.
@@ -209,62 +212,167 @@ func (_ElectionFact *ElectionFactTransactorRaw) Transact(opts *bind.TransactOpts
 	return _ElectionFact.Contract.contract.Transact(opts, method, params...)
 }
 
-// CompanyEmail is a free data retrieval call binding the contract method 0xc55bdbe4.
+// CompanyElectionCount is a free data retrieval call binding the contract method 0xbe8e8b3c.
 //
-// Solidity: function companyEmail(string , uint256 ) view returns(address deployedAddress, string el_n, string el_d)
-func (_ElectionFact *ElectionFactCaller) CompanyEmail(opts *bind.CallOpts, arg0 string, arg1 *big.Int) (struct {
-	DeployedAddress common.Address
-	ElN             string
-	ElD             string
-}, error) {
+// Solidity: function companyElectionCount(bytes32 companyId) view returns(uint256)
+func (_ElectionFact *ElectionFactCaller) CompanyElectionCount(opts *bind.CallOpts, companyId [32]byte) (*big.Int, error) {
 	var out []interface{}
-	err := _ElectionFact.contract.Call(opts, &out, "companyEmail", arg0, arg1)
+	err := _ElectionFact.contract.Call(opts, &out, "companyElectionCount", companyId)
 
-	outstruct := new(struct {
-		DeployedAddress common.Address
-		ElN             string
-		ElD             string
-	})
 	if err != nil {
-		return *outstruct, err
+		return *new(*big.Int), err
 	}
 
-	outstruct.DeployedAddress = *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
-	outstruct.ElN = *abi.ConvertType(out[1], new(string)).(*string)
-	outstruct.ElD = *abi.ConvertType(out[2], new(string)).(*string)
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
 
-	return *outstruct, err
+	return out0, err
 
 }
 
-// CompanyEmail is a free data retrieval call binding the contract method 0xc55bdbe4.
+// CompanyElectionCount is a free data retrieval call binding the contract method 0xbe8e8b3c.
 //
-// Solidity: function companyEmail(string , uint256 ) view returns(address deployedAddress, string el_n, string el_d)
-func (_ElectionFact *ElectionFactSession) CompanyEmail(arg0 string, arg1 *big.Int) (struct {
-	DeployedAddress common.Address
-	ElN             string
-	ElD             string
-}, error) {
-	return _ElectionFact.Contract.CompanyEmail(&_ElectionFact.CallOpts, arg0, arg1)
+// Solidity: function companyElectionCount(bytes32 companyId) view returns(uint256)
+func (_ElectionFact *ElectionFactSession) CompanyElectionCount(companyId [32]byte) (*big.Int, error) {
+	return _ElectionFact.Contract.CompanyElectionCount(&_ElectionFact.CallOpts, companyId)
 }
 
-// CompanyEmail is a free data retrieval call binding the contract method 0xc55bdbe4.
+// CompanyElectionCount is a free data retrieval call binding the contract method 0xbe8e8b3c.
 //
-// Solidity: function companyEmail(string , uint256 ) view returns(address deployedAddress, string el_n, string el_d)
-func (_ElectionFact *ElectionFactCallerSession) CompanyEmail(arg0 string, arg1 *big.Int) (struct {
-	DeployedAddress common.Address
-	ElN             string
-	ElD             string
-}, error) {
-	return _ElectionFact.Contract.CompanyEmail(&_ElectionFact.CallOpts, arg0, arg1)
+// Solidity: function companyElectionCount(bytes32 companyId) view returns(uint256)
+func (_ElectionFact *ElectionFactCallerSession) CompanyElectionCount(companyId [32]byte) (*big.Int, error) {
+	return _ElectionFact.Contract.CompanyElectionCount(&_ElectionFact.CallOpts, companyId)
+}
+
+// ElectionCount is a free data retrieval call binding the contract method 0x997d2830.
+//
+// Solidity: function electionCount() view returns(uint256)
+func (_ElectionFact *ElectionFactCaller) ElectionCount(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _ElectionFact.contract.Call(opts, &out, "electionCount")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// ElectionCount is a free data retrieval call binding the contract method 0x997d2830.
+//
+// Solidity: function electionCount() view returns(uint256)
+func (_ElectionFact *ElectionFactSession) ElectionCount() (*big.Int, error) {
+	return _ElectionFact.Contract.ElectionCount(&_ElectionFact.CallOpts)
+}
+
+// ElectionCount is a free data retrieval call binding the contract method 0x997d2830.
+//
+// Solidity: function electionCount() view returns(uint256)
+func (_ElectionFact *ElectionFactCallerSession) ElectionCount() (*big.Int, error) {
+	return _ElectionFact.Contract.ElectionCount(&_ElectionFact.CallOpts)
+}
+
+// ElectionIds is a free data retrieval call binding the contract method 0xbbbe4b79.
+//
+// Solidity: function electionIds(address ) view returns(uint256)
+func (_ElectionFact *ElectionFactCaller) ElectionIds(opts *bind.CallOpts, arg0 common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _ElectionFact.contract.Call(opts, &out, "electionIds", arg0)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// ElectionIds is a free data retrieval call binding the contract method 0xbbbe4b79.
+//
+// Solidity: function electionIds(address ) view returns(uint256)
+func (_ElectionFact *ElectionFactSession) ElectionIds(arg0 common.Address) (*big.Int, error) {
+	return _ElectionFact.Contract.ElectionIds(&_ElectionFact.CallOpts, arg0)
+}
+
+// ElectionIds is a free data retrieval call binding the contract method 0xbbbe4b79.
+//
+// Solidity: function electionIds(address ) view returns(uint256)
+func (_ElectionFact *ElectionFactCallerSession) ElectionIds(arg0 common.Address) (*big.Int, error) {
+	return _ElectionFact.Contract.ElectionIds(&_ElectionFact.CallOpts, arg0)
+}
+
+// GetCompanyElections is a free data retrieval call binding the contract method 0xce04aa02.
+//
+// Solidity: function getCompanyElections(bytes32 companyId, uint256 offset, uint256 limit) view returns((uint256,address,bytes32,string,string,uint256)[] page)
+func (_ElectionFact *ElectionFactCaller) GetCompanyElections(opts *bind.CallOpts, companyId [32]byte, offset *big.Int, limit *big.Int) ([]ElectionFactElectionDet, error) {
+	var out []interface{}
+	err := _ElectionFact.contract.Call(opts, &out, "getCompanyElections", companyId, offset, limit)
+
+	if err != nil {
+		return *new([]ElectionFactElectionDet), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([]ElectionFactElectionDet)).(*[]ElectionFactElectionDet)
+
+	return out0, err
+
+}
+
+// GetCompanyElections is a free data retrieval call binding the contract method 0xce04aa02.
+//
+// Solidity: function getCompanyElections(bytes32 companyId, uint256 offset, uint256 limit) view returns((uint256,address,bytes32,string,string,uint256)[] page)
+func (_ElectionFact *ElectionFactSession) GetCompanyElections(companyId [32]byte, offset *big.Int, limit *big.Int) ([]ElectionFactElectionDet, error) {
+	return _ElectionFact.Contract.GetCompanyElections(&_ElectionFact.CallOpts, companyId, offset, limit)
 }
 
-// GetDeployedElections is a free data retrieval call binding the contract method 0x381dde0d.
+// GetCompanyElections is a free data retrieval call binding the contract method 0xce04aa02.
 //
-// Solidity: function getDeployedElections(string email) view returns((address,string,string)[])
-func (_ElectionFact *ElectionFactCaller) GetDeployedElections(opts *bind.CallOpts, email string) ([]ElectionFactElectionDet, error) {
+// Solidity: function getCompanyElections(bytes32 companyId, uint256 offset, uint256 limit) view returns((uint256,address,bytes32,string,string,uint256)[] page)
+func (_ElectionFact *ElectionFactCallerSession) GetCompanyElections(companyId [32]byte, offset *big.Int, limit *big.Int) ([]ElectionFactElectionDet, error) {
+	return _ElectionFact.Contract.GetCompanyElections(&_ElectionFact.CallOpts, companyId, offset, limit)
+}
+
+// GetElection is a free data retrieval call binding the contract method 0x9d710777.
+//
+// Solidity: function getElection(uint256 id) view returns((uint256,address,bytes32,string,string,uint256))
+func (_ElectionFact *ElectionFactCaller) GetElection(opts *bind.CallOpts, id *big.Int) (ElectionFactElectionDet, error) {
+	var out []interface{}
+	err := _ElectionFact.contract.Call(opts, &out, "getElection", id)
+
+	if err != nil {
+		return *new(ElectionFactElectionDet), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(ElectionFactElectionDet)).(*ElectionFactElectionDet)
+
+	return out0, err
+
+}
+
+// GetElection is a free data retrieval call binding the contract method 0x9d710777.
+//
+// Solidity: function getElection(uint256 id) view returns((uint256,address,bytes32,string,string,uint256))
+func (_ElectionFact *ElectionFactSession) GetElection(id *big.Int) (ElectionFactElectionDet, error) {
+	return _ElectionFact.Contract.GetElection(&_ElectionFact.CallOpts, id)
+}
+
+// GetElection is a free data retrieval call binding the contract method 0x9d710777.
+//
+// Solidity: function getElection(uint256 id) view returns((uint256,address,bytes32,string,string,uint256))
+func (_ElectionFact *ElectionFactCallerSession) GetElection(id *big.Int) (ElectionFactElectionDet, error) {
+	return _ElectionFact.Contract.GetElection(&_ElectionFact.CallOpts, id)
+}
+
+// GetElections is a free data retrieval call binding the contract method 0x33898108.
+//
+// Solidity: function getElections(uint256 offset, uint256 limit) view returns((uint256,address,bytes32,string,string,uint256)[] page)
+func (_ElectionFact *ElectionFactCaller) GetElections(opts *bind.CallOpts, offset *big.Int, limit *big.Int) ([]ElectionFactElectionDet, error) {
 	var out []interface{}
-	err := _ElectionFact.contract.Call(opts, &out, "getDeployedElections", email)
+	err := _ElectionFact.contract.Call(opts, &out, "getElections", offset, limit)
 
 	if err != nil {
 		return *new([]ElectionFactElectionDet), err
@@ -276,37 +384,202 @@ func (_ElectionFact *ElectionFactCaller) GetDeployedElections(opts *bind.CallOpt
 
 }
 
-// GetDeployedElections is a free data retrieval call binding the contract method 0x381dde0d.
+// GetElections is a free data retrieval call binding the contract method 0x33898108.
+//
+// Solidity: function getElections(uint256 offset, uint256 limit) view returns((uint256,address,bytes32,string,string,uint256)[] page)
+func (_ElectionFact *ElectionFactSession) GetElections(offset *big.Int, limit *big.Int) ([]ElectionFactElectionDet, error) {
+	return _ElectionFact.Contract.GetElections(&_ElectionFact.CallOpts, offset, limit)
+}
+
+// GetElections is a free data retrieval call binding the contract method 0x33898108.
 //
-// Solidity: function getDeployedElections(string email) view returns((address,string,string)[])
-func (_ElectionFact *ElectionFactSession) GetDeployedElections(email string) ([]ElectionFactElectionDet, error) {
-	return _ElectionFact.Contract.GetDeployedElections(&_ElectionFact.CallOpts, email)
+// Solidity: function getElections(uint256 offset, uint256 limit) view returns((uint256,address,bytes32,string,string,uint256)[] page)
+func (_ElectionFact *ElectionFactCallerSession) GetElections(offset *big.Int, limit *big.Int) ([]ElectionFactElectionDet, error) {
+	return _ElectionFact.Contract.GetElections(&_ElectionFact.CallOpts, offset, limit)
 }
 
-// GetDeployedElections is a free data retrieval call binding the contract method 0x381dde0d.
+// CreateElection is a paid mutator transaction binding the contract method 0xc50955b2.
 //
-// Solidity: function getDeployedElections(string email) view returns((address,string,string)[])
-func (_ElectionFact *ElectionFactCallerSession) GetDeployedElections(email string) ([]ElectionFactElectionDet, error) {
-	return _ElectionFact.Contract.GetDeployedElections(&_ElectionFact.CallOpts, email)
+// Solidity: function createElection(bytes32 companyId, string election_name, string election_description) returns(uint256)
+func (_ElectionFact *ElectionFactTransactor) CreateElection(opts *bind.TransactOpts, companyId [32]byte, election_name string, election_description string) (*types.Transaction, error) {
+	return _ElectionFact.contract.Transact(opts, "createElection", companyId, election_name, election_description)
 }
 
-// CreateElection is a paid mutator transaction binding the contract method 0x4a6cf3a9.
+// CreateElection is a paid mutator transaction binding the contract method 0xc50955b2.
 //
-// Solidity: function createElection(string email, string election_name, string election_description) returns()
-func (_ElectionFact *ElectionFactTransactor) CreateElection(opts *bind.TransactOpts, email string, election_name string, election_description string) (*types.Transaction, error) {
-	return _ElectionFact.contract.Transact(opts, "createElection", email, election_name, election_description)
+// Solidity: function createElection(bytes32 companyId, string election_name, string election_description) returns(uint256)
+func (_ElectionFact *ElectionFactSession) CreateElection(companyId [32]byte, election_name string, election_description string) (*types.Transaction, error) {
+	return _ElectionFact.Contract.CreateElection(&_ElectionFact.TransactOpts, companyId, election_name, election_description)
+}
+
+// CreateElection is a paid mutator transaction binding the contract method 0xc50955b2.
+//
+// Solidity: function createElection(bytes32 companyId, string election_name, string election_description) returns(uint256)
+func (_ElectionFact *ElectionFactTransactorSession) CreateElection(companyId [32]byte, election_name string, election_description string) (*types.Transaction, error) {
+	return _ElectionFact.Contract.CreateElection(&_ElectionFact.TransactOpts, companyId, election_name, election_description)
+}
+
+// ElectionFactElectionCreatedIterator is returned from FilterElectionCreated and is used to iterate over the raw logs and unpacked data for ElectionCreated events raised by the ElectionFact contract.
+type ElectionFactElectionCreatedIterator struct {
+	Event *ElectionFactElectionCreated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
 }
 
-// CreateElection is a paid mutator transaction binding the contract method 0x4a6cf3a9.
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ElectionFactElectionCreatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ElectionFactElectionCreated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ElectionFactElectionCreated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ElectionFactElectionCreatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ElectionFactElectionCreatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ElectionFactElectionCreated represents a ElectionCreated event raised by the ElectionFact contract.
+type ElectionFactElectionCreated struct {
+	Id          *big.Int
+	Election    common.Address
+	CompanyId   [32]byte
+	Authority   common.Address
+	Name        string
+	Description string
+	Raw         types.Log // Blockchain specific contextual infos
+}
+
+// FilterElectionCreated is a free log retrieval operation binding the contract event 0x6262ddee9ed9032a157db5ad59fbb0f1264148e5cea485a1771879ebf872d149.
 //
-// Solidity: function createElection(string email, string election_name, string election_description) returns()
-func (_ElectionFact *ElectionFactSession) CreateElection(email string, election_name string, election_description string) (*types.Transaction, error) {
-	return _ElectionFact.Contract.CreateElection(&_ElectionFact.TransactOpts, email, election_name, election_description)
+// Solidity: event ElectionCreated(uint256 indexed id, address indexed election, bytes32 indexed companyId, address authority, string name, string description)
+func (_ElectionFact *ElectionFactFilterer) FilterElectionCreated(opts *bind.FilterOpts, id []*big.Int, election []common.Address, companyId [][32]byte) (*ElectionFactElectionCreatedIterator, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+	var electionRule []interface{}
+	for _, electionItem := range election {
+		electionRule = append(electionRule, electionItem)
+	}
+	var companyIdRule []interface{}
+	for _, companyIdItem := range companyId {
+		companyIdRule = append(companyIdRule, companyIdItem)
+	}
+
+	logs, sub, err := _ElectionFact.contract.FilterLogs(opts, "ElectionCreated", idRule, electionRule, companyIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &ElectionFactElectionCreatedIterator{contract: _ElectionFact.contract, event: "ElectionCreated", logs: logs, sub: sub}, nil
 }
 
-// CreateElection is a paid mutator transaction binding the contract method 0x4a6cf3a9.
+// WatchElectionCreated is a free log subscription operation binding the contract event 0x6262ddee9ed9032a157db5ad59fbb0f1264148e5cea485a1771879ebf872d149.
 //
-// Solidity: function createElection(string email, string election_name, string election_description) returns()
-func (_ElectionFact *ElectionFactTransactorSession) CreateElection(email string, election_name string, election_description string) (*types.Transaction, error) {
-	return _ElectionFact.Contract.CreateElection(&_ElectionFact.TransactOpts, email, election_name, election_description)
+// Solidity: event ElectionCreated(uint256 indexed id, address indexed election, bytes32 indexed companyId, address authority, string name, string description)
+func (_ElectionFact *ElectionFactFilterer) WatchElectionCreated(opts *bind.WatchOpts, sink chan<- *ElectionFactElectionCreated, id []*big.Int, election []common.Address, companyId [][32]byte) (event.Subscription, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+	var electionRule []interface{}
+	for _, electionItem := range election {
+		electionRule = append(electionRule, electionItem)
+	}
+	var companyIdRule []interface{}
+	for _, companyIdItem := range companyId {
+		companyIdRule = append(companyIdRule, companyIdItem)
+	}
+
+	logs, sub, err := _ElectionFact.contract.WatchLogs(opts, "ElectionCreated", idRule, electionRule, companyIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ElectionFactElectionCreated)
+				if err := _ElectionFact.contract.UnpackLog(event, "ElectionCreated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseElectionCreated is a log parse operation binding the contract event 0x6262ddee9ed9032a157db5ad59fbb0f1264148e5cea485a1771879ebf872d149.
+//
+// Solidity: event ElectionCreated(uint256 indexed id, address indexed election, bytes32 indexed companyId, address authority, string name, string description)
+func (_ElectionFact *ElectionFactFilterer) ParseElectionCreated(log types.Log) (*ElectionFactElectionCreated, error) {
+	event := new(ElectionFactElectionCreated)
+	if err := _ElectionFact.contract.UnpackLog(event, "ElectionCreated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
 }
@@ -0,0 +1,301 @@
+﻿// Package bundle writes and reads signed election archives: a zip holding one election's
+// records (see Export), a manifest.json listing every file with its SHA-256 and size, and
+// a signature.json with an EIP-191 signature over the manifest bytes. Open checks the
+// signature and every hash before anything is restored (see Import).
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"MAJOR-PROJECT/signer"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Format identifies the archive layout; Open refuses any other value.
+const Format = "evote-election-bundle/1"
+
+const (
+	manifestName  = "manifest.json"
+	signatureName = "signature.json"
+	maxMetaSize   = 4 << 20   // manifest.json and signature.json each
+	maxTotalSize  = 512 << 20 // all listed files together
+)
+
+var (
+	// ErrNoSigningKey is returned by Export when neither BUNDLE_SIGNING_KEY nor
+	// EVM_PRIVATE_KEY is set.
+	ErrNoSigningKey = errors.New("bundle: BUNDLE_SIGNING_KEY is not set")
+	// ErrUntrustedSigner is returned by Import when the bundle was signed by a key that
+	// is neither this server's nor listed in BUNDLE_TRUSTED_SIGNERS.
+	ErrUntrustedSigner = errors.New("bundle: signer is not trusted")
+)
+
+// File is one entry of the archive.
+type File struct {
+	Name string
+	Data []byte
+}
+
+// FileEntry is a file as listed in the manifest.
+type FileEntry struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// Manifest describes the archive and is the signed document.
+type Manifest struct {
+	Format       string      `json:"format"`
+	Election     string      `json:"election"`
+	ElectionName string      `json:"election_name,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+	Files        []FileEntry `json:"files"`
+}
+
+// Signature covers keccak256(EIP-191 prefix + manifest.json exactly as stored).
+type Signature struct {
+	Signer    string `json:"signer"`
+	Hash      string `json:"hash"`
+	Signature string `json:"signature"`
+}
+
+// Bundle is an opened archive whose hashes and signature have been checked.
+type Bundle struct {
+	Manifest  Manifest
+	Signature Signature
+	Files     map[string][]byte
+}
+
+// SigningKey returns the key bundles are signed with: BUNDLE_SIGNING_KEY, falling back to
+// the shared operator key EVM_PRIVATE_KEY like the "key" signer does.
+func SigningKey() (*ecdsa.PrivateKey, error) {
+	hexKey := env("BUNDLE_SIGNING_KEY")
+	if hexKey == "" {
+		hexKey = env("EVM_PRIVATE_KEY")
+	}
+	if hexKey == "" {
+		return nil, ErrNoSigningKey
+	}
+	key, err := signer.ParsePrivateKey(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("BUNDLE_SIGNING_KEY: %w", err)
+	}
+	return key, nil
+}
+
+// TrustedSigners returns the addresses whose bundles Import accepts: this server's
+// signing key plus the comma-separated BUNDLE_TRUSTED_SIGNERS.
+func TrustedSigners() (map[common.Address]bool, error) {
+	trusted := map[common.Address]bool{}
+	if key, err := SigningKey(); err == nil {
+		trusted[crypto.PubkeyToAddress(key.PublicKey)] = true
+	} else if !errors.Is(err, ErrNoSigningKey) {
+		return nil, err
+	}
+	for _, a := range strings.Split(env("BUNDLE_TRUSTED_SIGNERS"), ",") {
+		a = strings.TrimSpace(a)
+		if a == "" {
+			continue
+		}
+		if !common.IsHexAddress(a) {
+			return nil, fmt.Errorf("BUNDLE_TRUSTED_SIGNERS: invalid address %q", a)
+		}
+		trusted[common.HexToAddress(a)] = true
+	}
+	return trusted, nil
+}
+
+// Write builds the archive for m's election from files and signs its manifest with key.
+// The manifest's file list is filled in here, sorted by name.
+func Write(w io.Writer, m Manifest, files []File, key *ecdsa.PrivateKey) error {
+	m.Format = Format
+	m.Files = nil
+	for _, f := range files {
+		if f.Name == manifestName || f.Name == signatureName {
+			return fmt.Errorf("bundle: %s is a reserved file name", f.Name)
+		}
+		sum := sha256.Sum256(f.Data)
+		m.Files = append(m.Files, FileEntry{Name: f.Name, SHA256: hex.EncodeToString(sum[:]), Size: int64(len(f.Data))})
+	}
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Name < m.Files[j].Name })
+
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	hash := accounts.TextHash(manifest)
+	sig, err := crypto.Sign(hash, key)
+	if err != nil {
+		return err
+	}
+	sig[crypto.RecoveryIDOffset] += 27 // personal_sign style v
+	signature, err := json.MarshalIndent(Signature{
+		Signer:    crypto.PubkeyToAddress(key.PublicKey).Hex(),
+		Hash:      hexutil.Encode(hash),
+		Signature: hexutil.Encode(sig),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	all := append([]File{{manifestName, manifest}, {signatureName, signature}}, files...)
+	for _, f := range all {
+		fw, err := zw.Create(f.Name)
+		if err == nil {
+			_, err = fw.Write(f.Data)
+		}
+		if err != nil {
+			return fmt.Errorf("bundle: write %s: %w", f.Name, err)
+		}
+	}
+	return zw.Close()
+}
+
+// Open reads an archive and verifies it: the signature must recover to the signer it
+// names, and the archive must hold exactly the manifest's files with matching hashes.
+// Only manifest.json and signature.json are read before the signature is checked; the
+// listed files are then read up to their manifest size, under a total cap.
+// Whether that signer is trusted is left to the caller (see Import).
+func Open(data []byte) (*Bundle, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("bundle: not a zip archive: %w", err)
+	}
+	entries := map[string]*zip.File{}
+	for _, f := range zr.File {
+		if _, dup := entries[f.Name]; dup {
+			return nil, fmt.Errorf("bundle: %s appears twice", f.Name)
+		}
+		entries[f.Name] = f
+	}
+
+	manifest, err := readEntry(entries, manifestName, maxMetaSize)
+	if err != nil {
+		return nil, err
+	}
+	sigJSON, err := readEntry(entries, signatureName, maxMetaSize)
+	if err != nil {
+		return nil, err
+	}
+	b := &Bundle{Files: map[string][]byte{}}
+	if err := json.Unmarshal(manifest, &b.Manifest); err != nil {
+		return nil, fmt.Errorf("bundle: manifest.json: %w", err)
+	}
+	if b.Manifest.Format != Format {
+		return nil, fmt.Errorf("bundle: unsupported format %q", b.Manifest.Format)
+	}
+	if err := json.Unmarshal(sigJSON, &b.Signature); err != nil {
+		return nil, fmt.Errorf("bundle: signature.json: %w", err)
+	}
+	if err := verifySignature(manifest, b.Signature); err != nil {
+		return nil, err
+	}
+
+	listed := map[string]bool{manifestName: true, signatureName: true}
+	var total int64
+	for _, e := range b.Manifest.Files {
+		if listed[e.Name] {
+			return nil, fmt.Errorf("bundle: %s is listed twice", e.Name)
+		}
+		listed[e.Name] = true
+		if e.Size < 0 {
+			return nil, fmt.Errorf("bundle: %s has a negative size", e.Name)
+		}
+		if total += e.Size; total > maxTotalSize {
+			return nil, errors.New("bundle: listed files exceed the size limit")
+		}
+	}
+	for name := range entries {
+		if !listed[name] {
+			return nil, fmt.Errorf("bundle: %s is not listed in the manifest", name)
+		}
+	}
+
+	for _, e := range b.Manifest.Files {
+		data, err := readEntry(entries, e.Name, e.Size)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != strings.ToLower(e.SHA256) || int64(len(data)) != e.Size {
+			return nil, fmt.Errorf("bundle: %s does not match its manifest hash", e.Name)
+		}
+		b.Files[e.Name] = data
+	}
+	return b, nil
+}
+
+// readEntry decompresses one archive entry, refusing more than limit bytes whatever
+// the zip header claims.
+func readEntry(entries map[string]*zip.File, name string, limit int64) ([]byte, error) {
+	f, ok := entries[name]
+	if !ok {
+		if name == manifestName || name == signatureName {
+			return nil, fmt.Errorf("bundle: %s is missing", name)
+		}
+		return nil, fmt.Errorf("bundle: %s is listed in the manifest but missing", name)
+	}
+	if f.UncompressedSize64 > uint64(limit) {
+		return nil, fmt.Errorf("bundle: %s is too large", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("bundle: read %s: %w", name, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("bundle: read %s: %w", name, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("bundle: %s is too large", name)
+	}
+	return data, nil
+}
+
+// SignerAddress is the address that signed the manifest.
+func (b *Bundle) SignerAddress() common.Address {
+	return common.HexToAddress(b.Signature.Signer)
+}
+
+func verifySignature(manifest []byte, s Signature) error {
+	if !common.IsHexAddress(s.Signer) {
+		return fmt.Errorf("bundle: invalid signer %q", s.Signer)
+	}
+	sig, err := hexutil.Decode(s.Signature)
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return errors.New("bundle: malformed signature")
+	}
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash(manifest), sig)
+	if err != nil {
+		return fmt.Errorf("bundle: bad signature: %w", err)
+	}
+	if got := crypto.PubkeyToAddress(*pub); got != common.HexToAddress(s.Signer) {
+		return fmt.Errorf("bundle: manifest was signed by %s, not %s", got.Hex(), s.Signer)
+	}
+	return nil
+}
+
+func env(name string) string {
+	return strings.Trim(strings.TrimSpace(os.Getenv(name)), `"'`)
+}
@@ -0,0 +1,176 @@
+﻿package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func write(t *testing.T, key *ecdsa.PrivateKey, files ...File) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Write(&buf, Manifest{Election: "0xabc", ElectionName: "Council"}, files, key); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// rezip unpacks an archive, lets edit change its entries and packs it again, keeping
+// manifest.json and signature.json first.
+func rezip(t *testing.T, data []byte, edit func(entries map[string][]byte)) []byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := map[string][]byte{}
+	var order []string
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries[f.Name], err = io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		order = append(order, f.Name)
+	}
+	edit(entries)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name := range entries {
+		if !containsName(order, name) {
+			order = append(order, name)
+		}
+	}
+	for _, name := range order {
+		content, ok := entries[name]
+		if !ok {
+			continue
+		}
+		fw, err := zw.Create(name)
+		if err == nil {
+			_, err = fw.Write(content)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestWriteOpenRoundTrip(t *testing.T) {
+	key := newKey(t)
+	data := write(t, key, File{"voters.json", []byte(`[{"email":"a@example.com"}]`)}, File{"election.json", []byte(`{}`)})
+
+	b, err := Open(data)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if got, want := b.SignerAddress(), crypto.PubkeyToAddress(key.PublicKey); got != want {
+		t.Errorf("signer %s, want %s", got.Hex(), want.Hex())
+	}
+	if b.Manifest.Format != Format || b.Manifest.Election != "0xabc" || b.Manifest.ElectionName != "Council" {
+		t.Errorf("manifest %+v", b.Manifest)
+	}
+	if len(b.Manifest.Files) != 2 || b.Manifest.Files[0].Name != "election.json" || b.Manifest.Files[1].Name != "voters.json" {
+		t.Errorf("manifest files %+v, want election.json then voters.json", b.Manifest.Files)
+	}
+	if string(b.Files["voters.json"]) != `[{"email":"a@example.com"}]` || string(b.Files["election.json"]) != `{}` {
+		t.Errorf("files did not survive the round trip: %q", b.Files)
+	}
+
+	if err := Write(&bytes.Buffer{}, Manifest{}, []File{{manifestName, nil}}, key); err == nil {
+		t.Error("a file named manifest.json was accepted")
+	}
+}
+
+func TestOpenDetectsTampering(t *testing.T) {
+	key := newKey(t)
+	data := write(t, key, File{"voters.json", []byte(`[{"email":"a@example.com"}]`)}, File{"election.json", []byte(`{}`)})
+
+	for _, c := range []struct {
+		name string
+		edit func(entries map[string][]byte)
+		want string
+	}{
+		{"file changed", func(e map[string][]byte) {
+			e["voters.json"] = []byte(`[{"email":"b@example.com"}]`)
+		}, "does not match its manifest hash"},
+		{"manifest changed", func(e map[string][]byte) {
+			e[manifestName] = bytes.Replace(e[manifestName], []byte("Council"), []byte("Senate!"), 1)
+		}, "was signed by"},
+		{"signer swapped", func(e map[string][]byte) {
+			other := crypto.PubkeyToAddress(newKey(t).PublicKey).Hex()
+			e[signatureName] = bytes.Replace(e[signatureName], []byte(crypto.PubkeyToAddress(key.PublicKey).Hex()), []byte(other), 1)
+		}, "was signed by"},
+		{"file added", func(e map[string][]byte) {
+			e["extra.json"] = []byte(`{}`)
+		}, "not listed in the manifest"},
+		{"file removed", func(e map[string][]byte) {
+			delete(e, "election.json")
+		}, "listed in the manifest but missing"},
+		{"signature removed", func(e map[string][]byte) {
+			delete(e, signatureName)
+		}, "signature.json is missing"},
+	} {
+		_, err := Open(rezip(t, data, c.edit))
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%s: got %v, want an error containing %q", c.name, err, c.want)
+		}
+	}
+
+	if _, err := Open(rezip(t, data, func(map[string][]byte) {})); err != nil {
+		t.Errorf("repacked without changes: %v", err)
+	}
+}
+
+func TestTrustedSigners(t *testing.T) {
+	key := newKey(t)
+	other := crypto.PubkeyToAddress(newKey(t).PublicKey)
+	t.Setenv("BUNDLE_SIGNING_KEY", hex.EncodeToString(crypto.FromECDSA(key)))
+	t.Setenv("BUNDLE_TRUSTED_SIGNERS", " "+other.Hex()+", ")
+
+	trusted, err := TrustedSigners()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trusted) != 2 || !trusted[crypto.PubkeyToAddress(key.PublicKey)] || !trusted[other] {
+		t.Errorf("trusted %v, want this server's key and %s", trusted, other.Hex())
+	}
+
+	t.Setenv("BUNDLE_TRUSTED_SIGNERS", "not-an-address")
+	if _, err := TrustedSigners(); err == nil {
+		t.Error("an invalid BUNDLE_TRUSTED_SIGNERS entry was accepted")
+	}
+}
@@ -0,0 +1,336 @@
+﻿package bundle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"MAJOR-PROJECT/repository"
+	"MAJOR-PROJECT/txmanager"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Files of an election bundle. Ballots are not exported: they tie a voter to a candidate.
+const (
+	ElectionFile     = "election.json"     // repository.ElectionMetadata
+	CandidatesFile   = "candidates.json"   // []repository.CandidateDocument
+	VotersFile       = "voters.json"       // []repository.Voter, registrations for this election only, no password
+	AuditFile        = "audit_log.json"    // []repository.AuditLog, oldest first
+	TransactionsFile = "transactions.json" // []Transaction, oldest first
+	AnchorFile       = "anchor.json"       // Anchor
+)
+
+// ErrElectionExists is returned by Import when the target already has the election.
+var ErrElectionExists = errors.New("bundle: election already exists in this deployment")
+
+// Source is the deployment a bundle is exported from or imported into. Repos.AnchorJobs
+// and Repos.Bundles may be nil, and so may Txs when no transaction manager runs; those
+// parts are then skipped.
+type Source struct {
+	Repos *repository.Repositories
+	Txs   txmanager.Store
+}
+
+// Transaction is an election's managed transaction without its calldata and payload,
+// which can carry a voter's choice.
+type Transaction struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id"`
+	Chain       string             `bson:"chain" json:"chain"`
+	Purpose     string             `bson:"purpose" json:"purpose"`
+	Status      string             `bson:"status" json:"status"`
+	From        string             `bson:"from" json:"from"`
+	To          string             `bson:"to" json:"to"`
+	TxHash      string             `bson:"tx_hash" json:"tx_hash"`
+	TxHashes    []string           `bson:"tx_hashes" json:"tx_hashes"`
+	BlockNumber uint64             `bson:"block_number,omitempty" json:"block_number,omitempty"`
+	GasUsed     uint64             `bson:"gas_used,omitempty" json:"gas_used,omitempty"`
+	Error       string             `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	ConfirmedAt *time.Time         `bson:"confirmed_at,omitempty" json:"confirmed_at,omitempty"`
+}
+
+// Anchor is the election's L1 anchor: what the indexer saw, and the anchor job if any.
+type Anchor struct {
+	TxHash     string     `json:"anchor_tx_hash,omitempty"`
+	AnchoredAt *time.Time `json:"anchored_at,omitempty"`
+	Job        *AnchorJob `json:"job,omitempty"`
+}
+
+// AnchorJob is the anchor_jobs record, limited to the result sent to L1 and its receipt.
+type AnchorJob struct {
+	Status        string     `bson:"status" json:"status"`
+	Title         string     `bson:"title,omitempty" json:"title,omitempty"`
+	WinnerName    string     `bson:"winner_name,omitempty" json:"winner_name,omitempty"`
+	WinningVotes  string     `bson:"winning_votes,omitempty" json:"winning_votes,omitempty"`
+	TotalVoters   string     `bson:"total_voters,omitempty" json:"total_voters,omitempty"`
+	TxHash        string     `bson:"tx_hash,omitempty" json:"tx_hash,omitempty"`
+	BlockNumber   uint64     `bson:"block_number,omitempty" json:"block_number,omitempty"`
+	Confirmations uint64     `bson:"confirmations" json:"confirmations"`
+	ConfirmedAt   *time.Time `bson:"confirmed_at,omitempty" json:"confirmed_at,omitempty"`
+}
+
+// ImportResult is returned by Import.
+type ImportResult struct {
+	Election      string `json:"election"`
+	Signer        string `json:"signer"`
+	Candidates    int    `json:"candidates"`
+	VotersCreated int    `json:"voters_created"` // new accounts, without a password
+	VotersLinked  int    `json:"voters_linked"`  // existing accounts registered for the election
+	AuditEntries  int    `json:"audit_entries"`
+	Transactions  int    `json:"transactions"`
+	Archived      bool   `json:"archived"` // manifest, transactions and anchor stored in election_bundles
+}
+
+// Export writes the signed bundle of one election to w and returns its manifest.
+func Export(ctx context.Context, src Source, electionAddr string, w *bytes.Buffer) (*Manifest, error) {
+	key, err := SigningKey()
+	if err != nil {
+		return nil, err
+	}
+	meta, err := src.Repos.Elections.Get(ctx, electionAddr)
+	if err != nil {
+		return nil, err
+	}
+	addr := meta.ElectionAddress
+	candidates, err := src.Repos.Candidates.List(ctx, addr)
+	if err != nil {
+		return nil, fmt.Errorf("candidates: %w", err)
+	}
+	voters, err := src.Repos.Voters.List(ctx, addr)
+	if err != nil {
+		return nil, fmt.Errorf("voters: %w", err)
+	}
+	for i := range voters {
+		var regs []repository.VoterRegistration
+		for _, reg := range voters[i].Registrations {
+			if repository.CanonicalAddress(reg.ElectionAddress) == addr {
+				regs = append(regs, reg)
+			}
+		}
+		voters[i].Registrations = regs
+	}
+	entries, err := src.Repos.Audit.List(ctx, addr)
+	if err != nil {
+		return nil, fmt.Errorf("audit log: %w", err)
+	}
+	txs, anchor, err := chainRecords(ctx, src, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	if candidates == nil {
+		candidates = []repository.CandidateDocument{}
+	}
+	if voters == nil {
+		voters = []repository.Voter{}
+	}
+	if entries == nil {
+		entries = []repository.AuditLog{}
+	}
+	var files []File
+	for _, f := range []struct {
+		name string
+		v    interface{}
+	}{
+		{ElectionFile, meta},
+		{CandidatesFile, candidates},
+		{VotersFile, voters},
+		{AuditFile, entries},
+		{TransactionsFile, txs},
+		{AnchorFile, anchor},
+	} {
+		data, err := json.MarshalIndent(f.v, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.name, err)
+		}
+		files = append(files, File{Name: f.name, Data: data})
+	}
+
+	m := Manifest{Election: addr, ElectionName: meta.ElectionName, CreatedAt: time.Now().UTC()}
+	if err := Write(w, m, files, key); err != nil {
+		return nil, err
+	}
+	opened, err := Open(w.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("bundle: written archive does not verify: %w", err)
+	}
+	return &opened.Manifest, nil
+}
+
+// chainRecords reads the election's transactions and anchor job; without them only the
+// anchor fields of the metadata are known.
+func chainRecords(ctx context.Context, src Source, meta *repository.ElectionMetadata) ([]Transaction, Anchor, error) {
+	anchor := Anchor{TxHash: meta.AnchorTxHash, AnchoredAt: meta.AnchoredAt}
+	txs := []Transaction{}
+	if src.Txs != nil {
+		recs, err := src.Txs.List(ctx, txmanager.Filter{Election: meta.ElectionAddress}, 0)
+		if err != nil {
+			return nil, anchor, fmt.Errorf("transactions: %w", err)
+		}
+		// List is newest first
+		for i := len(recs) - 1; i >= 0; i-- {
+			r := recs[i]
+			txs = append(txs, Transaction{
+				ID: r.ID, Chain: r.Chain, Purpose: r.Purpose, Status: r.Status, From: r.From, To: r.To,
+				TxHash: r.TxHash, TxHashes: r.TxHashes, BlockNumber: r.BlockNumber, GasUsed: r.GasUsed,
+				Error: r.Error, CreatedAt: r.CreatedAt, ConfirmedAt: r.ConfirmedAt,
+			})
+		}
+	}
+
+	if src.Repos.AnchorJobs != nil {
+		job, err := src.Repos.AnchorJobs.Get(ctx, meta.ElectionAddress)
+		switch {
+		case err == nil:
+			anchor.Job = &AnchorJob{
+				Status: job.Status, Title: job.Title, WinnerName: job.WinnerName, WinningVotes: job.WinningVotes,
+				TotalVoters: job.TotalVoters, TxHash: job.TxHash, BlockNumber: job.BlockNumber,
+				Confirmations: job.Confirmations, ConfirmedAt: job.ConfirmedAt,
+			}
+		case !errors.Is(err, repository.ErrNotFound):
+			return nil, anchor, fmt.Errorf("anchor job: %w", err)
+		}
+	}
+	return txs, anchor, nil
+}
+
+// Import restores a verified bundle into src: the election metadata, candidates, audit
+// log and voter roll go back into the live collections, and the bundle's transactions
+// and anchor are kept in election_bundles. Voters that do not exist yet are created
+// without a password (they use forgot-password to log in); existing accounts with the
+// same email are registered for the election. Bundles signed by an untrusted key and
+// elections the target already has are refused before anything is written.
+func Import(ctx context.Context, src Source, b *Bundle, actor string) (*ImportResult, error) {
+	trusted, err := TrustedSigners()
+	if err != nil {
+		return nil, err
+	}
+	if !trusted[b.SignerAddress()] {
+		return nil, fmt.Errorf("%w: %s (add it to BUNDLE_TRUSTED_SIGNERS)", ErrUntrustedSigner, b.SignerAddress().Hex())
+	}
+
+	var (
+		meta       repository.ElectionMetadata
+		candidates []repository.CandidateDocument
+		voters     []repository.Voter
+		entries    []repository.AuditLog
+		txs        []Transaction
+		anchor     Anchor
+	)
+	for _, f := range []struct {
+		name string
+		v    interface{}
+	}{
+		{ElectionFile, &meta},
+		{CandidatesFile, &candidates},
+		{VotersFile, &voters},
+		{AuditFile, &entries},
+		{TransactionsFile, &txs},
+		{AnchorFile, &anchor},
+	} {
+		data, ok := b.Files[f.name]
+		if !ok {
+			return nil, fmt.Errorf("bundle: %s is missing", f.name)
+		}
+		if err := json.Unmarshal(data, f.v); err != nil {
+			return nil, fmt.Errorf("bundle: %s: %w", f.name, err)
+		}
+	}
+	addr := repository.CanonicalAddress(b.Manifest.Election)
+	if repository.CanonicalAddress(meta.ElectionAddress) != addr {
+		return nil, fmt.Errorf("bundle: %s is for %s, manifest says %s", ElectionFile, meta.ElectionAddress, addr)
+	}
+	if _, err := src.Repos.Elections.Get(ctx, addr); err == nil {
+		return nil, ErrElectionExists
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	res := &ImportResult{Election: addr, Signer: b.SignerAddress().Hex(), Transactions: len(txs)}
+	meta.ID = primitive.NilObjectID
+	if err := src.Repos.Elections.Create(ctx, &meta); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, ErrElectionExists
+		}
+		return nil, fmt.Errorf("election metadata: %w", err)
+	}
+	// From here on a failure leaves a partial import; the error names the step
+	for i := range candidates {
+		candidates[i].ElectionAddress = addr
+		if err := src.Repos.Candidates.Create(ctx, &candidates[i]); err != nil {
+			return res, fmt.Errorf("candidate %s: %w", candidates[i].Email, err)
+		}
+		res.Candidates++
+	}
+	for i := range voters {
+		if err := importVoter(ctx, src.Repos.Voters, &voters[i], addr, res); err != nil {
+			return res, fmt.Errorf("voter %s: %w", voters[i].Email, err)
+		}
+	}
+	for i := range entries {
+		entries[i].ID = primitive.NilObjectID
+		entries[i].ElectionAddress = addr
+		if err := src.Repos.Audit.Insert(ctx, &entries[i]); err != nil {
+			return res, fmt.Errorf("audit log: %w", err)
+		}
+		res.AuditEntries++
+	}
+
+	if src.Repos.Bundles != nil {
+		err := src.Repos.Bundles.Save(ctx, &repository.BundleArchive{
+			ElectionAddress: addr,
+			Manifest:        b.Manifest,
+			Signature:       b.Signature,
+			Transactions:    txs,
+			Anchor:          anchor,
+			ImportedAt:      time.Now().UTC(),
+			ImportedBy:      actor,
+		})
+		if err != nil {
+			return res, fmt.Errorf("election_bundles: %w", err)
+		}
+		res.Archived = true
+	}
+
+	_ = src.Repos.Audit.Insert(ctx, &repository.AuditLog{
+		ElectionAddress: addr,
+		Action:          "ELECTION_IMPORTED",
+		Actor:           actor,
+		Details:         fmt.Sprintf("Restored from bundle created %s, signed by %s", b.Manifest.CreatedAt.Format(time.RFC3339), res.Signer),
+		Timestamp:       time.Now().UTC(),
+	})
+	return res, nil
+}
+
+func importVoter(ctx context.Context, repo repository.VoterRepo, v *repository.Voter, addr string, res *ImportResult) error {
+	existing, err := repo.GetByEmail(ctx, v.Email)
+	if errors.Is(err, repository.ErrNotFound) {
+		v.ID = primitive.NilObjectID
+		v.Password = ""
+		if err := repo.Create(ctx, v); err != nil {
+			return err
+		}
+		res.VotersCreated++
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	reg := repository.VoterRegistration{ElectionAddress: addr, Status: "Verified", RegisteredAt: time.Now().UTC()}
+	if len(v.Registrations) > 0 {
+		reg = v.Registrations[0]
+		reg.ElectionAddress = addr
+	}
+	added, err := repo.AddRegistration(ctx, existing.ID, reg)
+	if err != nil {
+		return err
+	}
+	if added {
+		res.VotersLinked++
+	}
+	return nil
+}
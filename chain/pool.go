@@ -0,0 +1,399 @@
+﻿package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// PoolConfig tunes health probing. Zero values fall back to the defaults below.
+type PoolConfig struct {
+	ProbeInterval time.Duration // how often every endpoint is probed (default 15s)
+	ProbeTimeout  time.Duration // per-probe timeout (default 5s)
+	MaxBlockLag   uint64        // blocks an endpoint may trail the best one and stay healthy (default 5)
+}
+
+// EndpointStatus is the health of one RPC endpoint as last probed.
+type EndpointStatus struct {
+	URL         string    `json:"url"` // scheme and host only; paths usually carry API keys
+	Active      bool      `json:"active"`
+	Healthy     bool      `json:"healthy"`
+	BlockNumber uint64    `json:"block_number"`
+	Lag         uint64    `json:"lag"`
+	LatencyMs   int64     `json:"latency_ms"`
+	Failures    int       `json:"consecutive_failures"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastCheck   time.Time `json:"last_check"`
+}
+
+// endpoint is one node in a Pool. The dialed client is reused for every call.
+type endpoint struct {
+	url    string
+	label  string
+	client *rpcBackend
+
+	healthy   bool
+	height    uint64
+	latency   time.Duration
+	failures  int
+	lastErr   string
+	lastCheck time.Time
+}
+
+// Pool spreads one chain over several RPC endpoints. Calls go to the first healthy
+// endpoint in configuration order and fail over to the next one on transport errors;
+// a background probe tracks block height and latency and brings endpoints back.
+type Pool struct {
+	backend
+	cfg PoolConfig
+
+	mu        sync.RWMutex
+	endpoints []*endpoint
+	active    int
+
+	stop chan struct{}
+}
+
+// DialPool dials every URL and starts the health probe. It fails only when no endpoint
+// can be dialed at all.
+func DialPool(ctx context.Context, name string, urls []string, cfg PoolConfig) (*Pool, error) {
+	if cfg.ProbeInterval <= 0 {
+		cfg.ProbeInterval = 15 * time.Second
+	}
+	if cfg.ProbeTimeout <= 0 {
+		cfg.ProbeTimeout = 5 * time.Second
+	}
+	if cfg.MaxBlockLag == 0 {
+		cfg.MaxBlockLag = 5
+	}
+
+	p := &Pool{cfg: cfg, stop: make(chan struct{})}
+	p.backend = backend{client: poolClient{p}, name: name}
+	var errs []string
+	for _, u := range urls {
+		u = strings.Trim(strings.TrimSpace(u), `"'`)
+		if u == "" {
+			continue
+		}
+		b, err := Dial(ctx, name, u)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		p.endpoints = append(p.endpoints, &endpoint{url: u, label: redactURL(u), client: b.(*rpcBackend), healthy: true})
+	}
+	if len(p.endpoints) == 0 {
+		if len(errs) == 0 {
+			return nil, fmt.Errorf("%s node URL not configured", name)
+		}
+		return nil, fmt.Errorf("%s: no RPC endpoint reachable: %s", name, strings.Join(errs, "; "))
+	}
+
+	p.probe()
+	go p.probeLoop()
+	log.Printf("[CHAIN] %s pool with %d endpoint(s), active %s", name, len(p.endpoints), p.endpoints[p.active].label)
+	return p, nil
+}
+
+func (p *Pool) Deploy(auth *bind.TransactOpts, parsed abi.ABI, bytecode []byte, params ...interface{}) (common.Address, *types.Transaction, error) {
+	return p.deploy(p, auth, parsed, bytecode, params...)
+}
+
+func (p *Pool) Simulated() bool { return false }
+
+func (p *Pool) Close() {
+	close(p.stop)
+	for _, e := range p.endpoints {
+		e.client.Close()
+	}
+}
+
+// Status reports every endpoint in configuration order.
+func (p *Pool) Status() []EndpointStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	best := p.bestHeight()
+	out := make([]EndpointStatus, len(p.endpoints))
+	for i, e := range p.endpoints {
+		out[i] = EndpointStatus{
+			URL:         e.label,
+			Active:      i == p.active,
+			Healthy:     e.healthy,
+			BlockNumber: e.height,
+			LatencyMs:   e.latency.Milliseconds(),
+			Failures:    e.failures,
+			LastError:   e.lastErr,
+			LastCheck:   e.lastCheck,
+		}
+		if best > e.height {
+			out[i].Lag = best - e.height
+		}
+	}
+	return out
+}
+
+func (p *Pool) probeLoop() {
+	ticker := time.NewTicker(p.cfg.ProbeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.probe()
+		}
+	}
+}
+
+// probe asks every endpoint for its block number in parallel, then re-evaluates health
+// (reachable and within MaxBlockLag of the best height) and the active endpoint.
+func (p *Pool) probe() {
+	type result struct {
+		height  uint64
+		latency time.Duration
+		err     error
+	}
+	results := make([]result, len(p.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range p.endpoints {
+		wg.Add(1)
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), p.cfg.ProbeTimeout)
+			defer cancel()
+			start := time.Now()
+			h, err := e.client.BlockNumber(ctx)
+			results[i] = result{height: h, latency: time.Since(start), err: err}
+		}(i, e)
+	}
+	wg.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now().UTC()
+	for i, e := range p.endpoints {
+		r := results[i]
+		e.lastCheck = now
+		e.latency = r.latency
+		if r.err != nil {
+			e.failures++
+			e.lastErr = e.redact(r.err)
+			continue
+		}
+		e.height = r.height
+		e.failures = 0
+		e.lastErr = ""
+	}
+	best := p.bestHeight()
+	for i, e := range p.endpoints {
+		wasHealthy := e.healthy
+		e.healthy = results[i].err == nil && e.height+p.cfg.MaxBlockLag >= best
+		if wasHealthy && !e.healthy && e.lastErr != "" {
+			log.Printf("[CHAIN WARN] %s endpoint %s unreachable: %s", p.name, e.label, e.lastErr)
+		} else if wasHealthy && !e.healthy {
+			log.Printf("[CHAIN WARN] %s endpoint %s lagging (height %d of %d)", p.name, e.label, e.height, best)
+		} else if !wasHealthy && e.healthy {
+			log.Printf("[CHAIN] %s endpoint %s healthy again (height %d)", p.name, e.label, e.height)
+		}
+	}
+	p.selectActive()
+}
+
+// bestHeight is the highest block seen by a reachable endpoint. Callers hold p.mu.
+func (p *Pool) bestHeight() uint64 {
+	var best uint64
+	for _, e := range p.endpoints {
+		if e.failures == 0 && e.height > best {
+			best = e.height
+		}
+	}
+	return best
+}
+
+// selectActive prefers the first healthy endpoint, so traffic returns to the primary once
+// it recovers. With none healthy the current one is kept. Callers hold p.mu.
+func (p *Pool) selectActive() {
+	for i, e := range p.endpoints {
+		if e.healthy {
+			if i != p.active {
+				log.Printf("[CHAIN] %s switching RPC endpoint %s -> %s", p.name, p.endpoints[p.active].label, e.label)
+				p.active = i
+			}
+			return
+		}
+	}
+}
+
+// markFailed takes an endpoint out of rotation after a transport error.
+func (p *Pool) markFailed(i int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e := p.endpoints[i]
+	e.failures++
+	e.lastErr = e.redact(err)
+	if e.healthy {
+		e.healthy = false
+		log.Printf("[CHAIN WARN] %s endpoint %s failed, failing over: %s", p.name, e.label, e.lastErr)
+	}
+	p.selectActive()
+}
+
+// order returns endpoint indexes to try: the active one, then the other healthy ones,
+// then the unhealthy ones as a last resort.
+func (p *Pool) order() []int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	idx := []int{p.active}
+	for _, healthy := range []bool{true, false} {
+		for i, e := range p.endpoints {
+			if i != p.active && e.healthy == healthy {
+				idx = append(idx, i)
+			}
+		}
+	}
+	return idx
+}
+
+// call runs fn against endpoints until one answers. Only transport-level failures move on
+// to the next endpoint; a node's JSON-RPC error (revert, nonce too low, ...) is final.
+func (p *Pool) call(ctx context.Context, fn func(c *rpcBackend) error) error {
+	var err error
+	for _, i := range p.order() {
+		err = fn(p.endpoints[i].client)
+		if !failoverable(err) || ctx.Err() != nil {
+			return err
+		}
+		p.markFailed(i, err)
+	}
+	return err
+}
+
+// failoverable reports whether err means the endpoint itself is unusable.
+func failoverable(err error) bool {
+	if err == nil || errors.Is(err, ethereum.NotFound) || errors.Is(err, rpc.ErrNotificationsUnsupported) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == 429 || httpErr.StatusCode >= 500
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.ErrorCode() == -32005 // limit exceeded
+	}
+	return true
+}
+
+// redact renders err without the endpoint's full URL.
+func (e *endpoint) redact(err error) string {
+	return strings.ReplaceAll(err.Error(), e.url, e.label)
+}
+
+// redactURL keeps scheme and host; provider URLs carry the API key in the path or query.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "endpoint"
+	}
+	if u.Path != "" && u.Path != "/" || u.RawQuery != "" {
+		return u.Scheme + "://" + u.Host + "/***"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// poolClient is the client the embedded backend sees; every method goes through Pool.call.
+type poolClient struct{ p *Pool }
+
+func (c poolClient) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) (code []byte, err error) {
+	err = c.p.call(ctx, func(b *rpcBackend) (e error) { code, e = b.CodeAt(ctx, account, blockNumber); return })
+	return
+}
+
+func (c poolClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) (out []byte, err error) {
+	err = c.p.call(ctx, func(b *rpcBackend) (e error) { out, e = b.CallContract(ctx, call, blockNumber); return })
+	return
+}
+
+func (c poolClient) HeaderByNumber(ctx context.Context, number *big.Int) (h *types.Header, err error) {
+	err = c.p.call(ctx, func(b *rpcBackend) (e error) { h, e = b.HeaderByNumber(ctx, number); return })
+	return
+}
+
+func (c poolClient) PendingCodeAt(ctx context.Context, account common.Address) (code []byte, err error) {
+	err = c.p.call(ctx, func(b *rpcBackend) (e error) { code, e = b.PendingCodeAt(ctx, account); return })
+	return
+}
+
+func (c poolClient) PendingNonceAt(ctx context.Context, account common.Address) (n uint64, err error) {
+	err = c.p.call(ctx, func(b *rpcBackend) (e error) { n, e = b.PendingNonceAt(ctx, account); return })
+	return
+}
+
+func (c poolClient) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (n uint64, err error) {
+	err = c.p.call(ctx, func(b *rpcBackend) (e error) { n, e = b.NonceAt(ctx, account, blockNumber); return })
+	return
+}
+
+func (c poolClient) SuggestGasPrice(ctx context.Context) (v *big.Int, err error) {
+	err = c.p.call(ctx, func(b *rpcBackend) (e error) { v, e = b.SuggestGasPrice(ctx); return })
+	return
+}
+
+func (c poolClient) SuggestGasTipCap(ctx context.Context) (v *big.Int, err error) {
+	err = c.p.call(ctx, func(b *rpcBackend) (e error) { v, e = b.SuggestGasTipCap(ctx); return })
+	return
+}
+
+func (c poolClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (gas uint64, err error) {
+	err = c.p.call(ctx, func(b *rpcBackend) (e error) { gas, e = b.EstimateGas(ctx, call); return })
+	return
+}
+
+// SendTransaction broadcasts through the pool. A node that already has the tx (because
+// the previous endpoint accepted it before failing) counts as success.
+func (c poolClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	err := c.p.call(ctx, func(b *rpcBackend) error { return b.SendTransaction(ctx, tx) })
+	if err != nil && strings.Contains(strings.ToLower(err.Error()), "already known") {
+		return nil
+	}
+	return err
+}
+
+func (c poolClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) (logs []types.Log, err error) {
+	err = c.p.call(ctx, func(b *rpcBackend) (e error) { logs, e = b.FilterLogs(ctx, q); return })
+	return
+}
+
+func (c poolClient) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (sub ethereum.Subscription, err error) {
+	err = c.p.call(ctx, func(b *rpcBackend) (e error) { sub, e = b.SubscribeFilterLogs(ctx, q, ch); return })
+	return
+}
+
+func (c poolClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (r *types.Receipt, err error) {
+	err = c.p.call(ctx, func(b *rpcBackend) (e error) { r, e = b.TransactionReceipt(ctx, txHash); return })
+	return
+}
+
+func (c poolClient) ChainID(ctx context.Context) (id *big.Int, err error) {
+	err = c.p.call(ctx, func(b *rpcBackend) (e error) { id, e = b.ChainID(ctx); return })
+	return
+}
+
+func (c poolClient) BlockNumber(ctx context.Context) (n uint64, err error) {
+	err = c.p.call(ctx, func(b *rpcBackend) (e error) { n, e = b.BlockNumber(ctx); return })
+	return
+}
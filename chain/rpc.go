@@ -0,0 +1,44 @@
+﻿package chain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// rpcBackend talks to a real node over JSON-RPC.
+type rpcBackend struct {
+	backend
+	rpc *ethclient.Client
+}
+
+// Dial connects to a node URL. A bare host:port gets an http:// scheme.
+func Dial(ctx context.Context, name, nodeURL string) (ChainBackend, error) {
+	nodeURL = strings.Trim(strings.TrimSpace(nodeURL), `"'`)
+	if nodeURL == "" {
+		return nil, fmt.Errorf("%s node URL not configured", name)
+	}
+	if !strings.HasPrefix(nodeURL, "http://") && !strings.HasPrefix(nodeURL, "https://") && !strings.HasPrefix(nodeURL, "ws://") && !strings.HasPrefix(nodeURL, "wss://") {
+		nodeURL = "http://" + nodeURL
+	}
+
+	c, err := ethclient.DialContext(ctx, nodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ethereum node %q: %w", nodeURL, err)
+	}
+	return &rpcBackend{backend: backend{client: c, name: name}, rpc: c}, nil
+}
+
+func (b *rpcBackend) Deploy(auth *bind.TransactOpts, parsed abi.ABI, bytecode []byte, params ...interface{}) (common.Address, *types.Transaction, error) {
+	return b.deploy(b, auth, parsed, bytecode, params...)
+}
+
+func (b *rpcBackend) Simulated() bool { return false }
+
+func (b *rpcBackend) Close() { b.rpc.Close() }
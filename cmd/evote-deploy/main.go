@@ -0,0 +1,240 @@
+﻿// Command evote-deploy deploys the election contracts from the artifacts embedded in the
+// deploy package and keeps a manifest per network.
+//
+//	evote-deploy [-network l2|l1|all] [-dir deployments] deploy [-force]
+//	evote-deploy [-network l2|l1|all] verify-code [-address 0x...]
+//	evote-deploy [-network l2|l1|all] status
+//
+// Node URLs, chain ids and signers come from the same environment (.env) as the server.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"MAJOR-PROJECT/chain"
+	"MAJOR-PROJECT/deploy"
+	"MAJOR-PROJECT/signer"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	network := flag.String("network", "all", "network to act on: l2, l1 or all")
+	dir := flag.String("dir", "deployments", "directory holding the <network>.json manifests")
+	envFile := flag.String("env", ".env", "environment file to load (optional)")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+	if err := godotenv.Load(*envFile); err == nil {
+		log.Printf("[OK] Loaded %s", *envFile)
+	}
+
+	networks := deploy.Networks
+	if *network != "all" {
+		n, err := deploy.LookupNetwork(*network)
+		if err != nil {
+			log.Fatalf("[ERROR] %v", err)
+		}
+		networks = []deploy.Network{n}
+	}
+
+	cmd, args := flag.Arg(0), flag.Args()[1:]
+	failed := false
+	for _, n := range networks {
+		var err error
+		switch cmd {
+		case "deploy":
+			err = runDeploy(n, *dir, args)
+		case "verify-code":
+			err = runVerify(n, *dir, args)
+		case "status":
+			err = runStatus(n, *dir)
+		default:
+			usage()
+			os.Exit(2)
+		}
+		if err != nil {
+			log.Printf("[ERROR] %s: %v", n.Layer, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: evote-deploy [-network l2|l1|all] [-dir deployments] [-env .env] <deploy|verify-code|status> [flags]\n")
+	flag.PrintDefaults()
+}
+
+func dial(n deploy.Network) (chain.ChainBackend, error) {
+	if n.NodeURL() == "" {
+		return nil, fmt.Errorf("%s_NODE_URL is not set", n.Layer)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	return chain.Dial(ctx, n.Layer, n.NodeURL())
+}
+
+// runDeploy deploys the network's contract unless the manifest's current deployment is
+// still live and matches the embedded artifact (-force deploys anyway).
+func runDeploy(n deploy.Network, dir string, args []string) error {
+	fs := flag.NewFlagSet("deploy", flag.ExitOnError)
+	force := fs.Bool("force", false, "deploy even if the current deployment matches the artifact")
+	_ = fs.Parse(args)
+
+	art, err := deploy.LoadArtifact(n.Contract)
+	if err != nil {
+		return err
+	}
+	manifest, err := deploy.LoadManifest(dir, n)
+	if err != nil {
+		return err
+	}
+	backend, err := dial(n)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	if manifest.Current != nil && !*force {
+		_, verr := deploy.VerifyCode(ctx, backend, common.HexToAddress(manifest.Current.Address), art)
+		if verr == nil {
+			log.Printf("[SKIPPED] %s %s already deployed at %s (version %s); use -force to redeploy", n.Layer, n.Contract, manifest.Current.Address, manifest.Current.Version)
+			return nil
+		}
+		log.Printf("[INFO] %s current deployment is stale: %v", n.Layer, verr)
+	}
+
+	s, err := signer.FromEnv(n.Layer)
+	if err != nil {
+		return err
+	}
+	log.Printf("[START] Deploying %s %s v%s from %s...", n.Layer, n.Contract, deploy.Version(), s.Address().Hex())
+	d, err := deploy.Deploy(ctx, backend, art, s)
+	if err != nil {
+		return err
+	}
+	manifest.ChainID = n.ChainID().String()
+	manifest.Record(*d)
+	if err := manifest.Save(dir); err != nil {
+		return fmt.Errorf("deployed at %s but failed to write manifest: %w", d.Address, err)
+	}
+	log.Printf("[%s DEPLOYED] %s at %s | tx %s | block %d | code %s", n.Layer, n.Contract, d.Address, d.TxHash, d.Block, d.CodeHash)
+	log.Printf("[INFO] Manifest written to %s; set %s=%s for the server", deploy.ManifestPath(dir, n.Name), n.AddressEnv, d.Address)
+	return nil
+}
+
+// runVerify checks the code at -address, the configured address or the manifest's
+// current deployment (in that order) against the embedded artifact.
+func runVerify(n deploy.Network, dir string, args []string) error {
+	fs := flag.NewFlagSet("verify-code", flag.ExitOnError)
+	address := fs.String("address", "", "contract address to check")
+	_ = fs.Parse(args)
+
+	addr, source, err := resolveAddress(n, dir, *address)
+	if err != nil {
+		return err
+	}
+	art, err := deploy.LoadArtifact(n.Contract)
+	if err != nil {
+		return err
+	}
+	backend, err := dial(n)
+	if err != nil {
+		return err
+	}
+	defer backend.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	hash, err := deploy.VerifyCode(ctx, backend, addr, art)
+	if err != nil {
+		return err
+	}
+	log.Printf("[OK] %s %s at %s (%s) matches v%s, code %s", n.Layer, n.Contract, addr.Hex(), source, deploy.Version(), hash.Hex())
+	return nil
+}
+
+// runStatus prints the manifest, the configured address and what is live on-chain.
+func runStatus(n deploy.Network, dir string) error {
+	art, err := deploy.LoadArtifact(n.Contract)
+	if err != nil {
+		return err
+	}
+	manifest, err := deploy.LoadManifest(dir, n)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("== %s (%s, chain %s) ==\n", n.Layer, n.Contract, n.ChainID())
+	fmt.Printf("  artifact:   v%s code %s\n", deploy.Version(), art.CodeHash().Hex())
+	if manifest.Current != nil {
+		d := manifest.Current
+		fmt.Printf("  manifest:   %s v%s tx %s block %d (%d deployments)\n", d.Address, d.Version, d.TxHash, d.Block, len(manifest.History))
+	} else {
+		fmt.Printf("  manifest:   none (%s)\n", deploy.ManifestPath(dir, n.Name))
+	}
+	if addr, ok := n.ConfiguredAddress(); ok {
+		fmt.Printf("  configured: %s=%s\n", n.AddressEnv, addr.Hex())
+	} else {
+		fmt.Printf("  configured: %s not set\n", n.AddressEnv)
+	}
+
+	addr, _, err := resolveAddress(n, dir, "")
+	if err != nil {
+		return nil // nothing deployed yet
+	}
+	backend, err := dial(n)
+	if err != nil {
+		fmt.Printf("  on-chain:   unreachable (%v)\n", err)
+		return nil
+	}
+	defer backend.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	hash, err := deploy.VerifyCode(ctx, backend, addr, art)
+	switch {
+	case err == nil:
+		fmt.Printf("  on-chain:   %s OK (code %s)\n", addr.Hex(), hash.Hex())
+	case errors.Is(err, deploy.ErrNoCode), errors.Is(err, deploy.ErrCodeMismatch):
+		fmt.Printf("  on-chain:   %s MISMATCH: %v\n", addr.Hex(), err)
+		return err
+	default:
+		fmt.Printf("  on-chain:   %s unknown: %v\n", addr.Hex(), err)
+	}
+	return nil
+}
+
+func resolveAddress(n deploy.Network, dir, explicit string) (common.Address, string, error) {
+	if explicit != "" {
+		if !common.IsHexAddress(explicit) {
+			return common.Address{}, "", fmt.Errorf("invalid address %q", explicit)
+		}
+		return common.HexToAddress(explicit), "flag", nil
+	}
+	if addr, ok := n.ConfiguredAddress(); ok {
+		return addr, n.AddressEnv, nil
+	}
+	manifest, err := deploy.LoadManifest(dir, n)
+	if err != nil {
+		return common.Address{}, "", err
+	}
+	if manifest.Current == nil {
+		return common.Address{}, "", fmt.Errorf("no address: set %s, pass -address or deploy first", n.AddressEnv)
+	}
+	return common.HexToAddress(manifest.Current.Address), "manifest", nil
+}
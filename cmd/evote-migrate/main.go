@@ -0,0 +1,150 @@
+﻿// Command evote-migrate applies the versioned data migrations of the migrate package to
+// the backend's MongoDB database and shows which ones have run.
+//
+//	evote-migrate [-uri URI] [-db NAME] status
+//	evote-migrate [-uri URI] [-db NAME] up [-to VERSION]
+//	evote-migrate [-uri URI] [-db NAME] rekey
+//
+// The URI and database default to MONGODB_URI and DB_NAME from the server's environment
+// (.env). Stop the server before running up: it rewrites documents the server writes.
+// rekey encrypts voter and student PII under the current key of FIELD_KEY_FILE and can
+// run while the server is up.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"MAJOR-PROJECT/fieldcrypt"
+	"MAJOR-PROJECT/migrate"
+
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func main() {
+	envFile := flag.String("env", ".env", "environment file to load (optional)")
+	uri := flag.String("uri", "", "MongoDB URI (default $MONGODB_URI or mongodb://localhost:27017)")
+	dbName := flag.String("db", "", "database name (default $DB_NAME or voting_system)")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+	if err := godotenv.Load(*envFile); err == nil {
+		log.Printf("[OK] Loaded %s", *envFile)
+	}
+	if *uri == "" {
+		*uri = envOr("MONGODB_URI", "mongodb://localhost:27017")
+	}
+	if *dbName == "" {
+		*dbName = envOr("DB_NAME", "voting_system")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(*uri))
+	if err == nil {
+		err = client.Ping(ctx, nil)
+	}
+	cancel()
+	if err != nil {
+		log.Fatalf("[ERROR] MongoDB connect error: %v", err)
+	}
+	defer client.Disconnect(context.Background())
+	db := client.Database(*dbName)
+
+	cmd, args := flag.Arg(0), flag.Args()[1:]
+	switch cmd {
+	case "status":
+		err = runStatus(db)
+	case "up":
+		err = runUp(db, args)
+	case "rekey":
+		err = runRekey(db)
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Printf("[ERROR] %v", err)
+		client.Disconnect(context.Background())
+		os.Exit(1)
+	}
+}
+
+func runStatus(db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	applied, pending, err := migrate.Status(ctx, db)
+	if err != nil {
+		return err
+	}
+	for _, a := range applied {
+		log.Printf("[APPLIED] %d %s at %s (%s)", a.Version, a.Name, a.AppliedAt.Format(time.RFC3339), a.Duration)
+	}
+	for _, m := range pending {
+		log.Printf("[PENDING] %d %s", m.Version, m.Name)
+	}
+	if len(pending) == 0 {
+		log.Printf("[OK] %s is up to date", db.Name())
+	}
+	return nil
+}
+
+func runUp(db *mongo.Database, args []string) error {
+	fs := flag.NewFlagSet("up", flag.ExitOnError)
+	to := fs.Int("to", 0, "apply migrations up to this version only (0 = all)")
+	_ = fs.Parse(args)
+
+	// Rewrites touch every document, so there is no fixed time limit
+	applied, err := migrate.Up(context.Background(), db, *to)
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		log.Printf("[SKIPPED] Nothing to apply; %s is up to date", db.Name())
+		return nil
+	}
+	log.Printf("[OK] Applied %d migration(s) to %s", len(applied), db.Name())
+	return nil
+}
+
+func runRekey(db *mongo.Database) error {
+	cipher, err := fieldcrypt.FromEnv()
+	if err != nil {
+		return err
+	}
+	if cipher == nil {
+		return fmt.Errorf("FIELD_KEY_FILE is not set; there is no key to encrypt with")
+	}
+	log.Printf("[START] Re-encrypting PII in %s (%s)", db.Name(), cipher.Describe())
+	results, err := migrate.Rekey(context.Background(), db, cipher)
+	if err != nil {
+		return err
+	}
+	total := 0
+	for _, r := range results {
+		total += r.Rekeyed
+	}
+	log.Printf("[OK] %d document(s) re-encrypted; keys other than %s are no longer needed", total, cipher.Current())
+	return nil
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: evote-migrate [-uri URI] [-db NAME] [-env .env] <status|up|rekey> [flags]\n")
+	flag.PrintDefaults()
+}
+
+func envOr(name, def string) string {
+	if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+		return v
+	}
+	return def
+}
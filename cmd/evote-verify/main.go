@@ -0,0 +1,359 @@
+﻿// Command evote-verify independently recounts one election from the chains and checks
+// the published numbers against it:
+//
+//	evote-verify -election 0x... [-l2 URL] [-l1 URL -archive 0x...] [-mongo URI -db NAME] [-key HEX] [-out report.json]
+//
+// Every candidate and the voter count are read from the L2 election, the winner is
+// recomputed with the contract's tally rule (most votes, ties go to the lowest candidate
+// id) and compared with the election's own winnerCandidate, the L1 archivedResults entry
+// and, when a Mongo URI is given, the backend's metadata, candidates and audit log.
+// The JSON report is signed (EIP-191) with the observer's key so it can be published.
+// The exit code is 1 when any check fails.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"MAJOR-PROJECT/bindings"
+	"MAJOR-PROJECT/chain"
+	"MAJOR-PROJECT/deploy"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/joho/godotenv"
+)
+
+// Check is one comparison in the report.
+type Check struct {
+	Name     string `json:"name"`
+	OK       bool   `json:"ok"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+	Skipped  bool   `json:"skipped,omitempty"`
+	Note     string `json:"note,omitempty"`
+}
+
+// CandidateTally is one candidate as read from L2.
+type CandidateTally struct {
+	ID    uint64 `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Votes string `json:"votes"`
+}
+
+// Report is what gets signed.
+type Report struct {
+	Election  string `json:"election"`
+	Title     string `json:"title"`
+	L2ChainID string `json:"l2_chain_id"`
+	L2Block   uint64 `json:"l2_block"`
+	Closed    bool   `json:"closed"`
+	// Election contract build, identified by its runtime code hash ("unknown" if unreleased)
+	ContractVersion string           `json:"contract_version"`
+	CodeHash        string           `json:"code_hash"`
+	Candidates      []CandidateTally `json:"candidates"`
+	TotalVotes      string           `json:"total_votes"` // sum of candidate votes
+	TotalVoters     string           `json:"total_voters"`
+	Winner          *CandidateTally  `json:"winner,omitempty"`
+	Tied            bool             `json:"tied,omitempty"`
+	Archive         *ArchivedResult  `json:"archive,omitempty"`
+	Checks          []Check          `json:"checks"`
+	Verified        bool             `json:"verified"`
+	GeneratedAt     time.Time        `json:"generated_at"`
+}
+
+// ArchivedResult is the L1 archivedResults entry.
+type ArchivedResult struct {
+	Archive      string `json:"archive"`
+	L1ChainID    string `json:"l1_chain_id"`
+	Title        string `json:"title"`
+	WinnerName   string `json:"winner_name"`
+	WinningVotes string `json:"winning_votes"`
+	TotalVoters  string `json:"total_voters"`
+	Timestamp    string `json:"timestamp"`
+}
+
+// Signature covers keccak256(EIP-191 prefix + the report JSON exactly as printed).
+type Signature struct {
+	Signer    string `json:"signer"`
+	Hash      string `json:"hash"`
+	Signature string `json:"signature"`
+	Ephemeral bool   `json:"ephemeral,omitempty"` // no key given; proves integrity, not identity
+}
+
+// SignedReport is the command's output.
+type SignedReport struct {
+	Report    json.RawMessage `json:"report"`
+	Signature Signature       `json:"signature"`
+}
+
+func main() {
+	_ = godotenv.Load()
+	electionFlag := flag.String("election", "", "election contract address (required)")
+	l2URL := flag.String("l2", os.Getenv("L2_NODE_URL"), "L2 RPC URL")
+	l1URL := flag.String("l1", os.Getenv("L1_NODE_URL"), "L1 RPC URL (optional)")
+	archiveFlag := flag.String("archive", os.Getenv("L1_ARCHIVE_CONTRACT_ADDRESS"), "L1 archive contract address")
+	mongoURI := flag.String("mongo", "", "MongoDB URI of the backend to compare against (optional)")
+	dbName := flag.String("db", envOr("DB_NAME", "voting_system"), "MongoDB database name")
+	keyHex := flag.String("key", os.Getenv("VERIFY_PRIVATE_KEY"), "hex private key that signs the report")
+	out := flag.String("out", "", "write the signed report to this file instead of stdout")
+	flag.Parse()
+
+	if !common.IsHexAddress(*electionFlag) {
+		log.Fatalf("[ERROR] -election must be a contract address")
+	}
+	election := common.HexToAddress(*electionFlag)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	l2, err := chain.Dial(ctx, "L2", *l2URL)
+	if err != nil {
+		log.Fatalf("[ERROR] %v", err)
+	}
+	defer l2.Close()
+	report, err := recount(ctx, l2, election)
+	if err != nil {
+		log.Fatalf("[ERROR] L2 recount failed: %v", err)
+	}
+	if strings.TrimSpace(*l1URL) != "" && common.IsHexAddress(*archiveFlag) {
+		l1, err := chain.Dial(ctx, "L1", *l1URL)
+		if err != nil {
+			log.Fatalf("[ERROR] %v", err)
+		}
+		defer l1.Close()
+		if err := checkArchive(ctx, report, l1, common.HexToAddress(*archiveFlag)); err != nil {
+			log.Fatalf("[ERROR] L1 archive check failed: %v", err)
+		}
+	} else {
+		report.Checks = append(report.Checks, Check{Name: "l1_archive", Skipped: true, OK: true, Note: "no -l1 / -archive given"})
+	}
+	if *mongoURI != "" {
+		if err := checkMongo(ctx, report, *mongoURI, *dbName); err != nil {
+			log.Fatalf("[ERROR] MongoDB comparison failed: %v", err)
+		}
+	}
+
+	report.Verified = true
+	for _, c := range report.Checks {
+		report.Verified = report.Verified && c.OK
+	}
+	report.GeneratedAt = time.Now().UTC()
+
+	signed, err := sign(report, *keyHex)
+	if err != nil {
+		log.Fatalf("[ERROR] signing failed: %v", err)
+	}
+	b, _ := json.MarshalIndent(signed, "", "  ")
+	if *out != "" {
+		if err := os.WriteFile(*out, append(b, '\n'), 0o644); err != nil {
+			log.Fatalf("[ERROR] %v", err)
+		}
+		log.Printf("[OK] Report written to %s", *out)
+	} else {
+		fmt.Println(string(b))
+	}
+	if !report.Verified {
+		log.Printf("[FAILED] %s does not verify", election.Hex())
+		os.Exit(1)
+	}
+	log.Printf("[OK] %s verified", election.Hex())
+}
+
+// recount reads the election at one pinned L2 block and recomputes the winner.
+func recount(ctx context.Context, l2 chain.ChainBackend, election common.Address) (*Report, error) {
+	chainID, err := l2.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	head, err := l2.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	caller, err := bindings.NewElectionCaller(election, l2)
+	if err != nil {
+		return nil, err
+	}
+	// Pin every read to the same block so votes arriving meanwhile cannot skew the totals
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(head)}
+
+	title, _, err := caller.GetElectionDetails(opts)
+	if err != nil {
+		return nil, fmt.Errorf("getElectionDetails: %w", err)
+	}
+	numCandidates, err := caller.GetNumOfCandidates(opts)
+	if err != nil {
+		return nil, fmt.Errorf("getNumOfCandidates: %w", err)
+	}
+	numVoters, err := caller.GetNumOfVoters(opts)
+	if err != nil {
+		return nil, fmt.Errorf("getNumOfVoters: %w", err)
+	}
+	code, err := l2.CodeAt(ctx, election, opts.BlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("read code: %w", err)
+	}
+	codeHash := crypto.Keccak256Hash(code)
+	version := "unknown"
+	open := true // builds before getSchedule never close on-chain
+	if v, ok := deploy.ElectionVersionByCodeHash(codeHash); ok {
+		version = v.Version
+		if v.Has("getSchedule") {
+			if _, _, open, err = caller.GetSchedule(opts); err != nil {
+				return nil, fmt.Errorf("getSchedule: %w", err)
+			}
+		}
+	} else if _, _, open, err = caller.GetSchedule(opts); err != nil {
+		return nil, fmt.Errorf("getSchedule: %w", err)
+	}
+
+	r := &Report{
+		Election:        election.Hex(),
+		Title:           title,
+		L2ChainID:       chainID.String(),
+		L2Block:         head,
+		Closed:          !open,
+		ContractVersion: version,
+		CodeHash:        codeHash.Hex(),
+		TotalVoters:     numVoters.String(),
+	}
+	total := new(big.Int)
+	var best *big.Int
+	for i := uint64(0); i < numCandidates.Uint64(); i++ {
+		name, _, _, votes, email, err := caller.GetCandidate(opts, new(big.Int).SetUint64(i))
+		if err != nil {
+			return nil, fmt.Errorf("getCandidate(%d): %w", i, err)
+		}
+		c := CandidateTally{ID: i, Name: name, Email: email, Votes: votes.String()}
+		r.Candidates = append(r.Candidates, c)
+		total.Add(total, votes)
+		// Same rule as winnerCandidate(): strictly more votes wins, so ties keep the lower id
+		switch {
+		case best == nil || votes.Cmp(best) > 0:
+			best, r.Tied = votes, false
+			winner := c
+			r.Winner = &winner
+		case votes.Cmp(best) == 0:
+			r.Tied = true
+		}
+	}
+	r.TotalVotes = total.String()
+
+	r.Checks = append(r.Checks, Check{
+		Name: "votes_sum_equals_voters", Expected: numVoters.String(), Actual: total.String(),
+		OK: total.Cmp(numVoters) == 0,
+	})
+	if r.Winner != nil {
+		// winnerCandidate is owner-only; call it as the election authority
+		authority, err := caller.ElectionAuthority(opts)
+		if err == nil {
+			opts.From = authority
+			id, werr := caller.WinnerCandidate(opts)
+			if werr == nil {
+				r.Checks = append(r.Checks, Check{
+					Name: "contract_winner", Expected: fmt.Sprint(r.Winner.ID), Actual: id.String(),
+					OK: id.Uint64() == r.Winner.ID,
+				})
+			}
+		}
+	}
+	return r, nil
+}
+
+// checkArchive compares the L1 archivedResults entry with the recount.
+func checkArchive(ctx context.Context, r *Report, l1 chain.ChainBackend, archive common.Address) error {
+	chainID, err := l1.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+	caller, err := bindings.NewBindingsCaller(archive, l1)
+	if err != nil {
+		return err
+	}
+	res, err := caller.ArchivedResults(&bind.CallOpts{Context: ctx}, common.HexToAddress(r.Election))
+	if err != nil {
+		return fmt.Errorf("archivedResults: %w", err)
+	}
+	if res.ElectionAddress == (common.Address{}) {
+		r.Checks = append(r.Checks, Check{Name: "l1_archive", OK: false, Expected: "archived result", Actual: "none", Note: "election not anchored on L1"})
+		return nil
+	}
+	r.Archive = &ArchivedResult{
+		Archive:      archive.Hex(),
+		L1ChainID:    chainID.String(),
+		Title:        res.Title,
+		WinnerName:   res.WinnerName,
+		WinningVotes: res.WinningVotes.String(),
+		TotalVoters:  res.TotalVoters.String(),
+		Timestamp:    res.Timestamp.String(),
+	}
+	r.Checks = append(r.Checks,
+		eq("l1_title", r.Title, res.Title),
+		eq("l1_total_voters", r.TotalVoters, res.TotalVoters.String()),
+	)
+	if r.Winner != nil {
+		r.Checks = append(r.Checks,
+			eq("l1_winner_name", r.Winner.Name, res.WinnerName),
+			eq("l1_winning_votes", r.Winner.Votes, res.WinningVotes.String()),
+		)
+	}
+	if !r.Closed {
+		r.Checks = append(r.Checks, Check{Name: "l1_archive_final", OK: false, Expected: "closed election", Actual: "still open", Note: "archived before the election was closed; votes may have changed since"})
+	}
+	return nil
+}
+
+func eq(name, expected, actual string) Check {
+	return Check{Name: name, Expected: expected, Actual: actual, OK: expected == actual}
+}
+
+// sign marshals the report once and signs exactly those bytes, so a verifier can
+// re-hash the "report" field as published.
+func sign(r *Report, keyHex string) (*SignedReport, error) {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	ephemeral := false
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(keyHex), "0x"))
+	if strings.TrimSpace(keyHex) == "" {
+		log.Printf("[WARN] No -key / VERIFY_PRIVATE_KEY; signing with a throwaway key")
+		key, err = crypto.GenerateKey()
+		ephemeral = true
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key: %w", err)
+	}
+	hash := accounts.TextHash(body)
+	sig, err := crypto.Sign(hash, key)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27 // personal_sign style v
+	return &SignedReport{
+		Report: body,
+		Signature: Signature{
+			Signer:    crypto.PubkeyToAddress(key.PublicKey).Hex(),
+			Hash:      hexutil.Encode(hash),
+			Signature: hexutil.Encode(sig),
+			Ephemeral: ephemeral,
+		},
+	}, nil
+}
+
+func envOr(name, def string) string {
+	if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,77 @@
+﻿package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// checkMongo compares the backend's view (what GetArchivedResults and the election pages
+// serve) with the recount: metadata title and anchoring, candidate names, and the number
+// of VOTE_CAST audit entries.
+func checkMongo(ctx context.Context, r *Report, uri, dbName string) error {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(context.Background())
+	db := client.Database(dbName)
+	// The backend stores addresses checksummed, the form r.Election already has
+	addr := r.Election
+
+	var meta struct {
+		ElectionName string `bson:"election_name"`
+		Status       string `bson:"status"`
+		AnchorTxHash string `bson:"anchor_tx_hash"`
+	}
+	err = db.Collection("election_metadata").FindOne(ctx, bson.M{"election_address": addr}).Decode(&meta)
+	switch {
+	case err == mongo.ErrNoDocuments:
+		r.Checks = append(r.Checks, Check{Name: "db_metadata", OK: false, Expected: "metadata document", Actual: "none"})
+	case err != nil:
+		return fmt.Errorf("election_metadata: %w", err)
+	default:
+		r.Checks = append(r.Checks, eq("db_title", r.Title, meta.ElectionName))
+		if r.Closed {
+			r.Checks = append(r.Checks, eq("db_status", "ENDED", meta.Status))
+		}
+		if r.Archive != nil {
+			r.Checks = append(r.Checks, Check{Name: "db_anchor_recorded", OK: meta.AnchorTxHash != "", Expected: "anchor_tx_hash", Actual: meta.AnchorTxHash})
+		}
+	}
+
+	cur, err := db.Collection("candidates").Find(ctx, bson.M{"electionAddress": addr})
+	if err != nil {
+		return fmt.Errorf("candidates: %w", err)
+	}
+	var docs []struct {
+		Name  string `bson:"name"`
+		Email string `bson:"email"`
+	}
+	if err := cur.All(ctx, &docs); err != nil {
+		return fmt.Errorf("candidates: %w", err)
+	}
+	byEmail := map[string]string{}
+	for _, d := range docs {
+		byEmail[strings.ToLower(d.Email)] = d.Name
+	}
+	for _, c := range r.Candidates {
+		name, ok := byEmail[strings.ToLower(c.Email)]
+		if !ok {
+			r.Checks = append(r.Checks, Check{Name: fmt.Sprintf("db_candidate_%d", c.ID), OK: false, Expected: c.Name, Actual: "missing"})
+			continue
+		}
+		r.Checks = append(r.Checks, eq(fmt.Sprintf("db_candidate_%d", c.ID), c.Name, name))
+	}
+
+	votes, err := db.Collection("audit_logs").CountDocuments(ctx, bson.M{"election_address": addr, "action": "VOTE_CAST"})
+	if err != nil {
+		return fmt.Errorf("audit_logs: %w", err)
+	}
+	r.Checks = append(r.Checks, eq("db_vote_cast_entries", r.TotalVoters, fmt.Sprint(votes)))
+	return nil
+}
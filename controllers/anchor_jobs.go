@@ -0,0 +1,375 @@
+﻿package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"MAJOR-PROJECT/bindings"
+	"MAJOR-PROJECT/repository"
+	"MAJOR-PROJECT/txmanager"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Anchor job states; the jobs themselves are repository.AnchorJob.
+const (
+	AnchorQueued    = repository.AnchorQueued
+	AnchorSubmitted = repository.AnchorSubmitted
+	AnchorConfirmed = repository.AnchorConfirmed
+	AnchorFailed    = repository.AnchorFailed
+)
+
+var (
+	anchorMaxAttempts   = 5
+	anchorConfirmations uint64
+)
+
+// InitAnchorJobs starts the worker over app.AnchorJobs (every ANCHOR_POLL_INTERVAL,
+// default 15s). ANCHOR_MAX_ATTEMPTS (default 5) and ANCHOR_CONFIRMATIONS (default 3,
+// 1 on a simulated L1) tune retries and finality.
+func InitAnchorJobs() {
+	if n, err := strconv.Atoi(strings.TrimSpace(os.Getenv("ANCHOR_MAX_ATTEMPTS"))); err == nil && n > 0 {
+		anchorMaxAttempts = n
+	}
+	anchorConfirmations = 3
+	if l1, err := getL1Client(); err == nil && l1.Simulated() {
+		anchorConfirmations = 1
+	}
+	if v := envBigInt("ANCHOR_CONFIRMATIONS"); v != nil && v.IsUint64() {
+		anchorConfirmations = v.Uint64()
+	}
+	interval, err := time.ParseDuration(strings.TrimSpace(os.Getenv("ANCHOR_POLL_INTERVAL")))
+	if err != nil || interval <= 0 {
+		interval = 15 * time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			processAnchorJobs()
+		}
+	}()
+	log.Printf("[ANCHOR] Worker running every %s (%d attempts, %d confirmations)", interval, anchorMaxAttempts, anchorConfirmations)
+}
+
+// enqueueAnchor (re)queues an election for anchoring. closeTxID is the L2 closeElection
+// record to wait for, if one was just sent.
+func enqueueAnchor(ctx context.Context, electionAddr, closeTxID, actor string) error {
+	if app.AnchorJobs == nil {
+		return errors.New("anchor jobs not initialized")
+	}
+	if l1Tx == nil {
+		return errors.New("L1 anchoring is not configured")
+	}
+	err := app.AnchorJobs.Queue(ctx, electionAddr, closeTxID, actor, anchorConfirmations)
+	if err == nil {
+		log.Printf("[ANCHOR] Queued %s (requested by %s)", electionAddr, actor)
+	}
+	return err
+}
+
+func updateAnchorJob(ctx context.Context, job *AnchorJob, set bson.M) {
+	set["updated_at"] = time.Now().UTC()
+	if err := app.AnchorJobs.Update(ctx, job.ElectionAddress, repository.Update{Set: set}); err != nil {
+		log.Printf("[ANCHOR ERROR] Failed to update job %s: %v", job.ElectionAddress, err)
+	}
+}
+
+// processAnchorJobs advances every due QUEUED job and every SUBMITTED job by one step.
+func processAnchorJobs() {
+	if app.AnchorJobs == nil || l1Tx == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	jobs, err := app.AnchorJobs.Due(ctx, time.Now().UTC())
+	if err != nil {
+		log.Printf("[ANCHOR ERROR] Failed to list jobs: %v", err)
+		return
+	}
+
+	for i := range jobs {
+		job := &jobs[i]
+		var err error
+		if job.Status == AnchorQueued {
+			err = submitAnchor(ctx, job)
+		} else {
+			err = trackAnchor(ctx, job)
+		}
+		if err != nil {
+			retryAnchor(ctx, job, err)
+		}
+	}
+}
+
+// errAnchorWait means the job is not ready yet; it is retried without using an attempt.
+var errAnchorWait = errors.New("waiting")
+
+// submitAnchor reads the final result from the closed L2 election and sends it to L1.
+func submitAnchor(ctx context.Context, job *AnchorJob) error {
+	addr := common.HexToAddress(job.ElectionAddress)
+
+	sched, err := readSchedule(ctx, addr)
+	if err != nil {
+		return err
+	}
+	if !sched.Closed {
+		if job.CloseTxID != "" && txStore != nil {
+			if rec, err := txStore.Get(ctx, job.CloseTxID); err == nil && rec.Status == txmanager.StatusPending {
+				return errAnchorWait
+			}
+		}
+		return errors.New("election is not closed on-chain")
+	}
+	// The archive is on L1, so no contract consumes this approval; this check is the gate
+	if err := requireApproval(ctx, addr, ActionArchive, 0, 0); err != nil {
+		if errors.Is(err, errNotApproved) {
+			return fmt.Errorf("%w: %v", errAnchorWait, err)
+		}
+		return err
+	}
+
+	title, winnerName, winningVotes, numVoters, err := readFinalResult(ctx, addr)
+	if err != nil {
+		return err
+	}
+
+	l1ArchiveAddr := strings.TrimSpace(os.Getenv("L1_ARCHIVE_CONTRACT_ADDRESS"))
+	if !common.IsHexAddress(l1ArchiveAddr) {
+		return errors.New("L1_ARCHIVE_CONTRACT_ADDRESS not set")
+	}
+	data, err := packCall(bindings.BindingsMetaData, "archiveResult", addr, title, winnerName, winningVotes, numVoters)
+	if err != nil {
+		return err
+	}
+
+	// Nonce, fee bumps and mining are owned by the L1 manager
+	rec, err := l1Tx.Send(ctx, txmanager.Request{
+		Purpose:     PurposeL1Anchor,
+		To:          common.HexToAddress(l1ArchiveAddr),
+		Data:        data,
+		Company:     companyForElection(job.ElectionAddress),
+		Election:    job.ElectionAddress,
+		FeeStrategy: feeStrategyFor(PurposeL1Anchor),
+		Payload: map[string]interface{}{
+			"election_address": job.ElectionAddress,
+			"title":            title,
+			"winner_name":      winnerName,
+			"winning_votes":    winningVotes.String(),
+			"total_voters":     numVoters.String(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	job.Attempts++
+	updateAnchorJob(ctx, job, bson.M{
+		"status":        AnchorSubmitted,
+		"attempts":      job.Attempts,
+		"tx_id":         rec.ID.Hex(),
+		"tx_hash":       rec.TxHash,
+		"title":         title,
+		"winner_name":   winnerName,
+		"winning_votes": winningVotes.String(),
+		"total_voters":  numVoters.String(),
+		"submitted_at":  now,
+		"last_error":    "",
+	})
+	log.Printf("[ANCHOR] Result for %s sent to L1 (attempt %d) at tx: %s", job.ElectionAddress, job.Attempts, rec.TxHash)
+	go LogAction(job.ElectionAddress, "L1_ANCHOR_SUBMITTED", "System", fmt.Sprintf("Archived results to L1. Tx: %s (attempt %d)", rec.TxHash, job.Attempts))
+	return nil
+}
+
+// readFinalResult reads title, winner and turnout. winnerCandidate is owner-only, so the
+// call is made as the election authority (the operator account).
+func readFinalResult(ctx context.Context, addr common.Address) (string, string, *big.Int, *big.Int, error) {
+	election, err := newElectionContract(ctx, addr)
+	if err != nil {
+		return "", "", nil, nil, err
+	}
+	callOpts := &bind.CallOpts{Context: ctx, From: l2Tx.From()}
+
+	title, err := election.title(callOpts)
+	if err != nil {
+		return "", "", nil, nil, err
+	}
+	numVoters, err := election.uint(callOpts, "getNumOfVoters")
+	if err != nil {
+		return "", "", nil, nil, err
+	}
+	winnerID, err := election.uint(callOpts, "winnerCandidate")
+	if err != nil {
+		return "", "", nil, nil, err
+	}
+	winner, err := election.candidate(callOpts, winnerID)
+	if err != nil {
+		return "", "", nil, nil, err
+	}
+	return title, winner.Name, winner.VoteCount, numVoters, nil
+}
+
+// trackAnchor follows a SUBMITTED job: waits for the L1 record to be mined, then counts
+// confirmations on the receipt (which also notices a reorg that drops the tx).
+func trackAnchor(ctx context.Context, job *AnchorJob) error {
+	rec, err := txStore.Get(ctx, job.TxID)
+	if err != nil {
+		return fmt.Errorf("L1 transaction record %s: %w", job.TxID, err)
+	}
+	switch rec.Status {
+	case txmanager.StatusPending:
+		return nil
+	case txmanager.StatusReverted, txmanager.StatusFailed:
+		return fmt.Errorf("L1 transaction %s %s: %s", rec.TxHash, strings.ToLower(rec.Status), rec.Error)
+	}
+
+	l1, err := getL1Client()
+	if err != nil {
+		return nil // keep waiting; this is not the job's fault
+	}
+	receipt, err := l1.TransactionReceipt(ctx, common.HexToHash(rec.TxHash))
+	if err != nil || receipt == nil {
+		return fmt.Errorf("receipt for %s is gone (reorg?)", rec.TxHash)
+	}
+	head, err := l1.BlockNumber(ctx)
+	if err != nil {
+		return nil
+	}
+	block := receipt.BlockNumber.Uint64()
+	confirmations := uint64(0)
+	if head >= block {
+		confirmations = head - block + 1
+	}
+
+	set := bson.M{"tx_hash": rec.TxHash, "block_number": block, "confirmations": confirmations}
+	if confirmations >= job.RequiredConfirmations {
+		now := time.Now().UTC()
+		set["status"] = AnchorConfirmed
+		set["confirmed_at"] = now
+		log.Printf("[ANCHOR SUCCESS] %s anchored in L1 block %d (%d confirmations)", job.ElectionAddress, block, confirmations)
+	}
+	updateAnchorJob(ctx, job, set)
+	return nil
+}
+
+// retryAnchor records a failed step: back to QUEUED with exponential backoff, or FAILED
+// once the attempts are used up.
+func retryAnchor(ctx context.Context, job *AnchorJob, cause error) {
+	if errors.Is(cause, errAnchorWait) {
+		set := bson.M{"next_attempt_at": time.Now().UTC().Add(30 * time.Second)}
+		if cause != errAnchorWait {
+			set["last_error"] = cause.Error() // e.g. still collecting archive approvals
+		}
+		updateAnchorJob(ctx, job, set)
+		return
+	}
+	attempts := job.Attempts
+	if job.Status == AnchorQueued {
+		attempts++ // submitted jobs counted their attempt when they were sent
+	}
+	if attempts >= anchorMaxAttempts {
+		log.Printf("[ANCHOR ERROR] %s failed after %d attempts: %v", job.ElectionAddress, attempts, cause)
+		updateAnchorJob(ctx, job, bson.M{"status": AnchorFailed, "attempts": attempts, "last_error": cause.Error()})
+		go LogAction(job.ElectionAddress, "L1_ANCHOR_FAILED", "System", fmt.Sprintf("Anchoring gave up after %d attempts: %v", attempts, cause))
+		return
+	}
+
+	backoff := time.Minute << uint(attempts)
+	if backoff > 30*time.Minute {
+		backoff = 30 * time.Minute
+	}
+	log.Printf("[ANCHOR WARN] %s attempt %d failed, retrying in %s: %v", job.ElectionAddress, attempts, backoff, cause)
+	updateAnchorJob(ctx, job, bson.M{
+		"status":          AnchorQueued,
+		"attempts":        attempts,
+		"last_error":      cause.Error(),
+		"next_attempt_at": time.Now().UTC().Add(backoff),
+	})
+}
+
+// GetAnchorStatus returns the anchoring job of an election.
+// GET /api/elections/{address}/anchor
+func (h *Handlers) GetAnchorStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	addr, err := normalizeAddrParam(mux.Vars(r)["address"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	job, err := h.AnchorJobs.Get(ctx, addr)
+	if errors.Is(err, repository.ErrNotFound) {
+		respondError(w, http.StatusNotFound, "election has not been queued for anchoring")
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to load anchor job")
+		return
+	}
+	respondJSON(w, http.StatusOK, BlockchainResponse{Status: "success", Message: "anchor status " + job.Status, Data: job})
+}
+
+// ReAnchorElection queues an ended election for anchoring again, e.g. after FAILED or to
+// overwrite a stale archive entry. The L1 gas comes out of the company's budget, so it
+// takes {"password": ...} of the company that owns the election.
+// POST /api/admin/elections/{address}/anchor
+func (h *Handlers) ReAnchorElection(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	addr, err := normalizeAddrParam(mux.Vars(r)["address"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req struct {
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	owner := h.requireElectionOwner(ctx, w, addr, req.Password)
+	if owner == "" {
+		return
+	}
+
+	if job, err := h.AnchorJobs.Get(ctx, addr); err == nil && job.Status == AnchorSubmitted {
+		respondError(w, http.StatusConflict, "an anchor transaction is already in flight: "+job.TxHash)
+		return
+	}
+	sched, err := readSchedule(ctx, common.HexToAddress(addr))
+	if err != nil {
+		respondError(w, http.StatusBadGateway, "failed to read election state: "+err.Error())
+		return
+	}
+	if !sched.Closed {
+		respondError(w, http.StatusBadRequest, "election is not closed on-chain; end it first")
+		return
+	}
+	if err := enqueueAnchor(ctx, addr, "", owner); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to queue anchoring: "+err.Error())
+		return
+	}
+	go LogAction(addr, "L1_REANCHOR_REQUESTED", owner, "Re-anchoring requested via API")
+	respondJSON(w, http.StatusOK, BlockchainResponse{Status: "success", Message: "election queued for anchoring"})
+}
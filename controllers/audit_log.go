@@ -7,36 +7,13 @@ import (
 	"log"
 	"time"
 
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-
 	"github.com/jung-kurt/gofpdf"
 )
 
-// AuditLog represents a single event in the election lifecycle
-type AuditLog struct {
-	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	ElectionAddress string             `bson:"election_address" json:"election_address"`
-	Action          string             `bson:"action" json:"action"`   // e.g. "ELECTION_CREATED", "VOTE_CAST", "ELECTION_ENDED"
-	Actor           string             `bson:"actor" json:"actor"`     // email or ID of who performed it
-	Details         string             `bson:"details" json:"details"` // extra info
-	Timestamp       time.Time          `bson:"timestamp" json:"timestamp"`
-}
-
-var auditCollection *mongo.Collection
-
-// InitAuditCollection initializes the audit_logs collection
-func InitAuditCollection(client *mongo.Client, dbName string) {
-	auditCollection = client.Database(dbName).Collection("audit_logs")
-	fmt.Println("[OK] Initialized audit_logs collection")
-}
-
 // LogAction records an event to the database
 func LogAction(electionAddr, action, actor, details string) {
-	if auditCollection == nil {
-		log.Println("[WARN] Audit collection not initialized, skipping log:", action)
+	if app.Audit == nil {
+		log.Println("[WARN] Audit log not initialized, skipping log:", action)
 		return
 	}
 
@@ -51,35 +28,56 @@ func LogAction(electionAddr, action, actor, details string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	_, err := auditCollection.InsertOne(ctx, entry)
-	if err != nil {
+	if err := app.Audit.Insert(ctx, &entry); err != nil {
 		log.Printf("[ERROR] Failed to log action %s: %v", action, err)
 	} else {
 		log.Printf("[AUDIT] [%s] %s by %s", action, details, actor)
 	}
 }
 
-// GetElectionLogs retrieves all logs for a specific election, sorted by time
-func GetElectionLogs(electionAddr string) ([]AuditLog, error) {
-	if auditCollection == nil {
-		return nil, fmt.Errorf("audit collection not initialized")
+// logChainEvent records an audit entry derived from an on-chain event. It is keyed by the
+// indexer event id, so replaying the same log never writes a second entry.
+func logChainEvent(eventID, electionAddr, action, actor, details string) error {
+	if app.Audit == nil {
+		return nil
+	}
+	entry := AuditLog{
+		ElectionAddress: electionAddr,
+		Action:          action,
+		Actor:           actor,
+		Details:         details,
+		Timestamp:       time.Now().UTC(),
+		EventID:         eventID,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
+	err := app.Audit.InsertEvent(ctx, &entry)
+	if err == nil {
+		log.Printf("[AUDIT] [%s] %s by %s", action, details, actor)
+	}
+	return err
+}
 
-	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}}) // Oldest first for timeline
-	cursor, err := auditCollection.Find(ctx, bson.M{"election_address": electionAddr}, opts)
-	if err != nil {
-		return nil, err
+// removeChainEventLogs drops the audit entries of an event whose block was reorged away.
+func removeChainEventLogs(eventID string) error {
+	if app.Audit == nil {
+		return nil
 	}
-	defer cursor.Close(ctx)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return app.Audit.DeleteEvent(ctx, eventID)
+}
 
-	var logs []AuditLog
-	if err := cursor.All(ctx, &logs); err != nil {
-		return nil, err
+// GetElectionLogs retrieves all logs for a specific election, sorted by time
+func (h *Handlers) GetElectionLogs(electionAddr string) ([]AuditLog, error) {
+	if h.Audit == nil {
+		return nil, fmt.Errorf("audit log not initialized")
 	}
-	return logs, nil
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return h.Audit.List(ctx, electionAddr)
 }
 
 // GenerateAuditLogPDF creates a PDF of the audit logs
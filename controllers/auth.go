@@ -0,0 +1,174 @@
+﻿package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"MAJOR-PROJECT/repository"
+	"MAJOR-PROJECT/txmanager"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// errBadCredentials hides whether the account or the password was wrong.
+var errBadCredentials = errors.New("invalid email/password")
+
+// respondAuthError answers a failed credential check: 401 for bad credentials, 500 when
+// the accounts could not be read.
+func respondAuthError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errBadCredentials) {
+		respondError(w, http.StatusUnauthorized, "Invalid email/password!!!")
+		return
+	}
+	respondError(w, http.StatusInternalServerError, err.Error())
+}
+
+// checkCompanyPassword returns the company when password matches its bcrypt hash.
+func (h *Handlers) checkCompanyPassword(ctx context.Context, email, password string) (*Company, error) {
+	if h.Companies == nil {
+		return nil, errors.New("company storage not initialized")
+	}
+	if strings.TrimSpace(email) == "" || password == "" {
+		return nil, errBadCredentials
+	}
+	c, err := h.Companies.GetByEmail(ctx, email)
+	if err != nil || bcrypt.CompareHashAndPassword([]byte(c.Password), []byte(password)) != nil {
+		return nil, errBadCredentials
+	}
+	return c, nil
+}
+
+// requireElectionOwner checks password against the company that owns the election, as
+// recorded in its metadata. It writes the error response itself and returns "" on
+// failure, else the owner's normalized email.
+func (h *Handlers) requireElectionOwner(ctx context.Context, w http.ResponseWriter, addr, password string) string {
+	meta, err := h.Elections.Get(ctx, addr)
+	if err != nil || meta.CompanyEmail == "" {
+		respondError(w, http.StatusForbidden, "election has no owning company on record")
+		return ""
+	}
+	c, err := h.checkCompanyPassword(ctx, meta.CompanyEmail, password)
+	if err != nil {
+		respondAuthError(w, err)
+		return ""
+	}
+	return txmanager.NormalizeCompany(c.Email)
+}
+
+// authRealm is sent with the 401s of GET endpoints that read HTTP Basic credentials.
+const authRealm = `Basic realm="SecureVote"`
+
+// caller is whoever signed a request with HTTP Basic credentials: a company account (the
+// admin side) or a voter. Exactly one of the two is set.
+type caller struct {
+	Company *Company
+	Voter   *Voter
+}
+
+// basicCaller resolves the request's Basic credentials, company accounts first. It
+// returns nil without an error when the request carries none, and errBadCredentials when
+// they match no account.
+func (h *Handlers) basicCaller(ctx context.Context, r *http.Request) (*caller, error) {
+	email, password, ok := r.BasicAuth()
+	if !ok {
+		return nil, nil
+	}
+	c, err := h.checkCompanyPassword(ctx, email, password)
+	if err == nil {
+		return &caller{Company: c}, nil
+	}
+	if !errors.Is(err, errBadCredentials) {
+		return nil, err
+	}
+	if h.Voters != nil && password != "" {
+		v, err := h.Voters.GetByEmail(ctx, email)
+		if err == nil && bcrypt.CompareHashAndPassword([]byte(v.Password), []byte(password)) == nil {
+			return &caller{Voter: v}, nil
+		}
+	}
+	return nil, errBadCredentials
+}
+
+// isCompany reports whether the caller is the company account email.
+func (c *caller) isCompany(email string) bool {
+	return c != nil && c.Company != nil && email != "" && strings.EqualFold(c.Company.Email, email)
+}
+
+// isVoter reports whether the caller is the voter email.
+func (c *caller) isVoter(email string) bool {
+	return c != nil && c.Voter != nil && email != "" && strings.EqualFold(c.Voter.Email, email)
+}
+
+// admin reports whether the caller signed in with a company account.
+func (c *caller) admin() bool {
+	return c != nil && c.Company != nil
+}
+
+// respondUnauthorized answers 401 and asks for Basic credentials.
+func respondUnauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", authRealm)
+	respondError(w, http.StatusUnauthorized, message)
+}
+
+// requireOwnerBasic is requireElectionOwner for GET endpoints: the owning company signs the
+// request with HTTP Basic credentials. It writes the error response itself and returns ""
+// on failure, else the owner's normalized email.
+func (h *Handlers) requireOwnerBasic(ctx context.Context, w http.ResponseWriter, r *http.Request, addr string) string {
+	meta, err := h.Elections.Get(ctx, addr)
+	if errors.Is(err, repository.ErrNotFound) {
+		respondError(w, http.StatusNotFound, "election not found")
+		return ""
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return ""
+	}
+	if meta.CompanyEmail == "" {
+		respondError(w, http.StatusForbidden, "election has no owning company on record")
+		return ""
+	}
+	who, err := h.basicCaller(ctx, r)
+	if err != nil && !errors.Is(err, errBadCredentials) {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return ""
+	}
+	if !who.admin() || !who.isCompany(meta.CompanyEmail) {
+		respondUnauthorized(w, "the owning company's credentials are required")
+		return ""
+	}
+	return txmanager.NormalizeCompany(who.Company.Email)
+}
+
+// requireCompanyBasic is requireOwnerBasic for requests that concern no election: any
+// company account signs with HTTP Basic. It answers 401 and returns "" otherwise.
+func (h *Handlers) requireCompanyBasic(ctx context.Context, w http.ResponseWriter, r *http.Request) string {
+	who, err := h.basicCaller(ctx, r)
+	if err != nil && !errors.Is(err, errBadCredentials) {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return ""
+	}
+	if !who.admin() {
+		respondUnauthorized(w, "company credentials are required")
+		return ""
+	}
+	return txmanager.NormalizeCompany(who.Company.Email)
+}
+
+// mayReadVoter reports whether the caller may see v's personal data: the voter, or a
+// company that owns one of the elections v is registered for.
+func (h *Handlers) mayReadVoter(ctx context.Context, who *caller, v *Voter) bool {
+	if who.isVoter(v.Email) {
+		return true
+	}
+	if !who.admin() || h.Elections == nil {
+		return false
+	}
+	for _, reg := range v.Registrations {
+		if meta, err := h.Elections.Get(ctx, reg.ElectionAddress); err == nil && who.isCompany(meta.CompanyEmail) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+﻿package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/sync/singleflight"
+)
+
+var errNoContractCode = errors.New("no contract code at given address")
+
+// candidateCacheEntry is one election's ballot as read at an L2 block.
+type candidateCacheEntry struct {
+	block      uint64
+	candidates []Candidate
+}
+
+// candidateCache serves GetElectionCandidates while the chain head has not moved past the
+// block an entry was read at. Indexer events (CandidateAdded, VoteCast and their reorg
+// reverts) and candidate registrations drop an entry earlier.
+var candidateCache = struct {
+	sync.Mutex
+	entries map[common.Address]*candidateCacheEntry
+	head    uint64
+	headAt  time.Time
+}{entries: make(map[common.Address]*candidateCacheEntry)}
+
+// candidateFetches collapses concurrent misses for the same election into one chain read.
+var candidateFetches singleflight.Group
+
+// headTTL bounds how often the L2 head is asked for; one block on Amoy is ~2s.
+const headTTL = time.Second
+
+// candidateFetchTimeout bounds one shared chain read. The read is not tied to any one
+// caller's request, so a caller that goes away does not fail the others waiting on it.
+const candidateFetchTimeout = 15 * time.Second
+
+// invalidateCandidates drops the cached ballot of an election.
+func invalidateCandidates(addr common.Address) {
+	candidateCache.Lock()
+	delete(candidateCache.entries, addr)
+	candidateCache.Unlock()
+}
+
+// l2Head returns the latest L2 block number, reusing it for headTTL.
+func l2Head(ctx context.Context) (uint64, error) {
+	candidateCache.Lock()
+	if time.Since(candidateCache.headAt) < headTTL {
+		h := candidateCache.head
+		candidateCache.Unlock()
+		return h, nil
+	}
+	candidateCache.Unlock()
+
+	client, err := getClient()
+	if err != nil {
+		return 0, err
+	}
+	h, err := client.BlockNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+	candidateCache.Lock()
+	candidateCache.head, candidateCache.headAt = h, time.Now()
+	candidateCache.Unlock()
+	return h, nil
+}
+
+// cachedCandidates returns the election's candidates with manifesto links and the block
+// they were read at, from the cache when the head has not moved.
+func cachedCandidates(ctx context.Context, addr common.Address) ([]Candidate, uint64, error) {
+	head, err := l2Head(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	candidateCache.Lock()
+	if e, ok := candidateCache.entries[addr]; ok && e.block >= head {
+		candidateCache.Unlock()
+		return e.candidates, e.block, nil
+	}
+	candidateCache.Unlock()
+
+	ch := candidateFetches.DoChan(fmt.Sprintf("%s@%d", addr.Hex(), head), func() (interface{}, error) {
+		fetchCtx, cancel := context.WithTimeout(context.Background(), candidateFetchTimeout)
+		defer cancel()
+		list, err := readCandidates(fetchCtx, addr, head)
+		if err != nil {
+			return nil, err
+		}
+		e := &candidateCacheEntry{block: head, candidates: list}
+		candidateCache.Lock()
+		if cur, ok := candidateCache.entries[addr]; !ok || cur.block <= head {
+			candidateCache.entries[addr] = e
+		}
+		candidateCache.Unlock()
+		return e, nil
+	})
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, 0, res.Err
+		}
+		e := res.Val.(*candidateCacheEntry)
+		return e.candidates, e.block, nil
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	}
+}
+
+// readCandidates reads the ballot at one block through the election's own ABI:
+// getAllCandidates in a single call, or one getCandidate per id on older builds.
+func readCandidates(ctx context.Context, addr common.Address, block uint64) ([]Candidate, error) {
+	election, err := newElectionContract(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	list, err := election.candidates(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(block)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch candidates from chain: %w", err)
+	}
+
+	manifestos := candidateManifestos(ctx, addr)
+	for i := range list {
+		list[i].ManifestoUrl = manifestos[strings.ToLower(list[i].Email)]
+	}
+	return list, nil
+}
+
+// candidateManifestos loads every manifesto link of an election in one query, keyed by
+// lower-cased candidate email.
+func candidateManifestos(ctx context.Context, addr common.Address) map[string]string {
+	if app.Candidates == nil {
+		return map[string]string{}
+	}
+	out, err := app.Candidates.Manifestos(ctx, addr.Hex())
+	if err != nil {
+		log.Printf("GetElectionCandidates: manifesto lookup warning for %s: %v", addr.Hex(), err)
+		return map[string]string{}
+	}
+	return out
+}
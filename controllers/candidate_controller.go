@@ -10,28 +10,12 @@ import (
 	"time"
 
 	"MAJOR-PROJECT/bindings"
+	"MAJOR-PROJECT/repository"
+	"MAJOR-PROJECT/txmanager"
 
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
-// CandidateRequest and CandidateDocument kept same as before (ensure candidateCollection init exists)
-var candidateCollection *mongo.Collection
-
-func InitCandidateCollection(client *mongo.Client, dbName string) {
-	candidateCollection = client.Database(dbName).Collection("candidates")
-
-	// Create fast lookup index for election lists
-	indexModel := mongo.IndexModel{Keys: bson.D{{Key: "electionAddress", Value: 1}, {Key: "email", Value: 1}}}
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	_, _ = candidateCollection.Indexes().CreateOne(ctx, indexModel)
-
-	fmt.Println("[OK] Initialized candidates collection with indexes")
-}
-
 type Response struct {
 	Status  string      `json:"status"`
 	Message string      `json:"message"`
@@ -46,22 +30,9 @@ type CandidateRequest struct {
 	ElectionAddress string `json:"election_address,omitempty"`
 	ManifestoUrl    string `json:"manifesto_url,omitempty"` // For JSON requests (if pre-uploaded)
 }
-type CandidateDocument struct {
-	Name            string    `bson:"name"`
-	Email           string    `bson:"email"`
-	Description     string    `bson:"description"`
-	ImageHash       string    `bson:"imageHash"`
-	ManifestoUrl    string    `bson:"manifestoUrl,omitempty"`
-	ElectionName    string    `bson:"electionName,omitempty"`
-	ElectionAddress string    `bson:"electionAddress,omitempty"`
-	TxHash          string    `bson:"txHash,omitempty"`
-	Status          string    `bson:"status"` // e.g., "submitted", "mined", "reverted"
-	CreatedAt       time.Time `bson:"createdAt"`
-	UpdatedAt       time.Time `bson:"updatedAt"`
-}
 
 // RegisterCandidate registers a candidate on-chain (using bindings.NewElection) and persists metadata.
-func RegisterCandidate(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) RegisterCandidate(w http.ResponseWriter, r *http.Request) {
 	// CORS & headers
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -99,47 +70,50 @@ func RegisterCandidate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	client, err := getClient()
-	if err != nil {
+	if l2Tx == nil {
 		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(Response{Status: "error", Message: "Failed to connect to Ethereum node: " + err.Error()})
+		_ = json.NewEncoder(w).Encode(Response{Status: "error", Message: "Transaction manager not initialized"})
 		return
 	}
-	defer client.Close()
-
-	auth, err := getAuth()
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(Response{Status: "error", Message: "Failed to create transaction signer: " + err.Error()})
-		return
-	}
-	auth.Nonce = getNextNonce(client, auth.From)
 
 	contractAddr := common.HexToAddress(req.ElectionAddress)
-	contract, err := bindings.NewElection(contractAddr, client)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(Response{Status: "error", Message: "Failed to bind to election contract: " + err.Error()})
-		return
-	}
+	req.ElectionAddress = contractAddr.Hex()
 
 	imgHash := req.ImageHash
 	if imgHash == "" {
 		imgHash = ""
 	}
 
-	tx, err := contract.AddCandidate(auth, req.Name, req.Description, imgHash, req.Email)
+	data, err := packCall(bindings.ElectionMetaData, "addCandidate", req.Name, req.Description, imgHash, req.Email)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(Response{Status: "error", Message: "Failed to encode candidate call: " + err.Error()})
+		return
+	}
+
+	rec, err := l2Tx.Send(r.Context(), txmanager.Request{
+		Purpose:     PurposeAddCandidate,
+		To:          contractAddr,
+		Data:        data,
+		Company:     companyForElection(req.ElectionAddress),
+		Election:    req.ElectionAddress,
+		FeeStrategy: feeStrategyFor(PurposeAddCandidate),
+		Payload: map[string]interface{}{
+			"election_address": req.ElectionAddress,
+			"candidate_email":  req.Email,
+		},
+	})
+	if err != nil {
+		w.WriteHeader(sendErrorStatus(err))
 		_ = json.NewEncoder(w).Encode(Response{Status: "error", Message: "Failed to register candidate on blockchain: " + err.Error()})
 		return
 	}
 
-	txHashHex := tx.Hash().Hex()
+	txHashHex := rec.TxHash
 	now := time.Now().UTC()
 
-	// Persist candidate doc to MongoDB if candidateCollection initialized (same as before)
-	if candidateCollection != nil {
+	// Persist candidate doc when storage is configured (same as before)
+	if h.Candidates != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 		doc := CandidateDocument{
@@ -151,57 +125,42 @@ func RegisterCandidate(w http.ResponseWriter, r *http.Request) {
 			ElectionName:    req.ElectionName,
 			ElectionAddress: req.ElectionAddress,
 			TxHash:          txHashHex,
-			Status:          "submitted", // will update to mined or reverted async
+			TxID:            rec.ID.Hex(),
+			Status:          "submitted", // the indexer marks it mined on CandidateAdded; the tx hook handles reverted/failed
 			CreatedAt:       now,
 			UpdatedAt:       now,
 		}
-		if _, err := candidateCollection.InsertOne(ctx, doc); err != nil {
+		if err := h.Candidates.Create(ctx, &doc); err != nil {
 			fmt.Printf("warning: failed to insert candidate into DB: %v\n", err)
 		}
+		invalidateCandidates(common.HexToAddress(req.ElectionAddress))
 	}
 
 	// Always return success immediately to prevent frontend timeout
 	_ = json.NewEncoder(w).Encode(Response{
 		Status:  "success",
 		Message: "Candidate registration transaction submitted to the blockchain.",
-		Data:    map[string]interface{}{"txHash": txHashHex},
+		Data:    map[string]interface{}{"txHash": txHashHex, "txId": rec.ID.Hex()},
 	})
 
-	// Wait for mining in the background to avoid 30s+ HTTP timeouts
-	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second) // generous timeout for Sepolia
-		defer cancel()
-		receipt, werr := bind.WaitMined(ctx, client, tx)
-		if werr != nil {
-			fmt.Printf("[ALCHEMY] WaitMined error for tx %s: %v\n", txHashHex, werr)
-			updateCandidateStatus(txHashHex, "pending")
-		} else {
-			if receipt == nil || receipt.Status != 1 {
-				fmt.Printf("[ALCHEMY] Tx %s reverted\n", txHashHex)
-				updateCandidateStatus(txHashHex, "reverted")
-			} else {
-				fmt.Printf("[ALCHEMY] Tx %s mined successfully in block %v\n", txHashHex, receipt.BlockNumber)
-				updateCandidateStatus(txHashHex, "mined")
-			}
-		}
-	}()
-
 	// Send email asynchronously using the shared email queue from voter.go or simple goroutine
 	go func() {
 		_ = sendRegistrationEmail(req.Email, req.ElectionName)
 	}()
 }
-func updateCandidateStatus(txHash, status string) {
-	if candidateCollection == nil {
-		fmt.Println("warning: candidateCollection is nil; cannot update status")
+
+// updateCandidateStatus records a candidate's ADD_CANDIDATE transaction that ended without
+// an event (reverted or failed). The tx hash is refreshed too, since fee bumps replace the
+// originally submitted one.
+func updateCandidateStatus(txID, txHash, status string) {
+	if app.Candidates == nil {
+		fmt.Println("warning: candidate storage not initialized; cannot update status")
 		return
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 6*time.Second)
 	defer cancel()
 
-	filter := bson.M{"txHash": txHash}
-	update := bson.M{"$set": bson.M{"status": status, "updatedAt": time.Now().UTC()}}
-	_, err := candidateCollection.UpdateOne(ctx, filter, update)
+	err := app.Candidates.SetStatus(ctx, repository.CandidateMatch{TxID: txID}, status, txHash)
 	if err != nil {
 		fmt.Printf("warning: failed to update candidate status for tx %s: %v\n", txHash, err)
 	}
@@ -0,0 +1,70 @@
+﻿package controllers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"MAJOR-PROJECT/chain"
+)
+
+// ChainHealth is the state of one layer's RPC endpoints.
+type ChainHealth struct {
+	Name      string                 `json:"name"`
+	Simulated bool                   `json:"simulated"`
+	Healthy   bool                   `json:"healthy"`
+	Endpoints []chain.EndpointStatus `json:"endpoints"`
+}
+
+// chainHealth reports a pooled backend from its last probes; other backends (simulated)
+// are asked for their block number directly.
+func chainHealth(b chain.ChainBackend) ChainHealth {
+	h := ChainHealth{Name: b.Name(), Simulated: b.Simulated()}
+	if pool, ok := b.(interface{ Status() []chain.EndpointStatus }); ok {
+		h.Endpoints = pool.Status()
+		for _, e := range h.Endpoints {
+			h.Healthy = h.Healthy || e.Healthy
+		}
+		return h
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	n, err := b.BlockNumber(ctx)
+	st := chain.EndpointStatus{URL: "in-process", Active: true, Healthy: err == nil, BlockNumber: n, LatencyMs: time.Since(start).Milliseconds(), LastCheck: time.Now().UTC()}
+	if err != nil {
+		st.LastError = err.Error()
+	}
+	h.Healthy = st.Healthy
+	h.Endpoints = []chain.EndpointStatus{st}
+	return h
+}
+
+// GetChainHealth lists every RPC endpoint per layer with its block height, lag, latency
+// and whether it is the one in use. Answers 503 when a configured layer has no healthy endpoint.
+// GET /health/chain
+func (h *Handlers) GetChainHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	status := http.StatusOK
+	var chains []ChainHealth
+	for _, b := range []chain.ChainBackend{l2Chain, l1Chain} {
+		if b == nil {
+			continue
+		}
+		ch := chainHealth(b)
+		if !ch.Healthy {
+			status = http.StatusServiceUnavailable
+		}
+		chains = append(chains, ch)
+	}
+	if len(chains) == 0 {
+		status = http.StatusServiceUnavailable
+	}
+
+	resp := BlockchainResponse{Status: "success", Message: "all chains healthy", Data: chains}
+	if status != http.StatusOK {
+		resp.Status, resp.Message = "error", "a chain has no healthy RPC endpoint"
+	}
+	respondJSON(w, status, resp)
+}
@@ -0,0 +1,334 @@
+﻿package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"MAJOR-PROJECT/bindings"
+	"MAJOR-PROJECT/chain"
+	"MAJOR-PROJECT/indexer"
+	"MAJOR-PROJECT/repository"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	indexStore indexer.Store
+	l2Indexer  *indexer.Indexer
+	l1Indexer  *indexer.Indexer // nil when L1 is not configured
+
+	// Filterers are only used to decode logs, so they are bound to no address/backend.
+	factoryEvents  *bindings.ElectionFactFilterer
+	electionEvents *bindings.ElectionFilterer
+	archiveEvents  *bindings.BindingsFilterer
+)
+
+// InitIndexers starts following ElectionCreated / CandidateAdded / VoteCast on L2 and
+// ResultArchived on L1, checkpointing in store (indexer.NewMongoStore, or NewMemoryStore
+// with STORAGE_BACKEND=memory). Must run after InitHandlers and InitTxManagers.
+func InitIndexers(store indexer.Store) error {
+	indexStore = store
+
+	var err error
+	if factoryEvents, err = bindings.NewElectionFactFilterer(common.Address{}, nil); err != nil {
+		return err
+	}
+	if electionEvents, err = bindings.NewElectionFilterer(common.Address{}, nil); err != nil {
+		return err
+	}
+	if archiveEvents, err = bindings.NewBindingsFilterer(common.Address{}, nil); err != nil {
+		return err
+	}
+
+	l2Client, err := getClient()
+	if err != nil {
+		return err
+	}
+	if l2Indexer, err = newIndexer(l2Client, 10); err != nil {
+		return err
+	}
+	if err := handleEvent(l2Indexer, bindings.ElectionFactMetaData, "ElectionCreated", onElectionCreatedLog, revertElectionCreatedLog); err != nil {
+		return err
+	}
+	if err := handleEvent(l2Indexer, bindings.ElectionMetaData, "CandidateAdded", onCandidateAddedLog, revertCandidateAddedLog); err != nil {
+		return err
+	}
+	if err := handleEvent(l2Indexer, bindings.ElectionMetaData, "VoteCast", onVoteCastLog, revertVoteCastLog); err != nil {
+		return err
+	}
+	l2Indexer.Start(context.Background())
+
+	if l1Client, err := getL1Client(); err == nil {
+		if l1Indexer, err = newIndexer(l1Client, 3); err != nil {
+			log.Printf("[WARN] L1 indexer disabled: %v", err)
+		} else if err := handleEvent(l1Indexer, bindings.BindingsMetaData, "ResultArchived", onResultArchivedLog, revertResultArchivedLog); err != nil {
+			log.Printf("[WARN] L1 indexer disabled: %v", err)
+		} else {
+			l1Indexer.Start(context.Background())
+		}
+	}
+
+	fmt.Println("[OK] Initialized chain indexers")
+	return nil
+}
+
+// newIndexer configures an indexer from INDEXER_<CHAIN>_START_BLOCK / _CONFIRMATIONS.
+// A simulated chain is new on every run, so it gets its own checkpoint key starting at
+// genesis; a real chain without a checkpoint starts at the current head.
+func newIndexer(backend chain.ChainBackend, defaultConfirmations uint64) (*indexer.Indexer, error) {
+	name := backend.Name()
+	cfg := indexer.Config{
+		Chain:         name,
+		Backend:       backend,
+		Store:         indexStore,
+		Confirmations: defaultConfirmations,
+	}
+	if backend.Simulated() {
+		cfg.Chain = name + "-SIM"
+		cfg.Confirmations = 0
+		if err := indexStore.ResetCheckpoint(context.Background(), cfg.Chain); err != nil {
+			return nil, err
+		}
+		return indexer.New(cfg)
+	}
+
+	if v := strings.TrimSpace(os.Getenv("INDEXER_" + name + "_CONFIRMATIONS")); v != "" {
+		if n, err := strconv.ParseUint(v, 10, 64); err == nil {
+			cfg.Confirmations = n
+		}
+	}
+	if v := envBigInt("INDEXER_" + name + "_START_BLOCK"); v != nil && v.IsUint64() {
+		cfg.StartBlock = v.Uint64()
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		head, err := backend.BlockNumber(ctx)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s head: %w", name, err)
+		}
+		cfg.StartBlock = head
+	}
+	return indexer.New(cfg)
+}
+
+func handleEvent(ix *indexer.Indexer, meta *bind.MetaData, event string, apply, revert indexer.HandlerFunc) error {
+	parsed, err := meta.GetAbi()
+	if err != nil {
+		return err
+	}
+	ev, ok := parsed.Events[event]
+	if !ok {
+		return fmt.Errorf("event %s missing from ABI", event)
+	}
+	ix.Handle(ev.ID, indexer.Handler{Name: event, Apply: apply, Revert: revert})
+	return nil
+}
+
+// isIndexedElection reports whether addr is an election created by our factory (its
+// ElectionCreated has been applied, which always happens before its own events).
+func isIndexedElection(ctx context.Context, addr common.Address) bool {
+	if app.Elections == nil {
+		return true
+	}
+	_, err := app.Elections.Get(ctx, addr.Hex())
+	return err == nil
+}
+
+// logChainReorg leaves a trail of undone events; audit logs are never silently rewritten.
+func logChainReorg(electionAddr, event string, lg types.Log) {
+	go LogAction(electionAddr, "CHAIN_REORG", "System", fmt.Sprintf("%s in block %d (tx %s) was reorged away and reverted", event, lg.BlockNumber, lg.TxHash.Hex()))
+}
+
+// ----------------------------
+// L2 HANDLERS
+// ----------------------------
+
+func onElectionCreatedLog(ctx context.Context, eventID string, lg types.Log) error {
+	_, factoryAddr, err := normalizeFactoryAddr()
+	if err != nil || lg.Address != factoryAddr {
+		return indexer.ErrSkip
+	}
+	ev, err := factoryEvents.ParseElectionCreated(lg)
+	if err != nil {
+		return err
+	}
+
+	addrHex := ev.Election.Hex()
+	companyID := hexutil.Encode(ev.CompanyId[:])
+	companyEmail := companyEmailForID(ctx, ev.CompanyId, lg.TxHash)
+	log.Printf("[INDEXER] ElectionCreated #%s %s for company %s", ev.Id, addrHex, companyID)
+	EnsureMetadata(addrHex, companyEmail, ev.Name, ev.Description)
+	if app.Elections != nil {
+		set := map[string]interface{}{"election_id": ev.Id.Uint64(), "company_id": companyID}
+		// The contract owns the voting window; start from its values, not the metadata defaults
+		if sched, err := readSchedule(ctx, ev.Election); err == nil {
+			set["start_date"] = sched.Start
+			set["end_date"] = sched.End
+		}
+		if v, err := electionVersion(ctx, ev.Election); err == nil {
+			set["contract_version"] = v.Version
+			set["code_hash"] = v.CodeHash.Hex()
+		}
+		if err := app.Elections.Update(ctx, addrHex, repository.Update{Set: set}); err != nil {
+			return err
+		}
+	}
+
+	actor := companyEmail
+	if actor == "" {
+		actor = companyID
+	}
+	return logChainEvent(eventID, addrHex, "ELECTION_CREATED", actor, fmt.Sprintf("Created election #%s '%s'", ev.Id, ev.Name))
+}
+
+func revertElectionCreatedLog(ctx context.Context, eventID string, lg types.Log) error {
+	ev, err := factoryEvents.ParseElectionCreated(lg)
+	if err != nil {
+		return err
+	}
+	addrHex := ev.Election.Hex()
+	// Only the fields the event established are undone: the metadata doc also holds
+	// dates, approvals and settings written through the API, which outlive a reorg.
+	if app.Elections != nil {
+		err := app.Elections.Update(ctx, addrHex, repository.Update{Unset: []string{"election_id", "company_id"}})
+		if err != nil && err != repository.ErrNotFound {
+			return err
+		}
+	}
+	if err := removeChainEventLogs(eventID); err != nil {
+		return err
+	}
+	logChainReorg(addrHex, "ElectionCreated", lg)
+	return nil
+}
+
+func onCandidateAddedLog(ctx context.Context, eventID string, lg types.Log) error {
+	if !isIndexedElection(ctx, lg.Address) {
+		return indexer.ErrSkip
+	}
+	ev, err := electionEvents.ParseCandidateAdded(lg)
+	if err != nil {
+		return err
+	}
+	if err := setCandidateStatusFromLog(ctx, lg, ev.Email, "mined"); err != nil {
+		return err
+	}
+	invalidateCandidates(lg.Address)
+	return logChainEvent(eventID, lg.Address.Hex(), "CANDIDATE_ADDED", ev.Email,
+		fmt.Sprintf("Candidate '%s' registered on-chain (id %s, block %d)", ev.CandidateName, ev.CandidateID, lg.BlockNumber))
+}
+
+func revertCandidateAddedLog(ctx context.Context, eventID string, lg types.Log) error {
+	ev, err := electionEvents.ParseCandidateAdded(lg)
+	if err != nil {
+		return err
+	}
+	// Back to submitted: the tx manager still owns the tx and it will be mined again.
+	if err := setCandidateStatusFromLog(ctx, lg, ev.Email, "submitted"); err != nil {
+		return err
+	}
+	invalidateCandidates(lg.Address)
+	if err := removeChainEventLogs(eventID); err != nil {
+		return err
+	}
+	logChainReorg(lg.Address.Hex(), "CandidateAdded", lg)
+	return nil
+}
+
+// setCandidateStatusFromLog finds the candidate row for a CandidateAdded log, by the
+// managed tx that produced it when possible, else by election + email.
+func setCandidateStatusFromLog(ctx context.Context, lg types.Log, email, status string) error {
+	if app.Candidates == nil {
+		return nil
+	}
+	match := repository.CandidateMatch{ElectionAddress: lg.Address.Hex(), Email: email}
+	if txStore != nil {
+		if rec, err := txStore.Get(ctx, lg.TxHash.Hex()); err == nil {
+			match = repository.CandidateMatch{TxID: rec.ID.Hex()}
+		}
+	}
+	return app.Candidates.SetStatus(ctx, match, status, lg.TxHash.Hex())
+}
+
+func onVoteCastLog(ctx context.Context, eventID string, lg types.Log) error {
+	if !isIndexedElection(ctx, lg.Address) {
+		return indexer.ErrSkip
+	}
+	ev, err := electionEvents.ParseVoteCast(lg)
+	if err != nil {
+		return err
+	}
+	invalidateCandidates(lg.Address)
+	return logChainEvent(eventID, lg.Address.Hex(), "VOTE_CAST", ev.Voter,
+		fmt.Sprintf("Vote recorded on-chain in block %d (total voters %s)", lg.BlockNumber, ev.TotalVoters))
+}
+
+func revertVoteCastLog(ctx context.Context, eventID string, lg types.Log) error {
+	invalidateCandidates(lg.Address)
+	if err := removeChainEventLogs(eventID); err != nil {
+		return err
+	}
+	logChainReorg(lg.Address.Hex(), "VoteCast", lg)
+	return nil
+}
+
+// ----------------------------
+// L1 HANDLERS
+// ----------------------------
+
+func onResultArchivedLog(ctx context.Context, eventID string, lg types.Log) error {
+	archive := strings.TrimSpace(os.Getenv("L1_ARCHIVE_CONTRACT_ADDRESS"))
+	if !common.IsHexAddress(archive) || lg.Address != common.HexToAddress(archive) {
+		return indexer.ErrSkip
+	}
+	ev, err := archiveEvents.ParseResultArchived(lg)
+	if err != nil {
+		return err
+	}
+
+	electionAddr := ev.ElectionAddress.Hex()
+	if app.Elections != nil {
+		anchoredAt := time.Unix(ev.Timestamp.Int64(), 0).UTC()
+		err := app.Elections.Update(ctx, electionAddr, repository.Update{
+			Set: map[string]interface{}{"anchor_tx_hash": lg.TxHash.Hex(), "anchored_at": anchoredAt},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return logChainEvent(eventID, electionAddr, "L1_ANCHOR_CONFIRMED", "System",
+		fmt.Sprintf("Results anchored on L1. Winner: %s (%s votes). Tx: %s, block %d", ev.WinnerName, ev.WinningVotes, lg.TxHash.Hex(), lg.BlockNumber))
+}
+
+func revertResultArchivedLog(ctx context.Context, eventID string, lg types.Log) error {
+	ev, err := archiveEvents.ParseResultArchived(lg)
+	if err != nil {
+		return err
+	}
+	electionAddr := ev.ElectionAddress.Hex()
+	// Only undo the anchor this log set; a later archive of the same election stays
+	if app.Elections != nil {
+		m, err := app.Elections.Get(ctx, electionAddr)
+		if err != nil && err != repository.ErrNotFound {
+			return err
+		}
+		if err == nil && m.AnchorTxHash == lg.TxHash.Hex() {
+			if err := app.Elections.Update(ctx, electionAddr, repository.Update{Unset: []string{"anchor_tx_hash", "anchored_at"}}); err != nil {
+				return err
+			}
+		}
+	}
+	if err := removeChainEventLogs(eventID); err != nil {
+		return err
+	}
+	logChainReorg(electionAddr, "ResultArchived", lg)
+	return nil
+}
@@ -1,32 +1,22 @@
 ﻿package controllers
 
 import (
-	"MAJOR-PROJECT/bindings"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
-	"os"
 	"time"
 
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	"github.com/ethereum/go-ethereum/common"
+	"MAJOR-PROJECT/repository"
+	"MAJOR-PROJECT/txmanager"
 
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 	"golang.org/x/crypto/bcrypt"
 )
 
-type Company struct {
-	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Email    string             `bson:"email" json:"email"`
-	Password string             `bson:"password" json:"-"`
-}
-
 type CompanyRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email      string `json:"email"`
+	Password   string `json:"password"`
+	ElectionID string `json:"election_id,omitempty"` // optional registry id to open after login
 }
 
 type CompanyResponse struct {
@@ -35,19 +25,6 @@ type CompanyResponse struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
-var companyCollection *mongo.Collection
-
-// Initialize company collection and unique email index
-func InitCompanyCollection(client *mongo.Client, dbName string) {
-	companyCollection = client.Database(dbName).Collection("companies")
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	_, _ = companyCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys:    bson.D{{Key: "email", Value: 1}},
-		Options: options.Index().SetUnique(true),
-	})
-}
-
 func withCompanyCORS(w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -55,7 +32,7 @@ func withCompanyCORS(w http.ResponseWriter) {
 	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 }
 
-func CreateCompany(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) CreateCompany(w http.ResponseWriter, r *http.Request) {
 	withCompanyCORS(w)
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
@@ -88,29 +65,18 @@ func CreateCompany(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newCompany := Company{Email: req.Email, Password: string(hashedPassword)}
-	result, err := companyCollection.InsertOne(ctx, newCompany)
-	if err != nil {
-		if mongoErr, ok := err.(mongo.WriteException); ok {
-			for _, we := range mongoErr.WriteErrors {
-				if we.Code == 11000 {
-					w.WriteHeader(http.StatusConflict)
-					_ = json.NewEncoder(w).Encode(CompanyResponse{Status: "error", Message: "Company already exists"})
-					return
-				}
-			}
+	created := Company{Email: txmanager.NormalizeCompany(req.Email), Password: string(hashedPassword)}
+	if err := h.Companies.Create(ctx, &created); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(CompanyResponse{Status: "error", Message: "Company already exists"})
+			return
 		}
 		w.WriteHeader(http.StatusInternalServerError)
 		_ = json.NewEncoder(w).Encode(CompanyResponse{Status: "error", Message: "Error creating company"})
 		return
 	}
 
-	var created Company
-	if err := companyCollection.FindOne(ctx, bson.M{"_id": result.InsertedID}).Decode(&created); err != nil {
-		created.ID = result.InsertedID.(primitive.ObjectID)
-		created.Email = req.Email
-	}
-
 	_ = json.NewEncoder(w).Encode(CompanyResponse{
 		Status:  "success",
 		Message: "Company added successfully!!!",
@@ -118,7 +84,7 @@ func CreateCompany(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func AuthenticateCompany(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) AuthenticateCompany(w http.ResponseWriter, r *http.Request) {
 	withCompanyCORS(w)
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
@@ -144,8 +110,8 @@ func AuthenticateCompany(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	var companyInfo Company
-	if err := companyCollection.FindOne(ctx, bson.M{"email": req.Email}).Decode(&companyInfo); err != nil {
+	companyInfo, err := h.Companies.GetByEmail(ctx, req.Email)
+	if err != nil {
 		w.WriteHeader(http.StatusUnauthorized)
 		_ = json.NewEncoder(w).Encode(CompanyResponse{Status: "error", Message: "Invalid email/password!!!"})
 		return
@@ -157,41 +123,18 @@ func AuthenticateCompany(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Try to find deployed election address from the factory contract (optional)
-	electionAddrHex := ""
-	factoryAddrStr := os.Getenv("L2_FACTORY_CONTRACT_ADDRESS")
-	if factoryAddrStr != "" {
-		// getClient is defined in election_controller.go and is in the same package
-		client, err := getClient()
-		if err == nil {
-			// ensure client closed
-			defer client.Close()
+	data := map[string]interface{}{"id": companyInfo.ID.Hex(), "email": companyInfo.Email}
 
-			// Convert string address to common.Address and instantiate ElectionFact
-			contractAddress := common.HexToAddress(factoryAddrStr)
-			factory, err := bindings.NewElectionFact(contractAddress, client)
-			if err == nil {
-				// Use call opts with request context (non-blocking/polite)
-				callOpts := &bind.CallOpts{Context: r.Context(), Pending: false}
-				elections, err := factory.GetDeployedElections(callOpts, req.Email)
-				if err == nil && len(elections) > 0 {
-					// Get the latest one (last in array)
-					latest := elections[len(elections)-1]
-					if latest.DeployedAddress != (common.Address{}) {
-						electionAddrHex = latest.DeployedAddress.Hex()
-					}
-					// TODO: Return all elections in data if frontend supports it
-				}
-				// ignore errors - just return login without election address
-			}
+	// Attach the company's elections from the registry (optional). election_address is only
+	// set when it is unambiguous: the requested election_id, or the company's only election.
+	if elections, total, err := companyElections(r.Context(), req.Email, 0, 20); err == nil {
+		data["elections"] = elections
+		data["election_count"] = total
+		if addr, err := resolveCompanyElection(r.Context(), req.Email, req.ElectionID); err == nil {
+			data["election_address"] = addr.Hex()
 		}
 	}
 
-	data := map[string]interface{}{"id": companyInfo.ID.Hex(), "email": companyInfo.Email}
-	if electionAddrHex != "" {
-		data["election_address"] = electionAddrHex
-	}
-
 	respondJSON(w, http.StatusOK, map[string]interface{}{
 		"status":  "success",
 		"message": "company found!!!",
@@ -199,72 +142,4 @@ func AuthenticateCompany(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// ClearDatabase wipes Candidates, OTPs, Audit Logs, and Election Metadata
-// mirroring logic from scripts/clear_candidates.go
-func ClearDatabase(w http.ResponseWriter, r *http.Request) {
-	withCompanyCORS(w)
-	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
-		return
-	}
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	deletedStats := make(map[string]int64)
-
-	// 1. Candidates
-	if candidateCollection != nil {
-		res, err := candidateCollection.DeleteMany(ctx, bson.M{})
-		if err == nil {
-			deletedStats["candidates"] = res.DeletedCount
-		}
-	}
-
-	// 2. OTPs
-	if otpCollection != nil {
-		res, err := otpCollection.DeleteMany(ctx, bson.M{})
-		if err == nil {
-			deletedStats["otps"] = res.DeletedCount
-		}
-	}
-
-	// 3. Audit Logs
-	if auditCollection != nil {
-		res, err := auditCollection.DeleteMany(ctx, bson.M{})
-		if err == nil {
-			deletedStats["audit_logs"] = res.DeletedCount
-		}
-	}
-
-	// 4. Election Metadata
-	if metadataCollection != nil {
-		res, err := metadataCollection.DeleteMany(ctx, bson.M{})
-		if err == nil {
-			deletedStats["election_metadata"] = res.DeletedCount
-		}
-	}
-
-	// 5. Clear voter registrations (remove election links from voter accounts, but keep the voter accounts)
-	//    This ensures voters no longer see stale elections on their dashboard after a reset.
-	if voterCollection != nil {
-		res, err := voterCollection.UpdateMany(ctx, bson.M{}, bson.M{
-			"$unset": bson.M{"registrations": ""},
-		})
-		if err == nil {
-			deletedStats["voter_registrations_cleared"] = res.ModifiedCount
-		}
-	}
-
-	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"status":  "success",
-		"message": "Database cleared successfully (Candidates, OTPs, Logs, Metadata, Voter Registrations)",
-		"data":    deletedStats,
-	})
-}
-
 // Helper functions removed as they are already defined in election_controller.go
@@ -0,0 +1,348 @@
+﻿package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"MAJOR-PROJECT/bindings"
+	"MAJOR-PROJECT/deploy"
+	"MAJOR-PROJECT/repository"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Elections keep the code they were deployed with, so one backend serves several Election
+// builds at once. Every read goes through electionContract, which binds the ABI of the
+// address's build (deploy.ElectionVersions) and falls back where an older build lacks a
+// view: pre-1.0 elections have no on-chain window, so election_metadata is their schedule.
+
+// electionVersions maps an election address to its build. Deployed code never changes, so
+// entries are never invalidated.
+var electionVersions = struct {
+	sync.Mutex
+	m map[common.Address]*deploy.ElectionVersion
+}{m: make(map[common.Address]*deploy.ElectionVersion)}
+
+// electionVersion identifies the build at addr by the hash of its runtime code. Code that
+// matches no release is treated as the current build and reported as "unknown".
+func electionVersion(ctx context.Context, addr common.Address) (*deploy.ElectionVersion, error) {
+	electionVersions.Lock()
+	v, ok := electionVersions.m[addr]
+	electionVersions.Unlock()
+	if ok {
+		return v, nil
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return nil, err
+	}
+	code, err := client.CodeAt(ctx, addr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect contract code: %w", err)
+	}
+	if len(code) == 0 {
+		return nil, errNoContractCode
+	}
+	v, err = versionForCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	electionVersions.Lock()
+	electionVersions.m[addr] = v
+	electionVersions.Unlock()
+	return v, nil
+}
+
+func versionForCode(code []byte) (*deploy.ElectionVersion, error) {
+	hash := crypto.Keccak256Hash(code)
+	if v, ok := deploy.ElectionVersionByCodeHash(hash); ok {
+		return v, nil
+	}
+	current, err := deploy.CurrentElection()
+	if err != nil {
+		return nil, err
+	}
+	return &deploy.ElectionVersion{Version: "unknown", CodeHash: hash, ABI: current.ABI}, nil
+}
+
+// recordContractVersion stores the build of an election in its metadata.
+func recordContractVersion(ctx context.Context, addr common.Address, v *deploy.ElectionVersion) error {
+	if app.Elections == nil {
+		return nil
+	}
+	return app.Elections.Update(ctx, addr.Hex(), repository.Update{Set: map[string]interface{}{
+		"contract_version": v.Version,
+		"code_hash":        v.CodeHash.Hex(),
+	}})
+}
+
+// electionContract reads one election through the ABI of its own build.
+type electionContract struct {
+	addr    common.Address
+	version *deploy.ElectionVersion
+	*bind.BoundContract
+}
+
+func newElectionContract(ctx context.Context, addr common.Address) (*electionContract, error) {
+	v, err := electionVersion(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	client, err := getClient()
+	if err != nil {
+		return nil, err
+	}
+	return &electionContract{addr: addr, version: v, BoundContract: bind.NewBoundContract(addr, v.ABI, client, client, client)}, nil
+}
+
+// call invokes a view, refusing methods the build does not have.
+func (e *electionContract) call(opts *bind.CallOpts, method string, args ...interface{}) ([]interface{}, error) {
+	if !e.version.Has(method) {
+		return nil, fmt.Errorf("%s is not available on Election %s", method, e.version.Version)
+	}
+	var out []interface{}
+	if err := e.Call(opts, &out, method, args...); err != nil {
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+	return out, nil
+}
+
+// schedule returns the voting window. Builds without getSchedule never enforced one; their
+// window is whatever the admin recorded in election_metadata.
+func (e *electionContract) schedule(opts *bind.CallOpts) (*onChainSchedule, error) {
+	if !e.version.Has("getSchedule") {
+		return e.metadataSchedule(opts.Context)
+	}
+	out, err := e.call(opts, "getSchedule")
+	if err != nil {
+		return nil, err
+	}
+	return scheduleFrom(*abi.ConvertType(out[0], new(*big.Int)).(**big.Int), *abi.ConvertType(out[1], new(*big.Int)).(**big.Int), *abi.ConvertType(out[2], new(bool)).(*bool), nil)
+}
+
+func (e *electionContract) metadataSchedule(ctx context.Context) (*onChainSchedule, error) {
+	if app.Elections == nil {
+		return nil, fmt.Errorf("election %s (contract %s) has no on-chain schedule and no metadata", e.addr.Hex(), e.version.Version)
+	}
+	meta, err := app.Elections.Get(ctx, e.addr.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("election %s (contract %s) has no on-chain schedule: %w", e.addr.Hex(), e.version.Version, err)
+	}
+	return &onChainSchedule{
+		Start:  meta.StartDate,
+		End:    meta.EndDate,
+		Closed: meta.Status == "ENDED" || time.Now().UTC().After(meta.EndDate),
+	}, nil
+}
+
+// isOpen reports whether ballots are accepted right now.
+func (e *electionContract) isOpen(opts *bind.CallOpts) (bool, error) {
+	if !e.version.Has("isOpen") {
+		sched, err := e.metadataSchedule(opts.Context)
+		if err != nil {
+			return false, err
+		}
+		return !sched.Closed && !time.Now().UTC().Before(sched.Start), nil
+	}
+	out, err := e.call(opts, "isOpen")
+	if err != nil {
+		return false, err
+	}
+	return *abi.ConvertType(out[0], new(bool)).(*bool), nil
+}
+
+// candidates reads the ballot: getAllCandidates in a single call where the build has it,
+// otherwise one getCandidate per id.
+func (e *electionContract) candidates(opts *bind.CallOpts) ([]Candidate, error) {
+	if e.version.Has("getAllCandidates") {
+		out, err := e.call(opts, "getAllCandidates")
+		if err != nil {
+			return nil, err
+		}
+		all := *abi.ConvertType(out[0], new([]bindings.ElectionCandidate)).(*[]bindings.ElectionCandidate)
+		list := make([]Candidate, len(all))
+		for i, c := range all {
+			list[i] = Candidate{Name: c.CandidateName, Description: c.CandidateDescription, ImageHash: c.ImgHash, VoteCount: c.VoteCount, Email: c.Email}
+		}
+		return list, nil
+	}
+	n, err := e.uint(opts, "getNumOfCandidates")
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Candidate, 0, n.Int64())
+	for i := int64(0); i < n.Int64(); i++ {
+		c, err := e.candidate(opts, big.NewInt(i))
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, c)
+	}
+	return list, nil
+}
+
+func (e *electionContract) candidate(opts *bind.CallOpts, id *big.Int) (Candidate, error) {
+	out, err := e.call(opts, "getCandidate", id)
+	if err != nil {
+		return Candidate{}, fmt.Errorf("candidate %s: %w", id, err)
+	}
+	return Candidate{
+		Name:        *abi.ConvertType(out[0], new(string)).(*string),
+		Description: *abi.ConvertType(out[1], new(string)).(*string),
+		ImageHash:   *abi.ConvertType(out[2], new(string)).(*string),
+		VoteCount:   *abi.ConvertType(out[3], new(*big.Int)).(**big.Int),
+		Email:       *abi.ConvertType(out[4], new(string)).(*string),
+	}, nil
+}
+
+func (e *electionContract) uint(opts *bind.CallOpts, method string) (*big.Int, error) {
+	out, err := e.call(opts, method)
+	if err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int), nil
+}
+
+// title returns the election name from getElectionDetails.
+func (e *electionContract) title(opts *bind.CallOpts) (string, error) {
+	out, err := e.call(opts, "getElectionDetails")
+	if err != nil {
+		return "", err
+	}
+	return *abi.ConvertType(out[0], new(string)).(*string), nil
+}
+
+// ElectionVersionReport is the outcome of migrating one election's metadata.
+type ElectionVersionReport struct {
+	ElectionAddress string `json:"election_address"`
+	ContractVersion string `json:"contract_version,omitempty"`
+	CodeHash        string `json:"code_hash,omitempty"`
+	Current         bool   `json:"current"`
+	Readable        bool   `json:"readable"`
+	Candidates      int    `json:"candidates"`
+	Updated         bool   `json:"updated"`
+	Error           string `json:"error,omitempty"`
+}
+
+// migrateElection records the build of one election and proves it can still be read:
+// the ballot, the schedule and, once ended, the title the anchor worker needs.
+func migrateElection(ctx context.Context, meta ElectionMetadata) ElectionVersionReport {
+	rep := ElectionVersionReport{ElectionAddress: meta.ElectionAddress}
+	if !common.IsHexAddress(meta.ElectionAddress) {
+		rep.Error = "invalid election address"
+		return rep
+	}
+	addr := common.HexToAddress(meta.ElectionAddress)
+	e, err := newElectionContract(ctx, addr)
+	if err != nil {
+		rep.Error = err.Error()
+		return rep
+	}
+	rep.ContractVersion, rep.CodeHash, rep.Current = e.version.Version, e.version.CodeHash.Hex(), e.version.Current()
+
+	if meta.ContractVersion != rep.ContractVersion || meta.CodeHash != rep.CodeHash {
+		if err := recordContractVersion(ctx, addr, e.version); err != nil {
+			rep.Error = "failed to update metadata: " + err.Error()
+			return rep
+		}
+		rep.Updated = true
+	}
+
+	opts := &bind.CallOpts{Context: ctx}
+	list, err := e.candidates(opts)
+	if err != nil {
+		rep.Error = err.Error()
+		return rep
+	}
+	rep.Candidates = len(list)
+	if _, err := e.schedule(opts); err != nil {
+		rep.Error = err.Error()
+		return rep
+	}
+	if _, err := e.title(opts); err != nil {
+		rep.Error = err.Error()
+		return rep
+	}
+	rep.Readable = true
+	return rep
+}
+
+// MigrateElectionVersions records contract_version and code_hash on election metadata and
+// checks every election is still readable with the bindings this build ships. With
+// ?address= it migrates one election; ?all=true also re-checks elections already migrated.
+// POST /api/admin/elections/migrate
+func (h *Handlers) MigrateElectionVersions(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	if h.Elections == nil {
+		respondError(w, http.StatusServiceUnavailable, "database not initialized")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Minute)
+	defer cancel()
+
+	var metas []ElectionMetadata
+	if a := r.URL.Query().Get("address"); a != "" {
+		addr, err := normalizeAddrParam(a)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		meta, err := h.Elections.Get(ctx, addr)
+		if err != nil && err != repository.ErrNotFound {
+			respondError(w, http.StatusInternalServerError, "failed to list elections: "+err.Error())
+			return
+		}
+		if meta != nil {
+			metas = append(metas, *meta)
+		}
+	} else {
+		f := repository.ElectionFilter{MissingCodeHash: true}
+		if r.URL.Query().Get("all") == "true" {
+			f = repository.ElectionFilter{}
+		}
+		var err error
+		if metas, err = h.Elections.List(ctx, f); err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to list elections: "+err.Error())
+			return
+		}
+	}
+
+	reports := make([]ElectionVersionReport, 0, len(metas))
+	byVersion := map[string]int{}
+	unreadable := 0
+	for _, meta := range metas {
+		rep := migrateElection(ctx, meta)
+		if rep.ContractVersion != "" {
+			byVersion[rep.ContractVersion]++
+		}
+		if !rep.Readable {
+			unreadable++
+			log.Printf("[MIGRATE] %s not readable: %s", rep.ElectionAddress, rep.Error)
+		}
+		if rep.Updated {
+			go LogAction(rep.ElectionAddress, "CONTRACT_VERSION_RECORDED", "Admin", fmt.Sprintf("Election contract %s (code %s)", rep.ContractVersion, rep.CodeHash))
+		}
+		reports = append(reports, rep)
+	}
+	log.Printf("[MIGRATE] Checked %d elections, %d unreadable, by version: %v", len(reports), unreadable, byVersion)
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":          "success",
+		"current_version": deploy.Version(),
+		"checked":         len(reports),
+		"unreadable":      unreadable,
+		"by_version":      byVersion,
+		"elections":       reports,
+	})
+}
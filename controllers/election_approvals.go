@@ -0,0 +1,571 @@
+﻿package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"MAJOR-PROJECT/bindings"
+	"MAJOR-PROJECT/repository"
+	"MAJOR-PROJECT/txmanager"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+)
+
+// Elections with approvers configured need M-of-N approvals before they can be closed,
+// rescheduled or archived to L1. Approvals are collected through the approvals endpoint
+// and recorded on the contract (ActionApproved) and in election_metadata.approvals.
+
+// Actions that need approval.
+const (
+	ActionClose    = "close"
+	ActionSchedule = "schedule"
+	ActionArchive  = "archive"
+)
+
+// actionKinds are the action kinds the contract hashes: ACTION_CLOSE, ACTION_SCHEDULE,
+// and 3 for archive, whose approval the contract records but only submitAnchor checks.
+var actionKinds = map[string]uint8{ActionClose: 1, ActionSchedule: 2, ActionArchive: 3}
+
+const (
+	PurposeSetApprovers  = "SET_APPROVERS"
+	PurposeApproveAction = "APPROVE_ACTION"
+)
+
+// errNotApproved means the action does not have enough approvals yet.
+var errNotApproved = errors.New("not enough approvals")
+
+// addressApproverID is how the contract stores an address approver: left-padded to 32 bytes.
+func addressApproverID(addr common.Address) common.Hash {
+	return common.BytesToHash(addr.Bytes())
+}
+
+// memberApproverID is the opaque company id of an admin member, so emails stay off-chain.
+func memberApproverID(email string) common.Hash {
+	return common.Hash(CompanyID(email))
+}
+
+// actionApproval is the on-chain approval state of one action.
+type actionApproval struct {
+	Hash      common.Hash
+	Threshold uint64
+	Approvals uint64
+}
+
+// readActionApproval reads the hash approvers sign and how many have. Builds without
+// approvals report a zero threshold.
+func readActionApproval(ctx context.Context, e *electionContract, action string, start, end int64) (*actionApproval, error) {
+	kind, ok := actionKinds[action]
+	if !ok {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+	if !e.version.Has("actionHash") {
+		return &actionApproval{}, nil
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	threshold, err := e.uint(opts, "approvalThreshold")
+	if err != nil {
+		return nil, err
+	}
+	out, err := e.call(opts, "actionHash", kind, big.NewInt(start), big.NewInt(end))
+	if err != nil {
+		return nil, err
+	}
+	hash := common.Hash(out[0].([32]byte))
+	out, err = e.call(opts, "approvalCount", hash)
+	if err != nil {
+		return nil, err
+	}
+	return &actionApproval{Hash: hash, Threshold: threshold.Uint64(), Approvals: out[0].(*big.Int).Uint64()}, nil
+}
+
+// requireApproval fails with errNotApproved until the action has enough approvals.
+func requireApproval(ctx context.Context, addr common.Address, action string, start, end int64) error {
+	e, err := newElectionContract(ctx, addr)
+	if err != nil {
+		return err
+	}
+	st, err := readActionApproval(ctx, e, action, start, end)
+	if err != nil {
+		return err
+	}
+	if st.Threshold > 0 && st.Approvals < st.Threshold {
+		return fmt.Errorf("%w: %s has %d of %d (action hash %s)", errNotApproved, action, st.Approvals, st.Threshold, st.Hash.Hex())
+	}
+	return nil
+}
+
+func electionMetadata(ctx context.Context, addr common.Address) (*ElectionMetadata, error) {
+	if app.Elections == nil {
+		return nil, errors.New("database not initialized")
+	}
+	return app.Elections.Get(ctx, addr.Hex())
+}
+
+// parseDateParam accepts RFC3339 or "2006-01-02T15:04", like SetElectionDates.
+func parseDateParam(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t, err = time.Parse("2006-01-02T15:04", s)
+	}
+	return t.UTC().Truncate(time.Second), err
+}
+
+// actionWindow returns the start/end an action is approved for (zero except for schedule).
+// A schedule approval without end_date is the one resume with extend_end_date consumes:
+// the end moves out by the time spent paused, which is only known once the resume is mined.
+func actionWindow(action, startStr, endStr string) (time.Time, time.Time, error) {
+	if action != ActionSchedule {
+		return time.Time{}, time.Time{}, nil
+	}
+	start, err := parseDateParam(startStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid start_date format (use RFC3339 or YYYY-MM-DDTHH:MM)")
+	}
+	if strings.TrimSpace(endStr) == "" {
+		return start, time.Time{}, nil
+	}
+	end, err := parseDateParam(endStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid end_date format")
+	}
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, errors.New("end date must be after start date")
+	}
+	return start, end, nil
+}
+
+func windowUnix(start, end time.Time) (int64, int64) {
+	if start.IsZero() {
+		return 0, 0
+	}
+	if end.IsZero() {
+		return start.Unix(), 0
+	}
+	return start.Unix(), end.Unix()
+}
+
+// SetElectionApprovers configures the approvers and threshold of an election. The contract
+// accepts this once, before the election closes, so only the owning company may do it:
+// password is checked against the company recorded in the election's metadata.
+// PUT /api/elections/{address}/approvers
+func (h *Handlers) SetElectionApprovers(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	addrNorm, err := normalizeAddrParam(mux.Vars(r)["address"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req struct {
+		Addresses []string `json:"addresses"`
+		Members   []string `json:"members"` // admin member (company account) emails
+		Threshold uint64   `json:"threshold"`
+		Password  string   `json:"password"` // the owning company's
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	owner := h.requireElectionOwner(ctx, w, addrNorm, req.Password)
+	if owner == "" {
+		return
+	}
+
+	var approvers []ElectionApprover
+	var ids [][32]byte
+	for _, a := range req.Addresses {
+		if !common.IsHexAddress(a) {
+			respondError(w, http.StatusBadRequest, "invalid approver address: "+a)
+			return
+		}
+		addr := common.HexToAddress(a)
+		id := addressApproverID(addr)
+		approvers = append(approvers, ElectionApprover{ID: id.Hex(), Address: addr.Hex()})
+		ids = append(ids, id)
+	}
+	for _, m := range req.Members {
+		email := txmanager.NormalizeCompany(m)
+		if _, err := h.Companies.GetByEmail(ctx, email); err != nil {
+			respondError(w, http.StatusBadRequest, "unknown admin member: "+m)
+			return
+		}
+		id := memberApproverID(email)
+		approvers = append(approvers, ElectionApprover{ID: id.Hex(), Email: email})
+		ids = append(ids, id)
+	}
+	if req.Threshold == 0 || req.Threshold > uint64(len(ids)) {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("threshold must be between 1 and %d", len(ids)))
+		return
+	}
+
+	addr := common.HexToAddress(addrNorm)
+	e, err := newElectionContract(ctx, addr)
+	if err != nil {
+		respondError(w, http.StatusBadGateway, "failed to read election: "+err.Error())
+		return
+	}
+	if !e.version.Has("setApprovers") {
+		respondError(w, http.StatusBadRequest, "election contract "+e.version.Version+" does not support approvals")
+		return
+	}
+	if l2Tx == nil {
+		respondError(w, http.StatusServiceUnavailable, "transaction manager not initialized")
+		return
+	}
+	data, err := packCall(bindings.ElectionMetaData, "setApprovers", ids, new(big.Int).SetUint64(req.Threshold))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to encode setApprovers call")
+		return
+	}
+	approversJSON, _ := json.Marshal(approvers)
+	rec, err := l2Tx.Send(ctx, txmanager.Request{
+		Purpose:     PurposeSetApprovers,
+		To:          addr,
+		Data:        data,
+		Company:     owner,
+		Election:    addrNorm,
+		FeeStrategy: feeStrategyFor(PurposeSetApprovers),
+		Payload: map[string]interface{}{
+			"election_address": addrNorm,
+			"approvers":        string(approversJSON),
+			"threshold":        int64(req.Threshold),
+			"actor":            owner,
+		},
+	})
+	if err != nil {
+		log.Printf("SetElectionApprovers: send failed for %s: %v", addrNorm, err)
+		respondError(w, sendErrorStatus(err), "failed to submit setApprovers transaction: "+err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"status": "success", "message": "approvers submitted to the blockchain", "txHash": rec.TxHash, "txId": rec.ID.Hex()})
+}
+
+func onSetApproversFinal(rec *txmanager.Record, receipt *types.Receipt) {
+	electionAddr := payloadString(rec, "election_address")
+	if rec.Status != txmanager.StatusConfirmed {
+		go LogAction(electionAddr, "SET_APPROVERS_FAILED", "System", fmt.Sprintf("setApprovers %s (tx %s): %s", rec.Status, rec.TxHash, rec.Error))
+		return
+	}
+	var approvers []ElectionApprover
+	if err := json.Unmarshal([]byte(payloadString(rec, "approvers")), &approvers); err != nil {
+		log.Printf("[APPROVALS ERROR] Bad approvers payload on %s: %v", rec.ID.Hex(), err)
+		return
+	}
+	threshold, _ := payloadInt(rec, "threshold")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if app.Elections != nil && common.IsHexAddress(electionAddr) {
+		err := app.Elections.Update(ctx, electionAddr, repository.Update{Set: map[string]interface{}{
+			"approvers":          approvers,
+			"approval_threshold": threshold,
+		}})
+		if err != nil {
+			log.Printf("[APPROVALS ERROR] Failed to store approvers of %s: %v", electionAddr, err)
+		}
+	}
+	log.Printf("[APPROVALS] %s now needs %d of %d approvals (block %d)", electionAddr, threshold, len(approvers), rec.BlockNumber)
+	actor := payloadString(rec, "actor")
+	if actor == "" {
+		actor = "Admin"
+	}
+	go LogAction(electionAddr, "APPROVERS_SET", actor, fmt.Sprintf("%d of %d approvals required (tx %s)", threshold, len(approvers), rec.TxHash))
+}
+
+// GetElectionApprovals returns the approvers, the hash to sign for an action and who has
+// approved it so far.
+// GET /api/elections/{address}/approvals?action=close|schedule|archive[&start_date=&end_date=]
+func (h *Handlers) GetElectionApprovals(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	addrNorm, err := normalizeAddrParam(mux.Vars(r)["address"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	q := r.URL.Query()
+	action := q.Get("action")
+	if action == "" {
+		action = ActionClose
+	}
+	if _, ok := actionKinds[action]; !ok {
+		respondError(w, http.StatusBadRequest, "action must be close, schedule or archive")
+		return
+	}
+	start, end, err := actionWindow(action, q.Get("start_date"), q.Get("end_date"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	addr := common.HexToAddress(addrNorm)
+	e, err := newElectionContract(ctx, addr)
+	if err != nil {
+		respondError(w, http.StatusBadGateway, "failed to read election: "+err.Error())
+		return
+	}
+	s, en := windowUnix(start, end)
+	st, err := readActionApproval(ctx, e, action, s, en)
+	if err != nil {
+		respondError(w, http.StatusBadGateway, "failed to read approvals: "+err.Error())
+		return
+	}
+
+	resp := map[string]interface{}{
+		"status":      "success",
+		"action":      action,
+		"action_hash": st.Hash.Hex(),
+		"threshold":   st.Threshold,
+		"approvals":   st.Approvals,
+		"approved":    st.Approvals >= st.Threshold,
+		"approvers":   []ElectionApprover{},
+		"approved_by": []ElectionApproval{},
+	}
+	if meta, err := electionMetadata(ctx, addr); err == nil {
+		if meta.Approvers != nil {
+			resp["approvers"] = meta.Approvers
+		}
+		var by []ElectionApproval
+		for _, a := range meta.Approvals {
+			if a.ActionHash == st.Hash.Hex() {
+				by = append(by, a)
+			}
+		}
+		if by != nil {
+			resp["approved_by"] = by
+		}
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// ApproveElectionAction submits one approval. Address approvers send signature (a
+// personal_sign over action_hash from GET .../approvals); admin members send their
+// company email and password.
+// POST /api/elections/{address}/approvals
+func (h *Handlers) ApproveElectionAction(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	addrNorm, err := normalizeAddrParam(mux.Vars(r)["address"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req struct {
+		Action    string `json:"action"`
+		StartStr  string `json:"start_date"`
+		EndStr    string `json:"end_date"`
+		Signature string `json:"signature"`
+		Email     string `json:"email"`
+		Password  string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	if _, ok := actionKinds[req.Action]; !ok {
+		respondError(w, http.StatusBadRequest, "action must be close, schedule or archive")
+		return
+	}
+	start, end, err := actionWindow(req.Action, req.StartStr, req.EndStr)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	addr := common.HexToAddress(addrNorm)
+	e, err := newElectionContract(ctx, addr)
+	if err != nil {
+		respondError(w, http.StatusBadGateway, "failed to read election: "+err.Error())
+		return
+	}
+	s, en := windowUnix(start, end)
+	st, err := readActionApproval(ctx, e, req.Action, s, en)
+	if err != nil {
+		respondError(w, http.StatusBadGateway, "failed to read approvals: "+err.Error())
+		return
+	}
+	if st.Threshold == 0 {
+		respondError(w, http.StatusBadRequest, "election has no approvers configured")
+		return
+	}
+
+	// Check the approver before paying gas; the contract checks again.
+	var data []byte
+	var approverID common.Hash
+	var approver string
+	switch {
+	case req.Signature != "":
+		sig, err := hexutil.Decode(req.Signature)
+		if err != nil || len(sig) != crypto.SignatureLength {
+			respondError(w, http.StatusBadRequest, "signature must be 65 hex-encoded bytes")
+			return
+		}
+		signer, err := recoverApprover(st.Hash, sig)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid signature: "+err.Error())
+			return
+		}
+		approverID, approver = addressApproverID(signer), signer.Hex()
+		data, err = packCall(bindings.ElectionMetaData, "approve", [32]byte(st.Hash), sig)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to encode approve call")
+			return
+		}
+	case req.Email != "":
+		email := txmanager.NormalizeCompany(req.Email)
+		if _, err := h.checkCompanyPassword(ctx, email, req.Password); err != nil {
+			respondAuthError(w, err)
+			return
+		}
+		approverID, approver = memberApproverID(email), email
+		data, err = packCall(bindings.ElectionMetaData, "approveMember", [32]byte(st.Hash), [32]byte(approverID))
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to encode approveMember call")
+			return
+		}
+	default:
+		respondError(w, http.StatusBadRequest, "signature or email/password required")
+		return
+	}
+
+	opts := &bind.CallOpts{Context: ctx}
+	if out, err := e.call(opts, "isApprover", [32]byte(approverID)); err != nil || !out[0].(bool) {
+		respondError(w, http.StatusForbidden, approver+" is not an approver of this election")
+		return
+	}
+	if out, err := e.call(opts, "approvedBy", [32]byte(st.Hash), [32]byte(approverID)); err == nil && out[0].(bool) {
+		respondError(w, http.StatusConflict, approver+" has already approved this action")
+		return
+	}
+	if l2Tx == nil {
+		respondError(w, http.StatusServiceUnavailable, "transaction manager not initialized")
+		return
+	}
+
+	payload := map[string]interface{}{
+		"election_address": addrNorm,
+		"action":           req.Action,
+		"action_hash":      st.Hash.Hex(),
+		"approver_id":      approverID.Hex(),
+		"approver":         approver,
+	}
+	if !start.IsZero() {
+		payload["start"], payload["end"] = start.Unix(), end.Unix()
+	}
+	rec, err := l2Tx.Send(ctx, txmanager.Request{
+		Purpose:     PurposeApproveAction,
+		To:          addr,
+		Data:        data,
+		Company:     companyForElection(addrNorm),
+		Election:    addrNorm,
+		FeeStrategy: feeStrategyFor(PurposeApproveAction),
+		Payload:     payload,
+	})
+	if err != nil {
+		log.Printf("ApproveElectionAction: send failed for %s: %v", addrNorm, err)
+		respondError(w, sendErrorStatus(err), "failed to submit approval: "+err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":      "success",
+		"message":     "approval submitted to the blockchain",
+		"action_hash": st.Hash.Hex(),
+		"approvals":   st.Approvals,
+		"threshold":   st.Threshold,
+		"txHash":      rec.TxHash,
+		"txId":        rec.ID.Hex(),
+	})
+}
+
+// recoverApprover returns the address that personal_signed the action hash.
+func recoverApprover(hash common.Hash, sig []byte) (common.Address, error) {
+	sig = append([]byte(nil), sig...)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash(hash.Bytes()), sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+func onApproveFinal(rec *txmanager.Record, receipt *types.Receipt) {
+	electionAddr := payloadString(rec, "election_address")
+	action := payloadString(rec, "action")
+	approver := payloadString(rec, "approver")
+	if rec.Status != txmanager.StatusConfirmed || receipt == nil {
+		go LogAction(electionAddr, "APPROVAL_FAILED", approver, fmt.Sprintf("%s approval %s (tx %s): %s", action, rec.Status, rec.TxHash, rec.Error))
+		return
+	}
+
+	approval := ElectionApproval{
+		Action:     action,
+		ActionHash: payloadString(rec, "action_hash"),
+		ApproverID: payloadString(rec, "approver_id"),
+		Approver:   approver,
+		TxHash:     rec.TxHash,
+		ApprovedAt: time.Now().UTC(),
+	}
+	for _, lg := range receipt.Logs {
+		if lg == nil || electionEvents == nil {
+			continue
+		}
+		if ev, err := electionEvents.ParseActionApproved(*lg); err == nil {
+			approval.Count = ev.Approvals.Uint64()
+		}
+	}
+	if s, ok := payloadInt(rec, "start"); ok {
+		t := time.Unix(s, 0).UTC()
+		approval.Start = &t
+	}
+	if en, ok := payloadInt(rec, "end"); ok {
+		t := time.Unix(en, 0).UTC()
+		approval.End = &t
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if app.Elections != nil && common.IsHexAddress(electionAddr) {
+		if err := app.Elections.Update(ctx, electionAddr, repository.Update{Push: map[string]interface{}{"approvals": approval}}); err != nil {
+			log.Printf("[APPROVALS ERROR] Failed to record approval of %s: %v", electionAddr, err)
+		}
+	}
+	log.Printf("[APPROVALS] %s approved %s on %s (%d so far)", approver, action, electionAddr, approval.Count)
+	go LogAction(electionAddr, "ACTION_APPROVED", approver, fmt.Sprintf("Approved %s (%d so far, tx %s)", strings.ToUpper(action), approval.Count, rec.TxHash))
+}
+
+// payloadInt reads a numeric payload field, which comes back from Mongo as int32, int64
+// or float64 depending on its size.
+func payloadInt(rec *txmanager.Record, key string) (int64, bool) {
+	switch v := rec.Payload[key].(type) {
+	case int64:
+		return v, true
+	case int32:
+		return int64(v), true
+	case int:
+		return int64(v), true
+	case float64:
+		return int64(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,132 @@
+﻿package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestApprovalsReachThreshold(t *testing.T) {
+	h := newTestHandlers(t)
+	ctx := context.Background()
+	for _, c := range []string{"owner@example.com", "m1@example.com", "m2@example.com", "outsider@example.com"} {
+		registerCompany(t, h, c, c[:len(c)-len("@example.com")]+"-pw")
+	}
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := createTestElection(t, h, "owner@example.com", "Approvals")
+	path := "/elections/" + addr
+
+	// One address approver and two admin members, any two of them.
+	setApprovers := func(threshold int, password string) *httptest.ResponseRecorder {
+		body := map[string]interface{}{
+			"addresses": []string{crypto.PubkeyToAddress(key.PublicKey).Hex()},
+			"members":   []string{"m1@example.com", "m2@example.com"},
+			"threshold": threshold,
+			"password":  password,
+		}
+		return serve(t, h.SetElectionApprovers, http.MethodPut, "/elections/{address}/approvers", path+"/approvers", body, nil)
+	}
+	if rec := setApprovers(2, "m1-pw"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("approvers set by a member: got %d, want 401", rec.Code)
+	}
+	if rec := setApprovers(4, "owner-pw"); rec.Code != http.StatusBadRequest {
+		t.Errorf("threshold 4 of 3: got %d, want 400", rec.Code)
+	}
+	rec := setApprovers(2, "owner-pw")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("set approvers: status %d: %s", rec.Code, rec.Body.String())
+	}
+	confirmResponse(t, "set approvers", decodeBody(t, rec))
+
+	end := func() *httptest.ResponseRecorder {
+		return serve(t, h.EndElection, http.MethodPost, "/elections/{address}/end", path+"/end", map[string]string{"password": "owner-pw"}, nil)
+	}
+	status := func() map[string]interface{} {
+		t.Helper()
+		rec := serve(t, h.GetElectionApprovals, http.MethodGet, "/elections/{address}/approvals", path+"/approvals?action=close", nil, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("approvals: status %d: %s", rec.Code, rec.Body.String())
+		}
+		return decodeBody(t, rec)
+	}
+	approve := func(body map[string]interface{}) (int, map[string]interface{}) {
+		t.Helper()
+		body["action"] = ActionClose
+		rec := serve(t, h.ApproveElectionAction, http.MethodPost, "/elections/{address}/approvals", path+"/approvals", body, nil)
+		if rec.Code != http.StatusOK {
+			return rec.Code, nil
+		}
+		return rec.Code, decodeBody(t, rec)
+	}
+
+	st := status()
+	if st["threshold"] != float64(2) || st["approvals"] != float64(0) || st["approved"] != false {
+		t.Fatalf("before approvals: %v, want 0 of 2", st)
+	}
+	if rec := end(); rec.Code != http.StatusForbidden {
+		t.Fatalf("close with no approvals: got %d, want 403", rec.Code)
+	}
+
+	// The address approver signs the action hash.
+	hash := common.HexToHash(st["action_hash"].(string))
+	sig, err := crypto.Sign(accounts.TextHash(hash.Bytes()), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	code, body := approve(map[string]interface{}{"signature": hexutil.Encode(sig)})
+	if code != http.StatusOK {
+		t.Fatalf("signed approval: got %d", code)
+	}
+	confirmResponse(t, "signed approval", body)
+	if code, _ := approve(map[string]interface{}{"signature": hexutil.Encode(sig)}); code != http.StatusConflict {
+		t.Errorf("second signed approval: got %d, want 409", code)
+	}
+	if code, _ := approve(map[string]interface{}{"email": "outsider@example.com", "password": "outsider-pw"}); code != http.StatusForbidden {
+		t.Errorf("approval by a non-approver: got %d, want 403", code)
+	}
+	if code, _ := approve(map[string]interface{}{"email": "m1@example.com", "password": "wrong"}); code != http.StatusUnauthorized {
+		t.Errorf("member approval with a wrong password: got %d, want 401", code)
+	}
+	if rec := end(); rec.Code != http.StatusForbidden {
+		t.Fatalf("close with 1 of 2 approvals: got %d, want 403", rec.Code)
+	}
+
+	// An admin member's approval reaches the threshold.
+	code, body = approve(map[string]interface{}{"email": "m1@example.com", "password": "m1-pw"})
+	if code != http.StatusOK {
+		t.Fatalf("member approval: got %d", code)
+	}
+	confirmResponse(t, "member approval", body)
+	if st := status(); st["approvals"] != float64(2) || st["approved"] != true {
+		t.Fatalf("after two approvals: %v, want 2 of 2 and approved", st)
+	}
+	// The final hooks record who approved in the metadata.
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(20 * time.Millisecond) {
+		if by, _ := status()["approved_by"].([]interface{}); len(by) == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("approved_by never listed both approvers: %v", status()["approved_by"])
+		}
+	}
+
+	rec = end()
+	if rec.Code != http.StatusOK {
+		t.Fatalf("close with 2 of 2 approvals: status %d: %s", rec.Code, rec.Body.String())
+	}
+	confirmResponse(t, "close", decodeBody(t, rec))
+	if meta, err := h.Elections.Get(ctx, addr); err != nil || meta.Status != "ENDED" {
+		t.Errorf("after close: %+v, %v; want ENDED", meta, err)
+	}
+}
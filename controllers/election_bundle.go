@@ -0,0 +1,119 @@
+﻿package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"MAJOR-PROJECT/bundle"
+	"MAJOR-PROJECT/repository"
+
+	"github.com/gorilla/mux"
+)
+
+// maxBundleUpload caps the body of an import request.
+const maxBundleUpload = 512 << 20
+
+func (h *Handlers) bundleSource() bundle.Source {
+	return bundle.Source{
+		Repos: &repository.Repositories{
+			Voters:     h.Voters,
+			Elections:  h.Elections,
+			Candidates: h.Candidates,
+			Audit:      h.Audit,
+			OTPs:       h.OTPs,
+			AnchorJobs: h.AnchorJobs,
+			Bundles:    h.Bundles,
+		},
+		Txs: txStore,
+	}
+}
+
+// ExportElectionBundle downloads the signed archive of one election (metadata,
+// candidates, voter roll, audit log, transactions and L1 anchor), to keep before the
+// database is cleared. The archive holds the voter roll, so only the owning company may
+// download it, signed in with HTTP Basic credentials.
+// GET /api/admin/elections/{address}/bundle
+func (h *Handlers) ExportElectionBundle(w http.ResponseWriter, r *http.Request) {
+	addr, err := normalizeAddrParam(mux.Vars(r)["address"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+	owner := h.requireOwnerBasic(ctx, w, r, addr)
+	if owner == "" {
+		return
+	}
+	var buf bytes.Buffer
+	m, err := bundle.Export(ctx, h.bundleSource(), addr, &buf)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		respondError(w, http.StatusNotFound, "election not found")
+		return
+	case errors.Is(err, bundle.ErrNoSigningKey):
+		respondError(w, http.StatusServiceUnavailable, "bundle signing is not configured: set BUNDLE_SIGNING_KEY")
+		return
+	case err != nil:
+		log.Printf("[BUNDLE ERROR] export %s: %v", addr, err)
+		respondError(w, http.StatusInternalServerError, "failed to build election bundle")
+		return
+	}
+
+	go LogAction(m.Election, "ELECTION_EXPORTED", owner, fmt.Sprintf("Signed bundle exported (%d files)", len(m.Files)))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="election-%s.zip"`, m.Election))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	_, _ = w.Write(buf.Bytes())
+}
+
+// ImportElectionBundle restores an archive made by ExportElectionBundle, on this or
+// another deployment. The body is the zip itself. The signer must be this server's
+// bundle key or listed in BUNDLE_TRUSTED_SIGNERS, and the election must not exist yet.
+// POST /api/admin/elections/import
+func (h *Handlers) ImportElectionBundle(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBundleUpload))
+	if err != nil {
+		respondError(w, http.StatusRequestEntityTooLarge, "bundle is too large or could not be read")
+		return
+	}
+	b, err := bundle.Open(data)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Minute)
+	defer cancel()
+	res, err := bundle.Import(ctx, h.bundleSource(), b, "Admin")
+	switch {
+	case errors.Is(err, bundle.ErrUntrustedSigner):
+		respondError(w, http.StatusForbidden, err.Error())
+		return
+	case errors.Is(err, bundle.ErrElectionExists):
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		log.Printf("[BUNDLE ERROR] import %s: %v", b.Manifest.Election, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{
+			"status":  "error",
+			"message": "import failed: " + err.Error(),
+			"data":    res,
+		})
+		return
+	}
+	log.Printf("[BUNDLE] Imported %s signed by %s", res.Election, res.Signer)
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "election restored from bundle",
+		"data":    res,
+	})
+}
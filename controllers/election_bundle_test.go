@@ -0,0 +1,134 @@
+﻿package controllers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"MAJOR-PROJECT/bundle"
+	"MAJOR-PROJECT/repository"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+)
+
+func useBundleKey(t *testing.T) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BUNDLE_SIGNING_KEY", hex.EncodeToString(crypto.FromECDSA(key)))
+	t.Setenv("BUNDLE_TRUSTED_SIGNERS", "")
+}
+
+// importBundle posts a raw archive to h.ImportElectionBundle.
+func importBundle(t *testing.T, h *Handlers, data []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/elections/import", h.ImportElectionBundle).Methods(http.MethodPost)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/elections/import", bytes.NewReader(data)))
+	return rec
+}
+
+// repackBundle rewrites one entry of a zip archive with edit, leaving the rest as is.
+func repackBundle(t *testing.T, archive []byte, name string, edit func([]byte) []byte) []byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Name == name {
+			data = edit(data)
+		}
+		fw, err := zw.Create(f.Name)
+		if err == nil {
+			_, err = fw.Write(data)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestElectionBundleRoundTrip(t *testing.T) {
+	useBundleKey(t)
+	ctx := context.Background()
+	src := newTestHandlers(t)
+	registerCompany(t, src, "owner@example.com", "owner-pw")
+	ownElection(t, src, "owner@example.com")
+	createVoter(t, src, "voter@example.com", "voter-pw")
+	if err := src.Candidates.Create(ctx, &repository.CandidateDocument{Name: "Ada", Email: "ada@example.com", ElectionAddress: testElection, Status: "mined"}); err != nil {
+		t.Fatal(err)
+	}
+
+	path := "/admin/elections/" + testElection + "/bundle"
+	if rec := serve(t, src.ExportElectionBundle, http.MethodGet, "/admin/elections/{address}/bundle", path, nil, nil); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("anonymous export: got %d, want 401", rec.Code)
+	}
+	rec := serve(t, src.ExportElectionBundle, http.MethodGet, "/admin/elections/{address}/bundle", path, nil, basicAuth("owner@example.com", "owner-pw"))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/zip" {
+		t.Fatalf("export: status %d: %s", rec.Code, rec.Body.String())
+	}
+	archive := rec.Body.Bytes()
+
+	// Restored into an empty deployment that trusts the same key.
+	dst := newTestHandlers(t)
+	rec = importBundle(t, dst, archive)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("import: status %d: %s", rec.Code, rec.Body.String())
+	}
+	res := decodeBody(t, rec)["data"].(map[string]interface{})
+	if res["candidates"] != float64(1) || res["voters_created"] != float64(1) || res["archived"] != true {
+		t.Errorf("import result %v, want 1 candidate, 1 voter and an archive", res)
+	}
+	meta, err := dst.Elections.Get(ctx, testElection)
+	if err != nil || meta.CompanyEmail != "owner@example.com" {
+		t.Fatalf("restored election: %+v, %v", meta, err)
+	}
+	v, err := dst.Voters.GetByEmail(ctx, "voter@example.com")
+	if err != nil || v.Password != "" || len(v.Registrations) != 1 {
+		t.Errorf("restored voter: %+v, %v; want one registration and no password", v, err)
+	}
+	if rec := importBundle(t, dst, archive); rec.Code != http.StatusConflict {
+		t.Errorf("second import: got %d, want 409", rec.Code)
+	}
+
+	// A voter slipped into the roll no longer matches the signed manifest.
+	tampered := repackBundle(t, archive, bundle.VotersFile, func(data []byte) []byte {
+		return bytes.Replace(data, []byte("voter@example.com"), []byte("eve00@example.com"), 1)
+	})
+	rec = importBundle(t, newTestHandlers(t), tampered)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "manifest hash") {
+		t.Errorf("tampered import: status %d: %s; want 400 for the hash mismatch", rec.Code, rec.Body.String())
+	}
+
+	// Another deployment with its own key does not trust this one's bundles.
+	useBundleKey(t)
+	if rec := importBundle(t, newTestHandlers(t), archive); rec.Code != http.StatusForbidden {
+		t.Errorf("untrusted signer: got %d, want 403", rec.Code)
+	}
+}
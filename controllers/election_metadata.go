@@ -13,60 +13,30 @@ import (
 	"strings"
 
 	"MAJOR-PROJECT/bindings"
+	"MAJOR-PROJECT/repository"
+	"MAJOR-PROJECT/txmanager"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/gorilla/mux"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// ElectionMetadata stores off-chain details about an election, primarily for scheduling/phases.
-type ElectionMetadata struct {
-	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	ElectionAddress string             `bson:"election_address" json:"election_address"`
-
-	// Dates for Phases
-	StartDate time.Time `bson:"start_date" json:"start_date"`
-	EndDate   time.Time `bson:"end_date" json:"end_date"`
-
-	// Status derived from time or manual override
-	Status string `bson:"status" json:"status"` // "UPCOMING", "ONGOING", "ENDED"
-
-	// Display Details
-	ElectionName string `bson:"election_name" json:"election_name"`
-	ElectionDesc string `bson:"election_desc" json:"election_desc"`
-}
-
-var metadataCollection *mongo.Collection
-
-// InitMetadataCollection initializes the collection
-func InitMetadataCollection(client *mongo.Client, dbName string) {
-	metadataCollection = client.Database(dbName).Collection("election_metadata")
-	fmt.Println("[OK] Initialized election_metadata collection")
-}
-
-// EnsureMetadata creates a default metadata entry if one doesn't exist, and stores name/desc.
-func EnsureMetadata(electionAddr, name, desc string) {
-	if metadataCollection == nil {
+// EnsureMetadata creates a default metadata entry if one doesn't exist, and stores company/name/desc.
+func EnsureMetadata(electionAddr, companyEmail, name, desc string) {
+	if app.Elections == nil {
 		return
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var meta ElectionMetadata
-	err := metadataCollection.FindOne(ctx, bson.M{"election_address": electionAddr}).Decode(&meta)
+	meta, err := app.Elections.Get(ctx, electionAddr)
 
 	now := time.Now().UTC()
 	defaultDuration := 7 * 24 * time.Hour // 7 days
 
 	switch err {
-	case mongo.ErrNoDocuments:
+	case repository.ErrNotFound:
 		// Create new
 		newMeta := ElectionMetadata{
 			ElectionAddress: electionAddr,
@@ -75,8 +45,9 @@ func EnsureMetadata(electionAddr, name, desc string) {
 			Status:          "ONGOING",
 			ElectionName:    name,
 			ElectionDesc:    desc,
+			CompanyEmail:    txmanager.NormalizeCompany(companyEmail),
 		}
-		metadataCollection.InsertOne(ctx, newMeta)
+		app.Elections.Create(ctx, &newMeta)
 		fmt.Printf("[OK] Created metadata for %s (Expires: %s)\n", electionAddr, newMeta.EndDate)
 
 	case nil:
@@ -85,49 +56,68 @@ func EnsureMetadata(electionAddr, name, desc string) {
 			fmt.Printf("[INFO] Election %s is past EndDate (%s)\n", electionAddr, meta.EndDate)
 		}
 
-		// Update Name/Desc if missing and provided
-		if (meta.ElectionName == "" && name != "") || (meta.ElectionDesc == "" && desc != "") {
-			update := bson.M{"$set": bson.M{}}
+		// Update Company/Name/Desc if missing and provided
+		if (meta.ElectionName == "" && name != "") || (meta.ElectionDesc == "" && desc != "") || (meta.CompanyEmail == "" && companyEmail != "") {
+			set := map[string]interface{}{}
+			if companyEmail != "" {
+				set["company_email"] = txmanager.NormalizeCompany(companyEmail)
+			}
 			if name != "" {
-				update["$set"].(bson.M)["election_name"] = name
+				set["election_name"] = name
 			}
 			if desc != "" {
-				update["$set"].(bson.M)["election_desc"] = desc
+				set["election_desc"] = desc
 			}
-			metadataCollection.UpdateOne(ctx, bson.M{"election_address": electionAddr}, update)
+			app.Elections.Update(ctx, electionAddr, repository.Update{Set: set})
 			fmt.Printf("[OK] Updated metadata details for %s\n", electionAddr)
 		}
 	}
 }
 
-// IsElectionActive checks if the current time is within the start/end window
-func IsElectionActive(electionAddr string) (bool, string) {
-	if metadataCollection == nil {
-		return true, ""
-	} // fallback if DB issue: allow voting
-
+// IsElectionActive checks if the current time is within the start/end window. Metadata
+// gives the friendly reason; the contract has the final say and is the only source when
+// Mongo has no metadata or is unavailable.
+func (h *Handlers) IsElectionActive(electionAddr string) (bool, string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var meta ElectionMetadata
-	err := metadataCollection.FindOne(ctx, bson.M{"election_address": electionAddr}).Decode(&meta)
-	if err != nil {
-		// if no metadata found, assume it's legacy or open
-		return true, ""
+	var meta *ElectionMetadata
+	haveMeta := false
+	if h.Elections != nil {
+		var err error
+		meta, err = h.Elections.Get(ctx, electionAddr)
+		haveMeta = err == nil
 	}
 
 	now := time.Now().UTC()
-	if now.Before(meta.StartDate) {
-		return false, fmt.Sprintf("Election has not started yet. Starts at %s UTC", meta.StartDate.Format("2006-01-02 15:04"))
+	if haveMeta {
+		if meta.Status == StatusPaused {
+			return false, "Election is paused: " + meta.PauseReason
+		}
+		if now.Before(meta.StartDate) {
+			return false, fmt.Sprintf("Election has not started yet. Starts at %s UTC", meta.StartDate.Format("2006-01-02 15:04"))
+		}
+		if now.After(meta.EndDate) || meta.Status == "ENDED" {
+			return false, "Election has ended."
+		}
 	}
-	if now.After(meta.EndDate) || meta.Status == "ENDED" {
-		return false, "Election has ended."
+
+	open, err := electionOpenOnChain(ctx, common.HexToAddress(electionAddr))
+	if err != nil {
+		log.Printf("[WARN] isOpen check failed for %s: %v", electionAddr, err)
+		if haveMeta {
+			return true, "" // vote() still enforces the window on-chain
+		}
+		return false, "Unable to verify the election schedule."
+	}
+	if !open {
+		return false, "Election is not open for voting."
 	}
 	return true, ""
 }
 
-// SetElectionDates Endpoint
-func SetElectionDates(w http.ResponseWriter, r *http.Request) {
+// SetElectionDates Endpoint. Takes the password of the company that owns the election.
+func (h *Handlers) SetElectionDates(w http.ResponseWriter, r *http.Request) {
 	writeJSONHeader(w)
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -138,6 +128,7 @@ func SetElectionDates(w http.ResponseWriter, r *http.Request) {
 		ElectionAddress string `json:"election_address"`
 		StartStr        string `json:"start_date"` // Expect RFC3339 or "2006-01-02T15:04"
 		EndStr          string `json:"end_date"`
+		Password        string `json:"password"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondError(w, http.StatusBadRequest, "Invalid JSON")
@@ -166,39 +157,63 @@ func SetElectionDates(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if end.Before(start) {
-		respondError(w, http.StatusBadRequest, "End date cannot be before start date")
+	if !end.After(start) {
+		respondError(w, http.StatusBadRequest, "End date must be after start date")
+		return
+	}
+	// The contract stores whole seconds; keep Mongo identical so the reconciler sees no drift
+	start, end = start.UTC().Truncate(time.Second), end.UTC().Truncate(time.Second)
+
+	addrNorm, err := normalizeAddrParam(req.ElectionAddress)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid election_address: "+err.Error())
 		return
 	}
 
-	// Upsert to DB
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	filter := bson.M{"election_address": req.ElectionAddress}
-	update := bson.M{
-		"$set": bson.M{
-			"start_date": start,
-			"end_date":   end,
-			"status":     "SCHEDULED", // You might want logic to auto-calc status but this is fine
-		},
+	owner := h.requireElectionOwner(ctx, w, addrNorm, req.Password)
+	if owner == "" {
+		return
+	}
+
+	// The contract enforces the window, so it is written first; Mongo follows.
+	rec, err := sendSetSchedule(ctx, common.HexToAddress(addrNorm), start, end)
+	if err != nil {
+		log.Printf("SetElectionDates: setSchedule for %s failed: %v", addrNorm, err)
+		respondError(w, sendErrorStatus(err), "Failed to update schedule on-chain: "+err.Error())
+		return
 	}
-	opts := options.Update().SetUpsert(true)
 
-	_, err = metadataCollection.UpdateOne(ctx, filter, update, opts)
+	// Upsert to DB. A paused election stays paused; only resume clears it.
+	set := map[string]interface{}{
+		"start_date": start,
+		"end_date":   end,
+		"status":     "SCHEDULED", // You might want logic to auto-calc status but this is fine
+	}
+	if meta, err := h.Elections.Get(ctx, addrNorm); err == nil && meta.Status == StatusPaused {
+		delete(set, "status")
+	}
+	err = h.Elections.Upsert(ctx, addrNorm, repository.Update{Set: set})
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to update dates")
 		return
 	}
 
 	// Log it
-	go LogAction(req.ElectionAddress, "SCHEDULE_UPDATE", "Admin", fmt.Sprintf("Dates updated: %s to %s", start, end))
+	if rec == nil {
+		go LogAction(addrNorm, "SCHEDULE_UPDATE", owner, fmt.Sprintf("Dates updated: %s to %s (metadata only)", start, end))
+		respondJSON(w, http.StatusOK, map[string]string{"status": "success", "message": "Election dates updated"})
+		return
+	}
+	go LogAction(addrNorm, "SCHEDULE_UPDATE", owner, fmt.Sprintf("Dates updated: %s to %s (tx %s)", start, end, rec.TxHash))
 
-	respondJSON(w, http.StatusOK, map[string]string{"status": "success", "message": "Election dates updated"})
+	respondJSON(w, http.StatusOK, map[string]string{"status": "success", "message": "Election dates updated", "txHash": rec.TxHash, "txId": rec.ID.Hex()})
 }
 
 // GetElectionMetadata Endpoint
-func GetElectionMetadata(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) GetElectionMetadata(w http.ResponseWriter, r *http.Request) {
 	writeJSONHeader(w)
 	vars := mux.Vars(r)
 	addr := vars["address"]
@@ -206,8 +221,7 @@ func GetElectionMetadata(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var meta ElectionMetadata
-	err := metadataCollection.FindOne(ctx, bson.M{"election_address": addr}).Decode(&meta)
+	meta, err := h.Elections.Get(ctx, addr)
 	if err != nil {
 		// Return 404 or just default
 		respondError(w, http.StatusNotFound, "Metadata not found (election might use default open dates)")
@@ -220,218 +234,133 @@ func GetElectionMetadata(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// EndElection immediately stops an election
-func EndElection(w http.ResponseWriter, r *http.Request) {
+// EndElection immediately stops an election. Takes {"password": ...} of the company that
+// owns the election.
+func (h *Handlers) EndElection(w http.ResponseWriter, r *http.Request) {
 	writeJSONHeader(w)
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	vars := mux.Vars(r)
-	addr := vars["address"]
-	if addr == "" {
-		respondError(w, http.StatusBadRequest, "Address required")
+	addr, err := normalizeAddrParam(mux.Vars(r)["address"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid election address")
+		return
+	}
+	var req struct {
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid JSON")
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	owner := h.requireElectionOwner(ctx, w, addr, req.Password)
+	if owner == "" {
+		return
+	}
+
+	// Close the contract first so no ballot can land after the result is read
+	closeRec, err := sendCloseElection(ctx, common.HexToAddress(addr))
+	if err != nil {
+		log.Printf("EndElection: closeElection for %s failed: %v", addr, err)
+		respondError(w, sendErrorStatus(err), "Failed to close election on-chain: "+err.Error())
+		return
+	}
+
 	// Update Status=ENDED and EndDate=Now
-	update := bson.M{
-		"$set": bson.M{
+	err = h.Elections.Update(ctx, addr, repository.Update{
+		Set: map[string]interface{}{
 			"status":   "ENDED",
 			"end_date": time.Now().UTC(),
 		},
-	}
-	_, err := metadataCollection.UpdateOne(ctx, bson.M{"election_address": addr}, update)
+	})
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to end election")
 		return
 	}
 
-	// --- L2 -> L1 ANCHORING LOGIC ---
-	// Start anchoring asynchronously to not block the API response
-	go func(electionAddress string) {
-		log.Printf("[ANCHOR] Starting archiving process for election %s", electionAddress)
-
-		// 1. Connect to L2 Client to read the results
-		l2Url := strings.TrimSpace(os.Getenv("L2_NODE_URL"))
-		if l2Url == "" {
-			log.Println("[ANCHOR ERROR] L2_NODE_URL not set")
-			return
-		}
-
-		l2Client, err := ethclient.Dial(l2Url)
-		if err != nil {
-			log.Printf("[ANCHOR ERROR] Failed to connect to L2: %v", err)
-			return
-		}
-		defer l2Client.Close()
-
-		l2Election, err := bindings.NewElection(common.HexToAddress(electionAddress), l2Client)
-		if err != nil {
-			log.Printf("[ANCHOR ERROR] Failed to bind L2 Election contract: %v", err)
-			return
-		}
-
-		privKeyStr := os.Getenv("EVM_PRIVATE_KEY")
-		if privKeyStr == "" {
-			log.Println("[ANCHOR ERROR] EVM_PRIVATE_KEY missing")
-			return
-		}
-		privKey, err := crypto.HexToECDSA(strings.TrimPrefix(privKeyStr, "0x"))
-		if err != nil {
-			log.Printf("[ANCHOR ERROR] Invalid Private Key: %v", err)
-			return
-		}
-		fromAddress := crypto.PubkeyToAddress(privKey.PublicKey)
-
-		callOpts := &bind.CallOpts{Context: context.Background(), Pending: false, From: fromAddress}
-
-		// 2. Fetch Winners and Metadata from L2
-		title, _, err := l2Election.GetElectionDetails(callOpts)
-		if err != nil {
-			log.Printf("[ANCHOR ERROR] Failed to get title: %v", err)
-			return
-		}
-
-		numVoters, err := l2Election.GetNumOfVoters(callOpts)
-		if err != nil {
-			log.Printf("[ANCHOR ERROR] Failed to get Total Voters: %v", err)
-			return
-		}
-
-		winnerId, err := l2Election.WinnerCandidate(callOpts)
-		if err != nil {
-			log.Printf("[ANCHOR ERROR] Failed to get Winner ID: %v", err)
-			return
-		}
-
-		winnerName, _, _, winningVotes, _, err := l2Election.GetCandidate(callOpts, winnerId)
-		if err != nil {
-			log.Printf("[ANCHOR ERROR] Failed to get Winner Details: %v", err)
-			return
-		}
-
-		log.Printf("[ANCHOR] Results from L2: %s won '%s' with %s votes out of %s total voters", winnerName, title, winningVotes.String(), numVoters.String())
-
-		// 3. Connect to L1 Client to archive the results
-		l1Url := strings.TrimSpace(os.Getenv("L1_NODE_URL"))
-		l1ArchiveAddr := strings.TrimSpace(os.Getenv("L1_ARCHIVE_CONTRACT_ADDRESS"))
-		if l1Url == "" || l1ArchiveAddr == "" {
-			log.Println("[ANCHOR ERROR] L1 config missing")
-			return
-		}
-
-		l1Client, err := ethclient.Dial(l1Url)
-		if err != nil {
-			log.Printf("[ANCHOR ERROR] Failed to connect to L1: %v", err)
-			return
-		}
-		defer l1Client.Close()
-
-		l1Archive, err := bindings.NewBindings(common.HexToAddress(l1ArchiveAddr), l1Client)
-		if err != nil {
-			log.Printf("[ANCHOR ERROR] Failed to bind L1 Archive contract: %v", err)
-			return
-		}
-
-		// 4. Create L1 Transactor
-
-		l1ChainIDVal, _ := new(big.Int).SetString(os.Getenv("L1_CHAIN_ID"), 10)
-		if l1ChainIDVal == nil || l1ChainIDVal.Uint64() == 0 {
-			l1ChainIDVal = big.NewInt(11155111)
-		}
-
-		auth, err := bind.NewKeyedTransactorWithChainID(privKey, l1ChainIDVal)
-		if err != nil {
-			log.Printf("[ANCHOR ERROR] Failed to create L1 transactor: %v", err)
-			return
-		}
-
-		// Fetch L1 Nonce specifically
-		nonce, err := l1Client.PendingNonceAt(context.Background(), auth.From)
-		if err != nil {
-			log.Printf("[ANCHOR ERROR] Failed to get L1 nonce: %v", err)
-			return
-		}
-		auth.Nonce = big.NewInt(int64(nonce))
-
-		// 5. Submit to L1
-		tx, err := l1Archive.ArchiveResult(auth, common.HexToAddress(electionAddress), title, winnerName, winningVotes, numVoters)
-		if err != nil {
-			log.Printf("[ANCHOR ERROR] ArchiveResult tx failed: %v", err)
-			return
-		}
-
-		log.Printf("[ANCHOR SUCCESS] Result for %s sent to L1 Sepolia at tx: %s", electionAddress, tx.Hash().Hex())
-
-		// Log the anchoring completion in MongoDB audit
-		go LogAction(electionAddress, "L1_ANCHOR_SUBMITTED", "System", fmt.Sprintf("Archived results to L1 Sepolia. Tx: %s", tx.Hash().Hex()))
-	}(addr)
+	// --- L2 -> L1 ANCHORING ---
+	// Persisted job: the anchor worker waits for the close, submits to L1, retries and
+	// tracks confirmations (anchor_jobs.go).
+	closeTxID := ""
+	if closeRec != nil {
+		closeTxID = closeRec.ID.Hex()
+	}
+	if err := enqueueAnchor(ctx, addr, closeTxID, owner); err != nil {
+		log.Printf("[ANCHOR WARN] %s not queued for anchoring: %v", addr, err)
+	}
 
 	// AUDIT
-	go LogAction(addr, "ELECTION_ENDED", "Admin", "Manually ended election via API")
+	go LogAction(addr, "ELECTION_ENDED", owner, "Manually ended election via API")
 
-	respondJSON(w, http.StatusOK, map[string]string{"status": "success", "message": "Election ended successfully. Results are being anchored to L1."})
+	resp := map[string]string{"status": "success", "message": "Election ended successfully. Results are being anchored to L1."}
+	if closeRec != nil {
+		resp["txHash"] = closeRec.TxHash
+		resp["txId"] = closeRec.ID.Hex()
+	}
+	respondJSON(w, http.StatusOK, resp)
 }
 
-// GetAllElections returns a list of all elections (for Admin Dashboard)
-func GetAllElections(w http.ResponseWriter, r *http.Request) {
+// GetAllElections returns one page of elections (for Admin Dashboard), latest start first
+// unless sorted otherwise.
+func (h *Handlers) GetAllElections(w http.ResponseWriter, r *http.Request) {
 	writeJSONHeader(w)
-	if metadataCollection == nil {
+	if h.Elections == nil {
 		respondError(w, http.StatusInternalServerError, "DB not ready")
 		return
 	}
 
+	q := r.URL.Query()
+	eq, err := readElectionQuery(q)
+	if err != nil {
+		respondListError(w, err, "elections")
+		return
+	}
+	fields, err := readFields(q, electionListFields, nil)
+	if err != nil {
+		respondListError(w, err, "elections")
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Find all, sort by StartDate desc
-	opts := options.Find().SetSort(bson.M{"start_date": -1})
-	cursor, err := metadataCollection.Find(ctx, bson.M{}, opts)
+	page, err := h.Elections.Page(ctx, eq)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Error fetching elections")
+		respondListError(w, err, "elections")
 		return
 	}
-	defer cursor.Close(ctx)
-
-	var elections []ElectionMetadata
-	if err = cursor.All(ctx, &elections); err != nil {
-		respondError(w, http.StatusInternalServerError, "Error decoding elections")
-		return
+	rows := make([]map[string]interface{}, 0, len(page.Elections))
+	for _, m := range page.Elections {
+		row, err := electionRow(m)
+		if err != nil {
+			respondListError(w, err, "elections")
+			return
+		}
+		if fields != nil {
+			row = pick(row, fields)
+		}
+		rows = append(rows, row)
 	}
-
-	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"status": "success",
-		"data":   elections,
-		"count":  len(elections),
-	})
+	respondPage(w, "elections retrieved", rows, page.PageInfo)
 }
 
 // GetArchivedResults fetches all anchored election results directly from the L1 Sepolia contract
-func GetArchivedResults(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) GetArchivedResults(w http.ResponseWriter, r *http.Request) {
 	writeJSONHeader(w)
 
-	l1Url := strings.TrimSpace(os.Getenv("L1_NODE_URL"))
 	l1ArchiveAddr := strings.TrimSpace(os.Getenv("L1_ARCHIVE_CONTRACT_ADDRESS"))
-
-	if l1Url == "" || l1ArchiveAddr == "" {
+	l1Client, err := getL1Client()
+	if err != nil || l1ArchiveAddr == "" {
 		respondError(w, http.StatusInternalServerError, "L1 Archiving is not configured")
 		return
 	}
 
-	// 1. Connect to L1
-	l1Client, err := ethclient.Dial(l1Url)
-	if err != nil {
-		log.Printf("[ARCHIVE ERR] Failed to connect to L1: %v", err)
-		respondError(w, http.StatusInternalServerError, "Failed to connect to L1 Anchor")
-		return
-	}
-	defer l1Client.Close()
-
 	// 2. Bind Contract
 	l1Archive, err := bindings.NewBindings(common.HexToAddress(l1ArchiveAddr), l1Client)
 	if err != nil {
@@ -445,7 +374,7 @@ func GetArchivedResults(w http.ResponseWriter, r *http.Request) {
 	// we will fetch all ENDED elections from MongoDB, and then query the L1 contract
 	// for each address to get the verified on-chain result.
 
-	if metadataCollection == nil {
+	if h.Elections == nil {
 		respondError(w, http.StatusInternalServerError, "DB not ready")
 		return
 	}
@@ -454,20 +383,14 @@ func GetArchivedResults(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 
 	// Find all ENDED elections
-	cursor, err := metadataCollection.Find(ctx, bson.M{"status": "ENDED"})
+	endedElections, err := h.Elections.List(ctx, repository.ElectionFilter{Status: "ENDED"})
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Error fetching past elections")
 		return
 	}
-	defer cursor.Close(ctx)
 
-	var endedElections []ElectionMetadata
-	if err = cursor.All(ctx, &endedElections); err != nil {
-		respondError(w, http.StatusInternalServerError, "Error decoding past elections")
-		return
-	}
-
-	// 4. Query L1 for each ended election
+	// 4. Query L1 for each ended election. Elections without an archive entry are still
+	// listed, with the state of their anchor job, so missing anchors are visible.
 	type L1Result struct {
 		ElectionAddress string `json:"election_address"`
 		Title           string `json:"title"`
@@ -475,39 +398,53 @@ func GetArchivedResults(w http.ResponseWriter, r *http.Request) {
 		WinningVotes    int64  `json:"winning_votes"`
 		TotalVoters     int64  `json:"total_voters"`
 		Timestamp       int64  `json:"anchored_timestamp"`
+		Archived        bool   `json:"archived"`
+		AnchorStatus    string `json:"anchor_status,omitempty"`
+		AnchorTxHash    string `json:"anchor_tx_hash,omitempty"`
+		AnchorError     string `json:"anchor_error,omitempty"`
 	}
 
-	var verifiedResults []L1Result
+	results := []L1Result{}
+	archivedCount := 0
 	callOpts := &bind.CallOpts{Context: context.Background(), Pending: false}
 
 	for _, meta := range endedElections {
 		addr := common.HexToAddress(meta.ElectionAddress)
+		res := L1Result{ElectionAddress: addr.Hex(), Title: meta.ElectionName}
+		if h.AnchorJobs != nil {
+			if job, err := h.AnchorJobs.Get(ctx, addr.Hex()); err == nil {
+				res.AnchorStatus = job.Status
+				res.AnchorTxHash = job.TxHash
+				res.AnchorError = job.LastError
+			}
+		}
 
 		// Call archivedResults(address) mapping
 		archived, err := l1Archive.ArchivedResults(callOpts, addr)
 		if err != nil {
 			log.Printf("Warning: Failed to fetch L1 result for %s: %v", meta.ElectionAddress, err)
+			res.AnchorError = "L1 lookup failed: " + err.Error()
+			results = append(results, res)
 			continue
 		}
 
-		// If the timestamp is 0, it means it hasn't been archived yet (or the archiving tx is still pending on L1)
-		if archived.Timestamp == nil || archived.Timestamp.Cmp(big.NewInt(0)) == 0 {
-			continue
+		// A zero timestamp means it hasn't been archived yet (or the archiving tx is still pending on L1)
+		if archived.Timestamp != nil && archived.Timestamp.Cmp(big.NewInt(0)) != 0 {
+			res.Archived = true
+			res.Title = archived.Title
+			res.WinnerName = archived.WinnerName
+			res.WinningVotes = archived.WinningVotes.Int64()
+			res.TotalVoters = archived.TotalVoters.Int64()
+			res.Timestamp = archived.Timestamp.Int64()
+			archivedCount++
 		}
-
-		verifiedResults = append(verifiedResults, L1Result{
-			ElectionAddress: archived.ElectionAddress.Hex(),
-			Title:           archived.Title,
-			WinnerName:      archived.WinnerName,
-			WinningVotes:    archived.WinningVotes.Int64(),
-			TotalVoters:     archived.TotalVoters.Int64(),
-			Timestamp:       archived.Timestamp.Int64(),
-		})
+		results = append(results, res)
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"status": "success",
-		"data":   verifiedResults,
-		"count":  len(verifiedResults),
+		"status":   "success",
+		"data":     results,
+		"count":    len(results),
+		"archived": archivedCount,
 	})
 }
@@ -0,0 +1,267 @@
+﻿package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"MAJOR-PROJECT/bindings"
+	"MAJOR-PROJECT/repository"
+	"MAJOR-PROJECT/txmanager"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gorilla/mux"
+)
+
+// Emergency pause: the contract's circuit breaker stops ballots on-chain and
+// election_metadata.status = PAUSED stops them at the API, with the reason shown to voters.
+// Builds without pause()/resume() are paused in metadata only. Both calls take the owning
+// company's password; extending the end date on resume also needs schedule approval.
+
+const (
+	PurposePauseElection  = "PAUSE_ELECTION"
+	PurposeResumeElection = "RESUME_ELECTION"
+
+	StatusPaused = "PAUSED"
+)
+
+// electionPaused reports whether an election is paused in metadata.
+func electionPaused(ctx context.Context, electionAddr string) bool {
+	if !common.IsHexAddress(electionAddr) {
+		return false
+	}
+	meta, err := electionMetadata(ctx, common.HexToAddress(electionAddr))
+	return err == nil && meta.Status == StatusPaused
+}
+
+// sendPauseCall submits pause(reason) or resume(extend). It returns a nil record for
+// builds without the circuit breaker.
+func sendPauseCall(ctx context.Context, addr common.Address, purpose, method string, args ...interface{}) (*txmanager.Record, error) {
+	v, err := electionVersion(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	if !v.Has(method) {
+		return nil, nil
+	}
+	if l2Tx == nil {
+		return nil, fmt.Errorf("transaction manager not initialized")
+	}
+	data, err := packCall(bindings.ElectionMetaData, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return l2Tx.Send(ctx, txmanager.Request{
+		Purpose:     purpose,
+		To:          addr,
+		Data:        data,
+		Company:     companyForElection(addr.Hex()),
+		Election:    addr.Hex(),
+		FeeStrategy: feeStrategyFor(purpose),
+		Payload:     map[string]interface{}{"election_address": addr.Hex()},
+	})
+}
+
+// PauseElection stops voting until the election is resumed.
+// POST /api/elections/{address}/pause {"reason": "...", "password": "..."}
+func (h *Handlers) PauseElection(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	addrNorm, err := normalizeAddrParam(mux.Vars(r)["address"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req struct {
+		Reason   string `json:"reason"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		respondError(w, http.StatusBadRequest, "reason is required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	addr := common.HexToAddress(addrNorm)
+	meta, err := electionMetadata(ctx, addr)
+	if err != nil {
+		respondError(w, http.StatusNotFound, "Metadata not found")
+		return
+	}
+	owner := h.requireElectionOwner(ctx, w, addrNorm, req.Password)
+	if owner == "" {
+		return
+	}
+	switch meta.Status {
+	case StatusPaused:
+		respondError(w, http.StatusConflict, "election is already paused")
+		return
+	case "ENDED":
+		respondError(w, http.StatusBadRequest, "election has ended")
+		return
+	}
+
+	rec, err := sendPauseCall(ctx, addr, PurposePauseElection, "pause", req.Reason)
+	if err != nil {
+		log.Printf("PauseElection: pause for %s failed: %v", addrNorm, err)
+		respondError(w, sendErrorStatus(err), "Failed to pause election on-chain: "+err.Error())
+		return
+	}
+
+	// The API stops taking ballots now, without waiting for the tx to be mined
+	now := time.Now().UTC()
+	err = h.Elections.Update(ctx, addrNorm, repository.Update{Set: map[string]interface{}{
+		"status":             StatusPaused,
+		"pause_reason":       req.Reason,
+		"paused_at":          now,
+		"paused_from_status": meta.Status,
+	}})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to pause election")
+		return
+	}
+
+	detail := "Paused: " + req.Reason
+	resp := map[string]string{"status": "success", "message": "Election paused"}
+	if rec != nil {
+		detail += fmt.Sprintf(" (tx %s)", rec.TxHash)
+		resp["txHash"], resp["txId"] = rec.TxHash, rec.ID.Hex()
+	}
+	log.Printf("[PAUSE] %s paused: %s", addrNorm, req.Reason)
+	go LogAction(addrNorm, "ELECTION_PAUSED", owner, detail)
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// ResumeElection reopens a paused election. With extend_end_date the end date moves out
+// by the time spent paused; that is a schedule change, so it needs schedule approval over
+// the current start with the end left open (see GET .../approvals?action=schedule).
+// POST /api/elections/{address}/resume {"extend_end_date": true, "password": "..."}
+func (h *Handlers) ResumeElection(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	addrNorm, err := normalizeAddrParam(mux.Vars(r)["address"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req struct {
+		ExtendEndDate bool   `json:"extend_end_date"`
+		Password      string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	addr := common.HexToAddress(addrNorm)
+	meta, err := electionMetadata(ctx, addr)
+	if err != nil {
+		respondError(w, http.StatusNotFound, "Metadata not found")
+		return
+	}
+	owner := h.requireElectionOwner(ctx, w, addrNorm, req.Password)
+	if owner == "" {
+		return
+	}
+	if meta.Status != StatusPaused {
+		respondError(w, http.StatusConflict, "election is not paused")
+		return
+	}
+	if req.ExtendEndDate {
+		if err := requireExtendApproval(ctx, addr); err != nil {
+			if errors.Is(err, errNotApproved) {
+				respondError(w, http.StatusForbidden, err.Error())
+				return
+			}
+			respondError(w, http.StatusBadGateway, "failed to read approvals: "+err.Error())
+			return
+		}
+	}
+
+	rec, err := sendPauseCall(ctx, addr, PurposeResumeElection, "resume", req.ExtendEndDate)
+	if err != nil {
+		log.Printf("ResumeElection: resume for %s failed: %v", addrNorm, err)
+		respondError(w, sendErrorStatus(err), "Failed to resume election on-chain: "+err.Error())
+		return
+	}
+
+	now := time.Now().UTC()
+	var pausedFor time.Duration
+	if meta.PausedAt != nil {
+		pausedFor = now.Sub(*meta.PausedAt).Truncate(time.Second)
+	}
+	status := meta.PausedFromStatus
+	if status == "" {
+		status = "ONGOING"
+	}
+	set := map[string]interface{}{"status": status}
+	if req.ExtendEndDate {
+		// The contract extends by its own block-time measure; the reconciler copies it back
+		set["end_date"] = meta.EndDate.Add(pausedFor)
+	}
+	err = h.Elections.Update(ctx, addrNorm, repository.Update{
+		Set:   set,
+		Inc:   map[string]int64{"total_paused_seconds": int64(pausedFor / time.Second)},
+		Unset: []string{"pause_reason", "paused_at", "paused_from_status"},
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to resume election")
+		return
+	}
+
+	detail := fmt.Sprintf("Resumed after %s (reason was: %s)", pausedFor, meta.PauseReason)
+	if req.ExtendEndDate {
+		detail += fmt.Sprintf("; end date extended to %s", meta.EndDate.Add(pausedFor).Format(time.RFC3339))
+	}
+	resp := map[string]interface{}{"status": "success", "message": "Election resumed", "paused_seconds": int64(pausedFor / time.Second)}
+	if rec != nil {
+		detail += fmt.Sprintf(" (tx %s)", rec.TxHash)
+		resp["txHash"], resp["txId"] = rec.TxHash, rec.ID.Hex()
+	}
+	log.Printf("[PAUSE] %s resumed after %s", addrNorm, pausedFor)
+	go LogAction(addrNorm, "ELECTION_RESUMED", owner, detail)
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// requireExtendApproval checks the schedule approval resume(true) consumes: the current
+// on-chain start with an open end.
+func requireExtendApproval(ctx context.Context, addr common.Address) error {
+	e, err := newElectionContract(ctx, addr)
+	if err != nil {
+		return err
+	}
+	sched, err := e.schedule(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return err
+	}
+	return requireApproval(ctx, addr, ActionSchedule, sched.Start.Unix(), 0)
+}
+
+func onPauseFinal(rec *txmanager.Record, receipt *types.Receipt) {
+	electionAddr := payloadString(rec, "election_address")
+	if rec.Status == txmanager.StatusConfirmed {
+		log.Printf("[PAUSE] %s %s on-chain in block %d", electionAddr, strings.ToLower(rec.Purpose), rec.BlockNumber)
+		return
+	}
+	// Metadata keeps its state; the reconciler reports the mismatch with the contract
+	action := "PAUSE_FAILED"
+	if rec.Purpose == PurposeResumeElection {
+		action = "RESUME_FAILED"
+	}
+	go LogAction(electionAddr, action, "System", fmt.Sprintf("%s %s (tx %s): %s", strings.ToLower(rec.Purpose), rec.Status, rec.TxHash, rec.Error))
+}
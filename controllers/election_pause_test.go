@@ -0,0 +1,117 @@
+﻿package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"MAJOR-PROJECT/txmanager"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// confirmResponse waits for the tx a handler answered with and expects it mined.
+func confirmResponse(t *testing.T, what string, body map[string]interface{}) {
+	t.Helper()
+	id, _ := body["txId"].(string)
+	if id == "" {
+		t.Fatalf("%s: no txId in %v", what, body)
+	}
+	if rec := waitTx(t, id); rec.Status != txmanager.StatusConfirmed {
+		t.Fatalf("%s tx %s: %s %s", what, rec.TxHash, rec.Status, rec.Error)
+	}
+}
+
+func TestPauseBlocksVotesAndResumeExtendsEndDate(t *testing.T) {
+	h := newTestHandlers(t)
+	ctx := context.Background()
+	registerCompany(t, h, "owner@example.com", "owner-pw")
+	addr := createTestElection(t, h, "owner@example.com", "Pause")
+	if open, err := electionOpenOnChain(ctx, common.HexToAddress(addr)); err != nil || !open {
+		t.Fatalf("new election is not open on-chain: %v", err)
+	}
+
+	pause := func(password string) *httptest.ResponseRecorder {
+		body := map[string]string{"reason": "ballot misprint", "password": password}
+		return serve(t, h.PauseElection, http.MethodPost, "/elections/{address}/pause", "/elections/"+addr+"/pause", body, nil)
+	}
+	if rec := pause("wrong"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("pause with a wrong password: got %d, want 401", rec.Code)
+	}
+	rec := pause("owner-pw")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("pause: status %d: %s", rec.Code, rec.Body.String())
+	}
+	confirmResponse(t, "pause", decodeBody(t, rec))
+	paused, err := h.Elections.Get(ctx, addr)
+	if err != nil || paused.Status != StatusPaused || paused.PausedAt == nil {
+		t.Fatalf("paused metadata: %+v, %v", paused, err)
+	}
+
+	// Ballots stop at the API with the reason, and on-chain.
+	vote := serve(t, h.VoteCandidate, http.MethodPost, "/vote", "/vote",
+		map[string]interface{}{"election_address": addr, "candidate_id": 0, "voter_email": "voter@example.com", "otp": "000000"}, nil)
+	if vote.Code != http.StatusBadRequest || !strings.Contains(vote.Body.String(), "ballot misprint") {
+		t.Errorf("vote while paused: status %d: %s; want 400 with the reason", vote.Code, vote.Body.String())
+	}
+	if open, err := electionOpenOnChain(ctx, common.HexToAddress(addr)); err != nil || open {
+		t.Errorf("isOpen on-chain while paused: %v, %v; want false", open, err)
+	}
+	if rec := pause("owner-pw"); rec.Code != http.StatusConflict {
+		t.Errorf("second pause: got %d, want 409", rec.Code)
+	}
+
+	// New dates while paused move the window but leave the election paused.
+	newEnd := paused.EndDate.Add(time.Hour)
+	rec = serve(t, h.SetElectionDates, http.MethodPost, "/elections/dates", "/elections/dates", map[string]string{
+		"election_address": addr,
+		"start_date":       paused.StartDate.Format(time.RFC3339),
+		"end_date":         newEnd.Format(time.RFC3339),
+		"password":         "owner-pw",
+	}, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("set dates: status %d: %s", rec.Code, rec.Body.String())
+	}
+	confirmResponse(t, "set dates", decodeBody(t, rec))
+	if meta, _ := h.Elections.Get(ctx, addr); meta.Status != StatusPaused || !meta.EndDate.Equal(newEnd) {
+		t.Fatalf("after new dates: status %s, end %s; want PAUSED and %s", meta.Status, meta.EndDate, newEnd)
+	}
+
+	time.Sleep(1100 * time.Millisecond)
+	rec = serve(t, h.ResumeElection, http.MethodPost, "/elections/{address}/resume", "/elections/"+addr+"/resume",
+		map[string]interface{}{"extend_end_date": true, "password": "owner-pw"}, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("resume: status %d: %s", rec.Code, rec.Body.String())
+	}
+	body := decodeBody(t, rec)
+	confirmResponse(t, "resume", body)
+	pausedFor := time.Duration(body["paused_seconds"].(float64)) * time.Second
+	if pausedFor < time.Second {
+		t.Errorf("paused_seconds = %s, want at least 1s", pausedFor)
+	}
+
+	meta, err := h.Elections.Get(ctx, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Status == StatusPaused || meta.PausedAt != nil || meta.TotalPausedSeconds != int64(pausedFor/time.Second) {
+		t.Errorf("resumed metadata: status %s, paused_at %v, total %ds", meta.Status, meta.PausedAt, meta.TotalPausedSeconds)
+	}
+	if want := newEnd.Add(pausedFor); !meta.EndDate.Equal(want) {
+		t.Errorf("end date %s, want %s (moved out by the pause)", meta.EndDate, want)
+	}
+	e, err := newElectionContract(ctx, common.HexToAddress(addr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sched, err := e.schedule(&bind.CallOpts{Context: ctx}); err != nil || !sched.End.After(newEnd) {
+		t.Errorf("on-chain end after resume: %+v, %v; want past %s", sched, err, newEnd)
+	}
+	if open, err := electionOpenOnChain(ctx, common.HexToAddress(addr)); err != nil || !open {
+		t.Errorf("resumed election is not open on-chain: %v", err)
+	}
+}
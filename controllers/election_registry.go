@@ -0,0 +1,268 @@
+﻿package controllers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"MAJOR-PROJECT/bindings"
+	"MAJOR-PROJECT/txmanager"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gorilla/mux"
+)
+
+// Errors returned when an email-based request cannot be tied to a single election.
+var (
+	errNoElection        = errors.New("no election found for this company")
+	errAmbiguousElection = errors.New("company has several elections; pass election_id to pick one")
+)
+
+// RegistryElection is one entry of the ElectionFact registry.
+type RegistryElection struct {
+	ID              uint64    `json:"id"`
+	ElectionAddress string    `json:"election_address"`
+	CompanyID       string    `json:"company_id"`
+	ElectionName    string    `json:"election_name"`
+	ElectionDesc    string    `json:"election_desc"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// companyIDSecret keys CompanyID. InitCompanyIDSecret sets it at startup.
+var companyIDSecret []byte
+
+// InitCompanyIDSecret loads COMPANY_ID_SECRET. Without it the ids in the registry could be
+// matched against guessed emails, so it is required unless ephemeral is set (simulated
+// chain), in which case a random secret is generated for the life of the process.
+func InitCompanyIDSecret(ephemeral bool) error {
+	if secret := strings.TrimSpace(os.Getenv("COMPANY_ID_SECRET")); secret != "" {
+		companyIDSecret = []byte(secret)
+		return nil
+	}
+	if !ephemeral {
+		return errors.New("COMPANY_ID_SECRET is not set")
+	}
+	companyIDSecret = make([]byte, 32)
+	if _, err := rand.Read(companyIDSecret); err != nil {
+		return fmt.Errorf("failed to generate company id secret: %w", err)
+	}
+	log.Printf("[WARN] COMPANY_ID_SECRET is not set; using a random secret until restart")
+	return nil
+}
+
+// CompanyID derives the opaque id a company is registered under in ElectionFact:
+// HMAC-SHA256(COMPANY_ID_SECRET, lower(email)), so ids cannot be matched against guessed emails.
+func CompanyID(email string) [32]byte {
+	if companyIDSecret == nil {
+		// Only reachable before InitCompanyIDSecret, e.g. in tests.
+		_ = InitCompanyIDSecret(true)
+	}
+	mac := hmac.New(sha256.New, companyIDSecret)
+	mac.Write([]byte(txmanager.NormalizeCompany(email)))
+	var id [32]byte
+	copy(id[:], mac.Sum(nil))
+	return id
+}
+
+// companyEmailForID maps a registry company id back to an email: first via the
+// CREATE_ELECTION record that produced txHash, then by checking every known company.
+func companyEmailForID(ctx context.Context, id [32]byte, txHash common.Hash) string {
+	if txStore != nil {
+		if rec, err := txStore.Get(ctx, txHash.Hex()); err == nil && rec.Company != "" && CompanyID(rec.Company) == id {
+			return rec.Company
+		}
+	}
+	if app.Companies == nil {
+		return ""
+	}
+	companies, err := app.Companies.List(ctx)
+	if err != nil {
+		return ""
+	}
+	for _, c := range companies {
+		if CompanyID(c.Email) == id {
+			return txmanager.NormalizeCompany(c.Email)
+		}
+	}
+	return ""
+}
+
+func factoryCaller() (*bindings.ElectionFactCaller, error) {
+	_, factoryAddr, err := normalizeFactoryAddr()
+	if err != nil {
+		return nil, err
+	}
+	client, err := getClient()
+	if err != nil {
+		return nil, err
+	}
+	return bindings.NewElectionFactCaller(factoryAddr, client)
+}
+
+func toRegistryElection(d bindings.ElectionFactElectionDet) RegistryElection {
+	return RegistryElection{
+		ID:              d.Id.Uint64(),
+		ElectionAddress: d.DeployedAddress.Hex(),
+		CompanyID:       hexutil.Encode(d.CompanyId[:]),
+		ElectionName:    d.ElN,
+		ElectionDesc:    d.ElD,
+		CreatedAt:       time.Unix(d.CreatedAt.Int64(), 0).UTC(),
+	}
+}
+
+// companyElections returns one page of a company's elections (creation order) and the total.
+func companyElections(ctx context.Context, email string, offset, limit uint64) ([]RegistryElection, uint64, error) {
+	factory, err := factoryCaller()
+	if err != nil {
+		return nil, 0, err
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	id := CompanyID(email)
+	total, err := factory.CompanyElectionCount(opts, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	page, err := factory.GetCompanyElections(opts, id, new(big.Int).SetUint64(offset), new(big.Int).SetUint64(limit))
+	if err != nil {
+		return nil, 0, err
+	}
+	out := make([]RegistryElection, 0, len(page))
+	for _, d := range page {
+		out = append(out, toRegistryElection(d))
+	}
+	return out, total.Uint64(), nil
+}
+
+// registryElectionByID looks an election up by its on-chain id.
+func registryElectionByID(ctx context.Context, id uint64) (*RegistryElection, error) {
+	factory, err := factoryCaller()
+	if err != nil {
+		return nil, err
+	}
+	d, err := factory.GetElection(&bind.CallOpts{Context: ctx}, new(big.Int).SetUint64(id))
+	if err != nil {
+		return nil, err
+	}
+	e := toRegistryElection(d)
+	return &e, nil
+}
+
+// resolveCompanyElection picks the election an email-based request refers to. electionID
+// (the registry id, optional) selects one explicitly and must belong to the company;
+// without it the company must own exactly one election.
+func resolveCompanyElection(ctx context.Context, email, electionID string) (common.Address, error) {
+	if electionID = strings.TrimSpace(electionID); electionID != "" {
+		id, err := strconv.ParseUint(electionID, 10, 64)
+		if err != nil || id == 0 {
+			return common.Address{}, fmt.Errorf("invalid election_id %q", electionID)
+		}
+		e, err := registryElectionByID(ctx, id)
+		if err != nil {
+			return common.Address{}, err
+		}
+		if companyID := CompanyID(email); e.CompanyID != hexutil.Encode(companyID[:]) {
+			return common.Address{}, errNoElection
+		}
+		return common.HexToAddress(e.ElectionAddress), nil
+	}
+
+	page, total, err := companyElections(ctx, email, 0, 2)
+	if err != nil {
+		return common.Address{}, err
+	}
+	switch total {
+	case 0:
+		return common.Address{}, errNoElection
+	case 1:
+		return common.HexToAddress(page[0].ElectionAddress), nil
+	default:
+		return common.Address{}, fmt.Errorf("%w (%d elections)", errAmbiguousElection, total)
+	}
+}
+
+// pageParams reads ?offset=&limit= with a default and maximum page size.
+func pageParams(r *http.Request, def, max uint64) (uint64, uint64) {
+	q := r.URL.Query()
+	offset, _ := strconv.ParseUint(q.Get("offset"), 10, 64)
+	limit, _ := strconv.ParseUint(q.Get("limit"), 10, 64)
+	if limit == 0 {
+		limit = def
+	}
+	if limit > max {
+		limit = max
+	}
+	return offset, limit
+}
+
+// ListCompanyElections pages through a company's elections from the on-chain registry.
+// The company signs the request with HTTP Basic credentials.
+// GET /api/company/{email}/elections?offset=0&limit=20
+func (h *Handlers) ListCompanyElections(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	email, _ := url.PathUnescape(mux.Vars(r)["email"])
+	if strings.TrimSpace(email) == "" {
+		respondError(w, http.StatusBadRequest, "company email is required")
+		return
+	}
+	offset, limit := pageParams(r, 20, 100)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	who, err := h.basicCaller(ctx, r)
+	if err != nil && !errors.Is(err, errBadCredentials) {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !who.isCompany(email) {
+		respondUnauthorized(w, "Sign in as this company to list its elections")
+		return
+	}
+	elections, total, err := companyElections(ctx, email, offset, limit)
+	if err != nil {
+		log.Printf("ListCompanyElections: registry lookup for %s failed: %v", email, err)
+		respondError(w, http.StatusInternalServerError, "failed to read election registry")
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"data":   elections,
+		"count":  len(elections),
+		"total":  total,
+		"offset": offset,
+		"limit":  limit,
+	})
+}
+
+// GetElectionByID resolves a registry id to its election.
+// GET /api/elections/by-id/{id}
+func (h *Handlers) GetElectionByID(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id == 0 {
+		respondError(w, http.StatusBadRequest, "election id must be a positive integer")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	e, err := registryElectionByID(ctx, id)
+	if err != nil {
+		log.Printf("GetElectionByID: lookup of %d failed: %v", id, err)
+		respondError(w, http.StatusNotFound, "election not found")
+		return
+	}
+	respondJSON(w, http.StatusOK, BlockchainResponse{Status: "success", Message: "election found", Data: e})
+}
@@ -0,0 +1,109 @@
+﻿package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestInitCompanyIDSecret(t *testing.T) {
+	saved := companyIDSecret
+	defer func() { companyIDSecret = saved }()
+
+	t.Setenv("COMPANY_ID_SECRET", "")
+	if err := InitCompanyIDSecret(false); err == nil {
+		t.Fatal("started without COMPANY_ID_SECRET on a real chain")
+	}
+	if err := InitCompanyIDSecret(true); err != nil {
+		t.Fatal(err)
+	}
+	random := CompanyID("Owner@Example.com")
+
+	t.Setenv("COMPANY_ID_SECRET", "s3cret")
+	if err := InitCompanyIDSecret(false); err != nil {
+		t.Fatal(err)
+	}
+	if CompanyID("Owner@Example.com") != CompanyID("owner@example.com") {
+		t.Error("company id depends on email case")
+	}
+	if CompanyID("owner@example.com") == random {
+		t.Error("company id ignores the configured secret")
+	}
+}
+
+func TestListCompanyElectionsPages(t *testing.T) {
+	h := newTestHandlers(t)
+	// The simulated chain outlives the test, so the registry may already hold these names.
+	run := time.Now().UnixNano()
+	company, neighbour := fmt.Sprintf("registry-%d@example.com", run), fmt.Sprintf("neighbour-%d@example.com", run)
+	registerCompany(t, h, company, "registry-pw")
+	registerCompany(t, h, neighbour, "neighbour-pw")
+
+	// Another company's election lands between ours in the registry.
+	first := createTestElection(t, h, company, "First")
+	other := createTestElection(t, h, neighbour, "Neighbour")
+	second := createTestElection(t, h, company, "Second")
+	third := createTestElection(t, h, company, "Third")
+
+	list := func(query string, header http.Header) *httptest.ResponseRecorder {
+		return serve(t, h.ListCompanyElections, http.MethodGet, "/company/{email}/elections", "/company/"+company+"/elections"+query, nil, header)
+	}
+	if rec := list("", nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous: got %d, want 401", rec.Code)
+	}
+	if rec := list("", basicAuth(neighbour, "neighbour-pw")); rec.Code != http.StatusUnauthorized {
+		t.Errorf("other company: got %d, want 401", rec.Code)
+	}
+
+	var ids []uint64
+	page := func(query string, want ...string) {
+		t.Helper()
+		rec := list(query, basicAuth(company, "registry-pw"))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("list %s: status %d: %s", query, rec.Code, rec.Body.String())
+		}
+		body := decodeBody(t, rec)
+		if body["total"] != float64(3) {
+			t.Errorf("list %s: total %v, want 3", query, body["total"])
+		}
+		rows := body["data"].([]interface{})
+		if len(rows) != len(want) {
+			t.Fatalf("list %s: %d elections, want %d", query, len(rows), len(want))
+		}
+		for i, row := range rows {
+			e := row.(map[string]interface{})
+			if e["election_address"] != want[i] {
+				t.Errorf("list %s: row %d is %v (%v), want %s", query, i, e["election_address"], e["election_name"], want[i])
+			}
+			ids = append(ids, uint64(e["id"].(float64)))
+		}
+	}
+	page("?limit=2", first, second)
+	page("?offset=2&limit=2", third)
+	page("?offset=3")
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Errorf("registry ids %v are not in creation order", ids)
+		}
+	}
+
+	// Registry ids resolve back to their election, and only for the owning company.
+	rec := serve(t, h.GetElectionByID, http.MethodGet, "/elections/by-id/{id}", fmt.Sprintf("/elections/by-id/%d", ids[1]), nil, nil)
+	if rec.Code != http.StatusOK || decodeBody(t, rec)["data"].(map[string]interface{})["election_address"] != second {
+		t.Errorf("by id %d: status %d: %s", ids[1], rec.Code, rec.Body.String())
+	}
+	ctx := context.Background()
+	if addr, err := resolveCompanyElection(ctx, company, fmt.Sprint(ids[2])); err != nil || addr.Hex() != third {
+		t.Errorf("resolve id %d: %s, %v; want %s", ids[2], addr.Hex(), err, third)
+	}
+	if _, err := resolveCompanyElection(ctx, company, ""); !errors.Is(err, errAmbiguousElection) {
+		t.Errorf("resolve without an id: got %v, want errAmbiguousElection", err)
+	}
+	if addr, err := resolveCompanyElection(ctx, neighbour, ""); err != nil || addr.Hex() != other {
+		t.Errorf("resolve the neighbour's only election: %s, %v; want %s", addr.Hex(), err, other)
+	}
+}
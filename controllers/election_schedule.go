@@ -0,0 +1,205 @@
+﻿package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"MAJOR-PROJECT/bindings"
+	"MAJOR-PROJECT/repository"
+	"MAJOR-PROJECT/txmanager"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// onChainSchedule is the voting window and open flag stored in an Election contract.
+type onChainSchedule struct {
+	Start  time.Time
+	End    time.Time
+	Closed bool
+}
+
+func readSchedule(ctx context.Context, addr common.Address) (*onChainSchedule, error) {
+	e, err := newElectionContract(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	return e.schedule(&bind.CallOpts{Context: ctx})
+}
+
+func scheduleFrom(start, end *big.Int, status bool, err error) (*onChainSchedule, error) {
+	if err != nil {
+		return nil, fmt.Errorf("getSchedule failed: %w", err)
+	}
+	return &onChainSchedule{
+		Start:  time.Unix(start.Int64(), 0).UTC(),
+		End:    time.Unix(end.Int64(), 0).UTC(),
+		Closed: !status,
+	}, nil
+}
+
+// electionOpenOnChain asks the contract whether it accepts ballots right now.
+func electionOpenOnChain(ctx context.Context, addr common.Address) (bool, error) {
+	e, err := newElectionContract(ctx, addr)
+	if err != nil {
+		return false, err
+	}
+	return e.isOpen(&bind.CallOpts{Context: ctx})
+}
+
+// sendSetSchedule submits setSchedule(start, end) through the L2 transaction manager. It
+// returns a nil record for contracts built before setSchedule, whose window lives in
+// metadata only.
+func sendSetSchedule(ctx context.Context, addr common.Address, start, end time.Time) (*txmanager.Record, error) {
+	if l2Tx == nil {
+		return nil, fmt.Errorf("transaction manager not initialized")
+	}
+	v, err := electionVersion(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	if !v.Has("setSchedule") {
+		return nil, nil
+	}
+	if err := requireApproval(ctx, addr, ActionSchedule, start.Unix(), end.Unix()); err != nil {
+		return nil, err
+	}
+	data, err := packCall(bindings.ElectionMetaData, "setSchedule", big.NewInt(start.Unix()), big.NewInt(end.Unix()))
+	if err != nil {
+		return nil, err
+	}
+	return l2Tx.Send(ctx, txmanager.Request{
+		Purpose:     PurposeSetSchedule,
+		To:          addr,
+		Data:        data,
+		Company:     companyForElection(addr.Hex()),
+		Election:    addr.Hex(),
+		FeeStrategy: feeStrategyFor(PurposeSetSchedule),
+		Payload: map[string]interface{}{
+			"election_address": addr.Hex(),
+			"start":            start.Unix(),
+			"end":              end.Unix(),
+		},
+	})
+}
+
+// sendCloseElection submits closeElection(). It returns a nil record when the contract
+// is already closed, so ending an election twice is harmless, and for contracts built
+// before closeElection, which only metadata can end.
+func sendCloseElection(ctx context.Context, addr common.Address) (*txmanager.Record, error) {
+	if l2Tx == nil {
+		return nil, fmt.Errorf("transaction manager not initialized")
+	}
+	v, err := electionVersion(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	if !v.Has("closeElection") {
+		return nil, nil
+	}
+	sched, err := readSchedule(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	if sched.Closed {
+		return nil, nil
+	}
+	if err := requireApproval(ctx, addr, ActionClose, 0, 0); err != nil {
+		return nil, err
+	}
+	data, err := packCall(bindings.ElectionMetaData, "closeElection")
+	if err != nil {
+		return nil, err
+	}
+	return l2Tx.Send(ctx, txmanager.Request{
+		Purpose:     PurposeCloseElection,
+		To:          addr,
+		Data:        data,
+		Company:     companyForElection(addr.Hex()),
+		Election:    addr.Hex(),
+		FeeStrategy: feeStrategyFor(PurposeCloseElection),
+		Payload:     map[string]interface{}{"election_address": addr.Hex()},
+	})
+}
+
+func onScheduleFinal(rec *txmanager.Record, receipt *types.Receipt) {
+	electionAddr := payloadString(rec, "election_address")
+	if rec.Status == txmanager.StatusConfirmed {
+		log.Printf("[SCHEDULE] Window for %s set on-chain in block %d", electionAddr, rec.BlockNumber)
+		return
+	}
+	// The reconciler puts Mongo back in line with the contract.
+	go LogAction(electionAddr, "SCHEDULE_UPDATE_FAILED", "System", fmt.Sprintf("setSchedule %s (tx %s): %s", rec.Status, rec.TxHash, rec.Error))
+}
+
+func onCloseFinal(rec *txmanager.Record, receipt *types.Receipt) {
+	electionAddr := payloadString(rec, "election_address")
+	if rec.Status == txmanager.StatusConfirmed {
+		log.Printf("[SCHEDULE] %s closed on-chain in block %d", electionAddr, rec.BlockNumber)
+		return
+	}
+	go LogAction(electionAddr, "CLOSE_ELECTION_FAILED", "System", fmt.Sprintf("closeElection %s (tx %s): %s", rec.Status, rec.TxHash, rec.Error))
+}
+
+// reconcileSchedule copies the contract's window and closed flag into election_metadata.
+// It stays out of the way while a schedule or close transaction is still pending.
+func reconcileSchedule(ctx context.Context, e *electionContract, report *ConsistencyReport) {
+	if !e.version.Has("getSchedule") {
+		return // metadata is the only copy of the window
+	}
+	addr := e.addr
+	sched, err := e.schedule(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		report.Divergences = append(report.Divergences, "schedule: "+err.Error())
+		return
+	}
+	if txStore != nil {
+		pending, err := txStore.List(ctx, txmanager.Filter{
+			Purposes: []string{PurposeSetSchedule, PurposeCloseElection, PurposePauseElection, PurposeResumeElection},
+			Election: addr.Hex(),
+			Status:   txmanager.StatusPending,
+		}, 1)
+		if err != nil || len(pending) > 0 {
+			return
+		}
+	}
+	if app.Elections == nil {
+		return
+	}
+
+	meta, err := app.Elections.Get(ctx, addr.Hex())
+	if err != nil {
+		return
+	}
+
+	set := map[string]interface{}{}
+	if !meta.StartDate.Equal(sched.Start) {
+		set["start_date"] = sched.Start
+	}
+	if !meta.EndDate.Equal(sched.End) {
+		set["end_date"] = sched.End
+	}
+	if sched.Closed && meta.Status != "ENDED" {
+		set["status"] = "ENDED"
+	}
+	if e.version.Has("paused") {
+		if out, err := e.call(&bind.CallOpts{Context: ctx}, "paused"); err == nil {
+			if paused := out[0].(bool); paused != (meta.Status == StatusPaused) && !sched.Closed {
+				report.Divergences = append(report.Divergences, fmt.Sprintf("pause: chain paused=%v, metadata status %s", paused, meta.Status))
+			}
+		}
+	}
+	if len(set) == 0 {
+		return
+	}
+	if err := app.Elections.Update(ctx, addr.Hex(), repository.Update{Set: set}); err != nil {
+		log.Printf("[RECONCILE ERROR] Failed to sync schedule for %s: %v", addr.Hex(), err)
+		return
+	}
+	report.Repaired = append(report.Repaired, fmt.Sprintf("schedule: %s - %s (closed=%t) copied from chain",
+		sched.Start.Format(time.RFC3339), sched.End.Format(time.RFC3339), sched.Closed))
+}
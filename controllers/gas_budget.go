@@ -0,0 +1,162 @@
+﻿package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"MAJOR-PROJECT/txmanager"
+
+	"github.com/gorilla/mux"
+)
+
+// companyForElection returns the company that owns an election, as recorded in its
+// metadata by the ElectionCreated indexer. Empty when the election is unknown.
+func companyForElection(electionAddr string) string {
+	if app.Elections == nil || electionAddr == "" {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	meta, err := app.Elections.Get(ctx, strings.TrimSpace(electionAddr))
+	if err != nil {
+		log.Printf("[GAS WARN] no company on record for election %s; gas will not be charged to a budget", electionAddr)
+		return ""
+	}
+	return meta.CompanyEmail
+}
+
+// feeStrategyFor lets operators pick a strategy per purpose, e.g. GAS_FEE_STRATEGY_L1_ANCHOR=fast.
+// Empty means the manager default (GAS_FEE_STRATEGY).
+func feeStrategyFor(purpose string) string {
+	return strings.TrimSpace(os.Getenv("GAS_FEE_STRATEGY_" + purpose))
+}
+
+// sendErrorStatus maps a txmanager.Send error to an HTTP status.
+func sendErrorStatus(err error) int {
+	if errors.Is(err, txmanager.ErrBudgetExceeded) {
+		return http.StatusPaymentRequired
+	}
+	if errors.Is(err, errNotApproved) {
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// gasManagers returns the managers a spend report covers, optionally narrowed to one chain.
+func gasManagers(chainName string) []*txmanager.Manager {
+	var out []*txmanager.Manager
+	for _, m := range []*txmanager.Manager{l2Tx, l1Tx} {
+		if m == nil {
+			continue
+		}
+		if chainName != "" && !strings.EqualFold(chainName, m.Chain()) {
+			continue
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// GetCompanyGasSpend reports what a company has spent and reserved per chain, broken
+// down by election and purpose, against its budget.
+// GET /api/company/{email}/gas?chain=L2
+func (h *Handlers) GetCompanyGasSpend(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	email, _ := url.PathUnescape(mux.Vars(r)["email"])
+	if strings.TrimSpace(email) == "" {
+		respondError(w, http.StatusBadRequest, "company email is required")
+		return
+	}
+	managers := gasManagers(r.URL.Query().Get("chain"))
+	if len(managers) == 0 {
+		respondError(w, http.StatusNotFound, "no transaction manager for the requested chain")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	reports := make([]*txmanager.Spend, 0, len(managers))
+	for _, m := range managers {
+		spend, err := m.Spend(ctx, email)
+		if err != nil {
+			log.Printf("[GAS ERROR] spend report for %s on %s: %v", email, m.Chain(), err)
+			respondError(w, http.StatusInternalServerError, "failed to compute gas spend")
+			return
+		}
+		reports = append(reports, spend)
+	}
+	respondJSON(w, http.StatusOK, BlockchainResponse{Status: "success", Message: "gas spend report", Data: reports})
+}
+
+// SetCompanyGasBudget sets (or with a null limit, removes) a company's cap on one chain.
+// Only the company itself may change its cap.
+// PUT /api/company/{email}/gas/budget  {"chain": "L2", "limit_wei": "500000000000000000", "password": "..."}
+func (h *Handlers) SetCompanyGasBudget(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	email, _ := url.PathUnescape(mux.Vars(r)["email"])
+	if strings.TrimSpace(email) == "" {
+		respondError(w, http.StatusBadRequest, "company email is required")
+		return
+	}
+
+	var req struct {
+		Chain    string  `json:"chain"`
+		LimitWei *string `json:"limit_wei"`
+		Password string  `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	chainName := strings.ToUpper(strings.TrimSpace(req.Chain))
+	if chainName == "" {
+		chainName = "L2"
+	}
+	if len(gasManagers(chainName)) == 0 {
+		respondError(w, http.StatusBadRequest, "unknown chain "+chainName)
+		return
+	}
+
+	var limit *big.Int
+	if req.LimitWei != nil {
+		v, ok := new(big.Int).SetString(strings.TrimSpace(*req.LimitWei), 10)
+		if !ok || v.Sign() < 0 {
+			respondError(w, http.StatusBadRequest, "limit_wei must be a non-negative integer in wei")
+			return
+		}
+		limit = v
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := h.checkCompanyPassword(ctx, email, req.Password); err != nil {
+		respondAuthError(w, err)
+		return
+	}
+	if txStore == nil {
+		respondError(w, http.StatusInternalServerError, "transaction manager not initialized")
+		return
+	}
+	if err := txStore.SetBudget(ctx, email, chainName, limit); err != nil {
+		log.Printf("[GAS ERROR] set budget for %s on %s: %v", email, chainName, err)
+		respondError(w, http.StatusInternalServerError, "failed to store gas budget")
+		return
+	}
+
+	details := "Removed " + chainName + " gas budget"
+	if limit != nil {
+		details = "Set " + chainName + " gas budget to " + limit.String() + " wei"
+	}
+	go LogAction("", "GAS_BUDGET_SET", txmanager.NormalizeCompany(email), details)
+	respondJSON(w, http.StatusOK, BlockchainResponse{Status: "success", Message: details})
+}
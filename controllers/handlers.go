@@ -0,0 +1,69 @@
+﻿package controllers
+
+import (
+	"MAJOR-PROJECT/repository"
+)
+
+// Handlers serves the HTTP API over the storage repositories; routes.SetupRoutes
+// registers its methods. The tx hooks, chain indexer, reconciler and anchor worker use
+// the same instance through app, so InitHandlers runs before any of them start.
+type Handlers struct {
+	Voters     repository.VoterRepo
+	Elections  repository.ElectionRepo
+	Candidates repository.CandidateRepo
+	Audit      repository.AuditRepo
+	OTPs       repository.OTPRepo
+	Companies  repository.CompanyRepo
+	AnchorJobs repository.AnchorJobRepo
+	Rosters    repository.RosterImportRepo
+	Resets     repository.ResetRepo
+	Students   repository.StudentRepo
+	Ballots    repository.BallotRepo
+	Reports    repository.ReportRepo
+	Operators  repository.OperatorKeyRepo
+	Bundles    repository.BundleArchiveRepo
+}
+
+// app is the instance installed by InitHandlers. Until then its repositories are nil
+// and the background helpers skip storage, as they did before the database was ready.
+var app = &Handlers{}
+
+// InitHandlers builds the API over repos (repository.NewMongo, or repository.NewMemory
+// for tests and the no-DB demo mode) and installs it for the background workers.
+func InitHandlers(repos *repository.Repositories) *Handlers {
+	app = &Handlers{
+		Voters:     repos.Voters,
+		Elections:  repos.Elections,
+		Candidates: repos.Candidates,
+		Audit:      repos.Audit,
+		OTPs:       repos.OTPs,
+		Companies:  repos.Companies,
+		AnchorJobs: repos.AnchorJobs,
+		Rosters:    repos.Rosters,
+		Resets:     repos.Resets,
+		Students:   repos.Students,
+		Ballots:    repos.Ballots,
+		Reports:    repos.Reports,
+		Operators:  repos.Operators,
+		Bundles:    repos.Bundles,
+	}
+	return app
+}
+
+// The records are owned by the repository package; the controllers keep their names.
+type (
+	Voter             = repository.Voter
+	VoterRegistration = repository.VoterRegistration
+	ElectionMetadata  = repository.ElectionMetadata
+	ElectionApprover  = repository.ElectionApprover
+	ElectionApproval  = repository.ElectionApproval
+	CandidateDocument = repository.CandidateDocument
+	AuditLog          = repository.AuditLog
+	Company           = repository.Company
+	AnchorJob         = repository.AnchorJob
+	RosterImport      = repository.RosterImport
+	RosterRow         = repository.RosterRow
+	Student           = repository.Student
+	Ballot            = repository.Ballot
+	ConsistencyReport = repository.ConsistencyReport
+)
@@ -0,0 +1,317 @@
+﻿package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"MAJOR-PROJECT/repository"
+
+	"github.com/gorilla/mux"
+)
+
+// newTestHandlers installs handlers over fresh in-memory repositories.
+func newTestHandlers(t *testing.T) *Handlers {
+	t.Helper()
+	return InitHandlers(repository.NewMemory())
+}
+
+// serve routes one request through a router that only knows pattern, so path
+// variables reach the handler the way routes.SetupRoutes passes them.
+func serve(t *testing.T, handler http.HandlerFunc, method, pattern, target string, body interface{}, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, target, &buf)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	router := mux.NewRouter()
+	router.HandleFunc(pattern, handler).Methods(method)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeBody(t *testing.T, rec *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	var out map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("response is not JSON: %v\n%s", err, rec.Body.String())
+	}
+	return out
+}
+
+// basicAuth is the header of a request signed with HTTP Basic credentials.
+func basicAuth(email, password string) http.Header {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.SetBasicAuth(email, password)
+	return req.Header
+}
+
+const testElection = "0x00000000000000000000000000000000000000Aa"
+
+// ownElection records company as the owner of testElection.
+func ownElection(t *testing.T, h *Handlers, company string) {
+	t.Helper()
+	err := h.Elections.Upsert(context.Background(), testElection, repository.Update{Set: map[string]interface{}{
+		"company_email": company,
+		"status":        "UPCOMING",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func registerCompany(t *testing.T, h *Handlers, email, password string) {
+	t.Helper()
+	rec := serve(t, h.CreateCompany, http.MethodPost, "/company/register", "/company/register",
+		map[string]string{"email": email, "password": password}, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("register %s: status %d: %s", email, rec.Code, rec.Body.String())
+	}
+}
+
+func TestCompanyRegisterAndAuthenticate(t *testing.T) {
+	h := newTestHandlers(t)
+	registerCompany(t, h, "org@example.com", "s3cret")
+
+	rec := serve(t, h.CreateCompany, http.MethodPost, "/company/register", "/company/register",
+		map[string]string{"email": "org@example.com", "password": "other"}, nil)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("duplicate register: got %d, want 409", rec.Code)
+	}
+
+	for _, tc := range []struct {
+		email, password string
+		want            int
+	}{
+		{"org@example.com", "s3cret", http.StatusOK},
+		{"org@example.com", "wrong", http.StatusUnauthorized},
+		{"nobody@example.com", "s3cret", http.StatusUnauthorized},
+		{"org@example.com", "", http.StatusBadRequest},
+	} {
+		rec := serve(t, h.AuthenticateCompany, http.MethodPost, "/company/authenticate", "/company/authenticate",
+			map[string]string{"email": tc.email, "password": tc.password}, nil)
+		if rec.Code != tc.want {
+			t.Errorf("authenticate %s/%q: got %d, want %d: %s", tc.email, tc.password, rec.Code, tc.want, rec.Body.String())
+		}
+	}
+
+	c, err := h.Companies.GetByEmail(context.Background(), "org@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Password == "s3cret" {
+		t.Error("company password stored in clear text")
+	}
+}
+
+func TestSetCompanyRetention(t *testing.T) {
+	h := newTestHandlers(t)
+	registerCompany(t, h, "org@example.com", "s3cret")
+
+	rec := serve(t, h.SetCompanyRetention, http.MethodPut, "/company/{email}/retention", "/company/org@example.com/retention",
+		map[string]interface{}{"retention_days": 30}, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("set retention: status %d: %s", rec.Code, rec.Body.String())
+	}
+	c, _ := h.Companies.GetByEmail(context.Background(), "org@example.com")
+	if c.RetentionDays == nil || *c.RetentionDays != 30 {
+		t.Fatalf("retention_days = %v, want 30", c.RetentionDays)
+	}
+	if got := retentionFor(context.Background(), "org@example.com"); got != 30*24*time.Hour {
+		t.Errorf("retentionFor = %s, want 720h", got)
+	}
+
+	rec = serve(t, h.SetCompanyRetention, http.MethodPut, "/company/{email}/retention", "/company/nobody@example.com/retention",
+		map[string]interface{}{"retention_days": 30}, nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown company: got %d, want 404", rec.Code)
+	}
+}
+
+func TestGetAllVotersPages(t *testing.T) {
+	h := newTestHandlers(t)
+	ctx := context.Background()
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		if err := h.Voters.Create(ctx, &Voter{Email: email, FullName: email, Registrations: []VoterRegistration{}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	seen := map[string]bool{}
+	target := "/voters?limit=2&sort=email"
+	for pages := 0; target != ""; pages++ {
+		if pages > 2 {
+			t.Fatal("cursor never ran out")
+		}
+		rec := serve(t, h.GetAllVoters, http.MethodGet, "/voters", target, nil, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("list voters: status %d: %s", rec.Code, rec.Body.String())
+		}
+		body := decodeBody(t, rec)
+		for _, row := range body["data"].([]interface{}) {
+			seen[row.(map[string]interface{})["id"].(string)] = true
+		}
+		target = ""
+		if next, _ := body["page"].(map[string]interface{})["next_cursor"].(string); next != "" {
+			target = "/voters?limit=2&sort=email&cursor=" + next
+		}
+	}
+	if len(seen) != 3 {
+		t.Errorf("paged through %d voters, want 3", len(seen))
+	}
+
+	rec := serve(t, h.GetAllVoters, http.MethodGet, "/voters", "/voters?sort=password", nil, nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown sort key: got %d, want 400", rec.Code)
+	}
+}
+
+func TestListVotersPrivateFields(t *testing.T) {
+	h := newTestHandlers(t)
+	registerCompany(t, h, "owner@example.com", "owner-pw")
+	registerCompany(t, h, "other@example.com", "other-pw")
+	ownElection(t, h, "owner@example.com")
+	v := createVoter(t, h, "voter@example.com", "voter-pw")
+	if err := h.Voters.Update(context.Background(), v.ID, map[string]interface{}{"mobile": "9876543210"}); err != nil {
+		t.Fatal(err)
+	}
+	eraseWithinRetention(t, h, createVoter(t, h, "gone@example.com", "gone-pw"))
+
+	list := func(query string, header http.Header) (int, []interface{}) {
+		rec := serve(t, h.GetAllVoters, http.MethodGet, "/voters", "/voters?"+query, nil, header)
+		if rec.Code != http.StatusOK {
+			return rec.Code, nil
+		}
+		return rec.Code, decodeBody(t, rec)["data"].([]interface{})
+	}
+
+	for _, query := range []string{"fields=email,mobile", "fields=id,full_name"} {
+		if code, _ := list(query, nil); code != http.StatusUnauthorized {
+			t.Errorf("anonymous %s: got %d, want 401", query, code)
+		}
+	}
+	_, rows := list("", nil)
+	for _, row := range rows {
+		for _, f := range voterPrivateFields {
+			if _, ok := row.(map[string]interface{})[f]; ok {
+				t.Errorf("anonymous default row carries %s: %v", f, row)
+			}
+		}
+	}
+	if code, _ := list("fields=email,mobile", basicAuth("voter@example.com", "voter-pw")); code != http.StatusUnauthorized {
+		t.Errorf("voter mobile: got %d, want 401", code)
+	}
+	_, rows = list("", basicAuth("other@example.com", "other-pw"))
+	for _, row := range rows {
+		if _, ok := row.(map[string]interface{})["email"]; ok {
+			t.Errorf("email shown to a company that owns none of the voter's elections: %v", row)
+		}
+	}
+	_, rows = list("fields=email,mobile", basicAuth("owner@example.com", "owner-pw"))
+	if len(rows) != 1 || rows[0].(map[string]interface{})["mobile"] != "9876543210" || rows[0].(map[string]interface{})["email"] != "voter@example.com" {
+		t.Errorf("owner: got %v, want the voter's mobile and no erased stub", rows)
+	}
+
+	if _, rows = list("include_erased=true", nil); len(rows) != 2 {
+		t.Errorf("include_erased: got %d rows, want 2", len(rows))
+	}
+}
+
+func TestGetAnchorStatus(t *testing.T) {
+	h := newTestHandlers(t)
+	const addr = "0x00000000000000000000000000000000000000aa"
+
+	rec := serve(t, h.GetAnchorStatus, http.MethodGet, "/elections/{address}/anchor", "/elections/"+addr+"/anchor", nil, nil)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("no job: got %d, want 404", rec.Code)
+	}
+
+	if err := h.AnchorJobs.Queue(context.Background(), addr, "", "Admin", 3); err != nil {
+		t.Fatal(err)
+	}
+	rec = serve(t, h.GetAnchorStatus, http.MethodGet, "/elections/{address}/anchor", "/elections/"+addr+"/anchor", nil, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("queued job: status %d: %s", rec.Code, rec.Body.String())
+	}
+	job := decodeBody(t, rec)["data"].(map[string]interface{})
+	if job["status"] != AnchorQueued || job["required_confirmations"] != float64(3) {
+		t.Errorf("job = %v", job)
+	}
+
+	due, err := h.AnchorJobs.Due(context.Background(), time.Now().UTC())
+	if err != nil || len(due) != 1 {
+		t.Fatalf("Due = %v, %v; want the queued job", due, err)
+	}
+}
+
+func TestReAnchorRequiresOwner(t *testing.T) {
+	h := newTestHandlers(t)
+	registerCompany(t, h, "owner@example.com", "owner-pw")
+	registerCompany(t, h, "other@example.com", "other-pw")
+	ownElection(t, h, "owner@example.com")
+
+	for _, password := range []string{"", "other-pw"} {
+		rec := serve(t, h.ReAnchorElection, http.MethodPost, "/admin/elections/{address}/anchor", "/admin/elections/"+testElection+"/anchor",
+			map[string]string{"password": password}, nil)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("password %q: got %d, want 401", password, rec.Code)
+		}
+	}
+	if _, err := h.AnchorJobs.Get(context.Background(), testElection); err != repository.ErrNotFound {
+		t.Errorf("anchor job queued by a refused request (err %v)", err)
+	}
+}
+
+func TestResetTokenSurvivesRestart(t *testing.T) {
+	t.Setenv("BUNDLE_SIGNING_KEY", "")
+	repos := repository.NewMemory()
+	h := InitHandlers(repos)
+	registerCompany(t, h, "owner@example.com", "owner-pw")
+	ownElection(t, h, "owner@example.com")
+
+	rec := serve(t, h.PreviewCompanyReset, http.MethodPost, "/company/{email}/reset/preview", "/company/owner@example.com/reset/preview",
+		map[string]string{"password": "owner-pw"}, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("preview: got %d: %s", rec.Code, rec.Body.String())
+	}
+	token := decodeBody(t, rec)["data"].(map[string]interface{})["confirm_token"].(string)
+
+	// Another instance over the same storage, as after a restart or on another replica
+	h = InitHandlers(repos)
+	reset := func() int {
+		return serve(t, h.ResetCompany, http.MethodPost, "/company/{email}/reset", "/company/owner@example.com/reset",
+			map[string]string{"password": "owner-pw", "confirm_token": token}, nil).Code
+	}
+	if got := reset(); got != http.StatusServiceUnavailable {
+		t.Fatalf("reset with the token: got %d, want 503 (token accepted, no signing key)", got)
+	}
+	if got := reset(); got != http.StatusForbidden {
+		t.Errorf("token reused: got %d, want 403", got)
+	}
+
+	list := func(header http.Header) *httptest.ResponseRecorder {
+		return serve(t, h.ListCompanyResets, http.MethodGet, "/company/{email}/resets", "/company/owner@example.com/resets", nil, header)
+	}
+	if got := list(nil).Code; got != http.StatusUnauthorized {
+		t.Errorf("history without credentials: got %d, want 401", got)
+	}
+	rec = list(basicAuth("owner@example.com", "owner-pw"))
+	records := decodeBody(t, rec)["data"].([]interface{})
+	if len(records) != 1 || records[0].(map[string]interface{})["status"] != ResetFailed {
+		t.Errorf("history = %v, want the one failed reset", records)
+	}
+	if _, err := h.Elections.Get(context.Background(), testElection); err != nil {
+		t.Errorf("election deleted although the export failed: %v", err)
+	}
+}
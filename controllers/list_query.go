@@ -0,0 +1,315 @@
+﻿package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"MAJOR-PROJECT/repository"
+)
+
+// List endpoints (voters, election voters, elections) share one envelope:
+//
+//	{"status", "message", "data": [...], "page": {"total", "count", "limit", "next_cursor"}}
+//
+// and the query parameters limit, cursor, sort ("key" or "-key") and fields (comma list).
+
+// voterListFields are the fields a voter row can carry. The personal ones are only filled
+// in for an admin signed in with HTTP Basic credentials, and only for voters registered
+// for one of that company's elections; dob and mobile also have to be asked for.
+var (
+	voterListFields    = []string{"id", "email", "full_name", "dob", "roll_no", "mobile", "gender", "year", "status", "registered_at"}
+	voterPrivateFields = []string{"email", "full_name", "dob", "roll_no", "mobile", "gender"}
+	voterDefaultFields = []string{"id", "email", "full_name", "roll_no", "gender", "year", "status"}
+	electionListFields = jsonFields(reflect.TypeOf(repository.ElectionMetadata{}))
+)
+
+// readVoterQuery parses the voter list parameters; electionAddr, when set, scopes the list.
+func readVoterQuery(q url.Values, electionAddr string) (repository.VoterQuery, error) {
+	vq := repository.VoterQuery{
+		ElectionAddress: electionAddr,
+		Status:          q.Get("status"),
+		Year:            q.Get("year"),
+		Gender:          q.Get("gender"),
+		Search:          q.Get("search"),
+		IncludeErased:   q.Get("include_erased") == "true",
+		Cursor:          q.Get("cursor"),
+	}
+	var err error
+	if vq.Limit, err = readLimit(q); err != nil {
+		return vq, err
+	}
+	if vq.Sort, err = repository.ParseSort(q.Get("sort"), repository.VoterSortKeys, repository.Sort{Field: "_id"}); err != nil {
+		return vq, err
+	}
+	if vq.RegisteredFrom, err = readTime(q, "registered_from", false); err != nil {
+		return vq, err
+	}
+	vq.RegisteredTo, err = readTime(q, "registered_to", true)
+	return vq, err
+}
+
+// readElectionQuery parses the election list parameters; latest start date first by default.
+func readElectionQuery(q url.Values) (repository.ElectionQuery, error) {
+	eq := repository.ElectionQuery{
+		ElectionFilter: repository.ElectionFilter{
+			Status:       strings.ToUpper(strings.TrimSpace(q.Get("status"))),
+			CompanyEmail: q.Get("company"),
+		},
+		Search: q.Get("search"),
+		Cursor: q.Get("cursor"),
+	}
+	var err error
+	if eq.Limit, err = readLimit(q); err != nil {
+		return eq, err
+	}
+	if eq.Sort, err = repository.ParseSort(q.Get("sort"), repository.ElectionSortKeys, repository.Sort{Field: "start_date", Desc: true}); err != nil {
+		return eq, err
+	}
+	if eq.StartFrom, err = readTime(q, "start_from", false); err != nil {
+		return eq, err
+	}
+	eq.StartTo, err = readTime(q, "start_to", true)
+	return eq, err
+}
+
+func readLimit(q url.Values) (int, error) {
+	s := q.Get("limit")
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%w: limit must be a positive number", repository.ErrInvalidQuery)
+	}
+	return n, nil
+}
+
+// readTime accepts RFC 3339 or a plain date. A plain date as the upper bound covers that
+// whole day, since ranges are half-open.
+func readTime(q url.Values, name string, upper bool) (time.Time, error) {
+	s := strings.TrimSpace(q.Get(name))
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s must be a date (YYYY-MM-DD) or RFC 3339 time", repository.ErrInvalidQuery, name)
+	}
+	if upper {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// readFields returns the requested fields, or def when none were asked for.
+func readFields(q url.Values, allowed, def []string) ([]string, error) {
+	s := strings.TrimSpace(q.Get("fields"))
+	if s == "" {
+		return def, nil
+	}
+	var out []string
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if !containsString(allowed, f) {
+			return nil, fmt.Errorf("%w: unknown field %q", repository.ErrInvalidQuery, f)
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+// without returns fields minus the ones in drop.
+func without(fields, drop []string) []string {
+	var out []string
+	for _, f := range fields {
+		if !containsString(drop, f) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// jsonFields lists the JSON names of t's exported fields.
+func jsonFields(t reflect.Type) []string {
+	var out []string
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if tag != "" && tag != "-" {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+func containsString(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// pick keeps only fields of row.
+func pick(row map[string]interface{}, fields []string) map[string]interface{} {
+	out := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		if v, ok := row[f]; ok {
+			out[f] = v
+		}
+	}
+	return out
+}
+
+// voterRow is the list view of v. Status and registration time are those of the
+// electionAddr registration, or "Registered" and the first registration for "".
+func voterRow(v repository.Voter, electionAddr string) map[string]interface{} {
+	var dob string
+	if !v.DOB.IsZero() {
+		dob = v.DOB.Format("2006-01-02")
+	}
+	status := "Registered"
+	var registeredAt interface{}
+	for _, reg := range v.Registrations {
+		if electionAddr == "" || reg.ElectionAddress == electionAddr {
+			if electionAddr != "" {
+				status = reg.Status
+			}
+			registeredAt = reg.RegisteredAt
+			break
+		}
+	}
+	return map[string]interface{}{
+		"id":            v.ID.Hex(),
+		"email":         v.Email,
+		"full_name":     v.FullName,
+		"dob":           dob,
+		"roll_no":       v.RollNo,
+		"mobile":        v.Mobile,
+		"gender":        v.Gender,
+		"year":          v.Year,
+		"status":        status,
+		"registered_at": registeredAt,
+	}
+}
+
+// electionRow is the JSON object form of m, so fields can be picked from it.
+func electionRow(m repository.ElectionMetadata) (map[string]interface{}, error) {
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	var row map[string]interface{}
+	err = json.Unmarshal(raw, &row)
+	return row, err
+}
+
+func respondPage(w http.ResponseWriter, message string, rows []map[string]interface{}, page repository.PageInfo) {
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": message,
+		"data":    rows,
+		"page":    page,
+	})
+}
+
+// ownsVoter reports whether owns holds for the listed election, or, in an unscoped list,
+// for any election the voter is registered for.
+func ownsVoter(v Voter, electionAddr string, owns func(string) bool) bool {
+	if electionAddr != "" {
+		return owns(electionAddr)
+	}
+	for _, reg := range v.Registrations {
+		if owns(reg.ElectionAddress) {
+			return true
+		}
+	}
+	return false
+}
+
+// respondListError answers a rejected query with 400 and anything else with 500.
+func respondListError(w http.ResponseWriter, err error, what string) {
+	if errors.Is(err, repository.ErrInvalidQuery) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondError(w, http.StatusInternalServerError, "Error fetching "+what)
+}
+
+// listVoters serves one page of voters for GetAllVoters and GetElectionVoters.
+func (h *Handlers) listVoters(w http.ResponseWriter, r *http.Request, electionAddr string) {
+	q := r.URL.Query()
+	vq, err := readVoterQuery(q, electionAddr)
+	if err != nil {
+		respondListError(w, err, "voters")
+		return
+	}
+	fields, err := readFields(q, voterListFields, voterDefaultFields)
+	if err != nil {
+		respondListError(w, err, "voters")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var who *caller
+	private := false
+	for _, f := range voterPrivateFields {
+		private = private || containsString(fields, f)
+	}
+	if private {
+		if who, err = h.basicCaller(ctx, r); err != nil && !errors.Is(err, errBadCredentials) {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if !who.admin() && strings.TrimSpace(q.Get("fields")) != "" {
+			respondUnauthorized(w, "personal fields are only listed for signed-in admins")
+			return
+		}
+		if !who.admin() {
+			// Anonymous default rows keep only the fields that are not personal.
+			fields, private = without(fields, voterPrivateFields), false
+		}
+	}
+
+	page, err := h.Voters.Page(ctx, vq)
+	if err != nil {
+		respondListError(w, err, "voters")
+		return
+	}
+	owned := map[string]bool{} // election address -> the caller's company owns it
+	owns := func(addr string) bool {
+		if ok, seen := owned[addr]; seen {
+			return ok
+		}
+		meta, err := h.Elections.Get(ctx, addr)
+		owned[addr] = err == nil && who.isCompany(meta.CompanyEmail)
+		return owned[addr]
+	}
+	rows := make([]map[string]interface{}, 0, len(page.Voters))
+	for _, v := range page.Voters {
+		row := pick(voterRow(v, electionAddr), fields)
+		if private && !ownsVoter(v, electionAddr, owns) {
+			for _, f := range voterPrivateFields {
+				delete(row, f)
+			}
+		}
+		rows = append(rows, row)
+	}
+	respondPage(w, "voters retrieved", rows, page.PageInfo)
+}
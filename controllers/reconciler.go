@@ -0,0 +1,304 @@
+﻿package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"MAJOR-PROJECT/repository"
+	"MAJOR-PROJECT/txmanager"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+)
+
+// orphanGrace is how long a "submitted" candidate without a managed tx may stay off-chain
+// before it is flagged as orphaned.
+const orphanGrace = 30 * time.Minute
+
+// InitReconciler starts the periodic reconciler (every RECONCILE_INTERVAL, default 10m),
+// which keeps its reports in app.Reports.
+func InitReconciler() {
+	interval, err := time.ParseDuration(strings.TrimSpace(os.Getenv("RECONCILE_INTERVAL")))
+	if err != nil || interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			reconcileActiveElections()
+		}
+	}()
+	log.Printf("[RECONCILE] Reconciler running every %s", interval)
+}
+
+// reconcileActiveElections runs one pass over every election that has not ended.
+func reconcileActiveElections() {
+	if app.Elections == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	metas, err := app.Elections.List(ctx, repository.ElectionFilter{ExcludeStatus: "ENDED"})
+	cancel()
+	if err != nil {
+		log.Printf("[RECONCILE ERROR] Failed to list active elections: %v", err)
+		return
+	}
+
+	inconsistent := 0
+	for _, meta := range metas {
+		if !common.IsHexAddress(meta.ElectionAddress) {
+			continue
+		}
+		electionCtx, electionCancel := context.WithTimeout(context.Background(), 60*time.Second)
+		report := reconcileElection(electionCtx, common.HexToAddress(meta.ElectionAddress))
+		electionCancel()
+		if !report.Consistent {
+			inconsistent++
+		}
+	}
+	log.Printf("[RECONCILE] Checked %d active elections, %d inconsistent", len(metas), inconsistent)
+}
+
+// reconcileElection compares the contract with the candidates collection and the audit
+// trail, repairs candidate statuses it can prove, and stores the report.
+func reconcileElection(ctx context.Context, addr common.Address) *ConsistencyReport {
+	report := &ConsistencyReport{
+		ElectionAddress: addr.Hex(),
+		CheckedAt:       time.Now().UTC(),
+		Repaired:        []string{},
+		Orphans:         []string{},
+		MissingInDB:     []string{},
+		Divergences:     []string{},
+	}
+	defer saveReport(report)
+
+	election, err := newElectionContract(ctx, addr)
+	if err != nil {
+		report.Error = "failed to bind election: " + err.Error()
+		return report
+	}
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	numCandidates, err := election.uint(callOpts, "getNumOfCandidates")
+	if err != nil {
+		report.Error = "getNumOfCandidates failed: " + err.Error()
+		return report
+	}
+	numVoters, err := election.uint(callOpts, "getNumOfVoters")
+	if err != nil {
+		report.Error = "getNumOfVoters failed: " + err.Error()
+		return report
+	}
+	report.OnChainCandidates = numCandidates.Uint64()
+	report.OnChainVotes = numVoters.Uint64()
+
+	// On-chain candidates by lower-cased email
+	type chainCandidate struct {
+		id   uint64
+		name string
+	}
+	onChain := map[string]chainCandidate{}
+	for i := uint64(0); i < report.OnChainCandidates; i++ {
+		c, err := election.candidate(callOpts, new(big.Int).SetUint64(i))
+		if err != nil {
+			report.Error = fmt.Sprintf("getCandidate(%d) failed: %v", i, err)
+			return report
+		}
+		onChain[strings.ToLower(c.Email)] = chainCandidate{id: i, name: c.Name}
+	}
+
+	var docs []CandidateDocument
+	if app.Candidates != nil {
+		var err error
+		if docs, err = app.Candidates.List(ctx, addr.Hex()); err != nil {
+			report.Error = "failed to load candidates: " + err.Error()
+			return report
+		}
+	}
+
+	matched := map[string]bool{}
+	for _, doc := range docs {
+		key := strings.ToLower(doc.Email)
+		if c, ok := onChain[key]; ok {
+			matched[key] = true
+			report.DBCandidates++
+			if doc.Status != "mined" {
+				setCandidateStatus(ctx, doc, "mined")
+				report.Repaired = append(report.Repaired, fmt.Sprintf("%s: %s -> mined", doc.Email, statusLabel(doc.Status)))
+			}
+			if doc.Name != c.name {
+				report.Divergences = append(report.Divergences, fmt.Sprintf("candidate %d name: chain %q, db %q", c.id, c.name, doc.Name))
+			}
+			continue
+		}
+		reconcileOffChainCandidate(ctx, doc, report)
+	}
+
+	for email, c := range onChain {
+		if !matched[email] {
+			report.MissingInDB = append(report.MissingInDB, fmt.Sprintf("%d:%s (%s)", c.id, email, c.name))
+		}
+	}
+
+	if app.Audit != nil {
+		report.DBVotes, _ = app.Audit.CountAction(ctx, addr.Hex(), "VOTE_CAST")
+		baseline, err := voteBaseline(ctx, election)
+		if err != nil {
+			// Without the baseline a difference proves nothing; try again next pass.
+			log.Printf("[RECONCILE WARN] %s vote baseline unavailable: %v", addr.Hex(), err)
+		}
+		report.VoteBaseline = baseline
+		if err == nil && baseline+uint64(report.DBVotes) != report.OnChainVotes {
+			report.Divergences = append(report.Divergences, fmt.Sprintf("votes: chain %d, audit log %d + %d before indexing", report.OnChainVotes, report.DBVotes, baseline))
+		}
+	}
+
+	reconcileSchedule(ctx, election, report)
+
+	report.Consistent = len(report.Orphans) == 0 && len(report.MissingInDB) == 0 && len(report.Divergences) == 0
+	if !report.Consistent {
+		log.Printf("[RECONCILE] %s inconsistent: %d orphans, %d missing in DB, %d divergences",
+			addr.Hex(), len(report.Orphans), len(report.MissingInDB), len(report.Divergences))
+	}
+	return report
+}
+
+// voteBaseline is the number of votes the election had before the L2 indexer's first
+// block; those never got a VOTE_CAST entry. It is read once from the contract state at
+// that block and kept in the election metadata.
+func voteBaseline(ctx context.Context, election *electionContract) (uint64, error) {
+	addr := election.addr.Hex()
+	if meta, err := app.Elections.Get(ctx, addr); err == nil && meta.VoteBaseline != nil {
+		return *meta.VoteBaseline, nil
+	}
+	if l2Indexer == nil {
+		return 0, errors.New("chain indexer not running")
+	}
+	start, err := l2Indexer.StartBlock(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var baseline uint64
+	if start > 0 {
+		n, err := election.uint(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(start - 1)}, "getNumOfVoters")
+		switch {
+		case errors.Is(err, bind.ErrNoCode):
+			// deployed after the indexer started: every vote is indexed
+		case err != nil:
+			return 0, fmt.Errorf("getNumOfVoters at block %d: %w", start-1, err)
+		default:
+			baseline = n.Uint64()
+		}
+	}
+	if err := app.Elections.Update(ctx, addr, repository.Update{Set: map[string]interface{}{"vote_baseline": baseline}}); err != nil {
+		log.Printf("[RECONCILE WARN] Failed to store vote baseline for %s: %v", addr, err)
+	}
+	return baseline, nil
+}
+
+// reconcileOffChainCandidate settles a DB candidate that is not on the contract. Only
+// the managed tx can say whether it is still in flight; without one, age decides.
+func reconcileOffChainCandidate(ctx context.Context, doc CandidateDocument, report *ConsistencyReport) {
+	switch doc.Status {
+	case "failed", "reverted", "orphaned":
+		return // already settled, not counted
+	}
+
+	var rec *txmanager.Record
+	if txStore != nil {
+		ref := doc.TxID
+		if ref == "" {
+			ref = doc.TxHash
+		}
+		if ref != "" {
+			rec, _ = txStore.Get(ctx, ref)
+		}
+	}
+
+	switch {
+	case rec != nil && rec.Status == txmanager.StatusPending:
+		report.DBCandidates++ // still in flight
+	case rec != nil && (rec.Status == txmanager.StatusFailed || rec.Status == txmanager.StatusReverted):
+		newStatus := strings.ToLower(rec.Status)
+		setCandidateStatus(ctx, doc, newStatus)
+		report.Repaired = append(report.Repaired, fmt.Sprintf("%s: %s -> %s", doc.Email, statusLabel(doc.Status), newStatus))
+	case rec == nil && doc.Status != "mined" && time.Since(doc.CreatedAt) < orphanGrace:
+		report.DBCandidates++ // legacy row without a managed tx, give it time
+	default:
+		// Mined per DB, confirmed tx or too old: the row claims a candidate the chain does not have
+		setCandidateStatus(ctx, doc, "orphaned")
+		report.Orphans = append(report.Orphans, fmt.Sprintf("%s (was %s, tx %s)", doc.Email, statusLabel(doc.Status), doc.TxHash))
+	}
+}
+
+func setCandidateStatus(ctx context.Context, doc CandidateDocument, status string) {
+	if app.Candidates == nil {
+		return
+	}
+	match := repository.CandidateMatch{TxID: doc.TxID, ElectionAddress: doc.ElectionAddress, Email: doc.Email, Status: doc.Status}
+	if err := app.Candidates.SetStatus(ctx, match, status, ""); err != nil {
+		log.Printf("[RECONCILE ERROR] Failed to set candidate %s to %s: %v", doc.Email, status, err)
+	}
+}
+
+func statusLabel(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+func saveReport(report *ConsistencyReport) {
+	if app.Reports == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := app.Reports.Save(ctx, report); err != nil {
+		log.Printf("[RECONCILE ERROR] Failed to save report for %s: %v", report.ElectionAddress, err)
+	}
+}
+
+// GetConsistencyReport returns the latest reconciliation report for an election. Running
+// the reconciler (refresh=true, or no report yet) writes to the database, so only the
+// owning company may trigger it, with HTTP Basic credentials.
+// GET /api/elections/{address}/consistency?refresh=true
+func (h *Handlers) GetConsistencyReport(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	if h.Reports == nil {
+		respondError(w, http.StatusInternalServerError, "reconciler not initialized")
+		return
+	}
+
+	addrStr, err := normalizeAddrParam(mux.Vars(r)["address"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	addr := common.HexToAddress(addrStr)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	report, err := h.Reports.Get(ctx, addr.Hex())
+	if errors.Is(err, repository.ErrNotFound) || r.URL.Query().Get("refresh") == "true" {
+		if h.requireOwnerBasic(ctx, w, r, addrStr) == "" {
+			return
+		}
+		report = reconcileElection(ctx, addr)
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to load consistency report")
+		return
+	}
+	respondJSON(w, http.StatusOK, BlockchainResponse{Status: "success", Message: "consistency report for " + addrStr, Data: report})
+}
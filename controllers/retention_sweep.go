@@ -0,0 +1,160 @@
+﻿package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"MAJOR-PROJECT/repository"
+	"MAJOR-PROJECT/txmanager"
+)
+
+// Erasure only deletes an election's references to a voter when the election is already
+// past retention. The sweep catches up on the rest: once an ENDED election passes its
+// company's retention period, the audit entries and ballots that name an erased voter's
+// pseudonym are deleted, the pseudonym is dropped from vote tx payloads and the stubs lose
+// their registration (a stub left with none is deleted). Each election is swept once.
+
+// pseudonymPattern finds voterPseudonym values in audit entries.
+var pseudonymPattern = regexp.MustCompile(`erased-[0-9a-f]{24}`)
+
+// InitRetentionSweep runs the sweep shortly after startup and then every
+// RETENTION_SWEEP_INTERVAL (default 24h).
+func InitRetentionSweep() {
+	interval, err := time.ParseDuration(strings.TrimSpace(os.Getenv("RETENTION_SWEEP_INTERVAL")))
+	if err != nil || interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	go func() {
+		time.Sleep(time.Minute)
+		sweepRetention()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			sweepRetention()
+		}
+	}()
+	log.Printf("[RETENTION] Sweep running every %s", interval)
+}
+
+// sweepRetention purges every ENDED election that is past retention and not swept yet.
+func sweepRetention() {
+	if app.Elections == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	ended, err := app.Elections.List(ctx, repository.ElectionFilter{Status: "ENDED"})
+	if err != nil {
+		log.Printf("[RETENTION ERROR] Failed to list ended elections: %v", err)
+		return
+	}
+	for _, meta := range ended {
+		if meta.RetentionSweptAt != nil || !app.retentionExpired(ctx, meta.ElectionAddress) {
+			continue
+		}
+		n, err := app.purgeErasedVoters(ctx, meta.ElectionAddress)
+		if err != nil {
+			log.Printf("[RETENTION ERROR] Sweep of %s failed: %v", meta.ElectionAddress, err)
+			continue
+		}
+		err = app.Elections.Update(ctx, meta.ElectionAddress, repository.Update{Set: map[string]interface{}{"retention_swept_at": time.Now().UTC()}})
+		if err != nil {
+			log.Printf("[RETENTION ERROR] Failed to mark %s swept: %v", meta.ElectionAddress, err)
+			continue
+		}
+		if n > 0 {
+			log.Printf("[RETENTION] %s: purged %d erased-voter references", meta.ElectionAddress, n)
+			go LogAction(meta.ElectionAddress, "RETENTION_PURGED", "System", fmt.Sprintf("Purged %d erased-voter references past the retention period", n))
+		}
+	}
+}
+
+// purgeErasedVoters deletes one election's references to erased voters and reports how
+// many records were deleted or changed. Every step can be repeated safely.
+func (h *Handlers) purgeErasedVoters(ctx context.Context, electionAddr string) (int64, error) {
+	var total int64
+	pseudonyms := map[string]bool{}
+
+	voters, err := h.Voters.List(ctx, electionAddr)
+	if err != nil {
+		return 0, err
+	}
+	var stubs []Voter
+	for _, v := range voters {
+		if v.ErasedAt != nil {
+			stubs = append(stubs, v)
+			pseudonyms[v.Email] = true
+		}
+	}
+	entries, err := h.Audit.List(ctx, electionAddr)
+	if err != nil {
+		return 0, err
+	}
+	for _, e := range entries {
+		for _, p := range pseudonymPattern.FindAllString(e.Actor+" "+e.Details, -1) {
+			pseudonyms[p] = true
+		}
+	}
+	for p := range pseudonyms {
+		n, err := h.Audit.DeleteMentioning(ctx, electionAddr, p)
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+
+	erased := regexp.MustCompile("^" + pseudonymPattern.String() + "$")
+	if h.Ballots != nil {
+		n, err := h.Ballots.Delete(ctx, repository.BallotQuery{Election: electionAddr, Voter: erased})
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	if txStore != nil {
+		recs, err := txStore.List(ctx, txmanager.Filter{
+			Election: electionAddr,
+			Purposes: []string{PurposeVote},
+			Settled:  true,
+			Voter:    erased,
+		}, 0)
+		if err != nil {
+			return total, err
+		}
+		for _, rec := range recs {
+			changed, err := txStore.Redact(ctx, rec.ID, nil, []string{"payload.voter_email"})
+			if err != nil {
+				return total, err
+			}
+			if changed {
+				total++
+			}
+		}
+	}
+
+	addr := repository.CanonicalAddress(electionAddr)
+	for _, v := range stubs {
+		keep := []VoterRegistration{}
+		for _, reg := range v.Registrations {
+			if repository.CanonicalAddress(reg.ElectionAddress) != addr {
+				keep = append(keep, reg)
+			}
+		}
+		if len(keep) > 0 {
+			err = h.Voters.Update(ctx, v.ID, map[string]interface{}{"registrations": keep})
+		} else {
+			err = h.Voters.Delete(ctx, v.ID)
+		}
+		if err != nil {
+			return total, err
+		}
+		total++
+	}
+	return total, nil
+}
@@ -0,0 +1,707 @@
+﻿package controllers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"MAJOR-PROJECT/repository"
+	"MAJOR-PROJECT/roster"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Roster imports load students and voters from a CSV or XLSX file in two steps. The
+// preview validates every row and records what it would change (the dry-run diff); apply
+// queues the import for a background worker, which plans each row again against current
+// data, applies it and records the outcome. Imports and their rows are kept by
+// h.Rosters; rows are sealed like voters and expire after repository.RosterRowTTL.
+//
+// Every endpoint takes the company's HTTP Basic credentials, as the bundle export does.
+// An import for an election needs its owning company; the company that uploaded an
+// import is its RequestedBy and the only one that may apply or read it.
+
+const (
+	rosterMaxUpload  = 10 << 20
+	rosterMaxRows    = 10000
+	rosterPreviewTTL = 24 * time.Hour
+	rosterMinAge     = 16 // as for admin-registered voters
+)
+
+// Roster import targets.
+const (
+	rosterStudents = "students"
+	rosterVoters   = "voters"
+	rosterBoth     = "both"
+)
+
+// Roster import states.
+const (
+	RosterPreview   = repository.RosterPreview
+	RosterQueued    = repository.RosterQueued
+	RosterRunning   = repository.RosterRunning
+	RosterCompleted = repository.RosterCompleted
+	RosterFailed    = repository.RosterFailed
+)
+
+// Planned changes of a row, per target.
+const (
+	rosterCreate    = "create"
+	rosterUpdate    = "update"
+	rosterLink      = "link" // existing voter, to be registered for the election
+	rosterUnchanged = "unchanged"
+)
+
+// Row results after apply.
+const (
+	rosterApplied   = "applied"
+	rosterRowFailed = repository.RosterRowFailed
+	rosterSkipped   = "skipped" // invalid in the preview
+)
+
+// StartRosterImports starts the worker that applies queued imports. Imports left
+// RUNNING by a restart are queued again and resume at their first row without a result.
+func StartRosterImports() {
+	if app.Rosters == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	if n, err := app.Rosters.Requeue(ctx); err != nil {
+		log.Printf("[ROSTER ERROR] Failed to requeue interrupted imports: %v", err)
+	} else if n > 0 {
+		log.Printf("[ROSTER] Requeued %d interrupted import(s)", n)
+	}
+	cancel()
+
+	go func() {
+		ticker := time.NewTicker(5 * time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			processRosterImports()
+		}
+	}()
+	fmt.Println("[OK] Started roster import worker")
+}
+
+// PreviewRosterImport validates an uploaded roster and stores it as a PREVIEW import.
+// POST /api/admin/roster/preview, multipart form:
+//
+//	file              CSV or XLSX, header row first (required)
+//	target            students, voters or both (default both)
+//	election_address  register imported voters for this election (optional)
+//	columns           JSON {"field": "Header"} overriding the column matching (optional)
+//
+// HTTP Basic: a company account, the owning company when election_address is set.
+func (h *Handlers) PreviewRosterImport(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if h.Rosters == nil {
+		respondError(w, http.StatusInternalServerError, "roster import storage not initialized")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, rosterMaxUpload)
+	if err := r.ParseMultipartForm(rosterMaxUpload); err != nil {
+		respondError(w, http.StatusBadRequest, "expected a multipart form with a roster file of at most 10MB")
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "failed to read file")
+		return
+	}
+
+	job := &RosterImport{
+		ID:        primitive.NewObjectID(),
+		Target:    strings.ToLower(strings.TrimSpace(r.FormValue("target"))),
+		FileName:  header.Filename,
+		Status:    RosterPreview,
+		CreatedAt: time.Now().UTC(),
+	}
+	job.UpdatedAt = job.CreatedAt
+	switch job.Target {
+	case "":
+		job.Target = rosterBoth
+	case rosterStudents, rosterVoters, rosterBoth:
+	default:
+		respondError(w, http.StatusBadRequest, "target must be students, voters or both")
+		return
+	}
+	if job.Target != rosterVoters && h.Students == nil {
+		respondError(w, http.StatusInternalServerError, "student roster storage not initialized")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Minute)
+	defer cancel()
+
+	if s := r.FormValue("election_address"); s != "" {
+		if job.Target == rosterStudents {
+			respondError(w, http.StatusBadRequest, "election_address applies to voters only")
+			return
+		}
+		addr, err := normalizeAddrParam(s)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if job.RequestedBy = h.requireOwnerBasic(ctx, w, r, addr); job.RequestedBy == "" {
+			return
+		}
+		job.ElectionAddress = repository.CanonicalAddress(addr)
+	} else if job.RequestedBy = h.requireCompanyBasic(ctx, w, r); job.RequestedBy == "" {
+		return
+	}
+	var columns map[string]string
+	if s := r.FormValue("columns"); s != "" {
+		if err := json.Unmarshal([]byte(s), &columns); err != nil {
+			respondError(w, http.StatusBadRequest, "columns must be a JSON object of field to header")
+			return
+		}
+	}
+
+	table, err := roster.Read(header.Filename, data, columns, rosterMaxRows)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	job.Format = table.Format
+	job.Columns = table.Mapping.Columns(table.Header)
+
+	rows := make([]RosterRow, 0, len(table.Rows))
+	seenEmail, seenRoll := map[string]int{}, map[string]int{}
+	for _, tr := range table.Rows {
+		row := newRosterRow(job.ID, tr.Number, tr.Values, table.Format == "xlsx", seenEmail, seenRoll)
+		if len(row.Errors) == 0 {
+			if err := h.planRosterRow(ctx, job, &row); err != nil {
+				respondError(w, http.StatusInternalServerError, "failed to compare roster with stored records: "+err.Error())
+				return
+			}
+		}
+		job.Summary.Count(&row)
+		rows = append(rows, row)
+	}
+
+	if err := h.Rosters.Create(ctx, job, rows); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to save roster preview: "+err.Error())
+		return
+	}
+	log.Printf("[ROSTER] Preview %s by %s: %s, %d rows (%d invalid) for %s", job.ID.Hex(), job.RequestedBy, job.FileName, job.Summary.Rows, job.Summary.Invalid, job.Target)
+
+	// The diff: rows that would change something or cannot be imported
+	diff := []RosterRow{}
+	for _, row := range rows {
+		if len(row.Errors) > 0 || (row.Student != rosterUnchanged && row.Student != "") || (row.Voter != rosterUnchanged && row.Voter != "") {
+			diff = append(diff, row)
+		}
+	}
+	respondJSON(w, http.StatusOK, BlockchainResponse{
+		Status:  "success",
+		Message: fmt.Sprintf("%d rows checked, %d invalid; apply within %s", job.Summary.Rows, job.Summary.Invalid, rosterPreviewTTL),
+		Data:    map[string]interface{}{"import": job, "rows": diff},
+	})
+}
+
+// ApplyRosterImport queues a PREVIEW import for the worker.
+// POST /api/admin/roster/imports/{id}/apply (HTTP Basic: the company that uploaded it)
+func (h *Handlers) ApplyRosterImport(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	job := h.callerRosterImport(ctx, w, r)
+	if job == nil {
+		return
+	}
+
+	job, err := h.Rosters.Queue(ctx, job.ID, time.Now().UTC().Add(-rosterPreviewTTL))
+	if errors.Is(err, repository.ErrNotFound) {
+		respondError(w, http.StatusConflict, "import already applied, or its preview expired; upload the file again")
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to queue import: "+err.Error())
+		return
+	}
+	log.Printf("[ROSTER] Queued %s by %s (%d rows)", job.ID.Hex(), job.RequestedBy, job.Summary.Rows)
+	respondJSON(w, http.StatusAccepted, BlockchainResponse{Status: "success", Message: "import queued", Data: job})
+}
+
+// GetRosterImport returns an import with its invalid and failed rows, or every row with
+// ?rows=all.
+// GET /api/admin/roster/imports/{id} (HTTP Basic: the company that uploaded it)
+func (h *Handlers) GetRosterImport(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	job := h.callerRosterImport(ctx, w, r)
+	if job == nil {
+		return
+	}
+	rows, err := h.Rosters.Rows(ctx, repository.RosterRowQuery{ImportID: job.ID, Problems: r.URL.Query().Get("rows") != "all"})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to load rows: "+err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, BlockchainResponse{
+		Status:  "success",
+		Message: "import " + job.Status,
+		Data:    map[string]interface{}{"import": job, "rows": rows},
+	})
+}
+
+// GetRosterImportReport returns every row of an import with its planned change and
+// result as CSV.
+// GET /api/admin/roster/imports/{id}/report.csv (HTTP Basic: the company that uploaded it)
+func (h *Handlers) GetRosterImportReport(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	job := h.callerRosterImport(ctx, w, r)
+	if job == nil {
+		return
+	}
+	rows, err := h.Rosters.Rows(ctx, repository.RosterRowQuery{ImportID: job.ID})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to load rows: "+err.Error())
+		return
+	}
+	if len(rows) == 0 {
+		respondError(w, http.StatusNotFound, "no rows for this import (the report expired)")
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="roster-import-%s.csv"`, job.ID.Hex()))
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"row", "email", "full_name", "student", "voter", "changes", "result", "error"})
+	for _, row := range rows {
+		problem := row.ResultError
+		if problem == "" {
+			problem = strings.Join(row.Errors, "; ")
+		}
+		_ = cw.Write([]string{
+			strconv.Itoa(row.Row), csvCell(row.Email), csvCell(row.FullName), row.Student, row.Voter,
+			strings.Join(row.Changes, " "), row.Result, csvCell(problem),
+		})
+	}
+	cw.Flush()
+}
+
+// csvCell keeps a value from being read as a formula when the report is opened in a
+// spreadsheet.
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// callerRosterImport loads the import named in the path for the company that uploaded
+// it. It answers the request and returns nil when there is no such import or the Basic
+// credentials are not that company's.
+func (h *Handlers) callerRosterImport(ctx context.Context, w http.ResponseWriter, r *http.Request) *RosterImport {
+	if h.Rosters == nil {
+		respondError(w, http.StatusInternalServerError, "roster import storage not initialized")
+		return nil
+	}
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid import id")
+		return nil
+	}
+	job, err := h.Rosters.Get(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		respondError(w, http.StatusNotFound, "import not found")
+		return nil
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return nil
+	}
+	who, err := h.basicCaller(ctx, r)
+	if err != nil && !errors.Is(err, errBadCredentials) {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return nil
+	}
+	if !who.isCompany(job.RequestedBy) {
+		respondUnauthorized(w, "the credentials of the company that uploaded the import are required")
+		return nil
+	}
+	return job
+}
+
+// newRosterRow validates one record. xlsx says the record came from a spreadsheet, whose
+// dates may be serial numbers. seenEmail and seenRoll remember earlier rows of the file
+// to report duplicates.
+func newRosterRow(importID primitive.ObjectID, number int, rec map[string]string, xlsx bool, seenEmail, seenRoll map[string]int) RosterRow {
+	row := RosterRow{
+		ImportID:  importID,
+		Row:       number,
+		Email:     rec[roster.Email],
+		FullName:  rec[roster.FullName],
+		RollNo:    rec[roster.RollNo],
+		Mobile:    strings.NewReplacer(" ", "", "-", "").Replace(rec[roster.Mobile]),
+		Gender:    rec[roster.Gender],
+		Year:      rec[roster.Year],
+		CreatedAt: time.Now().UTC(),
+	}
+	fail := func(format string, args ...interface{}) {
+		row.Errors = append(row.Errors, fmt.Sprintf(format, args...))
+	}
+
+	if row.Email == "" {
+		fail("email is required")
+	} else if a, err := mail.ParseAddress(row.Email); err != nil || a.Address != row.Email {
+		fail("invalid email")
+	} else if prev, dup := seenEmail[strings.ToLower(row.Email)]; dup {
+		fail("duplicate email (row %d)", prev)
+	} else {
+		seenEmail[strings.ToLower(row.Email)] = number
+	}
+	if row.RollNo != "" {
+		if prev, dup := seenRoll[strings.ToLower(row.RollNo)]; dup {
+			fail("duplicate roll number (row %d)", prev)
+		} else {
+			seenRoll[strings.ToLower(row.RollNo)] = number
+		}
+	}
+	if s := rec[roster.DOB]; s != "" {
+		dob, err := parseRosterDOB(s, xlsx)
+		switch {
+		case err != nil:
+			fail("invalid dob %q: use YYYY-MM-DD or DD/MM/YYYY", s)
+		case computeAge(dob) < rosterMinAge:
+			fail("voter must be %d+", rosterMinAge)
+		default:
+			row.DOB = dob.Format("2006-01-02")
+		}
+	}
+	if row.Mobile != "" && !mobileRe.MatchString(row.Mobile) {
+		fail("invalid mobile format")
+	}
+	return row
+}
+
+// parseRosterDOB accepts what parseDOB does and DD/MM/YYYY. With xlsx it also accepts the
+// serial numbers XLSX stores dates as; in a CSV a bare number is not a date.
+func parseRosterDOB(s string, xlsx bool) (time.Time, error) {
+	if t, err := parseDOB(s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"02/01/2006", "02-01-2006"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	if n, err := strconv.ParseFloat(s, 64); xlsx && err == nil && n >= 1 && n < 100000 {
+		return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(n)), nil
+	}
+	return time.Time{}, errors.New("unrecognised date")
+}
+
+// planRosterRow works out what applying row would change now; conflicts with stored
+// records become row errors. Only non-empty imported values count as changes.
+func (h *Handlers) planRosterRow(ctx context.Context, job *RosterImport, row *RosterRow) error {
+	row.Student, row.Voter, row.Changes = "", "", nil
+	changed := map[string]bool{}
+
+	if job.Target != rosterVoters {
+		s, err := h.findStudent(ctx, row.Email)
+		if err != nil {
+			return err
+		}
+		if s == nil {
+			row.Student = rosterCreate
+		} else if diff := rosterDiff(row, s.FullName, s.RollNo, s.Mobile, s.Gender, s.Year, s.DOB); len(diff) > 0 {
+			row.Student = rosterUpdate
+			for _, f := range diff {
+				changed[f] = true
+			}
+		} else {
+			row.Student = rosterUnchanged
+		}
+	}
+
+	if job.Target != rosterStudents {
+		v, err := h.Voters.GetByEmail(ctx, row.Email)
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			row.Voter = rosterCreate
+		case err != nil:
+			return err
+		default:
+			diff := rosterDiff(row, v.FullName, v.RollNo, v.Mobile, v.Gender, v.Year, v.DOB)
+			for _, f := range diff {
+				changed[f] = true
+			}
+			registered := job.ElectionAddress == ""
+			for _, reg := range v.Registrations {
+				registered = registered || reg.ElectionAddress == job.ElectionAddress
+			}
+			switch {
+			case len(diff) > 0:
+				row.Voter = rosterUpdate
+			case !registered:
+				row.Voter = rosterLink
+			default:
+				row.Voter = rosterUnchanged
+			}
+		}
+		if row.RollNo != "" {
+			other, err := h.Voters.GetByRollNo(ctx, row.RollNo)
+			switch {
+			case err == nil && !strings.EqualFold(other.Email, row.Email):
+				row.Errors = append(row.Errors, "roll number belongs to another voter")
+			case err != nil && !errors.Is(err, repository.ErrNotFound):
+				return err
+			}
+		}
+	}
+
+	if len(row.Errors) > 0 {
+		row.Student, row.Voter = "", ""
+		return nil
+	}
+	for _, f := range roster.Fields {
+		if changed[f] {
+			row.Changes = append(row.Changes, f)
+		}
+	}
+	return nil
+}
+
+// rosterDiff lists the fields where row has a value that differs from the stored one.
+func rosterDiff(row *RosterRow, fullName, rollNo, mobile, gender, year string, dob time.Time) []string {
+	var out []string
+	check := func(field, in, have string) {
+		if in != "" && in != have {
+			out = append(out, field)
+		}
+	}
+	check(roster.FullName, row.FullName, fullName)
+	check(roster.RollNo, row.RollNo, rollNo)
+	check(roster.Mobile, row.Mobile, mobile)
+	check(roster.Gender, row.Gender, gender)
+	check(roster.Year, row.Year, year)
+	if row.DOB != "" && (dob.IsZero() || dob.UTC().Format("2006-01-02") != row.DOB) {
+		out = append(out, roster.DOB)
+	}
+	return out
+}
+
+// rosterSet is the stored form of row's values for fields, for an update.
+func rosterSet(row *RosterRow, fields []string) bson.M {
+	values := map[string]string{
+		roster.FullName: row.FullName, roster.RollNo: row.RollNo, roster.Mobile: row.Mobile,
+		roster.Gender: row.Gender, roster.Year: row.Year,
+	}
+	set := bson.M{}
+	for _, f := range fields {
+		if f == roster.DOB {
+			set[f] = rosterDOB(row)
+		} else {
+			set[f] = values[f]
+		}
+	}
+	return set
+}
+
+func rosterDOB(row *RosterRow) time.Time {
+	t, _ := time.Parse("2006-01-02", row.DOB)
+	return t
+}
+
+// applyRosterRow plans row again and applies it.
+func (h *Handlers) applyRosterRow(ctx context.Context, job *RosterImport, electionName string, row *RosterRow) error {
+	if err := h.planRosterRow(ctx, job, row); err != nil {
+		return err
+	}
+	if len(row.Errors) > 0 {
+		return errors.New(strings.Join(row.Errors, "; "))
+	}
+
+	switch row.Student {
+	case rosterCreate:
+		s := Student{Email: row.Email, FullName: row.FullName, RollNo: row.RollNo, Mobile: row.Mobile, Gender: row.Gender, Year: row.Year}
+		if row.DOB != "" {
+			s.DOB = rosterDOB(row)
+		}
+		if err := h.Students.Create(ctx, &s); err != nil {
+			return fmt.Errorf("student: %w", err)
+		}
+	case rosterUpdate:
+		if err := h.Students.Update(ctx, row.Email, rosterSet(row, row.Changes)); err != nil {
+			return fmt.Errorf("student: %w", err)
+		}
+	}
+
+	reg := VoterRegistration{ElectionAddress: job.ElectionAddress, Status: "Verified", RegisteredAt: time.Now().UTC()}
+	switch row.Voter {
+	case rosterCreate:
+		rawPassword, err := genPassword(12)
+		if err != nil {
+			return err
+		}
+		hashed, err := bcrypt.GenerateFromPassword([]byte(rawPassword), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+		v := Voter{
+			Email: row.Email, Password: string(hashed), FullName: row.FullName, RollNo: row.RollNo,
+			Mobile: row.Mobile, Gender: row.Gender, Year: row.Year, Registrations: []VoterRegistration{},
+		}
+		if row.DOB != "" {
+			v.DOB = rosterDOB(row)
+		}
+		if job.ElectionAddress != "" {
+			v.Registrations = append(v.Registrations, reg)
+		}
+		if err := h.Voters.Create(ctx, &v); err != nil {
+			return fmt.Errorf("voter: %w", err)
+		}
+		body := GenerateWelcomeEmail(row.FullName, row.Email, rawPassword, electionName)
+		if err := sendEmail(row.Email, "Welcome to SecureVote - Account Credentials", body); err != nil {
+			log.Printf("[ROSTER] Welcome email to %s failed: %v", row.Email, err)
+		}
+	case rosterUpdate, rosterLink:
+		v, err := h.Voters.GetByEmail(ctx, row.Email)
+		if err != nil {
+			return fmt.Errorf("voter: %w", err)
+		}
+		if row.Voter == rosterUpdate {
+			if err := h.Voters.Update(ctx, v.ID, map[string]interface{}(rosterSet(row, row.Changes))); err != nil {
+				return fmt.Errorf("voter: %w", err)
+			}
+		}
+		if job.ElectionAddress != "" {
+			if _, err := h.Voters.AddRegistration(ctx, v.ID, reg); err != nil {
+				return fmt.Errorf("voter registration: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// processRosterImports runs queued imports one at a time until none is left.
+func processRosterImports() {
+	if app.Rosters == nil || app.Voters == nil {
+		return
+	}
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		job, err := app.Rosters.Claim(ctx)
+		cancel()
+		if err != nil {
+			if !errors.Is(err, repository.ErrNotFound) {
+				log.Printf("[ROSTER ERROR] Failed to claim an import: %v", err)
+			}
+			return
+		}
+		app.runRosterImport(job)
+	}
+}
+
+// runRosterImport applies job's rows without a result in file order, in batches.
+func (h *Handlers) runRosterImport(job *RosterImport) {
+	log.Printf("[ROSTER] Applying %s (%s, %d rows)", job.ID.Hex(), job.Target, job.Summary.Rows)
+	electionName := "SecureVote"
+	if job.ElectionAddress != "" {
+		electionName = job.ElectionAddress
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if m, err := h.Elections.Get(ctx, job.ElectionAddress); err == nil && m.ElectionName != "" {
+			electionName = m.ElectionName
+		}
+		cancel()
+	}
+
+	finish := func(status, msg string) {
+		now := time.Now().UTC()
+		set := map[string]interface{}{"status": status, "finished_at": now, "updated_at": now}
+		if msg != "" {
+			set["error"] = msg
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := h.Rosters.Update(ctx, job.ID, repository.Update{Set: set}); err != nil {
+			log.Printf("[ROSTER ERROR] Failed to update %s: %v", job.ID.Hex(), err)
+		}
+	}
+
+	last := 0
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		rows, err := h.Rosters.Rows(ctx, repository.RosterRowQuery{ImportID: job.ID, After: last, Pending: true, Limit: 200})
+		cancel()
+		if err != nil {
+			log.Printf("[ROSTER ERROR] %s stopped: %v", job.ID.Hex(), err)
+			finish(RosterFailed, err.Error())
+			return
+		}
+		if len(rows) == 0 {
+			break
+		}
+		for i := range rows {
+			row := &rows[i]
+			last = row.Row
+			counter := "applied"
+			if len(row.Errors) > 0 {
+				row.Result, counter = rosterSkipped, "skipped"
+			} else {
+				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				err := h.applyRosterRow(ctx, job, electionName, row)
+				cancel()
+				row.Result = rosterApplied
+				if err != nil {
+					row.Result, row.ResultError, counter = rosterRowFailed, err.Error(), "failed"
+				}
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			err := h.Rosters.UpdateRow(ctx, row.ID, map[string]interface{}{
+				"result": row.Result, "result_error": row.ResultError, "errors": row.Errors,
+				"student": row.Student, "voter": row.Voter, "changes": row.Changes,
+			})
+			if err == nil {
+				err = h.Rosters.Update(ctx, job.ID, repository.Update{Inc: map[string]int64{counter: 1}})
+			}
+			cancel()
+			if err != nil {
+				log.Printf("[ROSTER ERROR] %s stopped at row %d: %v", job.ID.Hex(), row.Row, err)
+				finish(RosterFailed, err.Error())
+				return
+			}
+		}
+	}
+
+	finish(RosterCompleted, "")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if done, err := h.Rosters.Get(ctx, job.ID); err == nil {
+		job = done
+	}
+	details := fmt.Sprintf("Roster %s (%s): %d applied, %d failed, %d skipped", job.FileName, job.Target, job.Applied, job.Failed, job.Skipped)
+	log.Printf("[ROSTER] %s done. %s", job.ID.Hex(), details)
+	go LogAction(job.ElectionAddress, "ROSTER_IMPORTED", job.RequestedBy, details)
+}
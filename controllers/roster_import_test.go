@@ -0,0 +1,157 @@
+﻿package controllers
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"MAJOR-PROJECT/roster"
+
+	"github.com/gorilla/mux"
+)
+
+// xlsxRoster builds a one-sheet workbook with an Email header in A1, a Notes header in the
+// last column (XFD) and one inline-string row per email.
+func xlsxRoster(t *testing.T, emails ...string) []byte {
+	t.Helper()
+	var sheet strings.Builder
+	sheet.WriteString(`<worksheet><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>Email</t></is></c><c r="XFD1" t="inlineStr"><is><t>Notes</t></is></c></row>`)
+	for i, e := range emails {
+		n := i + 2
+		fmt.Fprintf(&sheet, `<row r="%d"><c r="A%d" t="inlineStr"><is><t>%s</t></is></c><c r="XFD%d"><v>1</v></c></row>`, n, n, e, n)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte(sheet.String())); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRosterReadLimits(t *testing.T) {
+	data := xlsxRoster(t, "a@example.com", "b@example.com", "c@example.com")
+
+	if _, err := roster.Read("roster.xlsx", data, nil, 2); !errors.Is(err, roster.ErrTooManyRows) {
+		t.Fatalf("3 rows with a limit of 2: got %v, want ErrTooManyRows", err)
+	}
+	table, err := roster.Read("roster.xlsx", data, nil, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(table.Rows) != 3 {
+		t.Fatalf("got %d rows, want 3", len(table.Rows))
+	}
+	for _, row := range table.Rows {
+		if len(row.Values) != 1 || row.Values[roster.Email] == "" {
+			t.Errorf("row %d kept %v, want only the email", row.Number, row.Values)
+		}
+	}
+}
+
+func TestParseRosterDOBSerialOnlyForXLSX(t *testing.T) {
+	got, err := parseRosterDOB("36526", true)
+	if err != nil || got.Format("2006-01-02") != "2000-01-01" {
+		t.Errorf("xlsx serial: got %v, %v", got, err)
+	}
+	if _, err := parseRosterDOB("36526", false); err == nil {
+		t.Error("csv accepted a bare number as a date")
+	}
+	if _, err := parseRosterDOB("01/01/2000", false); err != nil {
+		t.Errorf("csv DD/MM/YYYY: %v", err)
+	}
+}
+
+// previewRoster uploads a voters roster for testElection with header's credentials.
+func previewRoster(t *testing.T, h *Handlers, csvData string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", "roster.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write([]byte(csvData))
+	mw.WriteField("target", rosterVoters)
+	mw.WriteField("election_address", testElection)
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/roster/preview", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/roster/preview", h.PreviewRosterImport).Methods(http.MethodPost)
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRosterImportBelongsToUploader(t *testing.T) {
+	h := newTestHandlers(t)
+	registerCompany(t, h, "owner@example.com", "owner-pw")
+	registerCompany(t, h, "other@example.com", "other-pw")
+	ownElection(t, h, "owner@example.com")
+	const csvData = "Email,Name,Mobile\na@example.com,Ann,9876543210\nnot-an-email,Bob,\n"
+
+	if got := previewRoster(t, h, csvData, nil).Code; got != http.StatusUnauthorized {
+		t.Errorf("preview without credentials: got %d, want 401", got)
+	}
+	if got := previewRoster(t, h, csvData, basicAuth("other@example.com", "other-pw")).Code; got != http.StatusUnauthorized {
+		t.Errorf("preview by another company: got %d, want 401", got)
+	}
+	rec := previewRoster(t, h, csvData, basicAuth("owner@example.com", "owner-pw"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("preview by owner: got %d: %s", rec.Code, rec.Body.String())
+	}
+	job := decodeBody(t, rec)["data"].(map[string]interface{})["import"].(map[string]interface{})
+	if job["requested_by"] != "owner@example.com" {
+		t.Errorf("requested_by = %v, want the owning company", job["requested_by"])
+	}
+	id := job["id"].(string)
+
+	get := func(handler http.HandlerFunc, pattern, target string, header http.Header) *httptest.ResponseRecorder {
+		return serve(t, handler, http.MethodGet, pattern, target, nil, header)
+	}
+	for _, header := range []http.Header{nil, basicAuth("other@example.com", "other-pw")} {
+		if got := get(h.GetRosterImport, "/admin/roster/imports/{id}", "/admin/roster/imports/"+id+"?rows=all", header).Code; got != http.StatusUnauthorized {
+			t.Errorf("rows read by a non-uploader: got %d, want 401", got)
+		}
+		if got := get(h.GetRosterImportReport, "/admin/roster/imports/{id}/report.csv", "/admin/roster/imports/"+id+"/report.csv", header).Code; got != http.StatusUnauthorized {
+			t.Errorf("report read by a non-uploader: got %d, want 401", got)
+		}
+		if got := serve(t, h.ApplyRosterImport, http.MethodPost, "/admin/roster/imports/{id}/apply", "/admin/roster/imports/"+id+"/apply", nil, header).Code; got != http.StatusUnauthorized {
+			t.Errorf("apply by a non-uploader: got %d, want 401", got)
+		}
+	}
+
+	rec = get(h.GetRosterImport, "/admin/roster/imports/{id}", "/admin/roster/imports/"+id+"?rows=all", basicAuth("owner@example.com", "owner-pw"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("rows read by the uploader: got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rows := decodeBody(t, rec)["data"].(map[string]interface{})["rows"].([]interface{}); len(rows) != 2 {
+		t.Errorf("rows=all returned %d rows, want 2", len(rows))
+	}
+	rec = get(h.GetRosterImport, "/admin/roster/imports/{id}", "/admin/roster/imports/"+id, basicAuth("owner@example.com", "owner-pw"))
+	if rows := decodeBody(t, rec)["data"].(map[string]interface{})["rows"].([]interface{}); len(rows) != 1 {
+		t.Errorf("default view returned %d rows, want only the invalid one", len(rows))
+	}
+	if got := serve(t, h.ApplyRosterImport, http.MethodPost, "/admin/roster/imports/{id}/apply", "/admin/roster/imports/"+id+"/apply", nil, basicAuth("owner@example.com", "owner-pw")).Code; got != http.StatusAccepted {
+		t.Errorf("apply by the uploader: got %d, want 202", got)
+	}
+}
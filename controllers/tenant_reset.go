@@ -0,0 +1,347 @@
+﻿package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"MAJOR-PROJECT/bundle"
+	"MAJOR-PROJECT/repository"
+	"MAJOR-PROJECT/txmanager"
+
+	"github.com/gorilla/mux"
+)
+
+// A reset clears one company's elections, or one of them: candidates, OTPs, audit
+// entries, voter registrations and metadata. Voter accounts, ballots, transactions and
+// anchor jobs stay. It takes two calls: a preview that counts what would go and returns
+// a single-use confirmation token, then the reset itself, which first writes a signed
+// bundle of every election to RESET_EXPORT_DIR and aborts if any export fails. The
+// preview is stored in reset_history with the token's hash, so the reset may run on
+// another replica or after a restart. All three endpoints take the company's password.
+
+const resetTokenTTL = 10 * time.Minute
+
+// Reset record states.
+const (
+	ResetPreview   = repository.ResetPreview
+	ResetStarted   = repository.ResetStarted
+	ResetCompleted = repository.ResetCompleted
+	ResetFailed    = repository.ResetFailed
+)
+
+// The reset records are owned by the repository package (h.Resets, reset_history).
+type (
+	ResetCounts   = repository.ResetCounts
+	ResetElection = repository.ResetElection
+	ResetRecord   = repository.ResetRecord
+)
+
+// resetTokenHash is what reset_history keeps of a confirm_token.
+func resetTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+type resetRequest struct {
+	Password        string `json:"password"`
+	ElectionAddress string `json:"election_address,omitempty"` // preview only; empty = every election of the company
+	ConfirmToken    string `json:"confirm_token,omitempty"`    // reset only
+}
+
+// readResetRequest decodes the body and checks the company's password. It writes the
+// error response itself and returns "" on failure.
+func (h *Handlers) readResetRequest(w http.ResponseWriter, r *http.Request, req *resetRequest) string {
+	if h.Resets == nil {
+		respondError(w, http.StatusInternalServerError, "company storage not initialized")
+		return ""
+	}
+	email, _ := url.PathUnescape(mux.Vars(r)["email"])
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	if _, err := h.checkCompanyPassword(ctx, email, req.Password); err != nil {
+		respondAuthError(w, err)
+		return ""
+	}
+	return txmanager.NormalizeCompany(email)
+}
+
+// resetCounts counts what deleting one election would remove.
+func (h *Handlers) resetCounts(ctx context.Context, addr string) (ResetCounts, error) {
+	counts := ResetCounts{Metadata: 1}
+	candidates, err := h.Candidates.List(ctx, addr)
+	if err != nil {
+		return counts, err
+	}
+	counts.Candidates = int64(len(candidates))
+	entries, err := h.Audit.List(ctx, addr)
+	if err != nil {
+		return counts, err
+	}
+	counts.AuditLogs = int64(len(entries))
+	if counts.OTPs, err = h.OTPs.CountByElection(ctx, addr); err != nil {
+		return counts, err
+	}
+	counts.VoterRegistrations, err = h.Voters.CountByElection(ctx, addr)
+	return counts, err
+}
+
+// PreviewCompanyReset counts what a reset of the company's elections (or only
+// election_address) would delete and returns the confirm_token that executes exactly
+// that reset within ten minutes. Nothing is changed.
+// POST /api/company/{email}/reset/preview
+func (h *Handlers) PreviewCompanyReset(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	var req resetRequest
+	company := h.readResetRequest(w, r, &req)
+	if company == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	var elections []repository.ElectionMetadata
+	scope := "company"
+	if strings.TrimSpace(req.ElectionAddress) != "" {
+		meta, err := h.Elections.Get(ctx, req.ElectionAddress)
+		if err != nil || txmanager.NormalizeCompany(meta.CompanyEmail) != company {
+			respondError(w, http.StatusNotFound, "election not found for this company")
+			return
+		}
+		elections, scope = []repository.ElectionMetadata{*meta}, meta.ElectionAddress
+	} else {
+		var err error
+		if elections, err = h.Elections.List(ctx, repository.ElectionFilter{CompanyEmail: company}); err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to list elections")
+			return
+		}
+	}
+	if len(elections) == 0 {
+		respondError(w, http.StatusNotFound, "company has no elections to reset")
+		return
+	}
+
+	rec := &ResetRecord{Company: company, Scope: scope, Status: ResetPreview, PreviewAt: time.Now().UTC()}
+	var total ResetCounts
+	for _, m := range elections {
+		counts, err := h.resetCounts(ctx, m.ElectionAddress)
+		if err != nil {
+			log.Printf("[RESET ERROR] count %s: %v", m.ElectionAddress, err)
+			respondError(w, http.StatusInternalServerError, "failed to count election data")
+			return
+		}
+		rec.Elections = append(rec.Elections, ResetElection{Address: m.ElectionAddress, Name: m.ElectionName, Counts: counts})
+		total.Add(counts)
+	}
+
+	var raw [16]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to issue confirmation token")
+		return
+	}
+	token := hex.EncodeToString(raw[:])
+	expires := rec.PreviewAt.Add(resetTokenTTL)
+	rec.TokenHash, rec.ExpiresAt = resetTokenHash(token), &expires
+	if err := h.Resets.SavePreview(ctx, rec); err != nil {
+		log.Printf("[RESET ERROR] save preview for %s: %v", company, err)
+		respondError(w, http.StatusInternalServerError, "failed to issue confirmation token")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "dry run: nothing was deleted",
+		"data": map[string]interface{}{
+			"scope":         scope,
+			"elections":     rec.Elections,
+			"total":         total,
+			"confirm_token": token,
+			"expires_at":    expires,
+		},
+	})
+}
+
+// ResetCompany executes the reset a preview returned confirm_token for. Each election is
+// exported to a signed bundle first; deletion starts only when every export succeeded.
+// The outcome is kept in reset_history and in an audit entry without an election, which
+// no reset deletes.
+// POST /api/company/{email}/reset
+func (h *Handlers) ResetCompany(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	var req resetRequest
+	company := h.readResetRequest(w, r, &req)
+	if company == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	// Claiming the preview consumes the token, whichever replica issued it
+	rec, err := h.Resets.Start(ctx, company, resetTokenHash(req.ConfirmToken), r.RemoteAddr, time.Now().UTC())
+	if errors.Is(err, repository.ErrNotFound) {
+		respondError(w, http.StatusForbidden, "invalid or expired confirm_token; run the preview again")
+		return
+	}
+	if err != nil {
+		log.Printf("[RESET ERROR] start reset for %s: %v", company, err)
+		respondError(w, http.StatusInternalServerError, "failed to record the reset; nothing was deleted")
+		return
+	}
+	finish := func(status, msg string) {
+		now := time.Now().UTC()
+		rec.Status, rec.Error, rec.FinishedAt = status, msg, &now
+		if err := h.Resets.Update(ctx, rec.ID, repository.Update{Set: map[string]interface{}{
+			"status": rec.Status, "error": rec.Error, "elections": rec.Elections, "bundle_signer": rec.Signer,
+			"deleted": rec.Deleted, "finished_at": rec.FinishedAt,
+		}}); err != nil {
+			log.Printf("[RESET ERROR] update record %s: %v", rec.ID.Hex(), err)
+		}
+	}
+
+	for i := range rec.Elections {
+		e := &rec.Elections[i]
+		file, sum, signer, err := h.writeResetBundle(ctx, e.Address, *rec.StartedAt)
+		switch {
+		case errors.Is(err, bundle.ErrNoSigningKey):
+			finish(ResetFailed, "bundle signing is not configured; nothing was deleted")
+			respondError(w, http.StatusServiceUnavailable, "bundle signing is not configured (BUNDLE_SIGNING_KEY); nothing was deleted")
+			return
+		case err != nil:
+			log.Printf("[RESET ERROR] export %s: %v", e.Address, err)
+			finish(ResetFailed, fmt.Sprintf("export of %s failed; nothing was deleted", e.Address))
+			respondError(w, http.StatusInternalServerError, fmt.Sprintf("export of %s failed; nothing was deleted", e.Address))
+			return
+		}
+		e.Bundle, e.BundleSHA256, rec.Signer = file, sum, signer
+	}
+
+	var deleteErr error
+	for i := range rec.Elections {
+		e := &rec.Elections[i]
+		deleted, err := h.deleteElectionData(ctx, e.Address)
+		rec.Deleted.Add(deleted)
+		if err != nil {
+			deleteErr = fmt.Errorf("%s: %w", e.Address, err)
+			break
+		}
+	}
+
+	if deleteErr != nil {
+		log.Printf("[RESET ERROR] %s: %v", company, deleteErr)
+		finish(ResetFailed, deleteErr.Error())
+	} else {
+		finish(ResetCompleted, "")
+	}
+	LogAction("", "COMPANY_RESET", company, fmt.Sprintf("Reset %s (%d election(s)): %s; record %s",
+		rec.Scope, len(rec.Elections), rec.Status, rec.ID.Hex()))
+
+	if deleteErr != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{
+			"status":  "error",
+			"message": "reset failed part way; bundles were written, see data.error",
+			"data":    rec,
+		})
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "reset completed",
+		"data":    rec,
+	})
+}
+
+// writeResetBundle exports one election to RESET_EXPORT_DIR (default "exports") and
+// returns the file, its SHA-256 and the signer.
+func (h *Handlers) writeResetBundle(ctx context.Context, addr string, at time.Time) (string, string, string, error) {
+	dir := strings.TrimSpace(os.Getenv("RESET_EXPORT_DIR"))
+	if dir == "" {
+		dir = "exports"
+	}
+	var buf bytes.Buffer
+	m, err := bundle.Export(ctx, h.bundleSource(), addr, &buf)
+	if err != nil {
+		return "", "", "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", "", "", err
+	}
+	file := filepath.Join(dir, fmt.Sprintf("election-%s-%s.zip", m.Election, at.Format("20060102T150405Z")))
+	if err := os.WriteFile(file, buf.Bytes(), 0o600); err != nil {
+		return "", "", "", err
+	}
+	b, err := bundle.Open(buf.Bytes())
+	if err != nil {
+		return "", "", "", err
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return file, hex.EncodeToString(sum[:]), b.SignerAddress().Hex(), nil
+}
+
+// deleteElectionData removes one election's records; the metadata goes last so a failed
+// reset can be previewed and run again.
+func (h *Handlers) deleteElectionData(ctx context.Context, addr string) (ResetCounts, error) {
+	var c ResetCounts
+	var err error
+	if c.Candidates, err = h.Candidates.DeleteByElection(ctx, addr); err != nil {
+		return c, err
+	}
+	if c.OTPs, err = h.OTPs.DeleteByElection(ctx, addr); err != nil {
+		return c, err
+	}
+	if c.AuditLogs, err = h.Audit.DeleteByElection(ctx, addr); err != nil {
+		return c, err
+	}
+	if c.VoterRegistrations, err = h.Voters.RemoveRegistrations(ctx, addr); err != nil {
+		return c, err
+	}
+	if err = h.Elections.Delete(ctx, addr); err != nil {
+		return c, err
+	}
+	c.Metadata = 1
+	return c, nil
+}
+
+// ListCompanyResets returns a company's resets, latest first, with their requester
+// addresses and bundle paths. Takes the company's HTTP Basic credentials.
+// GET /api/company/{email}/resets
+func (h *Handlers) ListCompanyResets(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	if h.Resets == nil {
+		respondError(w, http.StatusInternalServerError, "company storage not initialized")
+		return
+	}
+	email, _ := url.PathUnescape(mux.Vars(r)["email"])
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	who, err := h.basicCaller(ctx, r)
+	if err != nil && !errors.Is(err, errBadCredentials) {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !who.isCompany(email) {
+		respondUnauthorized(w, "the company's credentials are required")
+		return
+	}
+	records, err := h.Resets.List(ctx, txmanager.NormalizeCompany(email), 100)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to read reset history")
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{"status": "success", "data": records, "count": len(records)})
+}
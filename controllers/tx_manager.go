@@ -0,0 +1,362 @@
+﻿package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"MAJOR-PROJECT/chain"
+	"MAJOR-PROJECT/repository"
+	"MAJOR-PROJECT/signer"
+	"MAJOR-PROJECT/txmanager"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gorilla/mux"
+)
+
+// Transaction purposes. Hooks are keyed by these, so they must stay stable across releases.
+const (
+	PurposeCreateElection = "CREATE_ELECTION"
+	PurposeVote           = "VOTE"
+	PurposeAddCandidate   = "ADD_CANDIDATE"
+	PurposeL1Anchor       = "L1_ANCHOR"
+	PurposeSetSchedule    = "SET_SCHEDULE"
+	PurposeCloseElection  = "CLOSE_ELECTION"
+)
+
+var (
+	txStore txmanager.Store
+	l2Tx    *txmanager.Manager
+	l1Tx    *txmanager.Manager // nil when L1 anchoring is not configured
+)
+
+// InitTxManagers creates the L2 (and optional L1) transaction managers over store
+// (txmanager.NewMongoStore, or NewMemoryStore with STORAGE_BACKEND=memory), registers the
+// confirmation hooks and resumes monitoring of any pending transactions. l1Signer may be
+// nil when L1 anchoring is not configured. Call it after InitHandlers.
+func InitTxManagers(store txmanager.Store, l2Signer, l1Signer signer.Signer) error {
+	txStore = store
+
+	l2Client, err := getClient()
+	if err != nil {
+		return err
+	}
+	if l2Signer == nil {
+		return fmt.Errorf("L2 signer not configured")
+	}
+	l2Tx, err = newTxManager(l2Client, l2Signer, envChainID("L2_CHAIN_ID", 80002))
+	if err != nil {
+		return err
+	}
+	l2Tx.OnFinal(PurposeCreateElection, onElectionCreated)
+	l2Tx.OnFinal(PurposeVote, onVoteFinal)
+	l2Tx.OnFinal(PurposeAddCandidate, onCandidateFinal)
+	l2Tx.OnFinal(PurposeSetSchedule, onScheduleFinal)
+	l2Tx.OnFinal(PurposeCloseElection, onCloseFinal)
+	l2Tx.OnFinal(PurposeVoteBatch, onVoteBatchFinal)
+	l2Tx.OnFinal(PurposeSetApprovers, onSetApproversFinal)
+	l2Tx.OnFinal(PurposeApproveAction, onApproveFinal)
+	l2Tx.OnFinal(PurposePauseElection, onPauseFinal)
+	l2Tx.OnFinal(PurposeResumeElection, onPauseFinal)
+	l2Tx.Start(context.Background())
+
+	if l1Client, err := getL1Client(); err == nil && l1Signer != nil {
+		if l1Tx, err = newTxManager(l1Client, l1Signer, envChainID("L1_CHAIN_ID", 11155111)); err != nil {
+			log.Printf("[WARN] L1 transaction manager disabled: %v", err)
+		} else {
+			l1Tx.OnFinal(PurposeL1Anchor, onAnchorFinal)
+			l1Tx.Start(context.Background())
+		}
+	}
+
+	fmt.Println("[OK] Initialized transaction managers")
+	return nil
+}
+
+// newTxManager builds a manager for one backend. The chain id comes from the node itself
+// and falls back to the configured one if the node cannot be asked.
+func newTxManager(backend chain.ChainBackend, s signer.Signer, fallbackChainID *big.Int) (*txmanager.Manager, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	chainID, err := backend.ChainID(ctx)
+	if err != nil {
+		log.Printf("[WARN] %s ChainID lookup failed, using %s: %v", backend.Name(), fallbackChainID, err)
+		chainID = fallbackChainID
+	}
+	if !backend.Simulated() {
+		recordOperatorKey(backend.Name(), s)
+	}
+	log.Printf("[OK] %s operator %s (%s signer)", backend.Name(), s.Address().Hex(), s.Kind())
+
+	strategy, err := txmanager.ParseFeeStrategy(os.Getenv("GAS_FEE_STRATEGY"))
+	if err != nil {
+		log.Printf("[WARN] %v; using standard", err)
+		strategy = txmanager.StrategyStandard
+	}
+	poll, err := time.ParseDuration(strings.TrimSpace(os.Getenv("TX_POLL_INTERVAL")))
+	if err != nil || poll <= 0 {
+		poll = 0 // txmanager default (5s)
+	}
+
+	return txmanager.New(txmanager.Config{
+		Chain:         backend.Name(),
+		Backend:       backend,
+		ChainID:       chainID,
+		From:          s.Address(),
+		Signer:        signer.SignerFn(s, chainID),
+		Store:         txStore,
+		GasLimit:      envUint("GAS_LIMIT"),
+		GasPrice:      envBigInt("GAS_PRICE"),
+		FeeStrategy:   strategy,
+		DefaultBudget: envBigInt(backend.Name() + "_GAS_BUDGET"),
+		PollInterval:  poll,
+	})
+}
+
+// recordOperatorKey writes KEY_REGISTERED / KEY_ROTATED to the audit log when the
+// operator account of a chain differs from the one used last time (app.Operators).
+func recordOperatorKey(chainName string, s signer.Signer) {
+	if app.Operators == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	prev, err := app.Operators.Get(ctx, chainName)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Printf("[WARN] Failed to read previous %s operator key: %v", chainName, err)
+		return
+	}
+	addr := s.Address().Hex()
+	if err == nil && strings.EqualFold(prev.Address, addr) && prev.Kind == s.Kind() {
+		return
+	}
+
+	key := &repository.OperatorKey{Chain: chainName, Address: addr, Kind: s.Kind(), Since: time.Now().UTC()}
+	if uerr := app.Operators.Save(ctx, key); uerr != nil {
+		log.Printf("[WARN] Failed to store %s operator key: %v", chainName, uerr)
+	}
+
+	switch {
+	case err != nil:
+		LogAction("", "KEY_REGISTERED", "System", fmt.Sprintf("%s operator key %s (%s signer)", chainName, addr, s.Kind()))
+	case strings.EqualFold(prev.Address, addr):
+		LogAction("", "KEY_ROTATED", "System", fmt.Sprintf("%s operator %s moved from %s to %s signer", chainName, addr, prev.Kind, s.Kind()))
+	default:
+		// Elections and the archive stay owned by the previous account (onlyOwner/onlyAdmin)
+		LogAction("", "KEY_ROTATED", "System", fmt.Sprintf("%s operator key rotated from %s to %s (%s signer); contracts owned by the old key need it for owner-only calls", chainName, prev.Address, addr, s.Kind()))
+	}
+}
+
+// envChainID reads a decimal chain id, falling back to def when unset or invalid.
+func envChainID(name string, def int64) *big.Int {
+	v, err := strconv.ParseInt(strings.TrimSpace(os.Getenv(name)), 10, 64)
+	if err != nil || v == 0 {
+		v = def
+	}
+	return big.NewInt(v)
+}
+
+// envBigInt parses an optional positive decimal env var (quotes tolerated).
+func envBigInt(name string) *big.Int {
+	s := strings.Trim(strings.TrimSpace(os.Getenv(name)), `"'`)
+	if s == "" {
+		return nil
+	}
+	if v, ok := new(big.Int).SetString(s, 10); ok && v.Sign() > 0 {
+		return v
+	}
+	return nil
+}
+
+func envUint(name string) uint64 {
+	if v := envBigInt(name); v != nil && v.IsUint64() {
+		return v.Uint64()
+	}
+	return 0
+}
+
+// packCall ABI-encodes a contract method call for the transaction manager.
+func packCall(meta *bind.MetaData, method string, args ...interface{}) ([]byte, error) {
+	parsed, err := meta.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Pack(method, args...)
+}
+
+// payloadString reads a string field from a record payload.
+func payloadString(rec *txmanager.Record, key string) string {
+	if rec.Payload == nil {
+		return ""
+	}
+	s, _ := rec.Payload[key].(string)
+	return s
+}
+
+// ----------------------------
+// CONFIRMATION HOOKS
+// ----------------------------
+
+// The hooks below only cover outcomes that emit no event. Successful txs are picked up
+// by the chain indexer (chain_indexer.go) from ElectionCreated / CandidateAdded /
+// VoteCast / ResultArchived, which also survives reorgs and txs sent from elsewhere.
+
+func onElectionCreated(rec *txmanager.Record, receipt *types.Receipt) {
+	companyEmail := payloadString(rec, "company_email")
+	if rec.Status == txmanager.StatusConfirmed {
+		log.Printf("[ALCHEMY] CreateElection for %s mined in block %d (tx %s)", companyEmail, rec.BlockNumber, rec.TxHash)
+		return
+	}
+	log.Printf("[ALCHEMY] CreateElection for %s ended %s (tx %s): %s", companyEmail, rec.Status, rec.TxHash, rec.Error)
+}
+
+func onVoteFinal(rec *txmanager.Record, receipt *types.Receipt) {
+	electionAddr := payloadString(rec, "election_address")
+	voterEmail := payloadString(rec, "voter_email")
+	switch rec.Status {
+	case txmanager.StatusConfirmed:
+		log.Printf("[ALCHEMY] Vote mined successfully in block %d", rec.BlockNumber)
+	case txmanager.StatusReverted:
+		log.Printf("[ALCHEMY] Vote transaction reverted for tx %s", rec.TxHash)
+		go LogAction(electionAddr, "VOTE_REVERTED", voterEmail, "Vote transaction reverted: "+rec.TxHash)
+	default:
+		log.Printf("[ALCHEMY] Vote transaction failed for %s: %s", voterEmail, rec.Error)
+		go LogAction(electionAddr, "VOTE_FAILED", voterEmail, "Vote transaction failed: "+rec.Error)
+	}
+}
+
+func onCandidateFinal(rec *txmanager.Record, receipt *types.Receipt) {
+	switch rec.Status {
+	case txmanager.StatusConfirmed:
+		fmt.Printf("[ALCHEMY] Tx %s mined successfully in block %d\n", rec.TxHash, rec.BlockNumber)
+	case txmanager.StatusReverted:
+		fmt.Printf("[ALCHEMY] Tx %s reverted\n", rec.TxHash)
+		updateCandidateStatus(rec.ID.Hex(), rec.TxHash, "reverted")
+	default:
+		updateCandidateStatus(rec.ID.Hex(), rec.TxHash, "failed")
+	}
+}
+
+func onAnchorFinal(rec *txmanager.Record, receipt *types.Receipt) {
+	electionAddr := payloadString(rec, "election_address")
+	if rec.Status == txmanager.StatusConfirmed {
+		log.Printf("[ANCHOR SUCCESS] Result for %s mined on L1 in block %d (tx %s)", electionAddr, rec.BlockNumber, rec.TxHash)
+		return
+	}
+	// The anchor worker retries the job; L1_ANCHOR_FAILED is only written when it gives up.
+	log.Printf("[ANCHOR WARN] L1 anchor tx for %s ended %s: %s", electionAddr, rec.Status, rec.Error)
+	go LogAction(electionAddr, "L1_ANCHOR_TX_FAILED", "System", fmt.Sprintf("L1 anchoring tx %s: %s %s", rec.Status, rec.TxHash, rec.Error))
+}
+
+// ----------------------------
+// STATUS ENDPOINTS
+// ----------------------------
+
+// redactRecord drops the payload (voter emails, candidate ids, ballot ids) unless the
+// caller is the company the transaction was charged to.
+func redactRecord(who *caller, rec *txmanager.Record) {
+	if !who.admin() || rec.Company == "" || !who.isCompany(rec.Company) {
+		rec.Payload = nil
+	}
+}
+
+// txCaller resolves the optional HTTP Basic credentials of the transaction endpoints,
+// answering 401 itself for wrong ones.
+func (h *Handlers) txCaller(ctx context.Context, w http.ResponseWriter, r *http.Request) (*caller, bool) {
+	who, err := h.basicCaller(ctx, r)
+	if errors.Is(err, errBadCredentials) {
+		respondUnauthorized(w, "Invalid email/password!!!")
+		return nil, false
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	return who, true
+}
+
+// GetTransactionStatus returns one managed transaction by record id or any of its hashes.
+// The payload is only included for the company it was charged to, signed in with HTTP
+// Basic credentials.
+// GET /api/transactions/{id}
+func (h *Handlers) GetTransactionStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	if txStore == nil {
+		respondError(w, http.StatusInternalServerError, "transaction manager not initialized")
+		return
+	}
+
+	id := strings.TrimSpace(mux.Vars(r)["id"])
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	who, ok := h.txCaller(ctx, w, r)
+	if !ok {
+		return
+	}
+	rec, err := txStore.Get(ctx, id)
+	if errors.Is(err, txmanager.ErrNotFound) {
+		respondError(w, http.StatusNotFound, "transaction not found")
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to load transaction")
+		return
+	}
+	redactRecord(who, rec)
+	respondJSON(w, http.StatusOK, BlockchainResponse{Status: "success", Message: "transaction found", Data: rec})
+}
+
+// ListTransactions returns the latest managed transactions, with payloads redacted as in
+// GetTransactionStatus.
+// GET /api/transactions?chain=L2&status=PENDING&purpose=VOTE&company=acme@example.com&limit=50
+func (h *Handlers) ListTransactions(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	if txStore == nil {
+		respondError(w, http.StatusInternalServerError, "transaction manager not initialized")
+		return
+	}
+
+	q := r.URL.Query()
+	filter := txmanager.Filter{
+		Chain:   strings.ToUpper(strings.TrimSpace(q.Get("chain"))),
+		Status:  strings.ToUpper(strings.TrimSpace(q.Get("status"))),
+		Company: txmanager.NormalizeCompany(q.Get("company")),
+	}
+	if v := strings.TrimSpace(q.Get("purpose")); v != "" {
+		filter.Purposes = []string{strings.ToUpper(v)}
+	}
+	limit, _ := strconv.ParseInt(q.Get("limit"), 10, 64)
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	who, ok := h.txCaller(ctx, w, r)
+	if !ok {
+		return
+	}
+	recs, err := txStore.List(ctx, filter, limit)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to list transactions")
+		return
+	}
+	for i := range recs {
+		redactRecord(who, &recs[i])
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"data":   recs,
+		"count":  len(recs),
+	})
+}
@@ -0,0 +1,165 @@
+﻿package controllers
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"MAJOR-PROJECT/bindings"
+	"MAJOR-PROJECT/chain"
+	"MAJOR-PROJECT/indexer"
+	"MAJOR-PROJECT/signer"
+	"MAJOR-PROJECT/txmanager"
+	"MAJOR-PROJECT/util"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	testChainOnce sync.Once
+	testChainErr  error
+)
+
+// useTestChain starts one simulated L2 with a deployed factory and an L2 transaction
+// manager over a memory store, shared by every test in the package. There is no L1 and
+// no indexer goroutine, which would write into whichever test's repositories are current;
+// tests apply the logs they need themselves.
+func useTestChain(t *testing.T) {
+	t.Helper()
+	testChainOnce.Do(func() {
+		os.Setenv("TX_POLL_INTERVAL", "20ms")
+		factoryEvents, _ = bindings.NewElectionFactFilterer(common.Address{}, nil)
+		electionEvents, _ = bindings.NewElectionFilterer(common.Address{}, nil)
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			testChainErr = err
+			return
+		}
+		s := signer.NewKeySigner(key)
+		l2 := chain.NewSimulated("L2", big.NewInt(80002), s.Address())
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		factory, _, err := util.DeployMeta(ctx, l2, bindings.ElectionFactMetaData, s)
+		if err != nil {
+			testChainErr = err
+			return
+		}
+		os.Setenv("L2_FACTORY_CONTRACT_ADDRESS", factory.Hex())
+		InitChainBackends(l2, nil)
+		testChainErr = InitTxManagers(txmanager.NewMemoryStore(), s, nil)
+	})
+	if testChainErr != nil {
+		t.Fatalf("test chain: %v", testChainErr)
+	}
+}
+
+// waitTx blocks until the managed transaction id is mined.
+func waitTx(t *testing.T, id string) *txmanager.Record {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	rec, err := l2Tx.Wait(ctx, id)
+	if err != nil {
+		t.Fatalf("wait for tx %s: %v", id, err)
+	}
+	return rec
+}
+
+// callElection sends an election contract call as the operator and waits for it to be mined.
+func callElection(t *testing.T, addr, method string, args ...interface{}) {
+	t.Helper()
+	data, err := packCall(bindings.ElectionMetaData, method, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err := l2Tx.Send(context.Background(), txmanager.Request{Purpose: "TEST", To: common.HexToAddress(addr), Data: data})
+	if err != nil {
+		t.Fatalf("%s: %v", method, err)
+	}
+	if rec = waitTx(t, rec.ID.Hex()); rec.Status != txmanager.StatusConfirmed {
+		t.Fatalf("%s: tx %s %s", method, rec.TxHash, rec.Status)
+	}
+}
+
+// createTestElection creates an election for company through the API and applies its
+// ElectionCreated log the way the chain indexer would. It returns the election address.
+func createTestElection(t *testing.T, h *Handlers, company, name string) string {
+	t.Helper()
+	useTestChain(t)
+	body := map[string]string{"company_email": company, "election_name": name, "election_description": name + " description"}
+	resp := serve(t, h.CreateElection, http.MethodPost, "/elections", "/elections", body, nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("create election: status %d: %s", resp.Code, resp.Body.String())
+	}
+	data, _ := decodeBody(t, resp)["data"].(map[string]interface{})
+	id, _ := data["txId"].(string)
+	if rec := waitTx(t, id); rec.Status != txmanager.StatusConfirmed {
+		t.Fatalf("create election tx %s: %s %s", rec.TxHash, rec.Status, rec.Error)
+	}
+
+	rec, _ := txStore.Get(context.Background(), id)
+	receipt, err := l2Chain.TransactionReceipt(context.Background(), common.HexToHash(rec.TxHash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, lg := range receipt.Logs {
+		ev, err := factoryEvents.ParseElectionCreated(*lg)
+		if err != nil {
+			continue
+		}
+		if err := onElectionCreatedLog(context.Background(), indexer.EventID("L2-SIM", *lg), *lg); err != nil {
+			t.Fatal(err)
+		}
+		return ev.Election.Hex()
+	}
+	t.Fatal("no ElectionCreated log in the receipt")
+	return ""
+}
+
+func TestRedactRecord(t *testing.T) {
+	h := newTestHandlers(t)
+	registerCompany(t, h, "owner@example.com", "owner-pw")
+	owner, err := h.checkCompanyPassword(context.Background(), "owner@example.com", "owner-pw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := func() *txmanager.Record {
+		return &txmanager.Record{Company: "owner@example.com", Payload: map[string]interface{}{"voter_email": "v@example.com"}}
+	}
+
+	for _, who := range []*caller{nil, {Voter: &Voter{Email: "v@example.com"}}, {Company: &Company{Email: "other@example.com"}}} {
+		rec := payload()
+		redactRecord(who, rec)
+		if rec.Payload != nil {
+			t.Errorf("payload shown to %+v", who)
+		}
+	}
+	rec := payload()
+	redactRecord(&caller{Company: owner}, rec)
+	if rec.Payload == nil {
+		t.Error("payload hidden from the company it was charged to")
+	}
+}
+
+func TestMemoryModeCreatesElection(t *testing.T) {
+	h := newTestHandlers(t)
+	addr := createTestElection(t, h, "owner@example.com", "Board")
+
+	meta, err := h.Elections.Get(context.Background(), addr)
+	if err != nil {
+		t.Fatalf("metadata of the new election: %v", err)
+	}
+	if meta.CompanyEmail != "owner@example.com" || meta.ElectionID == 0 {
+		t.Errorf("metadata: company %q, registry id %d", meta.CompanyEmail, meta.ElectionID)
+	}
+
+	resp := serve(t, h.ListTransactions, http.MethodGet, "/transactions", "/transactions?purpose=CREATE_ELECTION&status=CONFIRMED", nil, nil)
+	if resp.Code != http.StatusOK || decodeBody(t, resp)["count"].(float64) < 1 {
+		t.Errorf("listing confirmed creations: status %d: %s", resp.Code, resp.Body.String())
+	}
+}
@@ -0,0 +1,417 @@
+﻿package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"MAJOR-PROJECT/bindings"
+	"MAJOR-PROJECT/repository"
+	"MAJOR-PROJECT/txmanager"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PurposeVoteBatch is a voteBatch transaction carrying several queued ballots.
+const PurposeVoteBatch = "VOTE_BATCH"
+
+// Ballot states; the ballots themselves are repository.Ballot.
+const (
+	BallotQueued    = repository.BallotQueued
+	BallotSubmitted = repository.BallotSubmitted
+	BallotIncluded  = repository.BallotIncluded
+	BallotRejected  = repository.BallotRejected
+	BallotFailed    = repository.BallotFailed
+)
+
+var errBallotPending = errors.New("a ballot for this voter is already queued or included")
+
+// voteBatcher groups ballots per election and flushes a batch when it reaches size
+// ballots or when the oldest one has waited window.
+type voteBatcher struct {
+	size   int
+	window time.Duration
+
+	mu     sync.Mutex
+	queues map[string][]primitive.ObjectID
+	timers map[string]*time.Timer
+}
+
+// votes is nil when batching is disabled; votes go out one tx each.
+var votes *voteBatcher
+
+const maxVoteBatchSize = 200
+
+// InitVoteBatcher enables batching over app.Ballots when VOTE_BATCH_SIZE (> 1, capped at
+// 200) or VOTE_BATCH_WINDOW is set. The defaults are 50 ballots and 2s. Ballots still
+// QUEUED from a previous run are put back in their queues. Call it after InitHandlers.
+func InitVoteBatcher() {
+	sizeEnv := strings.TrimSpace(os.Getenv("VOTE_BATCH_SIZE"))
+	windowEnv := strings.TrimSpace(os.Getenv("VOTE_BATCH_WINDOW"))
+	if sizeEnv == "" && windowEnv == "" {
+		log.Printf("[BATCH] Vote batching disabled (set VOTE_BATCH_SIZE or VOTE_BATCH_WINDOW to enable)")
+		return
+	}
+	size := 50
+	if sizeEnv != "" {
+		n, err := strconv.Atoi(sizeEnv)
+		if err != nil || n <= 1 {
+			log.Printf("[BATCH] Vote batching disabled (VOTE_BATCH_SIZE=%q)", sizeEnv)
+			return
+		}
+		size = n
+	}
+	if size > maxVoteBatchSize {
+		size = maxVoteBatchSize
+	}
+	window := 2 * time.Second
+	if d, err := time.ParseDuration(windowEnv); err == nil && d > 0 {
+		window = d
+	}
+
+	votes = &voteBatcher{
+		size:   size,
+		window: window,
+		queues: make(map[string][]primitive.ObjectID),
+		timers: make(map[string]*time.Timer),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	votes.reload(ctx)
+	log.Printf("[BATCH] Vote batching enabled (%d ballots or %s per batch)", size, window)
+}
+
+// reload re-queues ballots that were accepted but never submitted before a restart.
+func (b *voteBatcher) reload(ctx context.Context) {
+	queued, err := app.Ballots.Find(ctx, repository.BallotQuery{Statuses: []string{BallotQueued}})
+	if err != nil {
+		log.Printf("[BATCH WARN] Failed to reload queued ballots: %v", err)
+		return
+	}
+	for _, bl := range queued {
+		b.push(bl.ElectionAddress, bl.ID)
+	}
+	if len(queued) > 0 {
+		log.Printf("[BATCH] Resumed %d queued ballots", len(queued))
+	}
+}
+
+// enqueue stores a validated ballot and adds it to its election's queue. The repository
+// allows one active ballot per voter, so a second ballot from the same voter is
+// errBallotPending, even when both requests arrive at once.
+func (b *voteBatcher) enqueue(ctx context.Context, electionAddr, voterEmail string, candidateID int64) (*Ballot, error) {
+	now := time.Now().UTC()
+	bl := &Ballot{
+		ElectionAddress: electionAddr,
+		VoterEmail:      voterEmail,
+		CandidateID:     candidateID,
+		Status:          BallotQueued,
+		Active:          true,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	err := app.Ballots.Insert(ctx, bl)
+	if errors.Is(err, repository.ErrDuplicate) {
+		return nil, errBallotPending
+	}
+	if err != nil {
+		return nil, err
+	}
+	b.push(electionAddr, bl.ID)
+	return bl, nil
+}
+
+// push appends a ballot id and triggers a flush when the batch is full, otherwise
+// makes sure a window timer is running for the election.
+func (b *voteBatcher) push(electionAddr string, id primitive.ObjectID) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.queues[electionAddr] = append(b.queues[electionAddr], id)
+	if len(b.queues[electionAddr]) >= b.size {
+		go b.flush(electionAddr)
+		return
+	}
+	if _, ok := b.timers[electionAddr]; !ok {
+		b.timers[electionAddr] = time.AfterFunc(b.window, func() { b.flush(electionAddr) })
+	}
+}
+
+// take removes up to size ballot ids from the election's queue.
+func (b *voteBatcher) take(electionAddr string) []primitive.ObjectID {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if t, ok := b.timers[electionAddr]; ok {
+		t.Stop()
+		delete(b.timers, electionAddr)
+	}
+	q := b.queues[electionAddr]
+	n := len(q)
+	if n > b.size {
+		n = b.size
+	}
+	ids := append([]primitive.ObjectID(nil), q[:n]...)
+	if rest := q[n:]; len(rest) > 0 {
+		b.queues[electionAddr] = rest
+		b.timers[electionAddr] = time.AfterFunc(b.window, func() { b.flush(electionAddr) })
+	} else {
+		delete(b.queues, electionAddr)
+	}
+	return ids
+}
+
+// later retries a flush after another window. The election's pending timer is stopped
+// first, so repeated deferrals keep a single timer instead of piling up flushes.
+func (b *voteBatcher) later(electionAddr string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if t, ok := b.timers[electionAddr]; ok {
+		t.Stop()
+	}
+	b.timers[electionAddr] = time.AfterFunc(b.window, func() { b.flush(electionAddr) })
+}
+
+// flush sends one voteBatch transaction for the election's oldest queued ballots.
+func (b *voteBatcher) flush(electionAddr string) {
+	if l2Tx == nil {
+		// Still starting up; try again after another window
+		b.later(electionAddr)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	if electionPaused(ctx, electionAddr) {
+		// voteBatch would revert; hold the queue until the election is resumed
+		b.later(electionAddr)
+		return
+	}
+	ids := b.take(electionAddr)
+	if len(ids) == 0 {
+		return
+	}
+
+	batch, err := app.Ballots.Find(ctx, repository.BallotQuery{IDs: ids, Statuses: []string{BallotQueued}})
+	if err != nil {
+		log.Printf("[BATCH ERROR] Failed to load ballots for %s: %v", electionAddr, err)
+		return
+	}
+	if len(batch) == 0 {
+		return
+	}
+
+	candidateIDs := make([]*big.Int, len(batch))
+	emails := make([]string, len(batch))
+	ballotIDs := make([]primitive.ObjectID, len(batch))
+	ballotHexes := make([]string, len(batch))
+	for i, bl := range batch {
+		candidateIDs[i] = big.NewInt(bl.CandidateID)
+		emails[i] = strings.ToLower(bl.VoterEmail) // ballots queued before emails were canonical
+		ballotIDs[i] = bl.ID
+		ballotHexes[i] = bl.ID.Hex()
+	}
+
+	data, err := packCall(bindings.ElectionMetaData, "voteBatch", candidateIDs, emails)
+	var rec *txmanager.Record
+	if err == nil {
+		rec, err = l2Tx.Send(ctx, txmanager.Request{
+			Purpose:     PurposeVoteBatch,
+			To:          common.HexToAddress(electionAddr),
+			Data:        data,
+			Company:     companyForElection(electionAddr),
+			Election:    electionAddr,
+			FeeStrategy: feeStrategyFor(PurposeVoteBatch),
+			Payload: map[string]interface{}{
+				"election_address": electionAddr,
+				"ballot_ids":       ballotHexes,
+			},
+		})
+	}
+	if err != nil {
+		log.Printf("[BATCH ERROR] voteBatch for %s (%d ballots) not sent: %v", electionAddr, len(batch), err)
+		failBallots(ctx, ballotIDs, electionAddr, "batch not sent: "+err.Error())
+		return
+	}
+
+	// Ballots stay QUEUED until the tx exists, so a crash before this point leaves them for
+	// reload; the status and the tx reference are written together. The hook may already
+	// have settled them, hence the QUEUED filter.
+	now := time.Now().UTC()
+	_, err = app.Ballots.Update(ctx, repository.BallotQuery{IDs: ballotIDs, Statuses: []string{BallotQueued}},
+		repository.Update{Set: map[string]interface{}{
+			"status":       BallotSubmitted,
+			"tx_id":        rec.ID.Hex(),
+			"tx_hash":      rec.TxHash,
+			"submitted_at": now,
+			"updated_at":   now,
+		}})
+	if err != nil {
+		log.Printf("[BATCH ERROR] Failed to mark ballots of tx %s submitted: %v", rec.TxHash, err)
+	}
+	log.Printf("[BATCH] voteBatch for %s with %d ballots sent (tx %s)", electionAddr, len(batch), rec.TxHash)
+}
+
+// failBallots marks the listed ballots that are still QUEUED or SUBMITTED FAILED, which
+// frees the voter to vote again, and records VOTE_FAILED for each voter.
+func failBallots(ctx context.Context, ids []primitive.ObjectID, electionAddr, reason string) {
+	q := repository.BallotQuery{IDs: ids, Statuses: []string{BallotQueued, BallotSubmitted}}
+	failed, err := app.Ballots.Find(ctx, q)
+	if err != nil {
+		log.Printf("[BATCH ERROR] Failed to load ballots to fail: %v", err)
+		return
+	}
+	_, _ = app.Ballots.Update(ctx, q, repository.Update{
+		Set:   map[string]interface{}{"status": BallotFailed, "reason": reason, "updated_at": time.Now().UTC()},
+		Unset: []string{"active"},
+	})
+	for _, bl := range failed {
+		go LogAction(electionAddr, "VOTE_FAILED", bl.VoterEmail, "Batched vote failed: "+reason)
+	}
+}
+
+// payloadObjectIDs reads a list of hex ids from a record payload. Records loaded back
+// from Mongo carry the list as primitive.A rather than []string.
+func payloadObjectIDs(rec *txmanager.Record, key string) []primitive.ObjectID {
+	var raw []string
+	switch v := rec.Payload[key].(type) {
+	case []string:
+		raw = v
+	case primitive.A:
+		for _, x := range v {
+			if s, ok := x.(string); ok {
+				raw = append(raw, s)
+			}
+		}
+	case []interface{}:
+		for _, x := range v {
+			if s, ok := x.(string); ok {
+				raw = append(raw, s)
+			}
+		}
+	}
+	ids := make([]primitive.ObjectID, 0, len(raw))
+	for _, s := range raw {
+		if id, err := primitive.ObjectIDFromHex(s); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// onVoteBatchFinal settles each ballot of a voteBatch tx from the receipt: VoteCast means
+// INCLUDED, BallotRejected means REJECTED. A reverted or failed tx fails the whole batch.
+func onVoteBatchFinal(rec *txmanager.Record, receipt *types.Receipt) {
+	if app.Ballots == nil {
+		return
+	}
+	electionAddr := payloadString(rec, "election_address")
+	ids := payloadObjectIDs(rec, "ballot_ids")
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if rec.Status != txmanager.StatusConfirmed || receipt == nil {
+		log.Printf("[BATCH ERROR] voteBatch %s for %s ended %s: %s", rec.TxHash, electionAddr, rec.Status, rec.Error)
+		reason := fmt.Sprintf("batch tx %s %s", rec.Status, rec.Error)
+		failBallots(ctx, ids, electionAddr, strings.TrimSpace(reason))
+		return
+	}
+
+	now := time.Now().UTC()
+	settle := func(voter, status, reason string) {
+		q := repository.BallotQuery{IDs: ids, Voter: emailPattern(voter), Statuses: []string{BallotQueued, BallotSubmitted}}
+		u := repository.Update{Set: map[string]interface{}{
+			"status":       status,
+			"tx_id":        rec.ID.Hex(),
+			"tx_hash":      rec.TxHash,
+			"block_number": rec.BlockNumber,
+			"updated_at":   now,
+		}}
+		if status == BallotIncluded {
+			u.Set["included_at"] = now
+		} else {
+			u.Set["reason"] = reason
+			u.Unset = []string{"active"}
+		}
+		if _, err := app.Ballots.Update(ctx, q, u); err != nil {
+			log.Printf("[BATCH ERROR] Failed to settle ballot of %s: %v", voter, err)
+		}
+	}
+
+	included, rejected := 0, 0
+	for _, lg := range receipt.Logs {
+		if lg == nil || len(lg.Topics) == 0 {
+			continue
+		}
+		if ev, err := electionEvents.ParseVoteCast(*lg); err == nil {
+			settle(ev.Voter, BallotIncluded, "")
+			included++
+			continue
+		}
+		if ev, err := electionEvents.ParseBallotRejected(*lg); err == nil {
+			settle(ev.Voter, BallotRejected, ev.Reason)
+			go LogAction(electionAddr, "VOTE_REJECTED", ev.Voter, "Batched vote rejected on-chain: "+ev.Reason)
+			rejected++
+		}
+	}
+	// Anything left was not mentioned by the contract, which should not happen.
+	failBallots(ctx, ids, electionAddr, "ballot missing from batch receipt "+rec.TxHash)
+	log.Printf("[BATCH] voteBatch %s mined in block %d: %d included, %d rejected", rec.TxHash, rec.BlockNumber, included, rejected)
+}
+
+// supportsVoteBatch reports whether the election's contract build has voteBatch.
+func supportsVoteBatch(ctx context.Context, addr common.Address) bool {
+	v, err := electionVersion(ctx, addr)
+	return err == nil && v.Has("voteBatch")
+}
+
+// GetBallotStatus reports where a queued vote is: QUEUED, SUBMITTED, INCLUDED, REJECTED or FAILED.
+// The voter and the candidate are only shown to the voter or the election's owning company,
+// signed in with HTTP Basic credentials; anyone holding the id sees the status.
+// GET /api/ballots/{id}
+func (h *Handlers) GetBallotStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	if h.Ballots == nil {
+		respondError(w, http.StatusInternalServerError, "ballots not initialized")
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(mux.Vars(r)["id"]))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid ballot id")
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	bl, err := h.Ballots.Get(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		respondError(w, http.StatusNotFound, "ballot not found")
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to load ballot")
+		return
+	}
+
+	who, err := h.basicCaller(ctx, r)
+	if errors.Is(err, errBadCredentials) {
+		respondUnauthorized(w, "Invalid email/password!!!")
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !who.isVoter(bl.VoterEmail) && !(who.admin() && who.isCompany(companyForElection(bl.ElectionAddress))) {
+		bl.VoterEmail, bl.CandidateID = "", 0
+	}
+	respondJSON(w, http.StatusOK, BlockchainResponse{Status: "success", Message: "ballot " + bl.Status, Data: bl})
+}
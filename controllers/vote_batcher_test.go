@@ -0,0 +1,147 @@
+﻿package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"MAJOR-PROJECT/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// useBatcher installs a batcher of size ballots per window for the test.
+func useBatcher(t *testing.T, size int, window time.Duration) *voteBatcher {
+	t.Helper()
+	b := &voteBatcher{size: size, window: window, queues: map[string][]primitive.ObjectID{}, timers: map[string]*time.Timer{}}
+	votes = b
+	t.Cleanup(func() {
+		b.mu.Lock()
+		for _, tm := range b.timers {
+			tm.Stop()
+		}
+		b.mu.Unlock()
+		votes = nil
+	})
+	return b
+}
+
+// batchElection is an open election with one candidate.
+func batchElection(t *testing.T, h *Handlers) string {
+	t.Helper()
+	addr := createTestElection(t, h, "owner@example.com", "Batch")
+	callElection(t, addr, "addCandidate", "Ada", "", "", "ada@example.com")
+	return addr
+}
+
+func enqueueBallot(t *testing.T, b *voteBatcher, addr, voter string) primitive.ObjectID {
+	t.Helper()
+	bl, err := b.enqueue(context.Background(), addr, voter, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bl.ID
+}
+
+func ballotStatus(t *testing.T, h *Handlers, id primitive.ObjectID) string {
+	t.Helper()
+	bl, err := h.Ballots.Get(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bl.Status
+}
+
+// waitIncluded waits until every ballot is settled and expects INCLUDED.
+func waitIncluded(t *testing.T, h *Handlers, ids ...primitive.ObjectID) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for _, id := range ids {
+		for ballotStatus(t, h, id) == BallotQueued || ballotStatus(t, h, id) == BallotSubmitted {
+			if time.Now().After(deadline) {
+				t.Fatalf("ballot %s still %s", id.Hex(), ballotStatus(t, h, id))
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		if got := ballotStatus(t, h, id); got != BallotIncluded {
+			t.Errorf("ballot %s: %s, want INCLUDED", id.Hex(), got)
+		}
+	}
+}
+
+func TestVoteBatcherFlushesWhenFull(t *testing.T) {
+	h := newTestHandlers(t)
+	addr := batchElection(t, h)
+	b := useBatcher(t, 2, time.Hour)
+
+	first := enqueueBallot(t, b, addr, "a@example.com")
+	time.Sleep(200 * time.Millisecond)
+	if got := ballotStatus(t, h, first); got != BallotQueued {
+		t.Fatalf("one ballot of two: %s, want QUEUED", got)
+	}
+	second := enqueueBallot(t, b, addr, "b@example.com")
+	waitIncluded(t, h, first, second)
+
+	if n, _ := h.Ballots.Count(context.Background(), repository.BallotQuery{Statuses: []string{BallotIncluded}}); n != 2 {
+		t.Errorf("%d ballots included, want 2", n)
+	}
+}
+
+func TestVoteBatcherFlushesAfterWindow(t *testing.T) {
+	h := newTestHandlers(t)
+	addr := batchElection(t, h)
+	b := useBatcher(t, 50, 100*time.Millisecond)
+
+	waitIncluded(t, h, enqueueBallot(t, b, addr, "a@example.com"))
+	if _, err := b.enqueue(context.Background(), addr, "a@example.com", 0); err != errBallotPending {
+		t.Errorf("second ballot of an included voter: got %v, want errBallotPending", err)
+	}
+}
+
+func TestVoteBatcherHoldsPausedElection(t *testing.T) {
+	h := newTestHandlers(t)
+	addr := batchElection(t, h)
+	b := useBatcher(t, 2, time.Hour)
+	setStatus := func(status string) {
+		if err := h.Elections.Update(context.Background(), addr, repository.Update{Set: map[string]interface{}{"status": status}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	timer := func() *time.Timer {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		return b.timers[addr]
+	}
+
+	setStatus(StatusPaused)
+	ids := []primitive.ObjectID{enqueueBallot(t, b, addr, "a@example.com"), enqueueBallot(t, b, addr, "b@example.com")}
+	deadline := time.Now().Add(5 * time.Second)
+	for timer() == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("full batch of a paused election was not deferred")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Each deferral replaces the retry timer instead of adding one.
+	deferred := timer()
+	b.flush(addr)
+	if timer() == deferred {
+		t.Fatal("second deferral kept the old timer")
+	}
+	if deferred.Stop() {
+		t.Error("the replaced retry timer was still running")
+	}
+	for _, id := range ids {
+		if got := ballotStatus(t, h, id); got != BallotQueued {
+			t.Errorf("ballot of a paused election: %s, want QUEUED", got)
+		}
+	}
+
+	setStatus("ONGOING")
+	b.flush(addr)
+	waitIncluded(t, h, ids...)
+	if timer() != nil {
+		t.Error("retry timer left behind after the batch was sent")
+	}
+}
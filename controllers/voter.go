@@ -6,7 +6,9 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"math/big"
 	"mime/multipart"
 	"net/http"
@@ -17,49 +19,17 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 	"golang.org/x/crypto/bcrypt"
 
 	"MAJOR-PROJECT/bindings"
+	"MAJOR-PROJECT/repository"
+	"MAJOR-PROJECT/txmanager"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 )
 
-type VoterRegistration struct {
-	ElectionAddress string    `bson:"election_address" json:"election_address"`
-	Status          string    `bson:"status" json:"status"` // "Verified", "Pending"
-	RegisteredAt    time.Time `bson:"registered_at" json:"registered_at"`
-}
-
-type Voter struct {
-	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
-	Email         string              `bson:"email" json:"email"`
-	Password      string              `bson:"password" json:"-"`
-	FullName      string              `bson:"full_name,omitempty" json:"full_name,omitempty"`
-	DOB           time.Time           `bson:"dob,omitempty" json:"dob,omitempty"`
-	RollNo        string              `bson:"roll_no,omitempty" json:"roll_no,omitempty"`
-	Mobile        string              `bson:"mobile,omitempty" json:"mobile,omitempty"`
-	Gender        string              `bson:"gender,omitempty" json:"gender,omitempty"`
-	Year          string              `bson:"year,omitempty" json:"year,omitempty"`
-	PhotoURL      string              `bson:"photo_url,omitempty" json:"photo_url,omitempty"`
-	Registrations []VoterRegistration `bson:"registrations" json:"registrations"`
-}
-
-type Student struct {
-	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Email    string             `bson:"email" json:"email"`
-	FullName string             `bson:"full_name" json:"full_name"`
-	RollNo   string             `bson:"roll_no" json:"roll_no"`
-	Mobile   string             `bson:"mobile" json:"mobile"`
-	Gender   string             `bson:"gender" json:"gender"`
-	Year     string             `bson:"year" json:"year"`
-	DOB      time.Time          `bson:"dob" json:"dob"`
-}
-
 type VoterRequest struct {
 	Email           string `json:"email"`
 	Password        string `json:"password"`
@@ -96,44 +66,16 @@ func sendJSONError(w http.ResponseWriter, message string, statusCode int) {
 	})
 }
 
-var voterCollection *mongo.Collection
-var otpCollection *mongo.Collection
-
-// Initialize collections
-func InitVoterCollection(client *mongo.Client, dbName string) {
-	voterCollection = client.Database(dbName).Collection("voters")
-
-	// Create fast lookup index for extreme concurrency
-	indexModel := mongo.IndexModel{Keys: bson.M{"email": 1}, Options: options.Index().SetUnique(true)}
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	_, _ = voterCollection.Indexes().CreateOne(ctx, indexModel)
-
-	fmt.Println("[OK] Initialized voters collection with indexes")
-}
-
-func InitOTPCollection(client *mongo.Client, dbName string) {
-	otpCollection = client.Database(dbName).Collection("otps")
-
-	indexModel := mongo.IndexModel{Keys: bson.M{"email": 1}}
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	_, _ = otpCollection.Indexes().CreateOne(ctx, indexModel)
-
-	fmt.Println("[OK] Initialized OTP collection with indexes")
-}
-
-var studentCollection *mongo.Collection
-
-func InitStudentCollection(client *mongo.Client, dbName string) {
-	studentCollection = client.Database(dbName).Collection("students")
-
-	indexModel := mongo.IndexModel{Keys: bson.M{"email": 1}, Options: options.Index().SetUnique(true)}
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	_, _ = studentCollection.Indexes().CreateOne(ctx, indexModel)
-
-	fmt.Println("[OK] Initialized Students collection with indexes")
+// findStudent returns the roster entry for email, or nil when there is none.
+func (h *Handlers) findStudent(ctx context.Context, email string) (*Student, error) {
+	if h.Students == nil {
+		return nil, nil
+	}
+	s, err := h.Students.GetByEmail(ctx, email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	return s, err
 }
 
 func withVoterCORS(w http.ResponseWriter) {
@@ -208,7 +150,7 @@ func genPassword(n int) (string, error) {
 // POST /api/voters/send-otp
 // body: { "email": "...", "election_address": "..." }
 // --------------------------
-func SendOTP(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) SendOTP(w http.ResponseWriter, r *http.Request) {
 	withVoterCORS(w)
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
@@ -231,8 +173,8 @@ func SendOTP(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "email required", http.StatusBadRequest)
 		return
 	}
-	if otpCollection == nil {
-		http.Error(w, "server misconfigured: otp collection not ready", http.StatusInternalServerError)
+	if h.OTPs == nil {
+		http.Error(w, "server misconfigured: otp storage not ready", http.StatusInternalServerError)
 		return
 	}
 
@@ -240,24 +182,11 @@ func SendOTP(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 
 	// rate-limit: if existing OTP not expired, deny quick repeat
-	var existing bson.M
-	err := otpCollection.FindOne(ctx, bson.M{"email": req.Email}).Decode(&existing)
-	if err == nil {
-		if exp, ok := existing["expiresAt"].(primitive.DateTime); ok {
-			expTime := exp.Time()
-			if time.Now().Before(expTime) {
-				// still valid; disallow too-frequent sends
-				w.WriteHeader(http.StatusTooManyRequests)
-				_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "OTP already sent and still valid (wait before requesting another)"})
-				return
-			}
-		} else if exp2, ok2 := existing["expiresAt"].(time.Time); ok2 {
-			if time.Now().Before(exp2) {
-				w.WriteHeader(http.StatusTooManyRequests)
-				_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "OTP already sent and still valid (wait before requesting another)"})
-				return
-			}
-		}
+	if existing, err := h.OTPs.Get(ctx, req.Email); err == nil && time.Now().Before(existing.ExpiresAt) {
+		// still valid; disallow too-frequent sends
+		w.WriteHeader(http.StatusTooManyRequests)
+		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "OTP already sent and still valid (wait before requesting another)"})
+		return
 	}
 
 	otp, err := genOTP()
@@ -267,17 +196,16 @@ func SendOTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	expiresAt := time.Now().Add(10 * time.Minute).UTC()
-	doc := bson.M{
-		"email":            req.Email,
-		"otp":              otp,
-		"expiresAt":        expiresAt,
-		"election_address": req.ElectionAddress,
-		"createdAt":        time.Now().UTC(),
+	doc := repository.OTP{
+		Email:           req.Email,
+		Code:            otp,
+		ExpiresAt:       expiresAt,
+		ElectionAddress: req.ElectionAddress,
+		CreatedAt:       time.Now().UTC(),
 	}
 
-	// remove older OTP entries
-	_, _ = otpCollection.DeleteMany(ctx, bson.M{"email": req.Email})
-	if _, err := otpCollection.InsertOne(ctx, doc); err != nil {
+	// replaces older OTP entries
+	if err := h.OTPs.Replace(ctx, &doc); err != nil {
 		http.Error(w, "db error", http.StatusInternalServerError)
 		return
 	}
@@ -288,18 +216,15 @@ func SendOTP(w http.ResponseWriter, r *http.Request) {
 	if err := sendEmail(req.Email, subject, body); err != nil {
 		fmt.Printf("sendEmail error (SendOTP): %v\n", err)
 		// remove inserted OTP because email failed
-		_, _ = otpCollection.DeleteMany(ctx, bson.M{"email": req.Email})
+		_ = h.OTPs.Delete(ctx, req.Email)
 		http.Error(w, "failed to send otp email: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	// Try to find student data
-	var studentData *Student
-	if studentCollection != nil {
-		var s Student
-		if err := studentCollection.FindOne(ctx, bson.M{"email": req.Email}).Decode(&s); err == nil {
-			studentData = &s
-		}
+	studentData, err := h.findStudent(ctx, req.Email)
+	if err != nil {
+		log.Printf("[WARN] SendOTP: student lookup for %s: %v", req.Email, err)
 	}
 
 	resp := VoterResponse{Status: "success", Message: "OTP sent"}
@@ -314,7 +239,7 @@ func SendOTP(w http.ResponseWriter, r *http.Request) {
 // POST /api/voters/verify-otp-register
 // body: { "email","otp","full_name","dob","mobile","address","father_name","mother_name","election_address" }
 // --------------------------
-func VerifyOTPAndRegister(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) VerifyOTPAndRegister(w http.ResponseWriter, r *http.Request) {
 	withVoterCORS(w)
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
@@ -334,8 +259,8 @@ func VerifyOTPAndRegister(w http.ResponseWriter, r *http.Request) {
 		sendJSONError(w, "email required", http.StatusBadRequest)
 		return
 	}
-	if otpCollection == nil || voterCollection == nil {
-		sendJSONError(w, "server misconfigured: collections not ready", http.StatusInternalServerError)
+	if h.OTPs == nil || h.Voters == nil {
+		sendJSONError(w, "server misconfigured: storage not ready", http.StatusInternalServerError)
 		return
 	}
 
@@ -363,8 +288,7 @@ func VerifyOTPAndRegister(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// ensure email not already registered globally (for this flow)
-	count, err := voterCollection.CountDocuments(ctx, bson.M{"email": req.Email})
-	if err == nil && count > 0 {
+	if _, err := h.Voters.GetByEmail(ctx, req.Email); err == nil {
 		sendJSONError(w, "Voter account already exists. Please login to join election.", http.StatusConflict)
 		return
 	}
@@ -402,7 +326,7 @@ func VerifyOTPAndRegister(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
-	if _, err := voterCollection.InsertOne(ctx, newVoter); err != nil {
+	if err := h.Voters.Create(ctx, &newVoter); err != nil {
 		sendJSONError(w, "failed to create voter", http.StatusInternalServerError)
 		return
 	}
@@ -420,7 +344,7 @@ func VerifyOTPAndRegister(w http.ResponseWriter, r *http.Request) {
 }
 
 // ===== RegisterVoter (expanded) =====
-func RegisterVoter(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) RegisterVoter(w http.ResponseWriter, r *http.Request) {
 	withVoterCORS(w)
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
@@ -476,31 +400,26 @@ func RegisterVoter(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 
 	// Check if already exists
-	var existing Voter
-	err := voterCollection.FindOne(ctx, bson.M{"email": req.Email}).Decode(&existing)
+	existing, err := h.Voters.GetByEmail(ctx, req.Email)
 
 	if err == nil {
 		// Existing user: Link to new election if not already linked
-		for _, reg := range existing.Registrations {
-			if reg.ElectionAddress == req.ElectionAddress {
-				w.WriteHeader(http.StatusConflict)
-				_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Voter already registered for this election"})
-				return
-			}
-		}
-
-		// Add registration
 		newReg := VoterRegistration{
 			ElectionAddress: req.ElectionAddress,
 			Status:          "Pending", // Admin added, maybe auto-verify? Let's say Pending until approved or verified
 			RegisteredAt:    time.Now().UTC(),
 		}
-		_, err := voterCollection.UpdateOne(ctx, bson.M{"_id": existing.ID}, bson.M{"$push": bson.M{"registrations": newReg}})
+		added, err := h.Voters.AddRegistration(ctx, existing.ID, newReg)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Failed to link voter to election"})
 			return
 		}
+		if !added {
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Voter already registered for this election"})
+			return
+		}
 
 		_ = json.NewEncoder(w).Encode(VoterResponse{
 			Status:  "success",
@@ -544,8 +463,7 @@ func RegisterVoter(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
-	result, err := voterCollection.InsertOne(ctx, newVoter)
-	if err != nil {
+	if err := h.Voters.Create(ctx, &newVoter); err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Voter could not be added"})
 		return
@@ -555,14 +473,14 @@ func RegisterVoter(w http.ResponseWriter, r *http.Request) {
 		Status:  "success",
 		Message: "Voter account created and added to election.",
 		Data: map[string]interface{}{
-			"id":    result.InsertedID,
+			"id":    newVoter.ID.Hex(),
 			"email": req.Email,
 		},
 	})
 }
 
 // ===== AuthenticateVoter =====
-func AuthenticateVoter(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) AuthenticateVoter(w http.ResponseWriter, r *http.Request) {
 	withVoterCORS(w)
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
@@ -588,8 +506,8 @@ func AuthenticateVoter(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	var voterInfo Voter
-	if err := voterCollection.FindOne(ctx, bson.M{"email": req.Email}).Decode(&voterInfo); err != nil {
+	voterInfo, err := h.Voters.GetByEmail(ctx, req.Email)
+	if err != nil {
 		w.WriteHeader(http.StatusUnauthorized)
 		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Invalid email/password"})
 		return
@@ -613,9 +531,8 @@ func AuthenticateVoter(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// ===== GetAllVoters (returns expanded fields) =====
-// ===== GetAllVoters (returns expanded fields) =====
-func GetAllVoters(w http.ResponseWriter, r *http.Request) {
+// ===== GetAllVoters (one page of voters, optionally for one election) =====
+func (h *Handlers) GetAllVoters(w http.ResponseWriter, r *http.Request) {
 	withVoterCORS(w)
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
@@ -634,76 +551,14 @@ func GetAllVoters(w http.ResponseWriter, r *http.Request) {
 		_ = json.NewDecoder(r.Body).Decode(&req)
 		electionAddress = req.ElectionAddress
 	}
-
-	// Build Query
-	filter := bson.M{}
 	if electionAddress != "" {
-		// Filter voters who have a registration for this election
-		filter = bson.M{"registrations.election_address": electionAddress}
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	cursor, err := voterCollection.Find(ctx, filter)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Error fetching voters"})
-		return
-	}
-	defer cursor.Close(ctx)
-
-	var voters []Voter
-	if err = cursor.All(ctx, &voters); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Error decoding voters"})
-		return
-	}
-
-	vlist := make([]map[string]interface{}, 0, len(voters))
-	for _, v := range voters {
-		id := v.ID.Hex()
-		var dobStr string
-		if !v.DOB.IsZero() {
-			dobStr = v.DOB.Format("2006-01-02")
-		}
-
-		// determine status for this specific election if filtered
-		status := "Registered" // Default for global view
-		if electionAddress != "" {
-			for _, reg := range v.Registrations {
-				if reg.ElectionAddress == electionAddress {
-					status = reg.Status
-					break
-				}
-			}
-		}
-
-		vlist = append(vlist, map[string]interface{}{
-			"id":        id,
-			"email":     v.Email,
-			"full_name": v.FullName,
-			"dob":       dobStr,
-			"roll_no":   v.RollNo,
-			"mobile":    v.Mobile,
-			"gender":    v.Gender,
-			"year":      v.Year,
-			"status":    status,
-		})
+		electionAddress = repository.CanonicalAddress(electionAddress)
 	}
-
-	resp := map[string]interface{}{
-		"status":  "success",
-		"message": "voters list found",
-		"voters":  vlist,
-		"data":    map[string]interface{}{"voters": vlist}, // Keep both for compatibility
-		"count":   len(vlist),
-	}
-	_ = json.NewEncoder(w).Encode(resp)
+	h.listVoters(w, r, electionAddress)
 }
 
 // ===== UpdateVoter (allow updating profile fields) =====
-func UpdateVoter(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) UpdateVoter(w http.ResponseWriter, r *http.Request) {
 	withVoterCORS(w)
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
@@ -736,7 +591,7 @@ func UpdateVoter(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	update := bson.M{}
+	update := map[string]interface{}{}
 	if req.Email != "" {
 		update["email"] = req.Email
 	}
@@ -796,13 +651,13 @@ func UpdateVoter(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	result, err := voterCollection.UpdateOne(ctx, bson.M{"_id": objID}, bson.M{"$set": update})
-	if err != nil {
+	err = h.Voters.Update(ctx, objID, update)
+	if err != nil && err != repository.ErrNotFound {
 		w.WriteHeader(http.StatusInternalServerError)
 		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "failed to update voter"})
 		return
 	}
-	if result.MatchedCount == 0 {
+	if err == repository.ErrNotFound {
 		w.WriteHeader(http.StatusNotFound)
 		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "voter not found"})
 		return
@@ -811,8 +666,8 @@ func UpdateVoter(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(VoterResponse{Status: "success", Message: "voter updated successfully"})
 }
 
-// ===== DeleteVoter (unchanged from original file) =====
-func DeleteVoter(w http.ResponseWriter, r *http.Request) {
+// ===== DeleteVoter (erasure, see voter_privacy.go) =====
+func (h *Handlers) DeleteVoter(w http.ResponseWriter, r *http.Request) {
 	withVoterCORS(w)
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
@@ -837,19 +692,62 @@ func DeleteVoter(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
-	if _, err = voterCollection.DeleteOne(ctx, bson.M{"_id": objID}); err != nil {
+	// Erasure cannot be undone: only the voter or an admin of one of their elections
+	who, err := h.basicCaller(ctx, r)
+	if who == nil {
+		status, message := http.StatusUnauthorized, "Sign in as the voter or an admin of their election"
+		if err != nil && !errors.Is(err, errBadCredentials) {
+			status, message = http.StatusInternalServerError, err.Error()
+		} else {
+			w.Header().Set("WWW-Authenticate", authRealm)
+		}
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: message})
+		return
+	}
+	v, err := h.Voters.Get(ctx, objID)
+	if errors.Is(err, repository.ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Voter not found"})
+		return
+	}
+	if err == nil && !h.mayReadVoter(ctx, who, v) {
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Not allowed to erase this voter"})
+		return
+	}
+
+	report, err := h.eraseVoter(ctx, objID)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Voter not found"})
+		return
+	case errors.Is(err, errVoterErased), errors.Is(err, errVoterBallotsInFlight):
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: err.Error()})
+		return
+	case err != nil:
+		log.Printf("[ERASE ERROR] voter %s: %v", voterID, err)
 		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Error deleting voter"})
+		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Error erasing voter"})
 		return
 	}
 
-	_ = json.NewEncoder(w).Encode(VoterResponse{Status: "success", Message: "voter deleted successfully"})
+	// The voter is named by the pseudonym, never the email that was just erased
+	actor := report.Pseudonym
+	if who.admin() {
+		actor = txmanager.NormalizeCompany(who.Company.Email)
+	}
+	go LogAction("", "VOTER_ERASED", actor, fmt.Sprintf("Personal data of %s erased (%d elections kept pseudonymized, %d purged)",
+		report.Pseudonym, len(report.KeptElections), len(report.PurgedElections)))
+	_ = json.NewEncoder(w).Encode(VoterResponse{Status: "success", Message: "voter erased successfully", Data: report})
 }
 
 // ===== NEW: GetVoterElections =====
-func GetVoterElections(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) GetVoterElections(w http.ResponseWriter, r *http.Request) {
 	withVoterCORS(w)
 	if r.Method == http.MethodGet {
 		// Expect ?voter_id=...
@@ -870,8 +768,8 @@ func GetVoterElections(w http.ResponseWriter, r *http.Request) {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		var voter Voter
-		if err := voterCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&voter); err != nil {
+		voter, err := h.Voters.Get(ctx, objID)
+		if err != nil {
 			w.WriteHeader(http.StatusNotFound)
 			_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Voter not found"})
 			return
@@ -1006,7 +904,7 @@ func sendEmailWithAttachment(to, subject, htmlBody, filename string, attachmentD
 	}
 	return nil
 }
-func GetElectionVoters(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) GetElectionVoters(w http.ResponseWriter, r *http.Request) {
 	withVoterCORS(w)
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
@@ -1017,89 +915,28 @@ func GetElectionVoters(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	vars := mux.Vars(r)
-	electionAddress := vars["address"]
+	electionAddress := mux.Vars(r)["address"]
 	if electionAddress == "" {
 		http.Error(w, "Missing election address", http.StatusBadRequest)
 		return
 	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	// Query nested array
-	cursor, err := voterCollection.Find(ctx, bson.M{"registrations.election_address": electionAddress})
-	if err != nil {
-		http.Error(w, "Failed to fetch voters: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-	defer cursor.Close(ctx)
-
-	var voters []Voter
-	if err := cursor.All(ctx, &voters); err != nil {
-		http.Error(w, "Failed to decode voters: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	// build response
-	vlist := make([]map[string]interface{}, 0, len(voters))
-	for _, v := range voters {
-		var dobStr string
-		if !v.DOB.IsZero() {
-			dobStr = v.DOB.Format("2006-01-02")
-		}
-
-		// Find status for this election
-		status := "Unknown"
-		for _, reg := range v.Registrations {
-			if reg.ElectionAddress == electionAddress {
-				status = reg.Status
-				break
-			}
-		}
-
-		vlist = append(vlist, map[string]interface{}{
-			"id":        v.ID.Hex(),
-			"email":     v.Email,
-			"full_name": v.FullName,
-			"dob":       dobStr,
-			"roll_no":   v.RollNo,
-			"mobile":    v.Mobile,
-			"gender":    v.Gender,
-			"year":      v.Year,
-			"status":    status,
-		})
-	}
-
-	resp := map[string]interface{}{
-		"status":  "success",
-		"message": "voters retrieved",
-		"voters":  vlist,
-		"data":    map[string]interface{}{"voters": vlist},
-		"count":   len(vlist),
-	}
-	_ = json.NewEncoder(w).Encode(resp)
+	h.listVoters(w, r, repository.CanonicalAddress(electionAddress))
 }
 
 // IsVoterVerified checks if a voter is allowed to vote
-func IsVoterVerified(email, electionAddr string) bool {
-	if voterCollection == nil {
+func (h *Handlers) IsVoterVerified(email, electionAddr string) bool {
+	if h.Voters == nil {
 		return false
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	// Find voter who has this election registration
-	var v Voter
-	err := voterCollection.FindOne(ctx, bson.M{
-		"email":                          email,
-		"registrations.election_address": electionAddr,
-	}).Decode(&v)
-
+	v, err := h.Voters.GetByEmail(ctx, email)
 	if err != nil {
 		return false
 	}
 
+	electionAddr = repository.CanonicalAddress(electionAddr)
 	for _, reg := range v.Registrations {
 		if reg.ElectionAddress == electionAddr {
 			return reg.Status == "Verified"
@@ -1108,7 +945,7 @@ func IsVoterVerified(email, electionAddr string) bool {
 	return false
 }
 
-func ResultMail(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) ResultMail(w http.ResponseWriter, r *http.Request) {
 	withVoterCORS(w)
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
@@ -1137,20 +974,12 @@ func ResultMail(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 	defer cancel()
 
-	cursor, err := voterCollection.Find(ctx, bson.M{"registrations.election_address": req.ElectionAddress})
+	voters, err := h.Voters.List(ctx, req.ElectionAddress)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Error fetching voters: " + err.Error()})
 		return
 	}
-	defer cursor.Close(ctx)
-
-	var voters []Voter
-	if err := cursor.All(ctx, &voters); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Error decoding voters: " + err.Error()})
-		return
-	}
 
 	// Fetch Candidates from Blockchain
 	var candidates []map[string]interface{}
@@ -1159,7 +988,6 @@ func ResultMail(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		fmt.Printf("ResultMail: failed to connect to eth node: %v\n", err)
 	} else {
-		defer client.Close()
 		ethAddr := common.HexToAddress(req.ElectionAddress)
 		contract, err := bindings.NewElection(ethAddr, client)
 		if err != nil {
@@ -1186,14 +1014,17 @@ func ResultMail(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Fallback to DB if blockchain fetch failed
-	if len(candidates) == 0 && candidateCollection != nil {
+	if len(candidates) == 0 && h.Candidates != nil {
 		fmt.Println("ResultMail: chain fetch failed, falling back to DB")
-		curC, errC := candidateCollection.Find(ctx, bson.M{"electionAddress": req.ElectionAddress})
-		if errC == nil {
-			var rawCands []map[string]interface{}
-			_ = curC.All(ctx, &rawCands)
-			candidates = rawCands
-			curC.Close(ctx)
+		if docs, errC := h.Candidates.List(ctx, req.ElectionAddress); errC == nil {
+			for _, c := range docs {
+				candidates = append(candidates, map[string]interface{}{
+					"name":        c.Name,
+					"email":       c.Email,
+					"description": c.Description,
+					"status":      c.Status,
+				})
+			}
 		}
 	}
 
@@ -1234,7 +1065,7 @@ func ResultMail(w http.ResponseWriter, r *http.Request) {
 }
 
 // ApproveVoter Endpoint
-func ApproveVoter(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) ApproveVoter(w http.ResponseWriter, r *http.Request) {
 	withVoterCORS(w)
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
@@ -1261,8 +1092,8 @@ func ApproveVoter(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	_, err := voterCollection.UpdateOne(ctx, bson.M{"_id": objID}, bson.M{"$set": bson.M{"status": req.Status}})
-	if err != nil {
+	err := h.Voters.Update(ctx, objID, map[string]interface{}{"status": req.Status})
+	if err != nil && err != repository.ErrNotFound {
 		http.Error(w, "Failed to update status", http.StatusInternalServerError)
 		return
 	}
@@ -1271,77 +1102,54 @@ func ApproveVoter(w http.ResponseWriter, r *http.Request) {
 }
 
 // VerifyAndDeleteOTP checks OTP validity and deletes it if valid
-func VerifyAndDeleteOTP(email, otp string) bool {
-	if otpCollection == nil {
+func (h *Handlers) VerifyAndDeleteOTP(email, otp string) bool {
+	if h.OTPs == nil {
 		return false
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var otpDoc struct {
-		OTP       string             `bson:"otp"`
-		ExpiresAt primitive.DateTime `bson:"expiresAt"`
-	}
 	// find by email
-	err := otpCollection.FindOne(ctx, bson.M{"email": email}).Decode(&otpDoc)
+	otpDoc, err := h.OTPs.Get(ctx, email)
 	if err != nil {
 		return false
 	} // not found
 
 	// check expiry
-	if time.Now().UTC().After(otpDoc.ExpiresAt.Time()) {
+	if time.Now().UTC().After(otpDoc.ExpiresAt) {
 		return false
 	}
 
 	// check match
-	if otpDoc.OTP != otp {
+	if otpDoc.Code != otp {
 		return false
 	}
 
 	// valid -> delete
-	otpCollection.DeleteMany(ctx, bson.M{"email": email})
+	_ = h.OTPs.Delete(ctx, email)
 	return true
 }
 
 // GetVoterAnalytics returns the count of voters grouped by address (e.g. City)
-func GetVoterAnalytics(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) GetVoterAnalytics(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	vars := mux.Vars(r)
 	electionAddr := vars["address"] // matches /elections/{address}/analytics/geo
 
-	if voterCollection == nil {
+	if h.Voters == nil {
 		http.Error(w, "DB not initialized", http.StatusInternalServerError)
 		return
 	}
 
-	// Pipeline: Match Election in Registrations -> Group by Address -> Count
-	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: bson.D{{Key: "registrations.election_address", Value: electionAddr}}}},
-		{{Key: "$group", Value: bson.D{
-			{Key: "_id", Value: "$address"},
-			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
-		}}},
-		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}}}}, // highest first
-	}
-
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	cursor, err := voterCollection.Aggregate(ctx, pipeline)
+	// Voters of the election grouped by address, highest count first
+	results, err := h.Voters.RegionCounts(ctx, electionAddr)
 	if err != nil {
 		http.Error(w, "Aggregation failed", http.StatusInternalServerError)
 		return
 	}
-	defer cursor.Close(ctx)
-
-	var results []struct {
-		Address string `bson:"_id" json:"address"`
-		Count   int    `bson:"count" json:"count"`
-	}
-	if err := cursor.All(ctx, &results); err != nil {
-		http.Error(w, "Cursor decode failed", http.StatusInternalServerError)
-		return
-	}
 
 	// Clean up empty addresses
 	final := []interface{}{}
@@ -1363,7 +1171,7 @@ func GetVoterAnalytics(w http.ResponseWriter, r *http.Request) {
 }
 
 // AddVotersToElection allows Admin to bulk add existing voters to an election
-func AddVotersToElection(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) AddVotersToElection(w http.ResponseWriter, r *http.Request) {
 	withVoterCORS(w)
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
@@ -1410,29 +1218,20 @@ func AddVotersToElection(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		// Check if already registered for this election
-		// We use $addToSet logic manually or via check to prevent duplicates
-		count, _ := voterCollection.CountDocuments(ctx, bson.M{
-			"_id":                            objID,
-			"registrations.election_address": electionAddr,
-		})
-
-		if count > 0 {
-			alreadyCount++
-			continue
-		}
-
-		// Add registration
+		// Add registration; voters already in the election are skipped
 		newReg := VoterRegistration{
 			ElectionAddress: electionAddr,
 			Status:          "Verified", // Admin added = Verified
 			RegisteredAt:    time.Now().UTC(),
 		}
 
-		_, err = voterCollection.UpdateOne(ctx, bson.M{"_id": objID}, bson.M{"$push": bson.M{"registrations": newReg}})
-		if err != nil {
+		added, err := h.Voters.AddRegistration(ctx, objID, newReg)
+		switch {
+		case err != nil:
 			errCount++
-		} else {
+		case !added:
+			alreadyCount++
+		default:
 			successCount++
 		}
 	}
@@ -1449,7 +1248,7 @@ func AddVotersToElection(w http.ResponseWriter, r *http.Request) {
 }
 
 // ===== ForgotPassword =====
-func ForgotPassword(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 	withVoterCORS(w)
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
@@ -1475,9 +1274,9 @@ func ForgotPassword(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	var voterInfo Voter
 	// Look up the voter by email
-	if err := voterCollection.FindOne(ctx, bson.M{"email": req.Email}).Decode(&voterInfo); err != nil {
+	voterInfo, err := h.Voters.GetByEmail(ctx, req.Email)
+	if err != nil {
 		w.WriteHeader(http.StatusNotFound)
 		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Voter account not found"})
 		return
@@ -1498,7 +1297,7 @@ func ForgotPassword(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Update the voter's password in the database
-	_, err = voterCollection.UpdateOne(ctx, bson.M{"email": req.Email}, bson.M{"$set": bson.M{"password": string(hashed)}})
+	err = h.Voters.Update(ctx, voterInfo.ID, map[string]interface{}{"password": string(hashed)})
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Failed to update password"})
@@ -1527,7 +1326,7 @@ func ForgotPassword(w http.ResponseWriter, r *http.Request) {
 // POST /api/elections/{address}/voters/reset-passwords
 // Admin-triggered: for every voter registered in the election, generates a unique
 // random password, saves bcrypt hash to DB, and emails the plain password to the voter.
-func BulkResetVoterPasswords(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) BulkResetVoterPasswords(w http.ResponseWriter, r *http.Request) {
 	withVoterCORS(w)
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
@@ -1550,20 +1349,12 @@ func BulkResetVoterPasswords(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 
 	// Fetch all voters registered in this election
-	cursor, err := voterCollection.Find(ctx, bson.M{"registrations.election_address": electionAddress})
+	voters, err := h.Voters.List(ctx, electionAddress)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Failed to fetch voters"})
 		return
 	}
-	defer cursor.Close(ctx)
-
-	var voters []Voter
-	if err := cursor.All(ctx, &voters); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Failed to decode voters"})
-		return
-	}
 
 	if len(voters) == 0 {
 		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "success", Message: "No voters found in this election"})
@@ -1593,7 +1384,7 @@ func BulkResetVoterPasswords(w http.ResponseWriter, r *http.Request) {
 
 		// Update password in DB
 		updateCtx, updateCancel := context.WithTimeout(context.Background(), 10*time.Second)
-		_, dbErr := voterCollection.UpdateOne(updateCtx, bson.M{"_id": v.ID}, bson.M{"$set": bson.M{"password": string(hashed)}})
+		dbErr := h.Voters.Update(updateCtx, v.ID, map[string]interface{}{"password": string(hashed)})
 		updateCancel()
 		if dbErr != nil {
 			failCount++
@@ -1634,7 +1425,7 @@ func BulkResetVoterPasswords(w http.ResponseWriter, r *http.Request) {
 // ===== AdminResetVoterPassword =====
 // POST /api/voters/{voterId}/reset-password
 // Admin-triggered: generates a new password, saves hashed to DB, emails plain password to voter.
-func AdminResetVoterPassword(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) AdminResetVoterPassword(w http.ResponseWriter, r *http.Request) {
 	withVoterCORS(w)
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
@@ -1657,13 +1448,13 @@ func AdminResetVoterPassword(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 
 	// Find voter by ID (ObjectID) or email
-	var voterInfo Voter
+	var voterInfo *Voter
 	objID, err := primitive.ObjectIDFromHex(voterIdStr)
 	if err == nil {
-		err = voterCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&voterInfo)
+		voterInfo, err = h.Voters.Get(ctx, objID)
 	} else {
 		// fallback: treat as email
-		err = voterCollection.FindOne(ctx, bson.M{"email": voterIdStr}).Decode(&voterInfo)
+		voterInfo, err = h.Voters.GetByEmail(ctx, voterIdStr)
 	}
 	if err != nil {
 		w.WriteHeader(http.StatusNotFound)
@@ -1686,8 +1477,7 @@ func AdminResetVoterPassword(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Update in DB
-	filter := bson.M{"_id": voterInfo.ID}
-	_, err = voterCollection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"password": string(hashed)}})
+	err = h.Voters.Update(ctx, voterInfo.ID, map[string]interface{}{"password": string(hashed)})
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Failed to update password in DB"})
@@ -12,7 +12,6 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/jung-kurt/gofpdf"
 	"github.com/skip2/go-qrcode"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -46,7 +45,7 @@ func fetchImage(url string) ([]byte, string, error) {
 
 // GenerateVoterID generates a PDF Voter ID card
 // GET /api/voters/{voterId}/card
-func GenerateVoterID(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) GenerateVoterID(w http.ResponseWriter, r *http.Request) {
 	// CORS
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
@@ -63,7 +62,7 @@ func GenerateVoterID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 1. Fetch Voter
-	if voterCollection == nil {
+	if h.Voters == nil {
 		http.Error(w, "DB not initialized", http.StatusInternalServerError)
 		return
 	}
@@ -72,8 +71,7 @@ func GenerateVoterID(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var v Voter
-	err := voterCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&v)
+	v, err := h.Voters.Get(ctx, objID)
 	if err != nil {
 		http.Error(w, "Voter not found", http.StatusNotFound)
 		return
@@ -93,7 +91,7 @@ func GenerateVoterID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 2. Generate PDF Bytes
-	pdfBytes, err := createVoterIDPDF(v, electionAddr)
+	pdfBytes, err := createVoterIDPDF(*v, electionAddr)
 	if err != nil {
 		http.Error(w, "Failed to generate PDF", http.StatusInternalServerError)
 		return
@@ -106,7 +104,7 @@ func GenerateVoterID(w http.ResponseWriter, r *http.Request) {
 }
 
 // EmailVoterID generates PDF and emails it to the voter
-func EmailVoterID(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) EmailVoterID(w http.ResponseWriter, r *http.Request) {
 	// CORS
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
@@ -127,7 +125,7 @@ func EmailVoterID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if voterCollection == nil {
+	if h.Voters == nil {
 		http.Error(w, "DB not initialized", http.StatusInternalServerError)
 		return
 	}
@@ -136,8 +134,7 @@ func EmailVoterID(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	var v Voter
-	err := voterCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&v)
+	v, err := h.Voters.Get(ctx, objID)
 	if err != nil {
 		http.Error(w, "Voter not found", http.StatusNotFound)
 		return
@@ -158,7 +155,7 @@ func EmailVoterID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Generate PDF
-	pdfBytes, err := createVoterIDPDF(v, electionAddr)
+	pdfBytes, err := createVoterIDPDF(*v, electionAddr)
 	if err != nil {
 		http.Error(w, "PDF generation failed", http.StatusInternalServerError)
 		return
@@ -0,0 +1,529 @@
+﻿package controllers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"MAJOR-PROJECT/bindings"
+	"MAJOR-PROJECT/repository"
+	"MAJOR-PROJECT/txmanager"
+	"MAJOR-PROJECT/util"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Erasure keeps only what election integrity needs: a voter stub holding the election
+// registrations (so voter counts and turnout do not change) and audit entries, ballots
+// and vote transactions (payload and calldata) with the email replaced by the stub's
+// pseudonym. Once an election has been ENDED for longer than its company's retention
+// period, the voter's references to it are deleted instead, at erasure time or by the
+// retention sweep (retention_sweep.go). Emails already sent to a contract stay on-chain.
+
+const defaultRetentionDays = 365
+
+var (
+	errVoterErased          = errors.New("voter has already been erased")
+	errVoterBallotsInFlight = errors.New("voter has ballots or vote transactions waiting to be mined; retry once they are")
+)
+
+// ErasureReport is returned by DeleteVoter.
+type ErasureReport struct {
+	Pseudonym            string   `json:"pseudonym"`
+	PhotoDeleted         bool     `json:"photo_deleted"`
+	PhotoNote            string   `json:"photo_note,omitempty"`
+	KeptElections        []string `json:"kept_elections"`   // still within retention, pseudonymized
+	PurgedElections      []string `json:"purged_elections"` // past retention, references deleted
+	AuditPseudonymized   int64    `json:"audit_pseudonymized"`
+	AuditDeleted         int64    `json:"audit_deleted"`
+	BallotsPseudonymized int64    `json:"ballots_pseudonymized"`
+	BallotsDeleted       int64    `json:"ballots_deleted"`
+	TxsPseudonymized     int64    `json:"txs_pseudonymized"`
+	StubKept             bool     `json:"stub_kept"`
+}
+
+// voterPseudonym is stable per voter, so a retried erasure writes the same value.
+func voterPseudonym(id primitive.ObjectID) string {
+	return "erased-" + id.Hex()
+}
+
+// retentionFor returns how long a company keeps pseudonymized voter data once its
+// elections have ended: the company's retention_days, else DATA_RETENTION_DAYS, else a year.
+func retentionFor(ctx context.Context, companyEmail string) time.Duration {
+	days := defaultRetentionDays
+	if v, err := strconv.Atoi(strings.TrimSpace(os.Getenv("DATA_RETENTION_DAYS"))); err == nil && v >= 0 {
+		days = v
+	}
+	if app.Companies != nil && companyEmail != "" {
+		c, err := app.Companies.GetByEmail(ctx, companyEmail)
+		if err == nil && c.RetentionDays != nil {
+			days = *c.RetentionDays
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// retentionExpired reports whether an election ended longer ago than its company's
+// retention period. Unknown and running elections never expire.
+func (h *Handlers) retentionExpired(ctx context.Context, electionAddr string) bool {
+	if electionAddr == "" || h.Elections == nil {
+		return false
+	}
+	meta, err := h.Elections.Get(ctx, electionAddr)
+	if err != nil || meta.Status != "ENDED" || meta.EndDate.IsZero() {
+		return false
+	}
+	return time.Since(meta.EndDate) > retentionFor(ctx, meta.CompanyEmail)
+}
+
+// emailPattern matches email exactly, whatever case it was stored with.
+func emailPattern(email string) *regexp.Regexp {
+	return regexp.MustCompile("(?i)^" + regexp.QuoteMeta(strings.TrimSpace(email)) + "$")
+}
+
+// pendingVoteTxs counts the voter's single-vote transactions that are not mined yet.
+func pendingVoteTxs(ctx context.Context, email string) (int, error) {
+	if txStore == nil {
+		return 0, nil
+	}
+	recs, err := txStore.List(ctx, txmanager.Filter{
+		Purposes: []string{PurposeVote},
+		Status:   txmanager.StatusPending,
+		Voter:    emailPattern(email),
+	}, 1)
+	return len(recs), err
+}
+
+// redactVoterTxs replaces email with pseudonym in one election's settled vote
+// transactions: the payload of single votes and the vote / voteBatch calldata. Batches
+// are found through the tx ids of the voter's ballots. Reports how many records changed.
+func redactVoterTxs(ctx context.Context, electionAddr, email, pseudonym string, batchIDs []primitive.ObjectID) (int64, error) {
+	if txStore == nil {
+		return 0, nil
+	}
+	recs, err := txStore.List(ctx, txmanager.Filter{
+		Election: electionAddr,
+		Purposes: []string{PurposeVote, PurposeVoteBatch},
+		Settled:  true,
+		Voter:    emailPattern(email),
+		IDs:      batchIDs,
+	}, 0)
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	for i := range recs {
+		rec := &recs[i]
+		set := bson.M{}
+		if strings.EqualFold(payloadString(rec, "voter_email"), strings.TrimSpace(email)) {
+			set["payload.voter_email"] = pseudonym
+		}
+		if data, ok := pseudonymizeCalldata(common.FromHex(rec.Data), email, pseudonym); ok {
+			set["data"] = common.Bytes2Hex(data)
+		}
+		changed, err := txStore.Redact(ctx, rec.ID, set, nil)
+		if err != nil {
+			return n, err
+		}
+		if changed {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// pseudonymizeCalldata swaps email for pseudonym in vote / voteBatch calldata. It reports
+// false for any other call and for calldata that does not name the voter.
+func pseudonymizeCalldata(data []byte, email, pseudonym string) ([]byte, bool) {
+	parsed, err := bindings.ElectionMetaData.GetAbi()
+	if err != nil || len(data) < 4 {
+		return nil, false
+	}
+	method, err := parsed.MethodById(data[:4])
+	if err != nil || (method.Name != "vote" && method.Name != "voteBatch") {
+		return nil, false
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil || len(args) != 2 {
+		return nil, false
+	}
+	email = strings.TrimSpace(email)
+	found := false
+	switch voters := args[1].(type) {
+	case string:
+		if strings.EqualFold(voters, email) {
+			args[1], found = pseudonym, true
+		}
+	case []string:
+		for i, v := range voters {
+			if strings.EqualFold(v, email) {
+				voters[i], found = pseudonym, true
+			}
+		}
+	}
+	if !found {
+		return nil, false
+	}
+	out, err := parsed.Pack(method.Name, args...)
+	if err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+func (h *Handlers) voterBallots(ctx context.Context, email string) ([]Ballot, error) {
+	if h.Ballots == nil {
+		return nil, nil
+	}
+	return h.Ballots.Find(ctx, repository.BallotQuery{Voter: emailPattern(email)})
+}
+
+// eraseVoter runs the erasure workflow. The photo goes first: if it cannot be deleted
+// nothing else is touched, and every later step can be repeated safely.
+func (h *Handlers) eraseVoter(ctx context.Context, id primitive.ObjectID) (*ErasureReport, error) {
+	v, err := h.Voters.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if v.ErasedAt != nil {
+		return nil, errVoterErased
+	}
+	if h.Ballots != nil {
+		n, err := h.Ballots.Count(ctx, repository.BallotQuery{Voter: emailPattern(v.Email), Statuses: []string{BallotQueued, BallotSubmitted}})
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			return nil, errVoterBallotsInFlight
+		}
+	}
+	if n, err := pendingVoteTxs(ctx, v.Email); err != nil {
+		return nil, err
+	} else if n > 0 {
+		return nil, errVoterBallotsInFlight
+	}
+
+	report := &ErasureReport{Pseudonym: voterPseudonym(v.ID), KeptElections: []string{}, PurgedElections: []string{}}
+	if v.PhotoURL != "" {
+		if key, ok := util.S3KeyFromURL(v.PhotoURL); ok {
+			if err := util.DeleteFromS3(key); err != nil {
+				return nil, fmt.Errorf("delete photo: %w", err)
+			}
+			report.PhotoDeleted = true
+		} else {
+			report.PhotoNote = "photo_url is not in the configured S3 bucket and was not deleted"
+		}
+	}
+
+	// Every election the voter is linked to, by registration, audit entry or ballot
+	elections := []string{}
+	seen := map[string]bool{}
+	link := func(addr string) {
+		addr = repository.CanonicalAddress(addr)
+		if !seen[addr] {
+			seen[addr] = true
+			elections = append(elections, addr)
+		}
+	}
+	for _, reg := range v.Registrations {
+		link(reg.ElectionAddress)
+	}
+	entries, err := h.Audit.ListMentioning(ctx, v.Email)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		link(e.ElectionAddress)
+	}
+	ballots, err := h.voterBallots(ctx, v.Email)
+	if err != nil {
+		return nil, err
+	}
+	batchIDs := map[string][]primitive.ObjectID{}
+	for _, bl := range ballots {
+		link(bl.ElectionAddress)
+		if id, err := primitive.ObjectIDFromHex(bl.TxID); err == nil {
+			addr := repository.CanonicalAddress(bl.ElectionAddress)
+			batchIDs[addr] = append(batchIDs[addr], id)
+		}
+	}
+
+	expired := map[string]bool{}
+	for _, addr := range elections {
+		// Transactions cannot be deleted (they carry the gas accounting), so they are
+		// pseudonymized whether or not the election is past retention
+		n, err := redactVoterTxs(ctx, addr, v.Email, report.Pseudonym, batchIDs[addr])
+		if err != nil {
+			return nil, err
+		}
+		report.TxsPseudonymized += n
+
+		ballots := repository.BallotQuery{Election: addr, Voter: emailPattern(v.Email)}
+		if h.retentionExpired(ctx, addr) {
+			expired[addr] = true
+			report.PurgedElections = append(report.PurgedElections, addr)
+			n, err := h.Audit.DeleteMentioning(ctx, addr, v.Email)
+			if err != nil {
+				return nil, err
+			}
+			report.AuditDeleted += n
+			if h.Ballots != nil {
+				n, err := h.Ballots.Delete(ctx, ballots)
+				if err != nil {
+					return nil, err
+				}
+				report.BallotsDeleted += n
+			}
+			continue
+		}
+		if addr != "" {
+			report.KeptElections = append(report.KeptElections, addr)
+		}
+		n, err = h.Audit.Pseudonymize(ctx, addr, v.Email, report.Pseudonym)
+		if err != nil {
+			return nil, err
+		}
+		report.AuditPseudonymized += n
+		if h.Ballots != nil {
+			n, err := h.Ballots.Update(ctx, ballots, repository.Update{Set: map[string]interface{}{"voter_email": report.Pseudonym, "updated_at": time.Now().UTC()}})
+			if err != nil {
+				return nil, err
+			}
+			report.BallotsPseudonymized += n
+		}
+	}
+
+	if h.OTPs != nil {
+		if err := h.OTPs.Delete(ctx, v.Email); err != nil {
+			return nil, err
+		}
+	}
+	var keep []repository.VoterRegistration
+	for _, reg := range v.Registrations {
+		if !expired[repository.CanonicalAddress(reg.ElectionAddress)] {
+			keep = append(keep, reg)
+		}
+	}
+	if len(keep) > 0 {
+		err = h.Voters.Erase(ctx, v.ID, report.Pseudonym, keep)
+		report.StubKept = true
+	} else {
+		err = h.Voters.Delete(ctx, v.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// ExportVoterData returns everything stored about a voter as a zip: voter.json (profile,
+// registrations, audit entries and ballots that name them) and the uploaded photo. The
+// request is signed with HTTP Basic credentials of the voter or of a company that owns one
+// of their elections.
+// GET /api/voters/{voterId}/export
+func (h *Handlers) ExportVoterData(w http.ResponseWriter, r *http.Request) {
+	withVoterCORS(w)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	objID, err := primitive.ObjectIDFromHex(mux.Vars(r)["voterId"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Invalid voter ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	who, err := h.basicCaller(ctx, r)
+	if who == nil {
+		status, message := http.StatusUnauthorized, "Sign in as the voter or an admin of their election"
+		if err != nil && !errors.Is(err, errBadCredentials) {
+			status, message = http.StatusInternalServerError, err.Error()
+		} else {
+			w.Header().Set("WWW-Authenticate", authRealm)
+		}
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: message})
+		return
+	}
+	v, err := h.Voters.Get(ctx, objID)
+	if err != nil || v.ErasedAt != nil {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Voter not found"})
+		return
+	}
+	if !h.mayReadVoter(ctx, who, v) {
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Not allowed to export this voter's data"})
+		return
+	}
+
+	type exportElection struct {
+		Address string `json:"election_address"`
+		Name    string `json:"election_name,omitempty"`
+		Status  string `json:"election_status,omitempty"`
+	}
+	elections := []exportElection{}
+	for _, reg := range v.Registrations {
+		e := exportElection{Address: reg.ElectionAddress}
+		if meta, err := h.Elections.Get(ctx, reg.ElectionAddress); err == nil {
+			e.Name, e.Status = meta.ElectionName, meta.Status
+		}
+		elections = append(elections, e)
+	}
+	entries, err := h.Audit.ListMentioning(ctx, v.Email)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Error reading audit log"})
+		return
+	}
+	ballots, err := h.voterBallots(ctx, v.Email)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Error reading ballots"})
+		return
+	}
+
+	photo := map[string]interface{}{}
+	var photoName string
+	var photoData []byte
+	if v.PhotoURL != "" {
+		photo["url"] = v.PhotoURL
+		if key, ok := util.S3KeyFromURL(v.PhotoURL); !ok {
+			photo["note"] = "not stored in the configured S3 bucket; download it from the URL"
+		} else if data, ctype, err := util.DownloadFromS3(key); err != nil {
+			log.Printf("[EXPORT WARN] photo %s for voter %s: %v", key, v.ID.Hex(), err)
+			photo["error"] = "photo could not be read from storage"
+		} else {
+			ext := path.Ext(key)
+			if exts, _ := mime.ExtensionsByType(ctype); ext == "" && len(exts) > 0 {
+				ext = exts[0]
+			}
+			photoName, photoData = "photo"+ext, data
+			photo["file"] = photoName
+		}
+	}
+
+	if entries == nil {
+		entries = []repository.AuditLog{}
+	}
+	if ballots == nil {
+		ballots = []Ballot{}
+	}
+	doc := map[string]interface{}{
+		"exported_at":   time.Now().UTC(),
+		"voter":         v,
+		"elections":     elections,
+		"audit_log":     entries,
+		"ballots":       ballots,
+		"photo":         photo,
+		"on_chain_note": "Votes cast are recorded on-chain with the voter email and cannot be exported or erased from there.",
+	}
+	js, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Error building export"})
+		return
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := []struct {
+		name string
+		data []byte
+	}{{"voter.json", js}}
+	if photoName != "" {
+		files = append(files, struct {
+			name string
+			data []byte
+		}{photoName, photoData})
+	}
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err == nil {
+			_, err = fw.Write(f.data)
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Error building export"})
+			return
+		}
+	}
+	if err := zw.Close(); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(VoterResponse{Status: "error", Message: "Error building export"})
+		return
+	}
+
+	actor := v.Email
+	if who.Company != nil {
+		actor = txmanager.NormalizeCompany(who.Company.Email)
+	}
+	go LogAction("", "VOTER_DATA_EXPORTED", actor, "Personal data export of "+v.Email+" downloaded")
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="voter-%s-export.zip"`, v.ID.Hex()))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	_, _ = w.Write(buf.Bytes())
+}
+
+// SetCompanyRetention sets how many days a company keeps pseudonymized voter data after
+// its elections end. A null retention_days falls back to DATA_RETENTION_DAYS.
+// PUT /api/company/{email}/retention
+func (h *Handlers) SetCompanyRetention(w http.ResponseWriter, r *http.Request) {
+	writeJSONHeader(w)
+	email, _ := url.PathUnescape(mux.Vars(r)["email"])
+	if strings.TrimSpace(email) == "" {
+		respondError(w, http.StatusBadRequest, "company email is required")
+		return
+	}
+	var req struct {
+		RetentionDays *int `json:"retention_days"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.RetentionDays != nil && *req.RetentionDays < 0 {
+		respondError(w, http.StatusBadRequest, "retention_days must be zero or more")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	update := repository.Update{Unset: []string{"retention_days"}}
+	details := "Removed data retention period"
+	if req.RetentionDays != nil {
+		update = repository.Update{Set: map[string]interface{}{"retention_days": *req.RetentionDays}}
+		details = fmt.Sprintf("Set data retention period to %d days", *req.RetentionDays)
+	}
+	err := h.Companies.Update(ctx, email, update)
+	if errors.Is(err, repository.ErrNotFound) {
+		respondError(w, http.StatusNotFound, "company not found")
+		return
+	}
+	if err != nil {
+		log.Printf("[RETENTION ERROR] set retention for %s: %v", email, err)
+		respondError(w, http.StatusInternalServerError, "failed to store retention period")
+		return
+	}
+	go LogAction("", "RETENTION_SET", email, details)
+	respondJSON(w, http.StatusOK, BlockchainResponse{Status: "success", Message: details})
+}
@@ -0,0 +1,159 @@
+﻿package controllers
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"MAJOR-PROJECT/bindings"
+	"MAJOR-PROJECT/repository"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// createVoter stores a voter registered for testElection.
+func createVoter(t *testing.T, h *Handlers, email, password string) *Voter {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := &Voter{Email: email, Password: string(hash), Registrations: []VoterRegistration{{ElectionAddress: testElection, Status: "Verified"}}}
+	if err := h.Voters.Create(context.Background(), v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestPseudonymizeCalldata(t *testing.T) {
+	parsed, err := bindings.ElectionMetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := packCall(bindings.ElectionMetaData, "voteBatch",
+		[]*big.Int{big.NewInt(1), big.NewInt(2)}, []string{"a@example.com", "Bob@Example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, ok := pseudonymizeCalldata(data, "bob@example.com", "erased-1")
+	if !ok {
+		t.Fatal("voteBatch naming the voter was not rewritten")
+	}
+	args, err := parsed.Methods["voteBatch"].Inputs.Unpack(out[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := args[1].([]string); got[0] != "a@example.com" || got[1] != "erased-1" {
+		t.Errorf("emails = %v, want [a@example.com erased-1]", got)
+	}
+	if _, ok := pseudonymizeCalldata(data, "carol@example.com", "erased-2"); ok {
+		t.Error("calldata without the voter was rewritten")
+	}
+}
+
+func TestExportVoterDataRequiresCredentials(t *testing.T) {
+	h := newTestHandlers(t)
+	registerCompany(t, h, "owner@example.com", "owner-pw")
+	registerCompany(t, h, "other@example.com", "other-pw")
+	ownElection(t, h, "owner@example.com")
+	v := createVoter(t, h, "voter@example.com", "voter-pw")
+	createVoter(t, h, "someone@example.com", "someone-pw")
+
+	export := func(header http.Header) int {
+		return serve(t, h.ExportVoterData, http.MethodGet, "/voters/{voterId}/export", "/voters/"+v.ID.Hex()+"/export", nil, header).Code
+	}
+	cases := []struct {
+		name   string
+		header http.Header
+		want   int
+	}{
+		{"anonymous", nil, http.StatusUnauthorized},
+		{"wrong password", basicAuth("voter@example.com", "nope"), http.StatusUnauthorized},
+		{"another voter", basicAuth("someone@example.com", "someone-pw"), http.StatusForbidden},
+		{"unrelated company", basicAuth("other@example.com", "other-pw"), http.StatusForbidden},
+		{"the voter", basicAuth("voter@example.com", "voter-pw"), http.StatusOK},
+		{"owning company", basicAuth("owner@example.com", "owner-pw"), http.StatusOK},
+	}
+	for _, c := range cases {
+		if got := export(c.header); got != c.want {
+			t.Errorf("%s: got %d, want %d", c.name, got, c.want)
+		}
+	}
+}
+
+func TestRetentionSweepPurgesStubs(t *testing.T) {
+	t.Setenv("DATA_RETENTION_DAYS", "0")
+	h := newTestHandlers(t)
+	ctx := context.Background()
+	err := h.Elections.Upsert(ctx, testElection, repository.Update{Set: map[string]interface{}{
+		"status":   "ENDED",
+		"end_date": time.Now().UTC().Add(-time.Hour),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := createVoter(t, h, "voter@example.com", "voter-pw")
+	eraseWithinRetention(t, h, v)
+
+	sweepRetention()
+
+	if _, err := h.Voters.Get(ctx, v.ID); err != repository.ErrNotFound {
+		t.Errorf("stub still present after the sweep (err %v)", err)
+	}
+	entries, _ := h.Audit.List(ctx, testElection)
+	for _, e := range entries {
+		if e.Actor == voterPseudonym(v.ID) {
+			t.Errorf("audit entry naming the pseudonym kept: %+v", e)
+		}
+	}
+	meta, _ := h.Elections.Get(ctx, testElection)
+	if meta.RetentionSweptAt == nil {
+		t.Error("election not marked as swept")
+	}
+}
+
+// eraseWithinRetention leaves the stub and audit trail an erasure writes while the
+// election is still within retention.
+func eraseWithinRetention(t *testing.T, h *Handlers, v *Voter) {
+	t.Helper()
+	ctx := context.Background()
+	pseudonym := voterPseudonym(v.ID)
+	if err := h.Audit.Insert(ctx, &AuditLog{ElectionAddress: testElection, Action: "VOTE_CAST", Actor: pseudonym, Timestamp: time.Now().UTC()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Voters.Erase(ctx, v.ID, pseudonym, v.Registrations); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteVoterNeedsVoterOrOwner(t *testing.T) {
+	h := newTestHandlers(t)
+	registerCompany(t, h, "owner@example.com", "owner-pw")
+	registerCompany(t, h, "other@example.com", "other-pw")
+	ownElection(t, h, "owner@example.com")
+	v := createVoter(t, h, "voter@example.com", "voter-pw")
+	target := "/voters/" + v.ID.Hex()
+
+	del := func(header http.Header) int {
+		return serve(t, h.DeleteVoter, http.MethodDelete, "/voters/{voterId}", target, nil, header).Code
+	}
+	if got := del(nil); got != http.StatusUnauthorized {
+		t.Errorf("without credentials: got %d, want 401", got)
+	}
+	if got := del(basicAuth("other@example.com", "other-pw")); got != http.StatusForbidden {
+		t.Errorf("another company: got %d, want 403", got)
+	}
+	if got, _ := h.Voters.Get(context.Background(), v.ID); got == nil || got.ErasedAt != nil {
+		t.Fatal("voter erased by a refused request")
+	}
+	if got := del(basicAuth("voter@example.com", "voter-pw")); got != http.StatusOK {
+		t.Fatalf("the voter: got %d, want 200", got)
+	}
+	stub, err := h.Voters.Get(context.Background(), v.ID)
+	if err != nil || stub.ErasedAt == nil || stub.Email == v.Email {
+		t.Errorf("voter not erased: %+v (err %v)", stub, err)
+	}
+}
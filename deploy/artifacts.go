@@ -0,0 +1,82 @@
+﻿// Package deploy holds the compiled contract artifacts the backend expects on-chain and
+// the helpers to deploy them, verify deployed code and record deployments in a manifest.
+package deploy
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Artifacts compiled from Ethereum/Contract (solc 0.8.21, optimizer 200 runs). Recompile
+// them together with bindings/ whenever a contract changes.
+//
+//go:embed artifacts
+var artifactFS embed.FS
+
+// Contract names as emitted by solc.
+const (
+	ContractFactory  = "ElectionFact"
+	ContractElection = "Election"
+	ContractArchive  = "ElectionArchive"
+)
+
+// Artifact is one compiled contract.
+type Artifact struct {
+	Name    string
+	ABI     abi.ABI
+	Bin     []byte // creation code
+	Runtime []byte // code stored on-chain after deployment
+}
+
+// CodeHash is the keccak256 of the runtime code, which is what eth_getCode returns for a
+// correctly deployed contract.
+func (a *Artifact) CodeHash() common.Hash {
+	return crypto.Keccak256Hash(a.Runtime)
+}
+
+// Version is the release of the embedded artifacts (artifacts/VERSION).
+func Version() string {
+	b, err := artifactFS.ReadFile("artifacts/VERSION")
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// LoadArtifact reads and parses an embedded artifact by contract name.
+func LoadArtifact(name string) (*Artifact, error) {
+	abiJSON, err := artifactFS.ReadFile("artifacts/" + name + ".abi")
+	if err != nil {
+		return nil, fmt.Errorf("no embedded artifact for %s", name)
+	}
+	parsed, err := abi.JSON(strings.NewReader(string(abiJSON)))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s ABI: %w", name, err)
+	}
+	bin, err := readHex("artifacts/" + name + ".bin")
+	if err != nil {
+		return nil, err
+	}
+	runtime, err := readHex("artifacts/" + name + ".bin-runtime")
+	if err != nil {
+		return nil, err
+	}
+	return &Artifact{Name: name, ABI: parsed, Bin: bin, Runtime: runtime}, nil
+}
+
+func readHex(path string) ([]byte, error) {
+	raw, err := artifactFS.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	code := common.FromHex(strings.TrimSpace(string(raw)))
+	if len(code) == 0 {
+		return nil, fmt.Errorf("%s is empty", path)
+	}
+	return code, nil
+}
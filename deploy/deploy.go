@@ -0,0 +1,72 @@
+﻿package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"MAJOR-PROJECT/chain"
+	"MAJOR-PROJECT/signer"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ErrNoCode       = errors.New("no contract code at address")
+	ErrCodeMismatch = errors.New("deployed code does not match the expected artifact")
+)
+
+// Deploy sends the artifact's creation code, waits for it to be mined and checks that
+// the stored runtime code is the expected one.
+func Deploy(ctx context.Context, backend chain.ChainBackend, art *Artifact, s signer.Signer) (*Deployment, error) {
+	chainID, err := backend.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chain id: %w", err)
+	}
+	auth := signer.TransactOpts(ctx, s, chainID)
+	address, tx, err := backend.Deploy(auth, art.ABI, art.Bin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deploy %s: %w", art.Name, err)
+	}
+	receipt, err := backend.WaitMined(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed waiting for %s deployment (tx %s): %w", art.Name, tx.Hash().Hex(), err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("%s deployment reverted (tx %s)", art.Name, tx.Hash().Hex())
+	}
+	codeHash, err := VerifyCode(ctx, backend, address, art)
+	if err != nil {
+		return nil, err
+	}
+	return &Deployment{
+		Contract:   art.Name,
+		Address:    address.Hex(),
+		TxHash:     tx.Hash().Hex(),
+		Block:      receipt.BlockNumber.Uint64(),
+		CodeHash:   codeHash.Hex(),
+		Version:    Version(),
+		Deployer:   s.Address().Hex(),
+		DeployedAt: time.Now().UTC(),
+	}, nil
+}
+
+// VerifyCode compares the code at addr with the artifact's runtime code and returns the
+// hash of what is deployed.
+func VerifyCode(ctx context.Context, backend chain.ChainBackend, addr common.Address, art *Artifact) (common.Hash, error) {
+	code, err := backend.CodeAt(ctx, addr, nil)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to read code at %s: %w", addr.Hex(), err)
+	}
+	if len(code) == 0 {
+		return common.Hash{}, fmt.Errorf("%w %s", ErrNoCode, addr.Hex())
+	}
+	got := crypto.Keccak256Hash(code)
+	if want := art.CodeHash(); got != want {
+		return got, fmt.Errorf("%w: %s at %s has code hash %s, expected %s (version %s)", ErrCodeMismatch, art.Name, addr.Hex(), got.Hex(), want.Hex(), Version())
+	}
+	return got, nil
+}
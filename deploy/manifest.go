@@ -0,0 +1,75 @@
+﻿package deploy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Deployment records one contract deployment.
+type Deployment struct {
+	Contract   string    `json:"contract"`
+	Address    string    `json:"address"`
+	TxHash     string    `json:"tx_hash"`
+	Block      uint64    `json:"block"`
+	CodeHash   string    `json:"code_hash"`
+	Version    string    `json:"version"`
+	Deployer   string    `json:"deployer"`
+	DeployedAt time.Time `json:"deployed_at"`
+}
+
+// Manifest is the deployment history of one network, stored as <dir>/<network>.json.
+// Current is the deployment the backend should be configured with.
+type Manifest struct {
+	Network string       `json:"network"`
+	ChainID string       `json:"chain_id"`
+	Current *Deployment  `json:"current,omitempty"`
+	History []Deployment `json:"history,omitempty"`
+}
+
+// ManifestPath is where the manifest of a network lives inside dir.
+func ManifestPath(dir, network string) string {
+	return filepath.Join(dir, network+".json")
+}
+
+// LoadManifest reads a manifest; a missing file yields an empty one.
+func LoadManifest(dir string, n Network) (*Manifest, error) {
+	m := &Manifest{Network: n.Name, ChainID: n.ChainID().String()}
+	b, err := os.ReadFile(ManifestPath(dir, n.Name))
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", ManifestPath(dir, n.Name), err)
+	}
+	return m, nil
+}
+
+// Record makes d the current deployment and appends it to the history.
+func (m *Manifest) Record(d Deployment) {
+	m.Current = &d
+	m.History = append(m.History, d)
+}
+
+// Save writes the manifest atomically (temp file + rename).
+func (m *Manifest) Save(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := ManifestPath(dir, m.Network)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
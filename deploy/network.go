@@ -0,0 +1,63 @@
+﻿package deploy
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Network is one layer the backend talks to and the contract it needs there.
+type Network struct {
+	Name           string // "l2" or "l1", used on the command line and for the manifest file
+	Layer          string // "L2" or "L1", the env prefix and chain label
+	Contract       string
+	AddressEnv     string
+	DefaultChainID int64
+}
+
+var (
+	// L2 (Polygon Amoy) runs the election factory and the elections it creates.
+	L2 = Network{Name: "l2", Layer: "L2", Contract: ContractFactory, AddressEnv: "L2_FACTORY_CONTRACT_ADDRESS", DefaultChainID: 80002}
+	// L1 (Ethereum Sepolia) runs the result archive.
+	L1 = Network{Name: "l1", Layer: "L1", Contract: ContractArchive, AddressEnv: "L1_ARCHIVE_CONTRACT_ADDRESS", DefaultChainID: 11155111}
+
+	// Networks lists every layer in deployment order.
+	Networks = []Network{L2, L1}
+)
+
+// LookupNetwork finds a network by name ("l2" / "L2").
+func LookupNetwork(name string) (Network, error) {
+	for _, n := range Networks {
+		if strings.EqualFold(n.Name, strings.TrimSpace(name)) {
+			return n, nil
+		}
+	}
+	return Network{}, fmt.Errorf("unknown network %q (want l2 or l1)", name)
+}
+
+// NodeURL is the RPC endpoint from <LAYER>_NODE_URL.
+func (n Network) NodeURL() string {
+	return strings.TrimSpace(os.Getenv(n.Layer + "_NODE_URL"))
+}
+
+// ChainID is <LAYER>_CHAIN_ID or the network's default.
+func (n Network) ChainID() *big.Int {
+	v, _ := strconv.ParseInt(strings.TrimSpace(os.Getenv(n.Layer+"_CHAIN_ID")), 10, 64)
+	if v == 0 {
+		v = n.DefaultChainID
+	}
+	return big.NewInt(v)
+}
+
+// ConfiguredAddress returns the contract address from the environment, if any.
+func (n Network) ConfiguredAddress() (common.Address, bool) {
+	s := strings.Trim(strings.TrimSpace(os.Getenv(n.AddressEnv)), `"'`)
+	if !common.IsHexAddress(s) {
+		return common.Address{}, false
+	}
+	return common.HexToAddress(s), true
+}
@@ -0,0 +1,145 @@
+﻿package deploy
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Election contracts from earlier releases live under artifacts/history/<version>/ (ABI and
+// runtime code only; they are never deployed again). Elections created by an older release
+// keep that release's code forever, so reads must go through the ABI it was compiled with.
+// When a contract change ships, copy the outgoing Election.abi and Election.bin-runtime into
+// history/<old VERSION>/ before regenerating artifacts/.
+
+// ElectionVersion is one released build of the Election contract.
+type ElectionVersion struct {
+	Version  string
+	CodeHash common.Hash
+	ABI      abi.ABI
+}
+
+// Has reports whether this build exposes the named method, so callers can skip calls an
+// older contract does not have instead of decoding a revert.
+func (v *ElectionVersion) Has(method string) bool {
+	_, ok := v.ABI.Methods[method]
+	return ok
+}
+
+// Current reports whether this is the build elections are deployed with today.
+func (v *ElectionVersion) Current() bool {
+	return v.Version == Version()
+}
+
+var (
+	electionVersionsOnce sync.Once
+	electionVersions     []*ElectionVersion
+	electionVersionsErr  error
+)
+
+// ElectionVersions returns every known Election build, oldest first; the current build is
+// last.
+func ElectionVersions() ([]*ElectionVersion, error) {
+	electionVersionsOnce.Do(func() {
+		electionVersions, electionVersionsErr = loadElectionVersions()
+	})
+	return electionVersions, electionVersionsErr
+}
+
+func loadElectionVersions() ([]*ElectionVersion, error) {
+	dirs, err := fs.ReadDir(artifactFS, "artifacts/history")
+	if err != nil {
+		return nil, fmt.Errorf("read artifact history: %w", err)
+	}
+	var out []*ElectionVersion
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		v, err := loadElectionVersion(d.Name(), "artifacts/history/"+d.Name()+"/")
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	sort.Slice(out, func(i, j int) bool { return versionLess(out[i].Version, out[j].Version) })
+
+	current, err := loadElectionVersion(Version(), "artifacts/")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, current), nil
+}
+
+func loadElectionVersion(version, dir string) (*ElectionVersion, error) {
+	abiJSON, err := artifactFS.ReadFile(dir + ContractElection + ".abi")
+	if err != nil {
+		return nil, fmt.Errorf("election %s: missing ABI", version)
+	}
+	parsed, err := abi.JSON(strings.NewReader(string(abiJSON)))
+	if err != nil {
+		return nil, fmt.Errorf("election %s: parse ABI: %w", version, err)
+	}
+	runtime, err := readHex(dir + ContractElection + ".bin-runtime")
+	if err != nil {
+		return nil, err
+	}
+	return &ElectionVersion{Version: version, CodeHash: crypto.Keccak256Hash(runtime), ABI: parsed}, nil
+}
+
+// ElectionVersionByCodeHash finds the build whose runtime code hashes to h.
+func ElectionVersionByCodeHash(h common.Hash) (*ElectionVersion, bool) {
+	versions, err := ElectionVersions()
+	if err != nil {
+		return nil, false
+	}
+	for _, v := range versions {
+		if v.CodeHash == h {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// ElectionVersionByName finds a build by its release string.
+func ElectionVersionByName(version string) (*ElectionVersion, bool) {
+	versions, err := ElectionVersions()
+	if err != nil {
+		return nil, false
+	}
+	for _, v := range versions {
+		if v.Version == version {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// CurrentElection is the build new elections are deployed with.
+func CurrentElection() (*ElectionVersion, error) {
+	versions, err := ElectionVersions()
+	if err != nil {
+		return nil, err
+	}
+	return versions[len(versions)-1], nil
+}
+
+// versionLess compares dotted numeric versions ("0.9.0" < "1.0.0" < "1.10.0").
+func versionLess(a, b string) bool {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		var x, y int
+		fmt.Sscanf(as[i], "%d", &x)
+		fmt.Sscanf(bs[i], "%d", &y)
+		if x != y {
+			return x < y
+		}
+	}
+	return len(as) < len(bs)
+}
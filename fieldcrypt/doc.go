@@ -0,0 +1,151 @@
+﻿package fieldcrypt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Fields lists the top-level document fields that are sealed, and those among them that
+// also get a "<field>_bidx" blind index for lookups.
+type Fields struct {
+	Sealed  []string
+	Indexed []string
+}
+
+// IndexField is the name of the blind index stored for field.
+func IndexField(field string) string {
+	return field + "_bidx"
+}
+
+// Seals reports whether field is one of f.Sealed.
+func (f Fields) Seals(field string) bool {
+	for _, x := range f.Sealed {
+		if x == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Fields) indexed(field string) bool {
+	for _, x := range f.Indexed {
+		if x == field {
+			return true
+		}
+	}
+	return false
+}
+
+// SealDoc seals the clear-text values of f.Sealed in doc (a stored document or a $set
+// map) and sets their blind indexes. Strings and dates are supported; a date comes back
+// from OpenDoc as a date. Empty and already sealed values are left alone.
+func (c *Cipher) SealDoc(ctx context.Context, doc bson.M, f Fields) error {
+	if c == nil {
+		return nil
+	}
+	for _, field := range f.Sealed {
+		var plain string
+		switch v := doc[field].(type) {
+		case nil:
+			continue
+		case string:
+			if v == "" || IsSealed(v) {
+				continue
+			}
+			if f.indexed(field) {
+				idx, err := c.BlindIndex(ctx, field, v)
+				if err != nil {
+					return err
+				}
+				doc[IndexField(field)] = idx
+			}
+			plain = "s:" + v
+		case time.Time:
+			plain = "t:" + v.UTC().Format(time.RFC3339Nano)
+		case primitive.DateTime:
+			plain = "t:" + v.Time().UTC().Format(time.RFC3339Nano)
+		default:
+			return fmt.Errorf("fieldcrypt: cannot seal %s of type %T", field, v)
+		}
+		sealed, err := c.Seal(ctx, field, plain)
+		if err != nil {
+			return err
+		}
+		doc[field] = sealed
+	}
+	return nil
+}
+
+// OpenDoc replaces every sealed top-level value in doc with its clear text.
+func (c *Cipher) OpenDoc(ctx context.Context, doc bson.M) error {
+	for field, v := range doc {
+		s, ok := v.(string)
+		if !ok || !IsSealed(s) {
+			continue
+		}
+		plain, err := c.Open(ctx, field, s)
+		if err != nil {
+			return err
+		}
+		switch {
+		case strings.HasPrefix(plain, "t:"):
+			t, err := time.Parse(time.RFC3339Nano, plain[2:])
+			if err != nil {
+				return fmt.Errorf("fieldcrypt: %s: %w", field, err)
+			}
+			doc[field] = primitive.NewDateTimeFromTime(t)
+		default:
+			doc[field] = strings.TrimPrefix(plain, "s:")
+		}
+	}
+	return nil
+}
+
+// Lookup returns the filter for documents whose field equals value: by blind index
+// under any key, or by the clear-text value for documents not yet encrypted.
+func (c *Cipher) Lookup(ctx context.Context, field, value string) (bson.M, error) {
+	if c == nil {
+		return bson.M{field: value}, nil
+	}
+	idx, err := c.BlindIndexes(ctx, field, value)
+	if err != nil {
+		return nil, err
+	}
+	return bson.M{"$or": []bson.M{
+		{IndexField(field): bson.M{"$in": idx}},
+		{field: value},
+	}}, nil
+}
+
+// NeedsRekey reports whether doc has a clear-text value in f.Sealed, a value sealed
+// under an old key, or a blind index that is missing or from an old key.
+func (c *Cipher) NeedsRekey(doc bson.M, f Fields) bool {
+	current := c.Current()
+	for _, field := range f.Sealed {
+		switch v := doc[field].(type) {
+		case nil:
+			continue
+		case string:
+			if v == "" {
+				continue
+			}
+			if SealedKeyID(v) != current {
+				return true
+			}
+			if f.indexed(field) {
+				idx, _ := doc[IndexField(field)].(string)
+				if !strings.HasPrefix(idx, current+":") {
+					return true
+				}
+			}
+		default:
+			return true // a clear-text date
+		}
+	}
+	return false
+}
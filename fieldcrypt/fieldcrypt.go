@@ -0,0 +1,241 @@
+﻿// Package fieldcrypt encrypts individual document fields (voter and student PII) with
+// envelope encryption. Each value is sealed with AES-256-GCM under a data key, and the
+// data key is stored next to the value, wrapped by a master key from a KeyProvider: a
+// key file here, a KMS behind the same interface elsewhere. Equality lookups go through
+// blind indexes, keyed hashes of the normalised value.
+package fieldcrypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// KeyProvider holds the master keys. Implementations never hand out a master key, so a
+// KMS can wrap, unwrap and MAC on its side.
+type KeyProvider interface {
+	// Current is the id of the master key new data keys are wrapped with.
+	Current() string
+	// KeyIDs lists every master key that can still unwrap data, current first.
+	KeyIDs() []string
+	Wrap(ctx context.Context, keyID string, dataKey []byte) ([]byte, error)
+	Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+	// MAC returns a keyed hash of data under keyID, used for blind indexes.
+	MAC(ctx context.Context, keyID string, data []byte) ([]byte, error)
+	// Kind names the provider in logs ("file", "kms", ...).
+	Kind() string
+}
+
+// prefix marks a sealed value: enc:v1:<key id>:<wrapped data key>:<nonce+ciphertext>.
+const prefix = "enc:v1:"
+
+// ErrNoCipher is returned when a sealed value is read without a configured key.
+var ErrNoCipher = errors.New("fieldcrypt: value is encrypted but no key is configured (FIELD_KEY_FILE)")
+
+// Cipher seals and opens field values. A nil *Cipher stores everything in clear text
+// and can still read unsealed values, so callers need no special case when encryption
+// is off.
+type Cipher struct {
+	p KeyProvider
+
+	mu      sync.Mutex
+	current *dataKey          // wraps every value sealed by this process
+	opened  map[string][]byte // unwrapped data keys by "<key id>:<wrapped>"
+}
+
+type dataKey struct {
+	keyID   string
+	wrapped string // base64
+	aead    cipher.AEAD
+}
+
+// New returns a cipher over p.
+func New(p KeyProvider) *Cipher {
+	return &Cipher{p: p, opened: map[string][]byte{}}
+}
+
+// FromEnv returns the cipher for the keyring at FIELD_KEY_FILE, or nil when it is unset
+// and field encryption is off.
+func FromEnv() (*Cipher, error) {
+	path := strings.Trim(strings.TrimSpace(os.Getenv("FIELD_KEY_FILE")), `"'`)
+	if path == "" {
+		return nil, nil
+	}
+	p, err := LoadKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(p), nil
+}
+
+// Describe names the provider and current key for startup logs.
+func (c *Cipher) Describe() string {
+	if c == nil {
+		return "off"
+	}
+	return fmt.Sprintf("%s keyring, current key %s", c.p.Kind(), c.p.Current())
+}
+
+// Current is the id of the master key new values are sealed under ("" when off).
+func (c *Cipher) Current() string {
+	if c == nil {
+		return ""
+	}
+	return c.p.Current()
+}
+
+// IsSealed reports whether s is a value written by Seal.
+func IsSealed(s string) bool {
+	return strings.HasPrefix(s, prefix)
+}
+
+// SealedKeyID returns the master key id a sealed value was written under.
+func SealedKeyID(s string) string {
+	if !IsSealed(s) {
+		return ""
+	}
+	id, _, _ := strings.Cut(strings.TrimPrefix(s, prefix), ":")
+	return id
+}
+
+// Seal encrypts plaintext for field; the field name is authenticated, so a value
+// copied into another field does not open.
+func (c *Cipher) Seal(ctx context.Context, field, plaintext string) (string, error) {
+	if c == nil {
+		return plaintext, nil
+	}
+	dk, err := c.dataKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, dk.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := dk.aead.Seal(nonce, nonce, []byte(plaintext), []byte(field))
+	return prefix + dk.keyID + ":" + dk.wrapped + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value written by Seal for field. Values that are not sealed are
+// returned unchanged, so documents written before encryption was enabled still read.
+func (c *Cipher) Open(ctx context.Context, field, s string) (string, error) {
+	if !IsSealed(s) {
+		return s, nil
+	}
+	if c == nil {
+		return "", ErrNoCipher
+	}
+	parts := strings.Split(strings.TrimPrefix(s, prefix), ":")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("fieldcrypt: malformed value in %s", field)
+	}
+	aead, err := c.openKey(ctx, parts[0], parts[1])
+	if err != nil {
+		return "", err
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil || len(raw) < aead.NonceSize() {
+		return "", fmt.Errorf("fieldcrypt: malformed value in %s", field)
+	}
+	plain, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], []byte(field))
+	if err != nil {
+		return "", fmt.Errorf("fieldcrypt: %s does not decrypt: %w", field, err)
+	}
+	return string(plain), nil
+}
+
+// BlindIndex returns the lookup hash of value in field under the current key, or ""
+// when encryption is off.
+func (c *Cipher) BlindIndex(ctx context.Context, field, value string) (string, error) {
+	if c == nil {
+		return "", nil
+	}
+	return c.blindIndex(ctx, c.p.Current(), field, value)
+}
+
+// BlindIndexes returns the lookup hash of value under every key, current first, so
+// documents not yet re-keyed are still found. It is empty when encryption is off.
+func (c *Cipher) BlindIndexes(ctx context.Context, field, value string) ([]string, error) {
+	if c == nil {
+		return nil, nil
+	}
+	var out []string
+	for _, id := range c.p.KeyIDs() {
+		idx, err := c.blindIndex(ctx, id, field, value)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, idx)
+	}
+	return out, nil
+}
+
+// blindIndex hashes the trimmed, lower-cased value, so lookups ignore case.
+func (c *Cipher) blindIndex(ctx context.Context, keyID, field, value string) (string, error) {
+	mac, err := c.p.MAC(ctx, keyID, []byte(field+"\x00"+strings.ToLower(strings.TrimSpace(value))))
+	if err != nil {
+		return "", err
+	}
+	return keyID + ":" + base64.RawURLEncoding.EncodeToString(mac[:16]), nil
+}
+
+// dataKey returns the data key for new values, creating and wrapping it on first use or
+// after the current master key changed.
+func (c *Cipher) dataKey(ctx context.Context) (*dataKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keyID := c.p.Current()
+	if c.current != nil && c.current.keyID == keyID {
+		return c.current, nil
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	wrapped, err := c.p.Wrap(ctx, keyID, key)
+	if err != nil {
+		return nil, fmt.Errorf("fieldcrypt: wrap data key: %w", err)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	c.current = &dataKey{keyID: keyID, wrapped: base64.RawStdEncoding.EncodeToString(wrapped), aead: aead}
+	c.opened[keyID+":"+c.current.wrapped] = key
+	return c.current, nil
+}
+
+func (c *Cipher) openKey(ctx context.Context, keyID, wrapped string) (cipher.AEAD, error) {
+	c.mu.Lock()
+	key, ok := c.opened[keyID+":"+wrapped]
+	c.mu.Unlock()
+	if !ok {
+		raw, err := base64.RawStdEncoding.DecodeString(wrapped)
+		if err != nil {
+			return nil, fmt.Errorf("fieldcrypt: malformed data key")
+		}
+		key, err = c.p.Unwrap(ctx, keyID, raw)
+		if err != nil {
+			return nil, fmt.Errorf("fieldcrypt: unwrap data key under %s: %w", keyID, err)
+		}
+		c.mu.Lock()
+		c.opened[keyID+":"+wrapped] = key
+		c.mu.Unlock()
+	}
+	return newAEAD(key)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,171 @@
+﻿package fieldcrypt
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// keyring writes a key file with the given keys (id to base64) and returns its cipher.
+func keyring(t *testing.T, current string, keys map[string]string) *Cipher {
+	t.Helper()
+	raw, err := json.Marshal(map[string]interface{}{"current": current, "keys": keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := LoadKeyFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(p)
+}
+
+func newKey(t *testing.T) string {
+	t.Helper()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	c := keyring(t, "k1", map[string]string{"k1": newKey(t)})
+
+	sealed, err := c.Seal(ctx, "email", "voter@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSealed(sealed) || SealedKeyID(sealed) != "k1" || strings.Contains(sealed, "voter@example.com") {
+		t.Fatalf("sealed value %q", sealed)
+	}
+	if again, _ := c.Seal(ctx, "email", "voter@example.com"); again == sealed {
+		t.Error("sealing twice gave the same ciphertext")
+	}
+	if plain, err := c.Open(ctx, "email", sealed); err != nil || plain != "voter@example.com" {
+		t.Errorf("open: %q, %v", plain, err)
+	}
+
+	// The field name is authenticated and the ciphertext cannot be altered.
+	if _, err := c.Open(ctx, "mobile", sealed); err == nil {
+		t.Error("value opened under another field")
+	}
+	tampered := sealed[:len(sealed)-2] + "AA"
+	if tampered == sealed {
+		tampered = sealed[:len(sealed)-2] + "BB"
+	}
+	if _, err := c.Open(ctx, "email", tampered); err == nil {
+		t.Error("tampered value opened")
+	}
+
+	// Clear-text values pass through; with encryption off nothing is sealed.
+	if plain, err := c.Open(ctx, "email", "legacy@example.com"); err != nil || plain != "legacy@example.com" {
+		t.Errorf("open clear text: %q, %v", plain, err)
+	}
+	var off *Cipher
+	if v, err := off.Seal(ctx, "email", "voter@example.com"); err != nil || v != "voter@example.com" {
+		t.Errorf("nil cipher seal: %q, %v", v, err)
+	}
+	if _, err := off.Open(ctx, "email", sealed); !errors.Is(err, ErrNoCipher) {
+		t.Errorf("nil cipher open of a sealed value: %v, want ErrNoCipher", err)
+	}
+}
+
+func TestRekey(t *testing.T) {
+	ctx := context.Background()
+	k1, k2 := newKey(t), newKey(t)
+	fields := Fields{Sealed: []string{"email", "dob"}, Indexed: []string{"email"}}
+
+	old := keyring(t, "k1", map[string]string{"k1": k1})
+	doc := bson.M{"email": "Voter@Example.com", "dob": "2000-01-02"}
+	if err := old.SealDoc(ctx, doc, fields); err != nil {
+		t.Fatal(err)
+	}
+
+	// k2 becomes current; k1 stays in the keyring until rekey has run.
+	rotated := keyring(t, "k2", map[string]string{"k1": k1, "k2": k2})
+	if !rotated.NeedsRekey(doc, fields) {
+		t.Fatal("document under k1 does not need a rekey")
+	}
+	idx, err := rotated.BlindIndexes(ctx, "email", "voter@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(idx) != 2 || !strings.HasPrefix(idx[0], "k2:") || idx[1] != doc[IndexField("email")] {
+		t.Errorf("indexes %v do not find the k1 document (%v)", idx, doc[IndexField("email")])
+	}
+
+	// Rekey is open then seal again under the current key.
+	if err := rotated.OpenDoc(ctx, doc); err != nil {
+		t.Fatal(err)
+	}
+	if err := rotated.SealDoc(ctx, doc, fields); err != nil {
+		t.Fatal(err)
+	}
+	if rotated.NeedsRekey(doc, fields) {
+		t.Errorf("document still needs a rekey: %v", doc)
+	}
+	for _, f := range fields.Sealed {
+		if id := SealedKeyID(doc[f].(string)); id != "k2" {
+			t.Errorf("%s sealed under %q, want k2", f, id)
+		}
+	}
+
+	// Without k1 the old keyring's values no longer open.
+	sealed, _ := old.Seal(ctx, "email", "voter@example.com")
+	if _, err := keyring(t, "k2", map[string]string{"k2": k2}).Open(ctx, "email", sealed); err == nil {
+		t.Error("value under a removed key opened")
+	}
+}
+
+func TestBlindIndexDeterministic(t *testing.T) {
+	ctx := context.Background()
+	key := newKey(t)
+	c := keyring(t, "k1", map[string]string{"k1": key})
+	index := func(c *Cipher, field, value string) string {
+		t.Helper()
+		idx, err := c.BlindIndex(ctx, field, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return idx
+	}
+
+	a := index(c, "email", "voter@example.com")
+	if b := index(c, "email", "  Voter@Example.COM "); b != a {
+		t.Errorf("case and spacing change the index: %s vs %s", a, b)
+	}
+	if b := index(keyring(t, "k1", map[string]string{"k1": key}), "email", "voter@example.com"); b != a {
+		t.Error("the same key in another process gives another index")
+	}
+	if b := index(c, "roll_no", "voter@example.com"); b == a {
+		t.Error("the field name does not change the index")
+	}
+	if b := index(c, "email", "other@example.com"); b == a {
+		t.Error("different values share an index")
+	}
+	if b := index(keyring(t, "k1", map[string]string{"k1": newKey(t)}), "email", "voter@example.com"); b == a {
+		t.Error("different keys share an index")
+	}
+
+	var off *Cipher
+	if idx := index(off, "email", "voter@example.com"); idx != "" {
+		t.Errorf("nil cipher index %q, want empty", idx)
+	}
+	if idx, err := off.BlindIndexes(ctx, "email", "voter@example.com"); err != nil || idx != nil {
+		t.Errorf("nil cipher indexes %v, %v", idx, err)
+	}
+}
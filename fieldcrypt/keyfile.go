@@ -0,0 +1,117 @@
+﻿package fieldcrypt
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// FileProvider is a KeyProvider over a JSON keyring of 32-byte master keys:
+//
+//	{"current": "2026-10", "keys": {"2026-10": "<base64>", "2025-01": "<base64>"}}
+//
+// Rotating means adding a key, making it current and running evote-migrate rekey; the
+// old key can be removed once rekey reports nothing left under it.
+type FileProvider struct {
+	current string
+	ids     []string
+	keys    map[string][]byte
+}
+
+// LoadKeyFile reads a keyring. Key ids may not contain ':'.
+func LoadKeyFile(path string) (*FileProvider, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key file: %w", err)
+	}
+	var kf struct {
+		Current string            `json:"current"`
+		Keys    map[string]string `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &kf); err != nil {
+		return nil, fmt.Errorf("parse key file %s: %w", path, err)
+	}
+	p := &FileProvider{current: kf.Current, keys: map[string][]byte{}}
+	for id, b64 := range kf.Keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("key file %s: invalid key id %q", path, id)
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(b64))
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("key file %s: key %s must be 32 bytes of base64", path, id)
+		}
+		p.keys[id] = key
+		if id != kf.Current {
+			p.ids = append(p.ids, id)
+		}
+	}
+	if _, ok := p.keys[kf.Current]; !ok {
+		return nil, fmt.Errorf("key file %s: current key %q is not in keys", path, kf.Current)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(p.ids)))
+	p.ids = append([]string{kf.Current}, p.ids...)
+	return p, nil
+}
+
+func (p *FileProvider) Current() string  { return p.current }
+func (p *FileProvider) KeyIDs() []string { return p.ids }
+func (p *FileProvider) Kind() string     { return "file" }
+
+func (p *FileProvider) key(keyID string) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("master key %q is not in the key file", keyID)
+	}
+	return key, nil
+}
+
+func (p *FileProvider) Wrap(ctx context.Context, keyID string, dataKey []byte) ([]byte, error) {
+	key, err := p.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, dataKey, []byte("fieldcrypt data key")), nil
+}
+
+func (p *FileProvider) Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	key, err := p.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, fmt.Errorf("wrapped data key too short")
+	}
+	return aead.Open(nil, wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():], []byte("fieldcrypt data key"))
+}
+
+// MAC uses a subkey of the master key, so blind indexes never reuse the wrapping key.
+func (p *FileProvider) MAC(ctx context.Context, keyID string, data []byte) ([]byte, error) {
+	key, err := p.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	sub := hmac.New(sha256.New, key)
+	sub.Write([]byte("fieldcrypt blind index"))
+	h := hmac.New(sha256.New, sub.Sum(nil))
+	h.Write(data)
+	return h.Sum(nil), nil
+}
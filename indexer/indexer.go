@@ -0,0 +1,383 @@
+﻿package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Backend is the subset of chain.ChainBackend the indexer reads from.
+type Backend interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+}
+
+// ErrSkip tells the indexer a log is not ours (e.g. same event signature emitted by a
+// foreign contract). Skipped logs are not recorded and are never reverted.
+var ErrSkip = errors.New("indexer: log not relevant")
+
+// HandlerFunc receives a log together with its event id (see EventID).
+type HandlerFunc func(ctx context.Context, id string, lg types.Log) error
+
+// Handler applies one event type to the database. Apply must be idempotent: a crash
+// between Apply and recording the event replays it. Revert undoes Apply when the
+// block holding the log is reorged away.
+type Handler struct {
+	Name   string
+	Apply  HandlerFunc
+	Revert HandlerFunc
+}
+
+// Config describes one followed chain.
+type Config struct {
+	Chain         string // "L1" or "L2", also the checkpoint key
+	Backend       Backend
+	Store         Store
+	StartBlock    uint64        // first block scanned when there is no checkpoint yet
+	Confirmations uint64        // only blocks this deep are indexed
+	BatchSize     uint64        // blocks per eth_getLogs call, default 500
+	ReorgDepth    uint64        // deepest rewind searched for a common ancestor, default 64
+	PollInterval  time.Duration // default 5s
+}
+
+// Indexer follows the logs of the registered event topics from a persisted checkpoint.
+type Indexer struct {
+	cfg       Config
+	mu        sync.RWMutex
+	handlers  map[common.Hash]Handler
+	startOnce sync.Once
+}
+
+// New validates cfg and fills defaults. Register handlers before calling Start.
+func New(cfg Config) (*Indexer, error) {
+	if cfg.Backend == nil || cfg.Store == nil {
+		return nil, errors.New("indexer: backend and store are required")
+	}
+	if cfg.Chain == "" {
+		cfg.Chain = "L2"
+	}
+	if cfg.BatchSize == 0 {
+		cfg.BatchSize = 500
+	}
+	if cfg.ReorgDepth == 0 {
+		cfg.ReorgDepth = 64
+	}
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	return &Indexer{cfg: cfg, handlers: make(map[common.Hash]Handler)}, nil
+}
+
+// Handle registers the handler for an event signature (topic 0).
+func (ix *Indexer) Handle(topic common.Hash, h Handler) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.handlers[topic] = h
+}
+
+// StartBlock is the first block this chain was ever indexed from, as recorded with the
+// first checkpoint. Before that (or for checkpoints saved without it) it is Config.StartBlock.
+func (ix *Indexer) StartBlock(ctx context.Context) (uint64, error) {
+	cp, err := ix.cfg.Store.Checkpoint(ctx, ix.cfg.Chain)
+	if err != nil {
+		return 0, err
+	}
+	if cp != nil && cp.Start > 0 {
+		return cp.Start, nil
+	}
+	return ix.cfg.StartBlock, nil
+}
+
+// Start launches the follow loop.
+func (ix *Indexer) Start(ctx context.Context) {
+	ix.startOnce.Do(func() {
+		go ix.loop(ctx)
+		log.Printf("[INDEXER] %s indexer started (%d events, %d confirmations)", ix.cfg.Chain, len(ix.topics()), ix.cfg.Confirmations)
+	})
+}
+
+func (ix *Indexer) loop(ctx context.Context) {
+	ticker := time.NewTicker(ix.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := ix.sync(ctx); err != nil {
+			log.Printf("[INDEXER ERROR] %s: %v", ix.cfg.Chain, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (ix *Indexer) topics() []common.Hash {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	ts := make([]common.Hash, 0, len(ix.handlers))
+	for t := range ix.handlers {
+		ts = append(ts, t)
+	}
+	return ts
+}
+
+func (ix *Indexer) handler(topic common.Hash) (Handler, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	h, ok := ix.handlers[topic]
+	return h, ok
+}
+
+// sync brings the checkpoint up to head-confirmations, rewinding first if the
+// checkpointed block is no longer canonical.
+func (ix *Indexer) sync(ctx context.Context) error {
+	head, err := ix.cfg.Backend.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read head: %w", err)
+	}
+	if head < ix.cfg.Confirmations {
+		return nil
+	}
+	target := head - ix.cfg.Confirmations
+
+	cp, err := ix.cfg.Store.Checkpoint(ctx, ix.cfg.Chain)
+	if err != nil {
+		return fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	from := ix.cfg.StartBlock
+	if cp != nil {
+		hdr, err := ix.cfg.Backend.HeaderByNumber(ctx, new(big.Int).SetUint64(cp.Block))
+		if err != nil {
+			return fmt.Errorf("failed to read checkpoint block %d: %w", cp.Block, err)
+		}
+		if hdr.Hash().Hex() != cp.Hash {
+			ancestor, err := ix.rewind(ctx, cp)
+			if err != nil {
+				return err
+			}
+			from = ancestor + 1
+		} else {
+			from = cp.Block + 1
+		}
+	}
+
+	for from <= target {
+		to := from + ix.cfg.BatchSize - 1
+		if to > target {
+			to = target
+		}
+		if err := ix.processRange(ctx, from, to); err != nil {
+			return err
+		}
+		from = to + 1
+	}
+	return nil
+}
+
+// processRange applies every log in [from, to] and then moves the checkpoint to `to`.
+// On a handler error the checkpoint stays put and the range is retried next tick.
+func (ix *Indexer) processRange(ctx context.Context, from, to uint64) error {
+	topics := ix.topics()
+	if len(topics) == 0 {
+		return nil
+	}
+
+	// Read the range end first: if a reorg lands while we apply, the saved hash is stale
+	// and the next tick rewinds over it.
+	toHeader, err := ix.cfg.Backend.HeaderByNumber(ctx, new(big.Int).SetUint64(to))
+	if err != nil {
+		return fmt.Errorf("failed to read block %d: %w", to, err)
+	}
+
+	logs, err := ix.cfg.Backend.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Topics:    [][]common.Hash{topics},
+	})
+	if err != nil {
+		return fmt.Errorf("eth_getLogs %d-%d failed: %w", from, to, err)
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+
+	for _, lg := range logs {
+		if lg.Removed || len(lg.Topics) == 0 {
+			continue
+		}
+		if err := ix.apply(ctx, lg); err != nil {
+			return err
+		}
+	}
+
+	if err := ix.cfg.Store.saveCheckpoint(ctx, ix.cfg.Chain, ix.cfg.StartBlock, to, toHeader.Hash().Hex()); err != nil {
+		return fmt.Errorf("failed to save checkpoint %d: %w", to, err)
+	}
+	if len(logs) > 0 {
+		log.Printf("[INDEXER] %s indexed blocks %d-%d (%d logs)", ix.cfg.Chain, from, to, len(logs))
+	}
+	return nil
+}
+
+func (ix *Indexer) apply(ctx context.Context, lg types.Log) error {
+	h, ok := ix.handler(lg.Topics[0])
+	if !ok {
+		return nil
+	}
+	id := EventID(ix.cfg.Chain, lg)
+	done, err := ix.cfg.Store.applied(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to check event %s: %w", id, err)
+	}
+	if done {
+		return nil
+	}
+
+	if err := h.Apply(ctx, id, lg); err != nil {
+		if errors.Is(err, ErrSkip) {
+			return nil
+		}
+		return fmt.Errorf("%s handler failed at block %d tx %s: %w", h.Name, lg.BlockNumber, lg.TxHash.Hex(), err)
+	}
+
+	topics := make([]string, len(lg.Topics))
+	for i, t := range lg.Topics {
+		topics[i] = t.Hex()
+	}
+	return ix.cfg.Store.insertEvent(ctx, &Event{
+		ID:          id,
+		Chain:       ix.cfg.Chain,
+		Name:        h.Name,
+		Address:     lg.Address.Hex(),
+		BlockNumber: lg.BlockNumber,
+		BlockHash:   lg.BlockHash.Hex(),
+		TxHash:      lg.TxHash.Hex(),
+		LogIndex:    lg.Index,
+		Topics:      topics,
+		Data:        common.Bytes2Hex(lg.Data),
+		CreatedAt:   time.Now().UTC(),
+	})
+}
+
+// EventID is the stable key of a log on one chain. Handlers get it to tag the rows they
+// write, so Revert can find them again.
+func EventID(chain string, lg types.Log) string {
+	return fmt.Sprintf("%s:%s:%d", chain, lg.BlockHash.Hex(), lg.Index)
+}
+
+// rewind reverts every applied event above the common ancestor of the indexed and the
+// canonical chain, newest first, and moves the checkpoint back to it. Returns the
+// ancestor block number.
+func (ix *Indexer) rewind(ctx context.Context, cp *Checkpoint) (uint64, error) {
+	ancestor, err := ix.commonAncestor(ctx, cp)
+	if err != nil {
+		return 0, err
+	}
+	log.Printf("[INDEXER REORG] %s block %d changed, rewinding to %d", ix.cfg.Chain, cp.Block, ancestor)
+
+	evs, err := ix.cfg.Store.eventsAbove(ctx, ix.cfg.Chain, ancestor)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load events to revert: %w", err)
+	}
+	for i := range evs {
+		ev := &evs[i]
+		lg := ev.log()
+		if h, ok := ix.handler(lg.Topics[0]); ok && h.Revert != nil {
+			if err := h.Revert(ctx, ev.ID, lg); err != nil {
+				return 0, fmt.Errorf("%s revert failed for %s: %w", h.Name, ev.ID, err)
+			}
+		}
+		if err := ix.cfg.Store.deleteEvent(ctx, ev.ID); err != nil {
+			return 0, fmt.Errorf("failed to delete reverted event %s: %w", ev.ID, err)
+		}
+	}
+
+	hdr, err := ix.cfg.Backend.HeaderByNumber(ctx, new(big.Int).SetUint64(ancestor))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read ancestor block %d: %w", ancestor, err)
+	}
+	if err := ix.cfg.Store.rewindCheckpoint(ctx, ix.cfg.Chain, ancestor, hdr.Hash().Hex()); err != nil {
+		return 0, fmt.Errorf("failed to save checkpoint %d: %w", ancestor, err)
+	}
+	log.Printf("[INDEXER REORG] %s reverted %d events", ix.cfg.Chain, len(evs))
+	return ancestor, nil
+}
+
+// commonAncestor returns the highest block the indexer recorded a hash for (a past
+// checkpoint or the block of an applied event) that is still canonical. A block hash
+// commits to all of its parents, so everything at or below that block is untouched by
+// the reorg and every recorded block above it is not. When nothing within ReorgDepth
+// matches, the search floor is returned.
+func (ix *Indexer) commonAncestor(ctx context.Context, cp *Checkpoint) (uint64, error) {
+	floor := uint64(0)
+	if cp.Block > ix.cfg.ReorgDepth {
+		floor = cp.Block - ix.cfg.ReorgDepth
+	}
+
+	known := make(map[uint64]string)
+	for _, ref := range cp.History {
+		if ref.Block >= floor && ref.Block < cp.Block {
+			known[ref.Block] = ref.Hash
+		}
+	}
+	evs, err := ix.cfg.Store.eventsAbove(ctx, ix.cfg.Chain, floor)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load recorded blocks: %w", err)
+	}
+	for _, ev := range evs {
+		if ev.BlockNumber < cp.Block {
+			known[ev.BlockNumber] = ev.BlockHash
+		}
+	}
+
+	blocks := make([]uint64, 0, len(known))
+	for b := range known {
+		blocks = append(blocks, b)
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i] > blocks[j] })
+
+	for _, b := range blocks {
+		hdr, err := ix.cfg.Backend.HeaderByNumber(ctx, new(big.Int).SetUint64(b))
+		if err != nil {
+			return 0, fmt.Errorf("failed to read block %d: %w", b, err)
+		}
+		if hdr.Hash().Hex() == known[b] {
+			return b, nil
+		}
+	}
+	log.Printf("[INDEXER REORG] %s no canonical block recorded within %d blocks of %d", ix.cfg.Chain, ix.cfg.ReorgDepth, cp.Block)
+	return floor, nil
+}
+
+// log rebuilds the original log from a stored event so handlers can decode it on revert.
+func (ev *Event) log() types.Log {
+	topics := make([]common.Hash, len(ev.Topics))
+	for i, t := range ev.Topics {
+		topics[i] = common.HexToHash(t)
+	}
+	return types.Log{
+		Address:     common.HexToAddress(ev.Address),
+		Topics:      topics,
+		Data:        common.FromHex(ev.Data),
+		BlockNumber: ev.BlockNumber,
+		BlockHash:   common.HexToHash(ev.BlockHash),
+		TxHash:      common.HexToHash(ev.TxHash),
+		Index:       ev.LogIndex,
+		Removed:     true,
+	}
+}
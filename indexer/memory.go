@@ -0,0 +1,113 @@
+﻿package indexer
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// memoryStore keeps checkpoints and applied events in process memory.
+type memoryStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]Checkpoint
+	events      map[string]Event
+}
+
+// NewMemoryStore returns an empty in-memory store. Nothing is persisted, so a restart
+// indexes every chain again from its start block.
+func NewMemoryStore() Store {
+	return &memoryStore{checkpoints: map[string]Checkpoint{}, events: map[string]Event{}}
+}
+
+func (s *memoryStore) Checkpoint(ctx context.Context, chain string) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp, ok := s.checkpoints[chain]
+	if !ok {
+		return nil, nil
+	}
+	cp.History = append([]BlockRef(nil), cp.History...)
+	return &cp, nil
+}
+
+func (s *memoryStore) saveCheckpoint(ctx context.Context, chain string, start, block uint64, hash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp, ok := s.checkpoints[chain]
+	if !ok {
+		cp = Checkpoint{Chain: chain, Start: start}
+	}
+	cp.Block, cp.Hash, cp.UpdatedAt = block, hash, time.Now().UTC()
+	cp.History = append(append([]BlockRef(nil), cp.History...), BlockRef{Block: block, Hash: hash})
+	if len(cp.History) > checkpointHistory {
+		cp.History = cp.History[len(cp.History)-checkpointHistory:]
+	}
+	s.checkpoints[chain] = cp
+	return nil
+}
+
+func (s *memoryStore) rewindCheckpoint(ctx context.Context, chain string, block uint64, hash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp := s.checkpoints[chain]
+	cp.Chain = chain
+	var kept []BlockRef
+	for _, ref := range cp.History {
+		if ref.Block <= block {
+			kept = append(kept, ref)
+		}
+	}
+	cp.History = kept
+	cp.Block, cp.Hash, cp.UpdatedAt = block, hash, time.Now().UTC()
+	s.checkpoints[chain] = cp
+	return nil
+}
+
+func (s *memoryStore) applied(ctx context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.events[id]
+	return ok, nil
+}
+
+func (s *memoryStore) insertEvent(ctx context.Context, ev *Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.events[ev.ID]; !ok {
+		s.events[ev.ID] = *ev
+	}
+	return nil
+}
+
+func (s *memoryStore) eventsAbove(ctx context.Context, chain string, block uint64) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var evs []Event
+	for _, ev := range s.events {
+		if ev.Chain == chain && ev.BlockNumber > block {
+			evs = append(evs, ev)
+		}
+	}
+	sort.Slice(evs, func(i, j int) bool {
+		if evs[i].BlockNumber != evs[j].BlockNumber {
+			return evs[i].BlockNumber > evs[j].BlockNumber
+		}
+		return evs[i].LogIndex > evs[j].LogIndex
+	})
+	return evs, nil
+}
+
+func (s *memoryStore) deleteEvent(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.events, id)
+	return nil
+}
+
+func (s *memoryStore) ResetCheckpoint(ctx context.Context, chain string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.checkpoints, chain)
+	return nil
+}
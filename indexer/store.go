@@ -0,0 +1,173 @@
+﻿package indexer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Checkpoint is the last block whose logs have been fully applied, per chain.
+// The hash lets the next tick notice that the block was reorged away; History keeps
+// the previous checkpoints so a rewind can find where the chains actually diverged.
+type Checkpoint struct {
+	Chain     string     `bson:"_id" json:"chain"`
+	Block     uint64     `bson:"block" json:"block"`
+	Hash      string     `bson:"hash" json:"hash"`
+	Start     uint64     `bson:"start_block,omitempty" json:"start_block,omitempty"` // first block ever indexed
+	History   []BlockRef `bson:"history,omitempty" json:"-"`
+	UpdatedAt time.Time  `bson:"updated_at" json:"updated_at"`
+}
+
+// BlockRef is a block number with the hash it had when it was indexed.
+type BlockRef struct {
+	Block uint64 `bson:"block"`
+	Hash  string `bson:"hash"`
+}
+
+// checkpointHistory bounds Checkpoint.History.
+const checkpointHistory = 128
+
+// Event is one applied log. Keeping them is what makes a reorg reversible: every
+// event above the common ancestor is handed back to its handler's Revert.
+type Event struct {
+	ID          string    `bson:"_id" json:"id"` // chain:blockHash:logIndex
+	Chain       string    `bson:"chain" json:"chain"`
+	Name        string    `bson:"name" json:"name"`
+	Address     string    `bson:"address" json:"address"`
+	BlockNumber uint64    `bson:"block_number" json:"block_number"`
+	BlockHash   string    `bson:"block_hash" json:"block_hash"`
+	TxHash      string    `bson:"tx_hash" json:"tx_hash"`
+	LogIndex    uint      `bson:"log_index" json:"log_index"`
+	Topics      []string  `bson:"topics" json:"topics"`
+	Data        string    `bson:"data" json:"-"`
+	CreatedAt   time.Time `bson:"created_at" json:"created_at"`
+}
+
+// Store persists checkpoints and applied events. NewMongoStore keeps them in MongoDB;
+// NewMemoryStore keeps them in process memory for tests and the STORAGE_BACKEND=memory
+// demo mode.
+type Store interface {
+	// Checkpoint returns the stored checkpoint for a chain, or nil if the chain was never indexed.
+	Checkpoint(ctx context.Context, chain string) (*Checkpoint, error)
+	// ResetCheckpoint forgets a chain's checkpoint, e.g. for a simulated chain that restarted from genesis.
+	ResetCheckpoint(ctx context.Context, chain string) error
+
+	saveCheckpoint(ctx context.Context, chain string, start, block uint64, hash string) error
+	// rewindCheckpoint moves the checkpoint back to block and drops the history above it,
+	// which belonged to the abandoned fork.
+	rewindCheckpoint(ctx context.Context, chain string, block uint64, hash string) error
+	applied(ctx context.Context, id string) (bool, error)
+	// insertEvent stores ev; an event that is already stored is left as it is.
+	insertEvent(ctx context.Context, ev *Event) error
+	// eventsAbove returns applied events newer than block, newest first (the order they must be undone in).
+	eventsAbove(ctx context.Context, chain string, block uint64) ([]Event, error)
+	deleteEvent(ctx context.Context, id string) error
+}
+
+// mongoStore keeps checkpoints in indexer_checkpoints and applied events in chain_events.
+type mongoStore struct {
+	checkpoints *mongo.Collection
+	events      *mongo.Collection
+}
+
+// NewMongoStore binds the indexer collections and creates their indexes.
+func NewMongoStore(client *mongo.Client, dbName string) Store {
+	db := client.Database(dbName)
+	s := &mongoStore{
+		checkpoints: db.Collection("indexer_checkpoints"),
+		events:      db.Collection("chain_events"),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, _ = s.events.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "chain", Value: 1}, {Key: "block_number", Value: -1}}},
+		{Keys: bson.D{{Key: "tx_hash", Value: 1}}},
+	})
+
+	fmt.Println("[OK] Initialized chain_events collection with indexes")
+	return s
+}
+
+func (s *mongoStore) Checkpoint(ctx context.Context, chain string) (*Checkpoint, error) {
+	var cp Checkpoint
+	err := s.checkpoints.FindOne(ctx, bson.M{"_id": chain}).Decode(&cp)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+func (s *mongoStore) saveCheckpoint(ctx context.Context, chain string, start, block uint64, hash string) error {
+	_, err := s.checkpoints.UpdateOne(ctx,
+		bson.M{"_id": chain},
+		bson.M{
+			"$set":         bson.M{"block": block, "hash": hash, "updated_at": time.Now().UTC()},
+			"$setOnInsert": bson.M{"start_block": start},
+			"$push":        bson.M{"history": bson.M{"$each": []BlockRef{{Block: block, Hash: hash}}, "$slice": -checkpointHistory}},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+func (s *mongoStore) rewindCheckpoint(ctx context.Context, chain string, block uint64, hash string) error {
+	_, err := s.checkpoints.UpdateOne(ctx,
+		bson.M{"_id": chain},
+		bson.M{"$pull": bson.M{"history": bson.M{"block": bson.M{"$gt": block}}}},
+	)
+	if err != nil {
+		return err
+	}
+	_, err = s.checkpoints.UpdateOne(ctx,
+		bson.M{"_id": chain},
+		bson.M{"$set": bson.M{"block": block, "hash": hash, "updated_at": time.Now().UTC()}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+func (s *mongoStore) applied(ctx context.Context, id string) (bool, error) {
+	n, err := s.events.CountDocuments(ctx, bson.M{"_id": id}, options.Count().SetLimit(1))
+	return n > 0, err
+}
+
+func (s *mongoStore) insertEvent(ctx context.Context, ev *Event) error {
+	_, err := s.events.InsertOne(ctx, ev)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *mongoStore) eventsAbove(ctx context.Context, chain string, block uint64) ([]Event, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "block_number", Value: -1}, {Key: "log_index", Value: -1}})
+	cursor, err := s.events.Find(ctx, bson.M{"chain": chain, "block_number": bson.M{"$gt": block}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var evs []Event
+	if err := cursor.All(ctx, &evs); err != nil {
+		return nil, err
+	}
+	return evs, nil
+}
+
+func (s *mongoStore) deleteEvent(ctx context.Context, id string) error {
+	_, err := s.events.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (s *mongoStore) ResetCheckpoint(ctx context.Context, chain string) error {
+	_, err := s.checkpoints.DeleteOne(ctx, bson.M{"_id": chain})
+	return err
+}
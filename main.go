@@ -11,15 +11,26 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"MAJOR-PROJECT/bindings"
+	"MAJOR-PROJECT/chain"
 	"MAJOR-PROJECT/controllers"
+	"MAJOR-PROJECT/deploy"
+	"MAJOR-PROJECT/fieldcrypt"
+	"MAJOR-PROJECT/indexer"
+	"MAJOR-PROJECT/migrate"
+	"MAJOR-PROJECT/repository"
 	"MAJOR-PROJECT/routes"
+	"MAJOR-PROJECT/signer"
+	"MAJOR-PROJECT/txmanager"
 	"MAJOR-PROJECT/util"
 
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/joho/godotenv"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -46,69 +57,24 @@ func main() {
 	}
 
 	// -----------------------------------------------------
-	// 2) DEPLOY CONTRACTS (IF NOT EXISTS)
+	// 2) CHAIN BACKENDS + CONTRACTS (IF NOT EXISTS)
 	// -----------------------------------------------------
-	privHex := os.Getenv("EVM_PRIVATE_KEY")
-	if privHex == "" {
-		log.Fatal("[ERROR] EVM_PRIVATE_KEY not set in .env. Execution aborted for security.")
-	}
-
-	// Deploy L2 Factory
-	l2FactoryAddr := os.Getenv("L2_FACTORY_CONTRACT_ADDRESS")
-	if l2FactoryAddr != "" {
-		log.Printf("[OK] Existing L2 Factory found in .env: %s", l2FactoryAddr)
-		log.Println("[SKIPPED] Skipping L2 Factory deployment.")
+	var chains chainSetup
+	if chain.Mode() == chain.ModeSimulated {
+		chains = setupSimulatedChains()
 	} else {
-		log.Println("[INFO] Deploying L2 Election Factory...")
-		l2Rpc := os.Getenv("L2_NODE_URL")
-		if l2Rpc == "" {
-			log.Fatal("[ERROR] L2_NODE_URL must be set")
-		}
-		l2ChainIDStr := os.Getenv("L2_CHAIN_ID")
-		l2ChainIDVal, _ := strconv.ParseInt(l2ChainIDStr, 10, 64)
-		if l2ChainIDVal == 0 {
-			l2ChainIDVal = 80002
-		} // Default Amoy
-
-		deployCtx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
-		addr, tx, err := util.Deploy(deployCtx, l2Rpc, "build/ElectionFact.abi", "build/ElectionFact.bin", privHex, big.NewInt(l2ChainIDVal))
-		cancel()
-		if err != nil {
-			log.Fatalf("[ERROR] L2 Factory deployment failed: %v", err)
-		}
-		log.Printf("[L2 DEPLOYED] Factory contract at: %s | tx: %s", addr.Hex(), tx.Hash().Hex())
-		os.Setenv("L2_FACTORY_CONTRACT_ADDRESS", addr.Hex())
+		chains = setupRPCChains()
 	}
-
-	// Deploy L1 Archive
-	l1ArchiveAddr := os.Getenv("L1_ARCHIVE_CONTRACT_ADDRESS")
-	if l1ArchiveAddr != "" {
-		log.Printf("[OK] Existing L1 Archive found in .env: %s", l1ArchiveAddr)
-		log.Println("[SKIPPED] Skipping L1 Archive deployment.")
-	} else {
-		log.Println("[INFO] Deploying L1 Election Archive...")
-		l1Rpc := os.Getenv("L1_NODE_URL")
-		if l1Rpc == "" {
-			log.Fatal("[ERROR] L1_NODE_URL must be set")
-		}
-		l1ChainIDStr := os.Getenv("L1_CHAIN_ID")
-		l1ChainIDVal, _ := strconv.ParseInt(l1ChainIDStr, 10, 64)
-		if l1ChainIDVal == 0 {
-			l1ChainIDVal = 11155111
-		} // Default Sepolia
-
-		deployCtx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
-		addr, tx, err := util.Deploy(deployCtx, l1Rpc, "build/Ethereum_Contract_ElectionArchive_sol_ElectionArchive.abi", "build/Ethereum_Contract_ElectionArchive_sol_ElectionArchive.bin", privHex, big.NewInt(l1ChainIDVal))
-		cancel()
-		if err != nil {
-			log.Fatalf("[ERROR] L1 Archive deployment failed: %v", err)
-		}
-		log.Printf("[L1 DEPLOYED] Archive contract at: %s | tx: %s", addr.Hex(), tx.Hash().Hex())
-		os.Setenv("L1_ARCHIVE_CONTRACT_ADDRESS", addr.Hex())
+	controllers.InitChainBackends(chains.l2, chains.l1)
+	if err := controllers.InitCompanyIDSecret(chain.Mode() == chain.ModeSimulated); err != nil {
+		log.Fatalf("[ERROR] %v. Execution aborted for security.", err)
 	}
 
 	// Validate required env variables
-	requiredEnvVars := []string{"MONGODB_URI", "EMAIL", "PASSWORD"}
+	requiredEnvVars := []string{"EMAIL", "PASSWORD"}
+	if repository.Mode() == repository.ModeMongo {
+		requiredEnvVars = append(requiredEnvVars, "MONGODB_URI")
+	}
 	for _, v := range requiredEnvVars {
 		if os.Getenv(v) == "" {
 			log.Printf("[WARN] Warning: Required environment variable %s is not set", v)
@@ -116,50 +82,23 @@ func main() {
 	}
 
 	// -----------------------------------------------------
-	// 3) MONGO SETUP
+	// 3) STORAGE
 	// -----------------------------------------------------
-	mongoURI := os.Getenv("MONGODB_URI")
-	if mongoURI == "" {
-		mongoURI = "mongodb://localhost:27017"
-	}
-
-	connectCtx, connectCancel := context.WithTimeout(context.Background(), 60*time.Second)
-	defer connectCancel()
-
-	clientOpts, tlsErr := buildMongoClientOptions(mongoURI)
-	if tlsErr != nil {
-		log.Fatalf("[ERROR] MongoDB TLS config error: %v", tlsErr)
-	}
-	client, err := mongo.Connect(connectCtx, clientOpts)
-	if err != nil {
-		log.Fatalf("[ERROR] MongoDB connect error: %v", err)
-	}
-
-	if err := client.Ping(connectCtx, nil); err != nil {
-		_ = client.Disconnect(context.Background())
-		log.Fatalf("[ERROR] MongoDB ping error: %v", err)
-	}
-
-	fmt.Println("[OK] Connected to MongoDB successfully")
-
-	dbName := os.Getenv("DB_NAME")
-	if dbName == "" {
-		dbName = "voting_system"
+	var client *mongo.Client // nil with STORAGE_BACKEND=memory
+	var h *controllers.Handlers
+	if repository.Mode() == repository.ModeMemory {
+		h = setupMemoryStorage(chains)
+	} else {
+		client, h = setupMongoStorage(chains)
 	}
 
-	controllers.InitCompanyCollection(client, dbName)
-	controllers.InitVoterCollection(client, dbName)
-	controllers.InitCandidateCollection(client, dbName)
-	controllers.InitOTPCollection(client, dbName)
-	controllers.InitAuditCollection(client, dbName)
-	controllers.InitMetadataCollection(client, dbName)
-	controllers.InitStudentCollection(client, dbName)
-	fmt.Println("[OK] Initialized database collections")
+	// Purge erased voters' references from elections once they pass retention
+	controllers.InitRetentionSweep()
 
 	// -----------------------------------------------------
 	// 4) START SERVER
 	// -----------------------------------------------------
-	router := routes.SetupRoutes()
+	router := routes.SetupRoutes(h)
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "3000"
@@ -199,13 +138,261 @@ func main() {
 	}
 
 	// Disconnect from MongoDB
-	disconnectCtx, disconnectCancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer disconnectCancel()
+	if client != nil {
+		disconnectCtx, disconnectCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer disconnectCancel()
+
+		if err := client.Disconnect(disconnectCtx); err != nil {
+			log.Fatalf("[ERROR] MongoDB disconnect error: %v", err)
+		}
+		log.Println("[OK] MongoDB disconnected")
+	}
+
+	chains.l2.Close()
+	if chains.l1 != nil {
+		chains.l1.Close()
+	}
+	log.Println("[OK] Chain backends closed, exiting")
+}
+
+// chainSetup is what the chain bootstrap hands to the controllers. l1 and l1Signer are
+// nil when L1 anchoring is not configured.
+type chainSetup struct {
+	l2, l1             chain.ChainBackend
+	l2Signer, l1Signer signer.Signer
+}
 
-	if err := client.Disconnect(disconnectCtx); err != nil {
-		log.Fatalf("[ERROR] MongoDB disconnect error: %v", err)
+// setupRPCChains loads the per-layer signers, connects to both nodes and checks that the
+// configured L2 factory / L1 archive run the expected code.
+func setupRPCChains() chainSetup {
+	l2Signer, err := signer.FromEnv("L2")
+	if err != nil {
+		log.Fatalf("[ERROR] %v. Execution aborted for security.", err)
 	}
-	log.Println("[OK] MongoDB disconnected, exiting")
+	l1Configured := len(nodeURLs("L1")) > 0
+	var l1Signer signer.Signer
+	if l1Configured {
+		if l1Signer, err = signer.FromEnv("L1"); err != nil {
+			log.Fatalf("[ERROR] %v. Execution aborted for security.", err)
+		}
+	}
+
+	// Each layer is a pool over <LAYER>_NODE_URL plus the fallbacks in <LAYER>_NODE_URLS
+	dialCtx, dialCancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer dialCancel()
+	poolCfg := chainPoolConfig()
+	l2Chain, err := chain.DialPool(dialCtx, "L2", nodeURLs("L2"), poolCfg)
+	if err != nil {
+		log.Fatalf("[ERROR] L2 connection failed: %v", err)
+	}
+
+	// L1 is optional at runtime: without it elections still run, only anchoring is disabled
+	var l1Chain chain.ChainBackend
+	if l1Configured {
+		if l1Pool, err := chain.DialPool(dialCtx, "L1", nodeURLs("L1"), poolCfg); err != nil {
+			log.Printf("[WARN] L1 connection failed, anchoring disabled: %v", err)
+		} else {
+			l1Chain = l1Pool
+		}
+	}
+
+	// Contracts are deployed with cmd/evote-deploy; refuse to run against anything else
+	verifyContract(deploy.L2, l2Chain)
+	if l1Chain != nil {
+		verifyContract(deploy.L1, l1Chain)
+	}
+	return chainSetup{l2: l2Chain, l1: l1Chain, l2Signer: l2Signer, l1Signer: l1Signer}
+}
+
+// nodeURLs returns <LAYER>_NODE_URL followed by the comma-separated <LAYER>_NODE_URLS,
+// without duplicates. The order is the failover priority.
+func nodeURLs(layer string) []string {
+	var urls []string
+	seen := map[string]bool{}
+	for _, u := range append([]string{os.Getenv(layer + "_NODE_URL")}, strings.Split(os.Getenv(layer+"_NODE_URLS"), ",")...) {
+		u = strings.Trim(strings.TrimSpace(u), `"'`)
+		if u != "" && !seen[u] {
+			seen[u] = true
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// chainPoolConfig reads CHAIN_PROBE_INTERVAL (default 15s) and CHAIN_MAX_BLOCK_LAG (default 5).
+func chainPoolConfig() chain.PoolConfig {
+	var cfg chain.PoolConfig
+	if d, err := time.ParseDuration(strings.TrimSpace(os.Getenv("CHAIN_PROBE_INTERVAL"))); err == nil && d > 0 {
+		cfg.ProbeInterval = d
+	}
+	if v, err := strconv.ParseUint(strings.TrimSpace(os.Getenv("CHAIN_MAX_BLOCK_LAG")), 10, 64); err == nil {
+		cfg.MaxBlockLag = v
+	}
+	return cfg
+}
+
+// verifyContract stops the server unless the network's configured contract address
+// holds exactly the code of the embedded artifact.
+func verifyContract(n deploy.Network, backend chain.ChainBackend) {
+	addr, ok := n.ConfiguredAddress()
+	if !ok {
+		log.Fatalf("[ERROR] %s is not set. Deploy with: go run ./cmd/evote-deploy -network %s deploy", n.AddressEnv, n.Name)
+	}
+	art, err := deploy.LoadArtifact(n.Contract)
+	if err != nil {
+		log.Fatalf("[ERROR] %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	hash, err := deploy.VerifyCode(ctx, backend, addr, art)
+	if err != nil {
+		log.Fatalf("[ERROR] %s contract check failed: %v. Redeploy with: go run ./cmd/evote-deploy -network %s deploy", n.Layer, err, n.Name)
+	}
+	log.Printf("[OK] %s %s at %s matches v%s (code %s)", n.Layer, n.Contract, addr.Hex(), deploy.Version(), hash.Hex())
+}
+
+// setupSimulatedChains starts in-memory L2 and L1 chains (CHAIN_BACKEND=simulated) and
+// deploys fresh contracts on both. The chains start from genesis on every run, so a new
+// operator key is generated too (used on both layers); addresses and keys in .env are
+// ignored, never rewritten.
+func setupSimulatedChains() chainSetup {
+	log.Println("[SIM] CHAIN_BACKEND=simulated: running L2 and L1 in-process, no RPC needed")
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		log.Fatalf("[ERROR] Failed to generate simulated operator key: %v", err)
+	}
+	operatorSigner := signer.NewKeySigner(key)
+	operator := operatorSigner.Address()
+	log.Printf("[SIM] Operator account: %s", operator.Hex())
+
+	l2Chain := chain.NewSimulated("L2", simChainID("L2_CHAIN_ID", 80002), operator)
+	l1Chain := chain.NewSimulated("L1", simChainID("L1_CHAIN_ID", 11155111), operator)
+
+	deployCtx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	factoryAddr, _, err := util.DeployMeta(deployCtx, l2Chain, bindings.ElectionFactMetaData, operatorSigner)
+	if err != nil {
+		log.Fatalf("[ERROR] Simulated L2 Factory deployment failed: %v", err)
+	}
+	os.Setenv("L2_FACTORY_CONTRACT_ADDRESS", factoryAddr.Hex())
+	log.Printf("[SIM DEPLOYED] L2 Factory contract at: %s", factoryAddr.Hex())
+
+	archiveAddr, _, err := util.DeployMeta(deployCtx, l1Chain, bindings.BindingsMetaData, operatorSigner)
+	if err != nil {
+		log.Fatalf("[ERROR] Simulated L1 Archive deployment failed: %v", err)
+	}
+	os.Setenv("L1_ARCHIVE_CONTRACT_ADDRESS", archiveAddr.Hex())
+	log.Printf("[SIM DEPLOYED] L1 Archive contract at: %s", archiveAddr.Hex())
+
+	return chainSetup{l2: l2Chain, l1: l1Chain, l2Signer: operatorSigner, l1Signer: operatorSigner}
+}
+
+// simChainID reads the chain id a simulated chain should report, so signatures match the real network.
+func simChainID(envVar string, def int64) *big.Int {
+	v, _ := strconv.ParseInt(strings.TrimSpace(os.Getenv(envVar)), 10, 64)
+	if v == 0 {
+		v = def
+	}
+	return big.NewInt(v)
+}
+
+// setupMongoStorage connects to MONGODB_URI, builds the repositories, transaction store
+// and indexer store over DB_NAME and starts the workers on them.
+func setupMongoStorage(chains chainSetup) (*mongo.Client, *controllers.Handlers) {
+	mongoURI := os.Getenv("MONGODB_URI")
+	if mongoURI == "" {
+		mongoURI = "mongodb://localhost:27017"
+	}
+
+	connectCtx, connectCancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer connectCancel()
+
+	clientOpts, tlsErr := buildMongoClientOptions(mongoURI)
+	if tlsErr != nil {
+		log.Fatalf("[ERROR] MongoDB TLS config error: %v", tlsErr)
+	}
+	client, err := mongo.Connect(connectCtx, clientOpts)
+	if err != nil {
+		log.Fatalf("[ERROR] MongoDB connect error: %v", err)
+	}
+
+	if err := client.Ping(connectCtx, nil); err != nil {
+		_ = client.Disconnect(context.Background())
+		log.Fatalf("[ERROR] MongoDB ping error: %v", err)
+	}
+
+	fmt.Println("[OK] Connected to MongoDB successfully")
+
+	dbName := os.Getenv("DB_NAME")
+	if dbName == "" {
+		dbName = "voting_system"
+	}
+
+	// The server never migrates data itself; evote-migrate does
+	if _, pending, err := migrate.Status(connectCtx, client.Database(dbName)); err != nil {
+		log.Printf("[WARN] Could not read migration status: %v", err)
+	} else if len(pending) > 0 {
+		log.Printf("[WARN] %d data migration(s) pending (first: %d %s); run evote-migrate up", len(pending), pending[0].Version, pending[0].Name)
+	}
+
+	// Voter and student PII is sealed at rest when FIELD_KEY_FILE is set
+	cipher, err := fieldcrypt.FromEnv()
+	if err != nil {
+		log.Fatalf("[ERROR] Field encryption setup failed: %v", err)
+	}
+	if cipher == nil {
+		log.Println("[WARN] FIELD_KEY_FILE not set: voter and student PII is stored in clear text")
+	} else {
+		fmt.Printf("[OK] Field encryption on (%s)\n", cipher.Describe())
+	}
+
+	h := controllers.InitHandlers(repository.NewMongo(client, dbName, cipher))
+	fmt.Println("[OK] Initialized database collections")
+
+	startWorkers(chains, txmanager.NewMongoStore(client, dbName), indexer.NewMongoStore(client, dbName))
+	return client, h
+}
+
+// setupMemoryStorage keeps every repository, transaction record and indexer checkpoint in
+// process (STORAGE_BACKEND=memory), for demos and tests without a database. Everything is
+// lost on restart; pair it with CHAIN_BACKEND=simulated so the chains reset with it.
+func setupMemoryStorage(chains chainSetup) *controllers.Handlers {
+	log.Println("[MEMORY] STORAGE_BACKEND=memory: storing all data, transactions and indexer checkpoints in process, no MongoDB needed")
+	if chain.Mode() != chain.ModeSimulated {
+		log.Println("[WARN] STORAGE_BACKEND=memory with RPC chains: nonces and checkpoints restart from scratch on every run")
+	}
+	h := controllers.InitHandlers(repository.NewMemory())
+	startWorkers(chains, txmanager.NewMemoryStore(), indexer.NewMemoryStore())
+	return h
+}
+
+// startWorkers starts the background workers over the chosen stores: the vote batcher,
+// the transaction managers, the chain indexers, the reconciler, the anchor worker and
+// roster imports.
+func startWorkers(chains chainSetup, txs txmanager.Store, idx indexer.Store) {
+	// Ballot queue for batched votes (VOTE_BATCH_SIZE / VOTE_BATCH_WINDOW)
+	controllers.InitVoteBatcher()
+
+	// Persistent nonce tracking, rebroadcast and recovery for every outbound tx
+	if err := controllers.InitTxManagers(txs, chains.l2Signer, chains.l1Signer); err != nil {
+		log.Fatalf("[ERROR] Transaction manager setup failed: %v", err)
+	}
+
+	// Follow contract events from a persisted checkpoint to keep storage in sync with the chain
+	if err := controllers.InitIndexers(idx); err != nil {
+		log.Fatalf("[ERROR] Chain indexer setup failed: %v", err)
+	}
+
+	// Periodically compare storage with the contracts and repair candidate statuses
+	controllers.InitReconciler()
+
+	// Persisted L1 anchoring jobs with retries and confirmation tracking
+	controllers.InitAnchorJobs()
+
+	// CSV/XLSX roster imports: previews, background apply and per-row reports
+	controllers.StartRosterImports()
 }
 
 // buildMongoClientOptions adds TLS config for AWS DocumentDB when DOCDB_TLS_CA_FILE is set.
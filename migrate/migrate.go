@@ -0,0 +1,111 @@
+﻿// Package migrate runs versioned data migrations against the MongoDB database and
+// records each applied version in the schema_migrations collection, so every migration
+// runs once per database. Migrations are applied by cmd/evote-migrate; the server only
+// reports pending ones.
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection holds one document per applied migration.
+const Collection = "schema_migrations"
+
+// Migration is one versioned change to the stored data. Up must be idempotent: a crash
+// between Up and recording the version replays it on the next run.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, db *mongo.Database) error
+}
+
+// Applied records a migration that has run.
+type Applied struct {
+	Version   int       `bson:"_id" json:"version"`
+	Name      string    `bson:"name" json:"name"`
+	AppliedAt time.Time `bson:"applied_at" json:"applied_at"`
+	Duration  string    `bson:"duration" json:"duration"`
+}
+
+// applied returns the recorded migrations by version.
+func applied(ctx context.Context, db *mongo.Database) (map[int]Applied, error) {
+	cursor, err := db.Collection(Collection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var rows []Applied
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	out := make(map[int]Applied, len(rows))
+	for _, a := range rows {
+		out[a.Version] = a
+	}
+	return out, nil
+}
+
+// Status returns the applied migrations and the ones still pending, both in version order.
+func Status(ctx context.Context, db *mongo.Database) ([]Applied, []Migration, error) {
+	done, err := applied(ctx, db)
+	if err != nil {
+		return nil, nil, err
+	}
+	var have []Applied
+	for _, a := range done {
+		have = append(have, a)
+	}
+	sort.Slice(have, func(i, j int) bool { return have[i].Version < have[j].Version })
+	var pending []Migration
+	for _, m := range Migrations {
+		if _, ok := done[m.Version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+	return have, pending, nil
+}
+
+// Up applies the pending migrations in version order, up to and including target
+// (0 means all). It stops at the first failure; everything before it stays recorded.
+func Up(ctx context.Context, db *mongo.Database, target int) ([]Applied, error) {
+	_, pending, err := Status(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	var out []Applied
+	for _, m := range pending {
+		if target > 0 && m.Version > target {
+			break
+		}
+		log.Printf("[MIGRATE] Applying %d %s", m.Version, m.Name)
+		start := time.Now()
+		if err := m.Up(ctx, db); err != nil {
+			return out, fmt.Errorf("migration %d (%s): %w", m.Version, m.Name, err)
+		}
+		a := Applied{Version: m.Version, Name: m.Name, AppliedAt: time.Now().UTC(), Duration: time.Since(start).Round(time.Millisecond).String()}
+		_, err := db.Collection(Collection).ReplaceOne(ctx, bson.M{"_id": a.Version}, a, options.Replace().SetUpsert(true))
+		if err != nil {
+			return out, fmt.Errorf("record migration %d: %w", m.Version, err)
+		}
+		log.Printf("[MIGRATE] Applied %d %s in %s", m.Version, m.Name, a.Duration)
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+func init() {
+	sort.Slice(Migrations, func(i, j int) bool { return Migrations[i].Version < Migrations[j].Version })
+	for i := 1; i < len(Migrations); i++ {
+		if Migrations[i].Version == Migrations[i-1].Version {
+			panic(fmt.Sprintf("migrate: duplicate migration version %d", Migrations[i].Version))
+		}
+	}
+}
@@ -0,0 +1,249 @@
+﻿package migrate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"MAJOR-PROJECT/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Migrations is every known migration. Versions are never reused or renumbered.
+var Migrations = []Migration{
+	{Version: 1, Name: "canonical_election_addresses", Up: canonicalElectionAddresses},
+}
+
+// addressFields are the plain string fields holding an election address. Anchor jobs and
+// consistency reports are keyed by the checksummed address already.
+var addressFields = []struct{ collection, field string }{
+	{"election_metadata", "election_address"},
+	{"candidates", "electionAddress"},
+	{"audit_logs", "election_address"},
+	{"otps", "election_address"},
+	{"ballots", "election_address"},
+	{"transactions", "election"},
+}
+
+// canonicalElectionAddresses rewrites every stored election address to its checksummed
+// form (repository.CanonicalAddress), folds the legacy voter link shapes into
+// registrations, and creates the unique metadata index the exact-match lookups rely on.
+func canonicalElectionAddresses(ctx context.Context, db *mongo.Database) error {
+	if err := checkMetadataDuplicates(ctx, db.Collection("election_metadata")); err != nil {
+		return err
+	}
+	for _, f := range addressFields {
+		n, err := rewriteAddressField(ctx, db.Collection(f.collection), f.field)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", f.collection, f.field, err)
+		}
+		log.Printf("[MIGRATE] %s.%s: %d documents rewritten", f.collection, f.field, n)
+	}
+	n, err := rewriteRegistrations(ctx, db.Collection("voters"))
+	if err != nil {
+		return fmt.Errorf("voters.registrations: %w", err)
+	}
+	log.Printf("[MIGRATE] voters.registrations: %d documents rewritten", n)
+
+	_, err = db.Collection("election_metadata").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.M{"election_address": 1},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// checkMetadataDuplicates refuses to run when two metadata documents differ only in the
+// case of their address: which one is right is an operator decision, not a rewrite.
+func checkMetadataDuplicates(ctx context.Context, c *mongo.Collection) error {
+	cursor, err := c.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"election_address": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	ids := map[string][]string{}
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+		addr, _ := doc["election_address"].(string)
+		canon := repository.CanonicalAddress(addr)
+		ids[canon] = append(ids[canon], fmt.Sprint(doc["_id"]))
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	var dups []string
+	for addr, docs := range ids {
+		if len(docs) > 1 {
+			dups = append(dups, fmt.Sprintf("%s (%s)", addr, strings.Join(docs, ", ")))
+		}
+	}
+	if len(dups) > 0 {
+		sort.Strings(dups)
+		return fmt.Errorf("election_metadata has several documents for the same election; delete all but one and rerun: %s", strings.Join(dups, "; "))
+	}
+	return nil
+}
+
+// rewriteAddressField sets field to its canonical form wherever it differs.
+func rewriteAddressField(ctx context.Context, c *mongo.Collection, field string) (int, error) {
+	cursor, err := c.Find(ctx, bson.M{field: bson.M{"$type": "string"}}, options.Find().SetProjection(bson.M{field: 1}))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+	n := 0
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return n, err
+		}
+		old, _ := doc[field].(string)
+		canon := repository.CanonicalAddress(old)
+		if canon == old {
+			continue
+		}
+		if _, err := c.UpdateOne(ctx, bson.M{"_id": doc["_id"]}, bson.M{"$set": bson.M{field: canon}}); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, cursor.Err()
+}
+
+// rewriteRegistrations converts every way a voter has been linked to an election (plain
+// address strings in registrations, registrations.electionAddress, top-level
+// election_address / electionAddress) into registrations entries with a canonical
+// election_address, one per election. Links without a status become "Pending".
+func rewriteRegistrations(ctx context.Context, c *mongo.Collection) (int, error) {
+	filter := bson.M{"$or": []bson.M{
+		{"registrations": bson.M{"$exists": true}},
+		{"election_address": bson.M{"$exists": true}},
+		{"electionAddress": bson.M{"$exists": true}},
+	}}
+	proj := bson.M{"registrations": 1, "election_address": 1, "electionAddress": 1}
+	cursor, err := c.Find(ctx, filter, options.Find().SetProjection(proj))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+	n := 0
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return n, err
+		}
+		regs, changed := canonicalRegistrations(doc)
+		if !changed {
+			continue
+		}
+		update := bson.M{
+			"$set":   bson.M{"registrations": regs},
+			"$unset": bson.M{"election_address": "", "electionAddress": ""},
+		}
+		if _, err := c.UpdateOne(ctx, bson.M{"_id": doc["_id"]}, update); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, cursor.Err()
+}
+
+// canonicalRegistrations builds the registrations array for one voter document and
+// reports whether it differs from what is stored.
+func canonicalRegistrations(doc bson.M) ([]bson.M, bool) {
+	var linkedAt time.Time
+	if oid, ok := doc["_id"].(primitive.ObjectID); ok {
+		linkedAt = oid.Timestamp().UTC()
+	}
+	var out []bson.M
+	index := map[string]int{}
+	changed := false
+	add := func(reg bson.M, old string) {
+		canon := repository.CanonicalAddress(old)
+		if canon != old || canon == "" {
+			changed = true
+		}
+		if canon == "" {
+			return // links nothing
+		}
+		reg["election_address"] = canon
+		if i, ok := index[canon]; ok {
+			// Keep one entry per election; a verified link wins
+			changed = true
+			if reg["status"] == "Verified" {
+				out[i]["status"] = "Verified"
+			}
+			return
+		}
+		index[canon] = len(out)
+		out = append(out, reg)
+	}
+
+	entries, _ := doc["registrations"].(primitive.A)
+	if v := doc["registrations"]; v != nil && entries == nil {
+		changed = true // not an array
+	}
+	for _, e := range entries {
+		switch v := e.(type) {
+		case string:
+			changed = true
+			add(bson.M{"status": "Pending", "registered_at": linkedAt}, v)
+		default:
+			reg := asMap(v)
+			if reg == nil {
+				changed = true
+				continue
+			}
+			addr, _ := reg["election_address"].(string)
+			if legacy, ok := reg["electionAddress"].(string); ok {
+				changed = true
+				delete(reg, "electionAddress")
+				if addr == "" {
+					addr = legacy
+				}
+			}
+			if _, ok := reg["status"].(string); !ok {
+				changed = true
+				reg["status"] = "Pending"
+			}
+			add(reg, addr)
+		}
+	}
+	for _, key := range []string{"election_address", "electionAddress"} {
+		if _, ok := doc[key]; !ok {
+			continue
+		}
+		changed = true
+		if addr, ok := doc[key].(string); ok {
+			add(bson.M{"status": "Pending", "registered_at": linkedAt}, addr)
+		}
+	}
+	if out == nil {
+		out = []bson.M{}
+	}
+	return out, changed
+}
+
+// asMap returns an embedded document as a map, whichever way it was decoded.
+func asMap(v interface{}) bson.M {
+	switch d := v.(type) {
+	case bson.M:
+		return d
+	case bson.D:
+		m := bson.M{}
+		for _, e := range d {
+			m[e.Key] = e.Value
+		}
+		return m
+	}
+	return nil
+}
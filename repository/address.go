@@ -0,0 +1,38 @@
+﻿package repository
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CanonicalAddress returns the EIP-55 checksummed form of an election address, the only
+// form the repositories store and query by. Input that is not a hex address is returned
+// trimmed, so lookups for garbage simply miss instead of failing.
+func CanonicalAddress(addr string) string {
+	s := strings.Trim(strings.TrimSpace(addr), `"'`)
+	if len(s) == 40 && !strings.HasPrefix(s, "0x") {
+		s = "0x" + s
+	}
+	if !common.IsHexAddress(s) {
+		return s
+	}
+	return common.HexToAddress(s).Hex()
+}
+
+// The canonicalize helpers rewrite a record's election addresses in place before it is
+// stored, so the caller sees the stored form too.
+
+func (v *Voter) canonicalize() {
+	for i := range v.Registrations {
+		v.Registrations[i].ElectionAddress = CanonicalAddress(v.Registrations[i].ElectionAddress)
+	}
+}
+
+func (m *ElectionMetadata) canonicalize() { m.ElectionAddress = CanonicalAddress(m.ElectionAddress) }
+
+func (c *CandidateDocument) canonicalize() { c.ElectionAddress = CanonicalAddress(c.ElectionAddress) }
+
+func (e *AuditLog) canonicalize() { e.ElectionAddress = CanonicalAddress(e.ElectionAddress) }
+
+func (o *OTP) canonicalize() { o.ElectionAddress = CanonicalAddress(o.ElectionAddress) }
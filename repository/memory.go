@@ -0,0 +1,1327 @@
+﻿package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NewMemory returns repositories that keep everything in process memory. Nothing is
+// persisted: it backs tests and the STORAGE_BACKEND=memory demo mode.
+func NewMemory() *Repositories {
+	return &Repositories{
+		Voters:     &memVoters{},
+		Elections:  &memElections{},
+		Candidates: &memCandidates{},
+		Audit:      &memAudit{},
+		OTPs:       &memOTPs{},
+		Companies:  &memCompanies{},
+		AnchorJobs: &memAnchorJobs{},
+		Rosters:    &memRosters{},
+		Resets:     &memResets{},
+		Students:   &memStudents{},
+		Ballots:    &memBallots{},
+		Reports:    &memReports{},
+		Operators:  &memOperators{},
+		Bundles:    &memBundles{},
+	}
+}
+
+// memCollection holds documents as bson maps, the shape Mongo stores them in, so an
+// Update keyed by bson field names applies the same way and fields the Go types do not
+// declare survive a round trip.
+type memCollection struct {
+	mu   sync.Mutex
+	docs []bson.M
+}
+
+// toDoc converts v to its stored form, giving it an _id when it has none.
+func toDoc(v interface{}) (bson.M, error) {
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	if doc["_id"] == nil {
+		doc["_id"] = primitive.NewObjectID()
+	}
+	return doc, nil
+}
+
+func fromDoc(doc bson.M, out interface{}) error {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(raw, out)
+}
+
+func (c *memCollection) insert(v interface{}) (primitive.ObjectID, error) {
+	return c.insertUnless(func(bson.M) bool { return false }, v)
+}
+
+// insertUnless stores v unless a document already matches conflict (ErrDuplicate).
+func (c *memCollection) insertUnless(conflict func(bson.M) bool, v interface{}) (primitive.ObjectID, error) {
+	doc, err := toDoc(v)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, existing := range c.docs {
+		if conflict(existing) {
+			return primitive.NilObjectID, ErrDuplicate
+		}
+	}
+	c.docs = append(c.docs, doc)
+	id, _ := doc["_id"].(primitive.ObjectID)
+	return id, nil
+}
+
+// find calls each, in insertion order, for every document keep accepts. Both run under
+// the collection lock.
+func (c *memCollection) find(keep func(bson.M) bool, each func(bson.M) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, doc := range c.docs {
+		if keep(doc) {
+			if err := each(doc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// page is the in-memory twin of mongoPage: it returns one page of keep's matches in
+// sort order.
+func (c *memCollection) page(keep func(bson.M) bool, s Sort, keys map[string]string, cursor string, limit int) ([]bson.M, *PageInfo, error) {
+	s, err := validSort(s, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	cur, err := decodeCursor(cursor, s)
+	if err != nil {
+		return nil, nil, err
+	}
+	var docs []bson.M
+	_ = c.find(keep, func(doc bson.M) error { docs = append(docs, doc); return nil })
+	info := &PageInfo{Total: int64(len(docs)), Limit: pageLimit(limit)}
+	id := func(doc bson.M) primitive.ObjectID {
+		oid, _ := doc["_id"].(primitive.ObjectID)
+		return oid
+	}
+	sort.SliceStable(docs, func(i, j int) bool {
+		return compareSorted(docs[i][s.Field], id(docs[i]), docs[j][s.Field], id(docs[j]), s.Desc) < 0
+	})
+	if cur != nil {
+		start := 0
+		for start < len(docs) && !cur.after(docs[start]) {
+			start++
+		}
+		docs = docs[start:]
+	}
+	if len(docs) > info.Limit {
+		docs = docs[:info.Limit]
+		info.Next = encodeCursor(docs[info.Limit-1], s)
+	}
+	info.Count = len(docs)
+	return docs, info, nil
+}
+
+func (c *memCollection) count(keep func(bson.M) bool) int64 {
+	var n int64
+	_ = c.find(keep, func(bson.M) error { n++; return nil })
+	return n
+}
+
+// update applies u to the first limit matches (every match when limit is 0) and
+// reports how many it changed.
+func (c *memCollection) update(keep func(bson.M) bool, u Update, limit int) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for i, doc := range c.docs {
+		if !keep(doc) {
+			continue
+		}
+		next, err := u.apply(doc)
+		if err != nil {
+			return n, err
+		}
+		c.docs[i] = next
+		n++
+		if n == limit {
+			break
+		}
+	}
+	return n, nil
+}
+
+// remove deletes the first limit matches (every match when limit is 0).
+func (c *memCollection) remove(keep func(bson.M) bool, limit int) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var n int64
+	kept := c.docs[:0]
+	for _, doc := range c.docs {
+		if (limit == 0 || n < int64(limit)) && keep(doc) {
+			n++
+			continue
+		}
+		kept = append(kept, doc)
+	}
+	c.docs = kept
+	return n
+}
+
+// replace swaps the first match for v and reports whether there was one.
+func (c *memCollection) replace(keep func(bson.M) bool, v interface{}) (bool, error) {
+	doc, err := toDoc(v)
+	if err != nil {
+		return false, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, existing := range c.docs {
+		if keep(existing) {
+			c.docs[i] = doc
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// upsert replaces the first match with v, or inserts v when nothing matches.
+func (c *memCollection) upsert(keep func(bson.M) bool, v interface{}) error {
+	replaced, err := c.replace(keep, v)
+	if err != nil || replaced {
+		return err
+	}
+	_, err = c.insert(v)
+	return err
+}
+
+// apply returns a copy of doc with u applied, normalised to the stored form.
+func (u Update) apply(doc bson.M) (bson.M, error) {
+	next := bson.M{}
+	for k, v := range doc {
+		next[k] = v
+	}
+	for k, v := range u.Set {
+		next[k] = v
+	}
+	for _, k := range u.Unset {
+		delete(next, k)
+	}
+	for k, n := range u.Inc {
+		next[k] = toInt64(next[k]) + n
+	}
+	for k, v := range u.Push {
+		arr, _ := next[k].(bson.A)
+		next[k] = append(append(bson.A{}, arr...), v)
+	}
+	return toDoc(next)
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int32:
+		return int64(n)
+	case int64:
+		return n
+	case float64:
+		return int64(n)
+	}
+	return 0
+}
+
+func all(bson.M) bool { return true }
+
+func str(doc bson.M, key string) string {
+	s, _ := doc[key].(string)
+	return s
+}
+
+// sameAddr compares a stored (canonical) address with a queried one.
+func sameAddr(stored, addr string) bool {
+	return stored == CanonicalAddress(addr)
+}
+
+func fieldIs(key, addr string) func(bson.M) bool {
+	return func(doc bson.M) bool { return sameAddr(str(doc, key), addr) }
+}
+
+// ----------------------------
+// VOTERS
+// ----------------------------
+
+type memVoters struct{ memCollection }
+
+func registeredFor(doc bson.M, electionAddr string) bool {
+	var v Voter
+	if fromDoc(doc, &v) != nil {
+		return false
+	}
+	for _, reg := range v.Registrations {
+		if sameAddr(reg.ElectionAddress, electionAddr) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memVoters) one(keep func(bson.M) bool) (*Voter, error) {
+	var out *Voter
+	err := r.find(keep, func(doc bson.M) error {
+		if out != nil {
+			return nil
+		}
+		out = &Voter{}
+		return fromDoc(doc, out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out == nil {
+		return nil, ErrNotFound
+	}
+	return out, nil
+}
+
+func byID(id primitive.ObjectID) func(bson.M) bool {
+	return func(doc bson.M) bool { return doc["_id"] == id }
+}
+
+func byEmail(email string) func(bson.M) bool {
+	return func(doc bson.M) bool { return str(doc, "email") == email }
+}
+
+func (r *memVoters) Create(ctx context.Context, v *Voter) error {
+	v.canonicalize()
+	id, err := r.insertUnless(byEmail(v.Email), v)
+	if err != nil {
+		return err
+	}
+	v.ID = id
+	return nil
+}
+
+func (r *memVoters) Get(ctx context.Context, id primitive.ObjectID) (*Voter, error) {
+	return r.one(byID(id))
+}
+
+func (r *memVoters) GetByEmail(ctx context.Context, email string) (*Voter, error) {
+	return r.one(byEmail(email))
+}
+
+func (r *memVoters) GetByRollNo(ctx context.Context, rollNo string) (*Voter, error) {
+	return r.one(func(doc bson.M) bool { return str(doc, "roll_no") == rollNo })
+}
+
+func (r *memVoters) List(ctx context.Context, electionAddr string) ([]Voter, error) {
+	keep := all
+	if electionAddr != "" {
+		keep = func(doc bson.M) bool { return registeredFor(doc, electionAddr) }
+	}
+	var out []Voter
+	err := r.find(keep, func(doc bson.M) error {
+		var v Voter
+		if err := fromDoc(doc, &v); err != nil {
+			return err
+		}
+		out = append(out, v)
+		return nil
+	})
+	return out, err
+}
+
+func (r *memVoters) Page(ctx context.Context, q VoterQuery) (*VoterPage, error) {
+	docs, info, err := r.page(voterMatches(q), q.Sort, VoterSortKeys, q.Cursor, q.Limit)
+	if err != nil {
+		return nil, err
+	}
+	page := &VoterPage{Voters: []Voter{}, PageInfo: *info}
+	for _, doc := range docs {
+		var v Voter
+		if err := fromDoc(doc, &v); err != nil {
+			return nil, err
+		}
+		page.Voters = append(page.Voters, v)
+	}
+	return page, nil
+}
+
+// voterMatches applies q's filters the way mongoVoters.pageFilter does.
+func voterMatches(q VoterQuery) func(bson.M) bool {
+	term := strings.ToLower(strings.TrimSpace(q.Search))
+	inRange := func(t time.Time) bool {
+		return (q.RegisteredFrom.IsZero() || !t.Before(q.RegisteredFrom)) && (q.RegisteredTo.IsZero() || t.Before(q.RegisteredTo))
+	}
+	return func(doc bson.M) bool {
+		var v Voter
+		if fromDoc(doc, &v) != nil {
+			return false
+		}
+		if q.ElectionAddress != "" || q.Status != "" || !q.RegisteredFrom.IsZero() || !q.RegisteredTo.IsZero() {
+			found := false
+			for _, reg := range v.Registrations {
+				if (q.ElectionAddress == "" || sameAddr(reg.ElectionAddress, q.ElectionAddress)) &&
+					(q.Status == "" || strings.EqualFold(reg.Status, strings.TrimSpace(q.Status))) &&
+					inRange(reg.RegisteredAt) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		switch {
+		case !q.IncludeErased && v.ErasedAt != nil:
+			return false
+		case q.Year != "" && v.Year != strings.TrimSpace(q.Year):
+			return false
+		case q.Gender != "" && !strings.EqualFold(v.Gender, strings.TrimSpace(q.Gender)):
+			return false
+		case term != "" && !strings.Contains(strings.ToLower(v.Email), term) &&
+			!strings.Contains(strings.ToLower(v.FullName), term) && !strings.Contains(strings.ToLower(v.RollNo), term):
+			return false
+		}
+		return true
+	}
+}
+
+func (r *memVoters) CountByElection(ctx context.Context, electionAddr string) (int64, error) {
+	return r.count(func(doc bson.M) bool { return registeredFor(doc, electionAddr) }), nil
+}
+
+func (r *memVoters) Update(ctx context.Context, id primitive.ObjectID, set map[string]interface{}) error {
+	if email, ok := set["email"].(string); ok {
+		taken := r.count(func(doc bson.M) bool { return str(doc, "email") == email && doc["_id"] != id })
+		if taken > 0 {
+			return ErrDuplicate
+		}
+	}
+	n, err := r.update(byID(id), Update{Set: set}, 1)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *memVoters) AddRegistration(ctx context.Context, id primitive.ObjectID, reg VoterRegistration) (bool, error) {
+	reg.ElectionAddress = CanonicalAddress(reg.ElectionAddress)
+	notLinked := func(doc bson.M) bool { return doc["_id"] == id && !registeredFor(doc, reg.ElectionAddress) }
+	n, err := r.update(notLinked, Update{Push: map[string]interface{}{"registrations": reg}}, 1)
+	if err != nil || n > 0 {
+		return n > 0, err
+	}
+	if r.count(byID(id)) == 0 {
+		return false, ErrNotFound
+	}
+	return false, nil
+}
+
+func (r *memVoters) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.remove(byID(id), 1)
+	return nil
+}
+
+func (r *memVoters) Erase(ctx context.Context, id primitive.ObjectID, pseudonym string, keep []VoterRegistration) error {
+	now := time.Now().UTC()
+	stub := Voter{ID: id, Email: pseudonym, Registrations: keep, ErasedAt: &now}
+	stub.canonicalize()
+	ok, err := r.replace(byID(id), stub)
+	if err == nil && !ok {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (r *memVoters) RegionCounts(ctx context.Context, electionAddr string) ([]RegionCount, error) {
+	counts := map[string]int{}
+	_ = r.find(func(doc bson.M) bool { return registeredFor(doc, electionAddr) }, func(doc bson.M) error {
+		counts[str(doc, "address")]++
+		return nil
+	})
+	out := make([]RegionCount, 0, len(counts))
+	for addr, n := range counts {
+		out = append(out, RegionCount{Address: addr, Count: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Address < out[j].Address
+	})
+	return out, nil
+}
+
+func (r *memVoters) RemoveRegistrations(ctx context.Context, electionAddr string) (int64, error) {
+	voters, err := r.List(ctx, electionAddr)
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	for _, v := range voters {
+		keep := []VoterRegistration{}
+		for _, reg := range v.Registrations {
+			if !sameAddr(reg.ElectionAddress, electionAddr) {
+				keep = append(keep, reg)
+			}
+		}
+		changed, err := r.update(byID(v.ID), Update{Set: map[string]interface{}{"registrations": keep}}, 1)
+		if err != nil {
+			return n, err
+		}
+		n += int64(changed)
+	}
+	return n, nil
+}
+
+// ----------------------------
+// ELECTIONS
+// ----------------------------
+
+type memElections struct{ memCollection }
+
+func (r *memElections) Get(ctx context.Context, addr string) (*ElectionMetadata, error) {
+	var out *ElectionMetadata
+	err := r.find(fieldIs("election_address", addr), func(doc bson.M) error {
+		if out != nil {
+			return nil
+		}
+		out = &ElectionMetadata{}
+		return fromDoc(doc, out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out == nil {
+		return nil, ErrNotFound
+	}
+	return out, nil
+}
+
+func (r *memElections) List(ctx context.Context, f ElectionFilter) ([]ElectionMetadata, error) {
+	var out []ElectionMetadata
+	err := r.find(electionMatches(f), func(doc bson.M) error {
+		var m ElectionMetadata
+		if err := fromDoc(doc, &m); err != nil {
+			return err
+		}
+		out = append(out, m)
+		return nil
+	})
+	sort.SliceStable(out, func(i, j int) bool { return out[i].StartDate.After(out[j].StartDate) })
+	return out, err
+}
+
+func (r *memElections) Page(ctx context.Context, q ElectionQuery) (*ElectionPage, error) {
+	match := electionMatches(q.ElectionFilter)
+	term := strings.ToLower(strings.TrimSpace(q.Search))
+	keep := func(doc bson.M) bool {
+		if !match(doc) {
+			return false
+		}
+		if term != "" && !strings.Contains(strings.ToLower(str(doc, "election_name")), term) {
+			return false
+		}
+		start := asTime(doc["start_date"])
+		return (q.StartFrom.IsZero() || !start.Before(q.StartFrom)) && (q.StartTo.IsZero() || start.Before(q.StartTo))
+	}
+	docs, info, err := r.page(keep, q.Sort, ElectionSortKeys, q.Cursor, q.Limit)
+	if err != nil {
+		return nil, err
+	}
+	page := &ElectionPage{Elections: []ElectionMetadata{}, PageInfo: *info}
+	for _, doc := range docs {
+		var m ElectionMetadata
+		if err := fromDoc(doc, &m); err != nil {
+			return nil, err
+		}
+		page.Elections = append(page.Elections, m)
+	}
+	return page, nil
+}
+
+func electionMatches(f ElectionFilter) func(bson.M) bool {
+	return func(doc bson.M) bool {
+		status := str(doc, "status")
+		switch {
+		case f.Status != "" && status != f.Status:
+			return false
+		case f.Status == "" && f.ExcludeStatus != "" && status == f.ExcludeStatus:
+			return false
+		case f.MissingCodeHash && str(doc, "code_hash") != "":
+			return false
+		case f.CompanyEmail != "" && str(doc, "company_email") != strings.ToLower(strings.TrimSpace(f.CompanyEmail)):
+			return false
+		}
+		return true
+	}
+}
+
+func (r *memElections) Create(ctx context.Context, m *ElectionMetadata) error {
+	m.canonicalize()
+	id, err := r.insertUnless(fieldIs("election_address", m.ElectionAddress), m)
+	if err != nil {
+		return err
+	}
+	m.ID = id
+	return nil
+}
+
+func (r *memElections) Update(ctx context.Context, addr string, u Update) error {
+	_, err := r.update(fieldIs("election_address", addr), u, 1)
+	return err
+}
+
+func (r *memElections) Upsert(ctx context.Context, addr string, u Update) error {
+	n, err := r.update(fieldIs("election_address", addr), u, 1)
+	if err != nil || n > 0 {
+		return err
+	}
+	doc, err := u.apply(bson.M{"election_address": CanonicalAddress(addr)})
+	if err != nil {
+		return err
+	}
+	_, err = r.insert(doc)
+	return err
+}
+
+func (r *memElections) Delete(ctx context.Context, addr string) error {
+	r.remove(fieldIs("election_address", addr), 1)
+	return nil
+}
+
+// ----------------------------
+// CANDIDATES
+// ----------------------------
+
+type memCandidates struct{ memCollection }
+
+func (r *memCandidates) list(keep func(bson.M) bool, limit int) ([]CandidateDocument, error) {
+	var out []CandidateDocument
+	err := r.find(keep, func(doc bson.M) error {
+		if limit > 0 && len(out) == limit {
+			return nil
+		}
+		var c CandidateDocument
+		if err := fromDoc(doc, &c); err != nil {
+			return err
+		}
+		out = append(out, c)
+		return nil
+	})
+	return out, err
+}
+
+func (r *memCandidates) Create(ctx context.Context, c *CandidateDocument) error {
+	c.canonicalize()
+	_, err := r.insert(c)
+	return err
+}
+
+func (r *memCandidates) List(ctx context.Context, electionAddr string) ([]CandidateDocument, error) {
+	return r.list(fieldIs("electionAddress", electionAddr), 0)
+}
+
+func (r *memCandidates) Manifestos(ctx context.Context, electionAddr string) (map[string]string, error) {
+	docs, err := r.list(fieldIs("electionAddress", electionAddr), 0)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]string{}
+	for _, d := range docs {
+		if d.ManifestoUrl != "" {
+			out[strings.ToLower(d.Email)] = d.ManifestoUrl
+		}
+	}
+	return out, nil
+}
+
+func (r *memCandidates) ElectionsByPrefix(ctx context.Context, prefix string, limit int) ([]string, error) {
+	prefix = strings.ToLower(prefix)
+	docs, err := r.list(func(doc bson.M) bool {
+		return strings.HasPrefix(strings.ToLower(str(doc, "electionAddress")), prefix)
+	}, limit)
+	if err != nil {
+		return nil, err
+	}
+	return distinctElections(docs), nil
+}
+
+func (r *memCandidates) CountActive(ctx context.Context, electionAddr string) (int64, error) {
+	return r.count(func(doc bson.M) bool {
+		switch str(doc, "status") {
+		case "failed", "reverted", "orphaned":
+			return false
+		}
+		return sameAddr(str(doc, "electionAddress"), electionAddr)
+	}), nil
+}
+
+func (r *memCandidates) SetStatus(ctx context.Context, m CandidateMatch, status, txHash string) error {
+	keep := func(doc bson.M) bool { return str(doc, "txId") == m.TxID }
+	if m.TxID == "" {
+		keep = func(doc bson.M) bool {
+			return sameAddr(str(doc, "electionAddress"), m.ElectionAddress) && str(doc, "email") == m.Email &&
+				(m.Status == "" || str(doc, "status") == m.Status)
+		}
+	}
+	set := map[string]interface{}{"status": status, "updatedAt": time.Now().UTC()}
+	if txHash != "" {
+		set["txHash"] = txHash
+	}
+	_, err := r.update(keep, Update{Set: set}, 0)
+	return err
+}
+
+func (r *memCandidates) DeleteByElection(ctx context.Context, electionAddr string) (int64, error) {
+	return r.remove(fieldIs("electionAddress", electionAddr), 0), nil
+}
+
+// ----------------------------
+// AUDIT LOGS
+// ----------------------------
+
+type memAudit struct{ memCollection }
+
+func (r *memAudit) Insert(ctx context.Context, e *AuditLog) error {
+	e.canonicalize()
+	id, err := r.insert(e)
+	if err != nil {
+		return err
+	}
+	e.ID = id
+	return nil
+}
+
+func (r *memAudit) InsertEvent(ctx context.Context, e *AuditLog) error {
+	if r.count(func(doc bson.M) bool { return str(doc, "event_id") == e.EventID }) > 0 {
+		return nil
+	}
+	return r.Insert(ctx, e)
+}
+
+func (r *memAudit) DeleteEvent(ctx context.Context, eventID string) error {
+	r.remove(func(doc bson.M) bool { return str(doc, "event_id") == eventID }, 0)
+	return nil
+}
+
+func (r *memAudit) List(ctx context.Context, electionAddr string) ([]AuditLog, error) {
+	var out []AuditLog
+	err := r.find(fieldIs("election_address", electionAddr), func(doc bson.M) error {
+		var e AuditLog
+		if err := fromDoc(doc, &e); err != nil {
+			return err
+		}
+		out = append(out, e)
+		return nil
+	})
+	// Oldest first for timeline
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Timestamp.Before(out[j].Timestamp) })
+	return out, err
+}
+
+func (r *memAudit) CountAction(ctx context.Context, electionAddr, action string) (int64, error) {
+	return r.count(func(doc bson.M) bool {
+		return str(doc, "action") == action && sameAddr(str(doc, "election_address"), electionAddr)
+	}), nil
+}
+
+// mentioning returns the entries accepted by keep that mention email, oldest first.
+func (r *memAudit) mentioning(keep func(bson.M) bool, email string) ([]AuditLog, error) {
+	var out []AuditLog
+	err := r.find(keep, func(doc bson.M) error {
+		var e AuditLog
+		if err := fromDoc(doc, &e); err != nil {
+			return err
+		}
+		if e.mentionsEmail(email) {
+			out = append(out, e)
+		}
+		return nil
+	})
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Timestamp.Before(out[j].Timestamp) })
+	return out, err
+}
+
+func (r *memAudit) ListMentioning(ctx context.Context, email string) ([]AuditLog, error) {
+	return r.mentioning(all, email)
+}
+
+func (r *memAudit) Pseudonymize(ctx context.Context, electionAddr, email, pseudonym string) (int64, error) {
+	docs, err := r.mentioning(fieldIs("election_address", electionAddr), email)
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	for _, e := range docs {
+		set := map[string]interface{}{"actor": scrubEmail(e.Actor, email, pseudonym), "details": scrubEmail(e.Details, email, pseudonym)}
+		if _, err := r.update(byID(e.ID), Update{Set: set}, 1); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+func (r *memAudit) DeleteMentioning(ctx context.Context, electionAddr, email string) (int64, error) {
+	docs, err := r.mentioning(fieldIs("election_address", electionAddr), email)
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	for _, e := range docs {
+		n += r.remove(byID(e.ID), 1)
+	}
+	return n, nil
+}
+
+func (r *memAudit) DeleteByElection(ctx context.Context, electionAddr string) (int64, error) {
+	return r.remove(fieldIs("election_address", electionAddr), 0), nil
+}
+
+// ----------------------------
+// OTPS
+// ----------------------------
+
+type memOTPs struct{ memCollection }
+
+func (r *memOTPs) Get(ctx context.Context, email string) (*OTP, error) {
+	var out *OTP
+	err := r.find(byEmail(email), func(doc bson.M) error {
+		out = &OTP{}
+		return fromDoc(doc, out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out == nil {
+		return nil, ErrNotFound
+	}
+	return out, nil
+}
+
+func (r *memOTPs) Replace(ctx context.Context, o *OTP) error {
+	o.canonicalize()
+	r.remove(byEmail(o.Email), 0)
+	_, err := r.insert(o)
+	return err
+}
+
+func (r *memOTPs) Delete(ctx context.Context, email string) error {
+	r.remove(byEmail(email), 0)
+	return nil
+}
+
+func (r *memOTPs) CountByElection(ctx context.Context, electionAddr string) (int64, error) {
+	return r.count(fieldIs("election_address", electionAddr)), nil
+}
+
+func (r *memOTPs) DeleteByElection(ctx context.Context, electionAddr string) (int64, error) {
+	return r.remove(fieldIs("election_address", electionAddr), 0), nil
+}
+
+// ----------------------------
+// COMPANIES
+// ----------------------------
+
+type memCompanies struct{ memCollection }
+
+func companyEmailIs(email string) func(bson.M) bool {
+	email = strings.TrimSpace(email)
+	return func(doc bson.M) bool { return strings.EqualFold(str(doc, "email"), email) }
+}
+
+func (r *memCompanies) Create(ctx context.Context, c *Company) error {
+	id, err := r.insertUnless(companyEmailIs(c.Email), c)
+	if err != nil {
+		return err
+	}
+	c.ID = id
+	return nil
+}
+
+func (r *memCompanies) GetByEmail(ctx context.Context, email string) (*Company, error) {
+	var out *Company
+	err := r.find(companyEmailIs(email), func(doc bson.M) error {
+		out = &Company{}
+		return fromDoc(doc, out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out == nil {
+		return nil, ErrNotFound
+	}
+	return out, nil
+}
+
+func (r *memCompanies) List(ctx context.Context) ([]Company, error) {
+	var out []Company
+	err := r.find(all, func(doc bson.M) error {
+		var c Company
+		if err := fromDoc(doc, &c); err != nil {
+			return err
+		}
+		out = append(out, c)
+		return nil
+	})
+	return out, err
+}
+
+func (r *memCompanies) Update(ctx context.Context, email string, u Update) error {
+	n, err := r.update(companyEmailIs(email), u, 1)
+	if err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return err
+}
+
+// ----------------------------
+// ANCHOR JOBS
+// ----------------------------
+
+type memAnchorJobs struct{ memCollection }
+
+func (r *memAnchorJobs) Get(ctx context.Context, electionAddr string) (*AnchorJob, error) {
+	var out *AnchorJob
+	err := r.find(fieldIs("_id", electionAddr), func(doc bson.M) error {
+		out = &AnchorJob{}
+		return fromDoc(doc, out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out == nil {
+		return nil, ErrNotFound
+	}
+	return out, nil
+}
+
+func (r *memAnchorJobs) Queue(ctx context.Context, electionAddr, closeTxID, requestedBy string, requiredConfirmations uint64) error {
+	now := time.Now().UTC()
+	job := AnchorJob{
+		ElectionAddress:       CanonicalAddress(electionAddr),
+		Status:                AnchorQueued,
+		NextAttemptAt:         now,
+		RequestedBy:           requestedBy,
+		CloseTxID:             closeTxID,
+		RequiredConfirmations: requiredConfirmations,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	}
+	if prev, err := r.Get(ctx, electionAddr); err == nil {
+		job.CreatedAt = prev.CreatedAt
+	}
+	if ok, err := r.replace(fieldIs("_id", electionAddr), job); ok || err != nil {
+		return err
+	}
+	_, err := r.insert(job)
+	return err
+}
+
+func (r *memAnchorJobs) Update(ctx context.Context, electionAddr string, u Update) error {
+	_, err := r.update(fieldIs("_id", electionAddr), u, 1)
+	return err
+}
+
+func (r *memAnchorJobs) Due(ctx context.Context, now time.Time) ([]AnchorJob, error) {
+	var out []AnchorJob
+	err := r.find(func(doc bson.M) bool {
+		switch str(doc, "status") {
+		case AnchorQueued:
+			return !asTime(doc["next_attempt_at"]).After(now)
+		case AnchorSubmitted:
+			return true
+		}
+		return false
+	}, func(doc bson.M) error {
+		var j AnchorJob
+		if err := fromDoc(doc, &j); err != nil {
+			return err
+		}
+		out = append(out, j)
+		return nil
+	})
+	return out, err
+}
+
+// ----------------------------
+// ROSTER IMPORTS
+// ----------------------------
+
+// memRosters keeps rows until restart; they do not expire.
+type memRosters struct {
+	imports, rows memCollection
+}
+
+func (r *memRosters) Create(ctx context.Context, job *RosterImport, rows []RosterRow) error {
+	for i := range rows {
+		if _, err := r.rows.insert(rows[i]); err != nil {
+			return err
+		}
+	}
+	_, err := r.imports.insert(job)
+	return err
+}
+
+func (r *memRosters) Get(ctx context.Context, id primitive.ObjectID) (*RosterImport, error) {
+	var out *RosterImport
+	err := r.imports.find(byID(id), func(doc bson.M) error {
+		out = &RosterImport{}
+		return fromDoc(doc, out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out == nil {
+		return nil, ErrNotFound
+	}
+	return out, nil
+}
+
+func (r *memRosters) Update(ctx context.Context, id primitive.ObjectID, u Update) error {
+	_, err := r.imports.update(byID(id), u, 1)
+	return err
+}
+
+// transition moves the longest-waiting import keep accepts to the values of set.
+func (r *memRosters) transition(keep func(bson.M) bool, set map[string]interface{}) (*RosterImport, error) {
+	var first bson.M
+	_ = r.imports.find(keep, func(doc bson.M) error {
+		if first == nil || asTime(doc["updated_at"]).Before(asTime(first["updated_at"])) {
+			first = doc
+		}
+		return nil
+	})
+	if first == nil {
+		return nil, ErrNotFound
+	}
+	id, _ := first["_id"].(primitive.ObjectID)
+	// keep again, so an import claimed meanwhile is not claimed twice
+	n, err := r.imports.update(func(doc bson.M) bool { return byID(id)(doc) && keep(doc) }, Update{Set: set}, 1)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrNotFound
+	}
+	return r.Get(context.Background(), id)
+}
+
+func (r *memRosters) Queue(ctx context.Context, id primitive.ObjectID, since time.Time) (*RosterImport, error) {
+	return r.transition(func(doc bson.M) bool {
+		return byID(id)(doc) && str(doc, "status") == RosterPreview && asTime(doc["created_at"]).After(since)
+	}, map[string]interface{}{"status": RosterQueued, "updated_at": time.Now().UTC()})
+}
+
+func (r *memRosters) Claim(ctx context.Context) (*RosterImport, error) {
+	now := time.Now().UTC()
+	return r.transition(func(doc bson.M) bool { return str(doc, "status") == RosterQueued },
+		map[string]interface{}{"status": RosterRunning, "started_at": now, "updated_at": now})
+}
+
+func (r *memRosters) Requeue(ctx context.Context) (int64, error) {
+	n, err := r.imports.update(func(doc bson.M) bool { return str(doc, "status") == RosterRunning },
+		Update{Set: map[string]interface{}{"status": RosterQueued}}, 0)
+	return int64(n), err
+}
+
+func (r *memRosters) Rows(ctx context.Context, q RosterRowQuery) ([]RosterRow, error) {
+	var out []RosterRow
+	err := r.rows.find(func(doc bson.M) bool { return doc["import_id"] == q.ImportID }, func(doc bson.M) error {
+		var row RosterRow
+		if err := fromDoc(doc, &row); err != nil {
+			return err
+		}
+		switch {
+		case row.Row <= q.After:
+		case q.Pending && row.Result != "":
+		case q.Problems && len(row.Errors) == 0 && row.Result != RosterRowFailed:
+		default:
+			out = append(out, row)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Row < out[j].Row })
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[:q.Limit]
+	}
+	return out, nil
+}
+
+func (r *memRosters) UpdateRow(ctx context.Context, id primitive.ObjectID, set map[string]interface{}) error {
+	_, err := r.rows.update(byID(id), Update{Set: set}, 1)
+	return err
+}
+
+// ----------------------------
+// COMPANY RESETS
+// ----------------------------
+
+type memResets struct{ memCollection }
+
+func (r *memResets) SavePreview(ctx context.Context, rec *ResetRecord) error {
+	r.remove(func(doc bson.M) bool { return str(doc, "company") == rec.Company && str(doc, "status") == ResetPreview }, 0)
+	rec.ID = primitive.NewObjectID()
+	_, err := r.insert(rec)
+	return err
+}
+
+func (r *memResets) Start(ctx context.Context, company, tokenHash, remoteAddr string, now time.Time) (*ResetRecord, error) {
+	var id primitive.ObjectID
+	n, err := r.update(func(doc bson.M) bool {
+		if str(doc, "company") != company || str(doc, "status") != ResetPreview || str(doc, "token_hash") != tokenHash ||
+			!asTime(doc["expires_at"]).After(now) {
+			return false
+		}
+		id, _ = doc["_id"].(primitive.ObjectID)
+		return true
+	}, Update{
+		Set:   map[string]interface{}{"status": ResetStarted, "started_at": now, "remote_addr": remoteAddr},
+		Unset: []string{"token_hash", "expires_at"},
+	}, 1)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrNotFound
+	}
+	var out *ResetRecord
+	err = r.find(byID(id), func(doc bson.M) error {
+		out = &ResetRecord{}
+		return fromDoc(doc, out)
+	})
+	return out, err
+}
+
+func (r *memResets) Update(ctx context.Context, id primitive.ObjectID, u Update) error {
+	_, err := r.update(byID(id), u, 1)
+	return err
+}
+
+func (r *memResets) List(ctx context.Context, company string, limit int) ([]ResetRecord, error) {
+	out := []ResetRecord{}
+	err := r.find(func(doc bson.M) bool {
+		return str(doc, "company") == company && str(doc, "status") != ResetPreview
+	}, func(doc bson.M) error {
+		var rec ResetRecord
+		if err := fromDoc(doc, &rec); err != nil {
+			return err
+		}
+		out = append(out, rec)
+		return nil
+	})
+	sort.SliceStable(out, func(i, j int) bool { return out[i].StartedAt.After(*out[j].StartedAt) })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, err
+}
+
+// ----------------------------
+// STUDENTS
+// ----------------------------
+
+type memStudents struct{ memCollection }
+
+func (r *memStudents) GetByEmail(ctx context.Context, email string) (*Student, error) {
+	var out *Student
+	err := r.find(byEmail(email), func(doc bson.M) error {
+		out = &Student{}
+		return fromDoc(doc, out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out == nil {
+		return nil, ErrNotFound
+	}
+	return out, nil
+}
+
+func (r *memStudents) Create(ctx context.Context, s *Student) error {
+	id, err := r.insertUnless(byEmail(s.Email), s)
+	if err != nil {
+		return err
+	}
+	s.ID = id
+	return nil
+}
+
+func (r *memStudents) Update(ctx context.Context, email string, set map[string]interface{}) error {
+	n, err := r.update(byEmail(email), Update{Set: set}, 1)
+	if err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return err
+}
+
+// ----------------------------
+// BALLOTS
+// ----------------------------
+
+type memBallots struct{ memCollection }
+
+func (q BallotQuery) match(doc bson.M) bool {
+	if len(q.IDs) > 0 {
+		listed := false
+		for _, id := range q.IDs {
+			listed = listed || doc["_id"] == id
+		}
+		if !listed {
+			return false
+		}
+	}
+	if q.Election != "" && str(doc, "election_address") != q.Election {
+		return false
+	}
+	if q.Voter != nil && !q.Voter.MatchString(str(doc, "voter_email")) {
+		return false
+	}
+	if len(q.Statuses) > 0 {
+		status := str(doc, "status")
+		for _, s := range q.Statuses {
+			if s == status {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (r *memBallots) Insert(ctx context.Context, bl *Ballot) error {
+	bl.ID = primitive.NewObjectID()
+	_, err := r.insertUnless(func(doc bson.M) bool {
+		return bl.Active && doc["active"] == true &&
+			str(doc, "election_address") == bl.ElectionAddress && str(doc, "voter_email") == bl.VoterEmail
+	}, bl)
+	if err != nil {
+		bl.ID = primitive.NilObjectID
+	}
+	return err
+}
+
+func (r *memBallots) Get(ctx context.Context, id primitive.ObjectID) (*Ballot, error) {
+	var out *Ballot
+	err := r.find(byID(id), func(doc bson.M) error {
+		out = &Ballot{}
+		return fromDoc(doc, out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out == nil {
+		return nil, ErrNotFound
+	}
+	return out, nil
+}
+
+func (r *memBallots) Find(ctx context.Context, q BallotQuery) ([]Ballot, error) {
+	out := []Ballot{}
+	err := r.find(q.match, func(doc bson.M) error {
+		var bl Ballot
+		if err := fromDoc(doc, &bl); err != nil {
+			return err
+		}
+		out = append(out, bl)
+		return nil
+	})
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, err
+}
+
+func (r *memBallots) Count(ctx context.Context, q BallotQuery) (int64, error) {
+	return r.count(q.match), nil
+}
+
+func (r *memBallots) Update(ctx context.Context, q BallotQuery, u Update) (int64, error) {
+	n, err := r.update(q.match, u, 0)
+	return int64(n), err
+}
+
+func (r *memBallots) Delete(ctx context.Context, q BallotQuery) (int64, error) {
+	return r.remove(q.match, 0), nil
+}
+
+// ----------------------------
+// CONSISTENCY REPORTS
+// ----------------------------
+
+type memReports struct{ memCollection }
+
+func (r *memReports) Get(ctx context.Context, electionAddr string) (*ConsistencyReport, error) {
+	var out *ConsistencyReport
+	err := r.find(fieldIs("_id", electionAddr), func(doc bson.M) error {
+		out = &ConsistencyReport{}
+		return fromDoc(doc, out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out == nil {
+		return nil, ErrNotFound
+	}
+	return out, nil
+}
+
+func (r *memReports) Save(ctx context.Context, rep *ConsistencyReport) error {
+	rep.ElectionAddress = CanonicalAddress(rep.ElectionAddress)
+	return r.upsert(fieldIs("_id", rep.ElectionAddress), rep)
+}
+
+// ----------------------------
+// OPERATOR KEYS
+// ----------------------------
+
+type memOperators struct{ memCollection }
+
+func (r *memOperators) Get(ctx context.Context, chain string) (*OperatorKey, error) {
+	var out *OperatorKey
+	err := r.find(func(doc bson.M) bool { return str(doc, "_id") == chain }, func(doc bson.M) error {
+		out = &OperatorKey{}
+		return fromDoc(doc, out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out == nil {
+		return nil, ErrNotFound
+	}
+	return out, nil
+}
+
+func (r *memOperators) Save(ctx context.Context, k *OperatorKey) error {
+	return r.upsert(func(doc bson.M) bool { return str(doc, "_id") == k.Chain }, k)
+}
+
+// ----------------------------
+// BUNDLE ARCHIVES
+// ----------------------------
+
+type memBundles struct{ memCollection }
+
+func (r *memBundles) Save(ctx context.Context, a *BundleArchive) error {
+	a.ElectionAddress = CanonicalAddress(a.ElectionAddress)
+	return r.upsert(fieldIs("_id", a.ElectionAddress), a)
+}
@@ -0,0 +1,429 @@
+﻿package repository
+
+import (
+	"time"
+
+	"MAJOR-PROJECT/fieldcrypt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type VoterRegistration struct {
+	ElectionAddress string    `bson:"election_address" json:"election_address"`
+	Status          string    `bson:"status" json:"status"` // "Verified", "Pending"
+	RegisteredAt    time.Time `bson:"registered_at" json:"registered_at"`
+}
+
+type Voter struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Email         string              `bson:"email" json:"email"`
+	Password      string              `bson:"password" json:"-"`
+	FullName      string              `bson:"full_name,omitempty" json:"full_name,omitempty"`
+	DOB           time.Time           `bson:"dob,omitempty" json:"dob,omitempty"`
+	RollNo        string              `bson:"roll_no,omitempty" json:"roll_no,omitempty"`
+	Mobile        string              `bson:"mobile,omitempty" json:"mobile,omitempty"`
+	Gender        string              `bson:"gender,omitempty" json:"gender,omitempty"`
+	Year          string              `bson:"year,omitempty" json:"year,omitempty"`
+	PhotoURL      string              `bson:"photo_url,omitempty" json:"photo_url,omitempty"`
+	Registrations []VoterRegistration `bson:"registrations" json:"registrations"`
+	ErasedAt      *time.Time          `bson:"erased_at,omitempty" json:"erased_at,omitempty"` // set on the stub left by an erasure
+}
+
+// VoterFields are the voter PII fields sealed at rest when field encryption is on.
+// Email, roll number and gender keep blind indexes for lookups and list filters.
+var VoterFields = fieldcrypt.Fields{
+	Sealed:  []string{"email", "full_name", "dob", "roll_no", "mobile", "gender"},
+	Indexed: []string{"email", "roll_no", "gender"},
+}
+
+// Student is one entry of the students roster that voters register against.
+type Student struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Email    string             `bson:"email" json:"email"`
+	FullName string             `bson:"full_name" json:"full_name"`
+	RollNo   string             `bson:"roll_no" json:"roll_no"`
+	Mobile   string             `bson:"mobile" json:"mobile"`
+	Gender   string             `bson:"gender" json:"gender"`
+	Year     string             `bson:"year" json:"year"`
+	DOB      time.Time          `bson:"dob" json:"dob"`
+}
+
+// StudentFields are the same for the students roster.
+var StudentFields = VoterFields
+
+// RegionCount is one row of VoterRepo.RegionCounts; Address is empty for voters without one.
+type RegionCount struct {
+	Address string `bson:"_id" json:"address"`
+	Count   int    `bson:"count" json:"count"`
+}
+
+// OTP is the one-time code last sent to an email.
+type OTP struct {
+	Email           string    `bson:"email"`
+	Code            string    `bson:"otp"`
+	ExpiresAt       time.Time `bson:"expiresAt"`
+	ElectionAddress string    `bson:"election_address"`
+	CreatedAt       time.Time `bson:"createdAt"`
+}
+
+// ElectionMetadata stores off-chain details about an election, primarily for scheduling/phases.
+type ElectionMetadata struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ElectionAddress string             `bson:"election_address" json:"election_address"`
+
+	// Dates for Phases
+	StartDate time.Time `bson:"start_date" json:"start_date"`
+	EndDate   time.Time `bson:"end_date" json:"end_date"`
+
+	// Status derived from time or manual override
+	Status string `bson:"status" json:"status"` // "UPCOMING", "ONGOING", "PAUSED", "ENDED"
+
+	// Emergency pause; the status before the pause comes back on resume
+	PauseReason        string     `bson:"pause_reason,omitempty" json:"pause_reason,omitempty"`
+	PausedAt           *time.Time `bson:"paused_at,omitempty" json:"paused_at,omitempty"`
+	PausedFromStatus   string     `bson:"paused_from_status,omitempty" json:"-"`
+	TotalPausedSeconds int64      `bson:"total_paused_seconds,omitempty" json:"total_paused_seconds,omitempty"`
+
+	// Display Details
+	ElectionName string `bson:"election_name" json:"election_name"`
+	ElectionDesc string `bson:"election_desc" json:"election_desc"`
+
+	// Registry id and owning company, taken from the ElectionCreated event; gas for the
+	// election is charged to the company
+	ElectionID   uint64 `bson:"election_id,omitempty" json:"election_id,omitempty"`
+	CompanyID    string `bson:"company_id,omitempty" json:"company_id,omitempty"`
+	CompanyEmail string `bson:"company_email,omitempty" json:"company_email,omitempty"`
+
+	// Set by the chain indexer once ResultArchived is seen on L1
+	AnchorTxHash string     `bson:"anchor_tx_hash,omitempty" json:"anchor_tx_hash,omitempty"`
+	AnchoredAt   *time.Time `bson:"anchored_at,omitempty" json:"anchored_at,omitempty"`
+
+	// Election contract build (deploy/artifacts VERSION) and keccak of its runtime code;
+	// set by the indexer, or by POST /api/admin/elections/migrate for older elections
+	ContractVersion string `bson:"contract_version,omitempty" json:"contract_version,omitempty"`
+	CodeHash        string `bson:"code_hash,omitempty" json:"code_hash,omitempty"`
+
+	// M-of-N approvers and every approval mined for this election
+	Approvers         []ElectionApprover `bson:"approvers,omitempty" json:"approvers,omitempty"`
+	ApprovalThreshold int64              `bson:"approval_threshold,omitempty" json:"approval_threshold,omitempty"`
+	Approvals         []ElectionApproval `bson:"approvals,omitempty" json:"approvals,omitempty"`
+
+	// Set once the retention sweep has purged the election's erased-voter references
+	RetentionSweptAt *time.Time `bson:"retention_swept_at,omitempty" json:"retention_swept_at,omitempty"`
+
+	// Votes cast before the chain indexer's first block, which have no VOTE_CAST entry;
+	// read once by the reconciler
+	VoteBaseline *uint64 `bson:"vote_baseline,omitempty" json:"vote_baseline,omitempty"`
+}
+
+// ElectionApprover is one of the N. Address approvers sign the action hash with their
+// wallet; admin members (company accounts) approve with their login instead.
+type ElectionApprover struct {
+	ID      string `bson:"id" json:"id"` // bytes32 id stored on the contract
+	Address string `bson:"address,omitempty" json:"address,omitempty"`
+	Email   string `bson:"email,omitempty" json:"email,omitempty"`
+}
+
+// ElectionApproval is an approval mined on-chain.
+type ElectionApproval struct {
+	Action     string     `bson:"action" json:"action"`
+	ActionHash string     `bson:"action_hash" json:"action_hash"`
+	ApproverID string     `bson:"approver_id" json:"approver_id"`
+	Approver   string     `bson:"approver" json:"approver"` // address or member email
+	Start      *time.Time `bson:"start_date,omitempty" json:"start_date,omitempty"`
+	End        *time.Time `bson:"end_date,omitempty" json:"end_date,omitempty"`
+	Count      uint64     `bson:"count" json:"count"` // approvals for the action after this one
+	TxHash     string     `bson:"tx_hash" json:"tx_hash"`
+	ApprovedAt time.Time  `bson:"approved_at" json:"approved_at"`
+}
+
+type CandidateDocument struct {
+	Name            string    `bson:"name"`
+	Email           string    `bson:"email"`
+	Description     string    `bson:"description"`
+	ImageHash       string    `bson:"imageHash"`
+	ManifestoUrl    string    `bson:"manifestoUrl,omitempty"`
+	ElectionName    string    `bson:"electionName,omitempty"`
+	ElectionAddress string    `bson:"electionAddress,omitempty"`
+	TxHash          string    `bson:"txHash,omitempty"`
+	TxID            string    `bson:"txId,omitempty"` // transactions record owning this registration
+	Status          string    `bson:"status"`         // "submitted", "mined", "reverted", "failed", or "orphaned" (reconciler)
+	CreatedAt       time.Time `bson:"createdAt"`
+	UpdatedAt       time.Time `bson:"updatedAt"`
+}
+
+// AuditLog represents a single event in the election lifecycle
+type AuditLog struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ElectionAddress string             `bson:"election_address" json:"election_address"`
+	Action          string             `bson:"action" json:"action"`   // e.g. "ELECTION_CREATED", "VOTE_CAST", "ELECTION_ENDED"
+	Actor           string             `bson:"actor" json:"actor"`     // email or ID of who performed it
+	Details         string             `bson:"details" json:"details"` // extra info
+	Timestamp       time.Time          `bson:"timestamp" json:"timestamp"`
+	EventID         string             `bson:"event_id,omitempty" json:"event_id,omitempty"` // set for entries written by the chain indexer
+}
+
+// Company is an organiser account. Elections, gas budgets and approvals refer to it by
+// email; the password is a bcrypt hash.
+type Company struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Email    string             `bson:"email" json:"email"`
+	Password string             `bson:"password" json:"-"`
+
+	// Days pseudonymized voter data is kept after an election ends; nil means DATA_RETENTION_DAYS
+	RetentionDays *int `bson:"retention_days,omitempty" json:"retention_days,omitempty"`
+}
+
+// Anchor job states.
+const (
+	AnchorQueued    = "QUEUED"    // waiting for its next attempt (or for the L2 close to be mined)
+	AnchorSubmitted = "SUBMITTED" // archiveResult sent to L1, waiting for confirmations
+	AnchorConfirmed = "CONFIRMED" // mined and buried under the required confirmations
+	AnchorFailed    = "FAILED"    // gave up after the maximum number of attempts
+)
+
+// AnchorJob tracks anchoring one election's final result on L1. There is one job per
+// election; re-anchoring resets it.
+type AnchorJob struct {
+	ElectionAddress string    `bson:"_id" json:"election_address"`
+	Status          string    `bson:"status" json:"status"`
+	Attempts        int       `bson:"attempts" json:"attempts"`
+	NextAttemptAt   time.Time `bson:"next_attempt_at" json:"next_attempt_at"`
+	LastError       string    `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	RequestedBy     string    `bson:"requested_by" json:"requested_by"`
+
+	CloseTxID string `bson:"close_tx_id,omitempty" json:"close_tx_id,omitempty"` // L2 closeElection record
+
+	// Result snapshot that was sent to L1
+	Title        string `bson:"title,omitempty" json:"title,omitempty"`
+	WinnerName   string `bson:"winner_name,omitempty" json:"winner_name,omitempty"`
+	WinningVotes string `bson:"winning_votes,omitempty" json:"winning_votes,omitempty"`
+	TotalVoters  string `bson:"total_voters,omitempty" json:"total_voters,omitempty"`
+
+	TxID                  string `bson:"tx_id,omitempty" json:"tx_id,omitempty"` // L1 transaction record
+	TxHash                string `bson:"tx_hash,omitempty" json:"tx_hash,omitempty"`
+	BlockNumber           uint64 `bson:"block_number,omitempty" json:"block_number,omitempty"`
+	Confirmations         uint64 `bson:"confirmations" json:"confirmations"`
+	RequiredConfirmations uint64 `bson:"required_confirmations" json:"required_confirmations"`
+
+	CreatedAt   time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time  `bson:"updated_at" json:"updated_at"`
+	SubmittedAt *time.Time `bson:"submitted_at,omitempty" json:"submitted_at,omitempty"`
+	ConfirmedAt *time.Time `bson:"confirmed_at,omitempty" json:"confirmed_at,omitempty"`
+}
+
+// Roster import states.
+const (
+	RosterPreview   = "PREVIEW" // validated, waiting to be applied
+	RosterQueued    = "QUEUED"
+	RosterRunning   = "RUNNING"
+	RosterCompleted = "COMPLETED" // every row has a result; failed rows are in the report
+	RosterFailed    = "FAILED"    // stopped by a storage error; later rows have no result
+)
+
+// RosterRowFailed is the result of a row that could not be applied.
+const RosterRowFailed = "failed"
+
+// RosterRowTTL is how long the rows of a roster import are kept; the import record stays.
+const RosterRowTTL = 30 * 24 * time.Hour
+
+// RosterSummary counts the rows of a roster import by planned change.
+type RosterSummary struct {
+	Rows     int            `bson:"rows" json:"rows"`
+	Invalid  int            `bson:"invalid" json:"invalid"`
+	Students map[string]int `bson:"students,omitempty" json:"students,omitempty"`
+	Voters   map[string]int `bson:"voters,omitempty" json:"voters,omitempty"`
+}
+
+// Count adds row to the summary.
+func (s *RosterSummary) Count(row *RosterRow) {
+	s.Rows++
+	if len(row.Errors) > 0 {
+		s.Invalid++
+		return
+	}
+	if row.Student != "" {
+		if s.Students == nil {
+			s.Students = map[string]int{}
+		}
+		s.Students[row.Student]++
+	}
+	if row.Voter != "" {
+		if s.Voters == nil {
+			s.Voters = map[string]int{}
+		}
+		s.Voters[row.Voter]++
+	}
+}
+
+// RosterImport is one uploaded CSV or XLSX roster.
+type RosterImport struct {
+	ID              primitive.ObjectID `bson:"_id" json:"id"`
+	Target          string             `bson:"target" json:"target"` // students, voters or both
+	ElectionAddress string             `bson:"election_address,omitempty" json:"election_address,omitempty"`
+	FileName        string             `bson:"file_name" json:"file_name"`
+	Format          string             `bson:"format" json:"format"`
+	Columns         map[string]string  `bson:"columns" json:"columns"` // field -> header it was read from
+	Status          string             `bson:"status" json:"status"`
+	Summary         RosterSummary      `bson:"summary" json:"summary"`
+	Applied         int                `bson:"applied" json:"applied"`
+	Failed          int                `bson:"failed" json:"failed"`
+	Skipped         int                `bson:"skipped" json:"skipped"`
+	Error           string             `bson:"error,omitempty" json:"error,omitempty"`
+	RequestedBy     string             `bson:"requested_by" json:"requested_by"` // company email
+	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time          `bson:"updated_at" json:"updated_at"`
+	StartedAt       *time.Time         `bson:"started_at,omitempty" json:"started_at,omitempty"`
+	FinishedAt      *time.Time         `bson:"finished_at,omitempty" json:"finished_at,omitempty"`
+}
+
+// RosterRow is one data row of an import: its values, validation errors, planned
+// changes and, once applied, its result.
+type RosterRow struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	ImportID primitive.ObjectID `bson:"import_id" json:"-"`
+	Row      int                `bson:"row" json:"row"` // line or spreadsheet row in the file
+
+	Email    string `bson:"email" json:"email"`
+	FullName string `bson:"full_name,omitempty" json:"full_name,omitempty"`
+	RollNo   string `bson:"roll_no,omitempty" json:"roll_no,omitempty"`
+	Mobile   string `bson:"mobile,omitempty" json:"mobile,omitempty"`
+	Gender   string `bson:"gender,omitempty" json:"gender,omitempty"`
+	Year     string `bson:"year,omitempty" json:"year,omitempty"`
+	DOB      string `bson:"dob,omitempty" json:"dob,omitempty"` // YYYY-MM-DD
+
+	Errors  []string `bson:"errors,omitempty" json:"errors,omitempty"`
+	Student string   `bson:"student,omitempty" json:"student,omitempty"`
+	Voter   string   `bson:"voter,omitempty" json:"voter,omitempty"`
+	Changes []string `bson:"changes,omitempty" json:"changes,omitempty"` // fields differing from the stored record
+
+	Result      string `bson:"result,omitempty" json:"result,omitempty"`
+	ResultError string `bson:"result_error,omitempty" json:"result_error,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at" json:"-"`
+}
+
+// Reset record states.
+const (
+	ResetPreview   = "PREVIEW" // counted, waiting for its confirm_token until ExpiresAt
+	ResetStarted   = "STARTED" // bundles being written or deletion running (or interrupted)
+	ResetCompleted = "COMPLETED"
+	ResetFailed    = "FAILED"
+)
+
+// ResetCounts is what a company reset deletes, per election or in total.
+type ResetCounts struct {
+	Metadata           int64 `bson:"metadata" json:"metadata"`
+	Candidates         int64 `bson:"candidates" json:"candidates"`
+	AuditLogs          int64 `bson:"audit_logs" json:"audit_logs"`
+	OTPs               int64 `bson:"otps" json:"otps"`
+	VoterRegistrations int64 `bson:"voter_registrations" json:"voter_registrations"`
+}
+
+// Add adds o to c.
+func (c *ResetCounts) Add(o ResetCounts) {
+	c.Metadata += o.Metadata
+	c.Candidates += o.Candidates
+	c.AuditLogs += o.AuditLogs
+	c.OTPs += o.OTPs
+	c.VoterRegistrations += o.VoterRegistrations
+}
+
+// ResetElection is one election in a reset.
+type ResetElection struct {
+	Address      string      `bson:"address" json:"address"`
+	Name         string      `bson:"name,omitempty" json:"name,omitempty"`
+	Counts       ResetCounts `bson:"counts" json:"counts"`
+	Bundle       string      `bson:"bundle,omitempty" json:"bundle,omitempty"` // archive written before deletion
+	BundleSHA256 string      `bson:"bundle_sha256,omitempty" json:"bundle_sha256,omitempty"`
+}
+
+// ResetRecord is the permanent trace of a company reset. It is written at the preview,
+// holding the SHA-256 of the confirm_token, so a token survives restarts and works on
+// any replica.
+type ResetRecord struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Company    string             `bson:"company" json:"company"`
+	Scope      string             `bson:"scope" json:"scope"` // "company" or an election address
+	Status     string             `bson:"status" json:"status"`
+	Error      string             `bson:"error,omitempty" json:"error,omitempty"`
+	Elections  []ResetElection    `bson:"elections" json:"elections"`
+	Deleted    ResetCounts        `bson:"deleted" json:"deleted"`
+	Signer     string             `bson:"bundle_signer,omitempty" json:"bundle_signer,omitempty"`
+	RemoteAddr string             `bson:"remote_addr,omitempty" json:"remote_addr,omitempty"`
+	TokenHash  string             `bson:"token_hash,omitempty" json:"-"`
+	ExpiresAt  *time.Time         `bson:"expires_at,omitempty" json:"-"` // of the confirm_token
+	PreviewAt  time.Time          `bson:"preview_at" json:"preview_at"`
+	StartedAt  *time.Time         `bson:"started_at,omitempty" json:"started_at,omitempty"`
+	FinishedAt *time.Time         `bson:"finished_at,omitempty" json:"finished_at,omitempty"`
+}
+
+// Ballot states.
+const (
+	BallotQueued    = "QUEUED"    // validated, waiting for the next batch
+	BallotSubmitted = "SUBMITTED" // part of a voteBatch tx that is not mined yet
+	BallotIncluded  = "INCLUDED"  // VoteCast emitted for this voter
+	BallotRejected  = "REJECTED"  // skipped by the contract (double vote, unknown candidate)
+	BallotFailed    = "FAILED"    // the batch tx reverted, failed or could not be sent
+)
+
+// Ballot is one validated vote waiting for, or carried by, a voteBatch transaction.
+type Ballot struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ElectionAddress string             `bson:"election_address" json:"election_address"`
+	VoterEmail      string             `bson:"voter_email" json:"voter_email,omitempty"`
+	CandidateID     int64              `bson:"candidate_id" json:"candidate_id,omitempty"`
+	Status          string             `bson:"status" json:"status"`
+	Reason          string             `bson:"reason,omitempty" json:"reason,omitempty"`
+
+	// Active is set while the ballot is QUEUED, SUBMITTED or INCLUDED; the unique index
+	// over active ballots allows one per voter and election.
+	Active bool `bson:"active,omitempty" json:"-"`
+
+	TxID        string `bson:"tx_id,omitempty" json:"tx_id,omitempty"`
+	TxHash      string `bson:"tx_hash,omitempty" json:"tx_hash,omitempty"`
+	BlockNumber uint64 `bson:"block_number,omitempty" json:"block_number,omitempty"`
+
+	CreatedAt   time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time  `bson:"updated_at" json:"updated_at"`
+	SubmittedAt *time.Time `bson:"submitted_at,omitempty" json:"submitted_at,omitempty"`
+	IncludedAt  *time.Time `bson:"included_at,omitempty" json:"included_at,omitempty"`
+}
+
+// ConsistencyReport is the outcome of comparing one election's stored state with its contract.
+type ConsistencyReport struct {
+	ElectionAddress   string    `bson:"_id" json:"election_address"`
+	CheckedAt         time.Time `bson:"checked_at" json:"checked_at"`
+	Consistent        bool      `bson:"consistent" json:"consistent"`
+	OnChainCandidates uint64    `bson:"onchain_candidates" json:"onchain_candidates"`
+	DBCandidates      int       `bson:"db_candidates" json:"db_candidates"`
+	OnChainVotes      uint64    `bson:"onchain_votes" json:"onchain_votes"`
+	DBVotes           int64     `bson:"db_votes" json:"db_votes"`           // VOTE_CAST audit entries
+	VoteBaseline      uint64    `bson:"vote_baseline" json:"vote_baseline"` // votes cast before the indexer started
+	Repaired          []string  `bson:"repaired" json:"repaired"`
+	Orphans           []string  `bson:"orphans" json:"orphans"`             // DB candidates that never reached the chain
+	MissingInDB       []string  `bson:"missing_in_db" json:"missing_in_db"` // on-chain candidates without a DB row
+	Divergences       []string  `bson:"divergences" json:"divergences"`
+	Error             string    `bson:"error,omitempty" json:"error,omitempty"`
+}
+
+// OperatorKey is the account a chain's transactions were last signed with, so a key
+// change is noticed at startup.
+type OperatorKey struct {
+	Chain   string    `bson:"_id"`
+	Address string    `bson:"address"`
+	Kind    string    `bson:"kind"` // signer kind: "key", "keystore" or "remote"
+	Since   time.Time `bson:"since"`
+}
+
+// BundleArchive keeps what an election bundle import cannot put back into the live
+// collections. Manifest, Transactions and Anchor hold the bundle package's types.
+type BundleArchive struct {
+	ElectionAddress string      `bson:"_id"`
+	Manifest        interface{} `bson:"manifest"`
+	Signature       interface{} `bson:"signature"`
+	Transactions    interface{} `bson:"transactions"`
+	Anchor          interface{} `bson:"anchor"`
+	ImportedAt      time.Time   `bson:"imported_at"`
+	ImportedBy      string      `bson:"imported_by"`
+}
@@ -0,0 +1,1342 @@
+﻿package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"MAJOR-PROJECT/fieldcrypt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NewMongo binds the voters, election_metadata, candidates, audit_logs, otps, companies,
+// anchor_jobs, roster_imports, roster_import_rows, reset_history, students, ballots,
+// consistency_reports, operator_keys and election_bundles collections and creates their
+// indexes. With a cipher, VoterFields are sealed at rest, in voters, students and roster rows.
+func NewMongo(client *mongo.Client, dbName string, cipher *fieldcrypt.Cipher) *Repositories {
+	db := client.Database(dbName)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	voters := db.Collection("voters")
+	// Create fast lookup index for extreme concurrency
+	_, _ = voters.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.M{"email": 1}, Options: options.Index().SetUnique(true)})
+	_, _ = voters.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.M{"registrations.election_address": 1}})
+	_, _ = voters.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.M{fieldcrypt.IndexField("email"): 1}, Options: options.Index().SetUnique(true).SetSparse(true)},
+		{Keys: bson.M{fieldcrypt.IndexField("roll_no"): 1}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.M{fieldcrypt.IndexField("gender"): 1}, Options: options.Index().SetSparse(true)},
+	})
+	fmt.Println("[OK] Initialized voters collection with indexes")
+
+	candidates := db.Collection("candidates")
+	// Create fast lookup index for election lists
+	_, _ = candidates.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "electionAddress", Value: 1}, {Key: "email", Value: 1}}})
+	fmt.Println("[OK] Initialized candidates collection with indexes")
+
+	otps := db.Collection("otps")
+	_, _ = otps.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.M{"email": 1}})
+	fmt.Println("[OK] Initialized OTP collection with indexes")
+
+	audit := db.Collection("audit_logs")
+	_, _ = audit.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "election_address", Value: 1}, {Key: "action", Value: 1}}},
+		{Keys: bson.M{"event_id": 1}},
+	})
+	fmt.Println("[OK] Initialized audit_logs collection with indexes")
+
+	metadata := db.Collection("election_metadata")
+	// Fails until evote-migrate has merged addresses that differ only in case
+	if _, err := metadata.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.M{"election_address": 1}, Options: options.Index().SetUnique(true)}); err != nil {
+		fmt.Printf("[WARN] election_metadata address index not created (run evote-migrate up): %v\n", err)
+	}
+	fmt.Println("[OK] Initialized election_metadata collection with indexes")
+
+	companies := db.Collection("companies")
+	_, _ = companies.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetUnique(true)})
+	fmt.Println("[OK] Initialized companies collection with indexes")
+
+	anchorJobs := db.Collection("anchor_jobs")
+	_, _ = anchorJobs.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}})
+	fmt.Println("[OK] Initialized anchor_jobs collection with indexes")
+
+	rosterImports := db.Collection("roster_imports")
+	rosterRows := db.Collection("roster_import_rows")
+	_, _ = rosterImports.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.M{"status": 1}})
+	_, _ = rosterRows.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "import_id", Value: 1}, {Key: "row", Value: 1}}},
+		{Keys: bson.M{"created_at": 1}, Options: options.Index().SetExpireAfterSeconds(int32(RosterRowTTL / time.Second))},
+	})
+	fmt.Println("[OK] Initialized roster_imports collections with indexes")
+
+	resets := db.Collection("reset_history")
+	_, _ = resets.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "company", Value: 1}, {Key: "started_at", Value: -1}}},
+		{Keys: bson.M{"token_hash": 1}, Options: options.Index().SetSparse(true)},
+	})
+	fmt.Println("[OK] Initialized reset_history collection with indexes")
+
+	students := db.Collection("students")
+	_, _ = students.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.M{"email": 1}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.M{fieldcrypt.IndexField("email"): 1}, Options: options.Index().SetSparse(true)},
+	})
+	fmt.Println("[OK] Initialized Students collection with indexes")
+
+	ballots := db.Collection("ballots")
+	// Ballots written before the active flag existed get it before the unique index is built
+	_, _ = ballots.UpdateMany(ctx,
+		bson.M{"status": bson.M{"$in": []string{BallotQueued, BallotSubmitted, BallotIncluded}}, "active": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"active": true}})
+	if _, err := ballots.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "election_address", Value: 1}, {Key: "voter_email", Value: 1}},
+			Options: options.Index().SetName("active_ballot_per_voter").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"active": true}),
+		},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "tx_id", Value: 1}}},
+	}); err != nil {
+		fmt.Printf("[WARN] ballot indexes not created (duplicate active ballots?): %v\n", err)
+	}
+	fmt.Println("[OK] Initialized ballots collection")
+
+	return &Repositories{
+		Voters:     mongoVoters{voters, cipher},
+		Elections:  mongoElections{metadata},
+		Candidates: mongoCandidates{candidates},
+		Audit:      mongoAudit{audit},
+		OTPs:       mongoOTPs{otps},
+		Companies:  mongoCompanies{companies},
+		AnchorJobs: mongoAnchorJobs{anchorJobs},
+		Rosters:    mongoRosters{rosterImports, rosterRows, cipher},
+		Resets:     mongoResets{resets},
+		Students:   mongoStudents{students, cipher},
+		Ballots:    mongoBallots{ballots},
+		Reports:    mongoReports{db.Collection("consistency_reports")},
+		Operators:  mongoOperators{db.Collection("operator_keys")},
+		Bundles:    mongoBundles{db.Collection("election_bundles")},
+	}
+}
+
+func (u Update) doc() bson.M {
+	doc := bson.M{}
+	if len(u.Set) > 0 {
+		doc["$set"] = u.Set
+	}
+	if len(u.Unset) > 0 {
+		unset := bson.M{}
+		for _, f := range u.Unset {
+			unset[f] = ""
+		}
+		doc["$unset"] = unset
+	}
+	if len(u.Inc) > 0 {
+		doc["$inc"] = u.Inc
+	}
+	if len(u.Push) > 0 {
+		doc["$push"] = u.Push
+	}
+	return doc
+}
+
+// findOne decodes the first match into out, or returns ErrNotFound.
+func findOne(ctx context.Context, c *mongo.Collection, filter, out interface{}) error {
+	err := c.FindOne(ctx, filter).Decode(out)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// findAll decodes every match into out, a pointer to a slice.
+func findAll(ctx context.Context, c *mongo.Collection, filter, out interface{}, opts ...*options.FindOptions) error {
+	cursor, err := c.Find(ctx, filter, opts...)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	return cursor.All(ctx, out)
+}
+
+// mongoPage loads one page of filter's matches in sort order into docs (a *[]bson.M,
+// still sealed) and counts every match.
+func mongoPage(ctx context.Context, c *mongo.Collection, filter bson.M, s Sort, keys map[string]string, cursor string, limit int, docs *[]bson.M) (*PageInfo, error) {
+	s, err := validSort(s, keys)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := decodeCursor(cursor, s)
+	if err != nil {
+		return nil, err
+	}
+	total, err := c.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if cur != nil {
+		filter = bson.M{"$and": []bson.M{filter, cur.filter()}}
+	}
+	dir := 1
+	if s.Desc {
+		dir = -1
+	}
+	order := bson.D{{Key: s.Field, Value: dir}}
+	if s.Field != "_id" {
+		order = append(order, bson.E{Key: "_id", Value: dir})
+	}
+	info := &PageInfo{Total: total, Limit: pageLimit(limit)}
+	if err := findAll(ctx, c, filter, docs, options.Find().SetSort(order).SetLimit(int64(info.Limit+1))); err != nil {
+		return nil, err
+	}
+	if len(*docs) > info.Limit {
+		*docs = (*docs)[:info.Limit]
+		info.Next = encodeCursor((*docs)[info.Limit-1], s)
+	}
+	info.Count = len(*docs)
+	return info, nil
+}
+
+// timeRange is a [from, to) filter; nil when both ends are open.
+func timeRange(from, to time.Time) bson.M {
+	r := bson.M{}
+	if !from.IsZero() {
+		r["$gte"] = from
+	}
+	if !to.IsZero() {
+		r["$lt"] = to
+	}
+	if len(r) == 0 {
+		return nil
+	}
+	return r
+}
+
+func deleteMany(ctx context.Context, c *mongo.Collection, filter bson.M) (int64, error) {
+	res, err := c.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+// ----------------------------
+// VOTERS
+// ----------------------------
+
+type mongoVoters struct {
+	c      *mongo.Collection
+	cipher *fieldcrypt.Cipher // nil stores PII in clear text
+}
+
+// stored converts v to its stored form with VoterFields sealed, giving it an ID.
+func (r mongoVoters) stored(ctx context.Context, v *Voter) (bson.M, error) {
+	v.canonicalize()
+	doc, err := toDoc(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.cipher.SealDoc(ctx, doc, VoterFields); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func (r mongoVoters) find(ctx context.Context, filter interface{}) ([]Voter, error) {
+	var docs []bson.M
+	if err := findAll(ctx, r.c, filter, &docs); err != nil {
+		return nil, err
+	}
+	out := make([]Voter, 0, len(docs))
+	for _, doc := range docs {
+		var v Voter
+		if err := r.cipher.OpenDoc(ctx, doc); err != nil {
+			return nil, err
+		}
+		if err := fromDoc(doc, &v); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func (r mongoVoters) one(ctx context.Context, filter interface{}) (*Voter, error) {
+	var doc bson.M
+	if err := findOne(ctx, r.c, filter, &doc); err != nil {
+		return nil, err
+	}
+	if err := r.cipher.OpenDoc(ctx, doc); err != nil {
+		return nil, err
+	}
+	var v Voter
+	if err := fromDoc(doc, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// emailTaken stands in for the unique email index when emails are sealed: two
+// encryptions of one address differ, and the blind index is per key.
+func (r mongoVoters) emailTaken(ctx context.Context, email string, self primitive.ObjectID) error {
+	if r.cipher == nil {
+		return nil
+	}
+	v, err := r.GetByEmail(ctx, email)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return nil
+	case err != nil:
+		return err
+	case v.ID != self:
+		return ErrDuplicate
+	}
+	return nil
+}
+
+func (r mongoVoters) Create(ctx context.Context, v *Voter) error {
+	if err := r.emailTaken(ctx, v.Email, primitive.NilObjectID); err != nil {
+		return err
+	}
+	doc, err := r.stored(ctx, v)
+	if err != nil {
+		return err
+	}
+	_, err = r.c.InsertOne(ctx, doc)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	v.ID, _ = doc["_id"].(primitive.ObjectID)
+	return nil
+}
+
+func (r mongoVoters) Get(ctx context.Context, id primitive.ObjectID) (*Voter, error) {
+	return r.one(ctx, bson.M{"_id": id})
+}
+
+func (r mongoVoters) GetByEmail(ctx context.Context, email string) (*Voter, error) {
+	filter, err := r.cipher.Lookup(ctx, "email", email)
+	if err != nil {
+		return nil, err
+	}
+	return r.one(ctx, filter)
+}
+
+func (r mongoVoters) GetByRollNo(ctx context.Context, rollNo string) (*Voter, error) {
+	filter, err := r.cipher.Lookup(ctx, "roll_no", rollNo)
+	if err != nil {
+		return nil, err
+	}
+	return r.one(ctx, filter)
+}
+
+func (r mongoVoters) List(ctx context.Context, electionAddr string) ([]Voter, error) {
+	filter := bson.M{}
+	if electionAddr != "" {
+		filter = bson.M{"registrations.election_address": CanonicalAddress(electionAddr)}
+	}
+	return r.find(ctx, filter)
+}
+
+func (r mongoVoters) Page(ctx context.Context, q VoterQuery) (*VoterPage, error) {
+	if r.cipher != nil && q.Sort.Field != "" && VoterFields.Seals(q.Sort.Field) {
+		return nil, fmt.Errorf("%w: %s is encrypted and cannot be sorted on", ErrInvalidQuery, q.Sort.Field)
+	}
+	filter, err := r.pageFilter(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	var docs []bson.M
+	info, err := mongoPage(ctx, r.c, filter, q.Sort, VoterSortKeys, q.Cursor, q.Limit, &docs)
+	if err != nil {
+		return nil, err
+	}
+	page := &VoterPage{Voters: []Voter{}, PageInfo: *info}
+	for _, doc := range docs {
+		if err := r.cipher.OpenDoc(ctx, doc); err != nil {
+			return nil, err
+		}
+		var v Voter
+		if err := fromDoc(doc, &v); err != nil {
+			return nil, err
+		}
+		page.Voters = append(page.Voters, v)
+	}
+	return page, nil
+}
+
+// pageFilter translates q. Sealed fields are matched through their blind indexes.
+func (r mongoVoters) pageFilter(ctx context.Context, q VoterQuery) (bson.M, error) {
+	var and []bson.M
+	reg := bson.M{}
+	if q.ElectionAddress != "" {
+		reg["election_address"] = CanonicalAddress(q.ElectionAddress)
+	}
+	if q.Status != "" {
+		reg["status"] = exactFold(q.Status)
+	}
+	if at := timeRange(q.RegisteredFrom, q.RegisteredTo); at != nil {
+		reg["registered_at"] = at
+	}
+	if len(reg) > 0 {
+		and = append(and, bson.M{"registrations": bson.M{"$elemMatch": reg}})
+	}
+	if !q.IncludeErased {
+		and = append(and, bson.M{"erased_at": bson.M{"$exists": false}})
+	}
+	if q.Year != "" {
+		and = append(and, bson.M{"year": strings.TrimSpace(q.Year)})
+	}
+	if q.Gender != "" {
+		if r.cipher == nil {
+			and = append(and, bson.M{"gender": exactFold(q.Gender)})
+		} else {
+			f, err := r.cipher.Lookup(ctx, "gender", q.Gender)
+			if err != nil {
+				return nil, err
+			}
+			and = append(and, f)
+		}
+	}
+	if term := strings.TrimSpace(q.Search); term != "" {
+		if r.cipher == nil {
+			re := primitive.Regex{Pattern: regexp.QuoteMeta(term), Options: "i"}
+			and = append(and, bson.M{"$or": []bson.M{{"email": re}, {"full_name": re}, {"roll_no": re}}})
+		} else if strings.ContainsAny(term, " \t") {
+			// Names are sealed without a blind index, so a name search could only come back empty.
+			return nil, fmt.Errorf("%w: with encryption on, search takes a whole email address or roll number, not a name", ErrInvalidQuery)
+		} else {
+			byEmail, err := r.cipher.Lookup(ctx, "email", term)
+			if err != nil {
+				return nil, err
+			}
+			byRoll, err := r.cipher.Lookup(ctx, "roll_no", term)
+			if err != nil {
+				return nil, err
+			}
+			and = append(and, bson.M{"$or": []bson.M{byEmail, byRoll}})
+		}
+	}
+	if len(and) == 0 {
+		return bson.M{}, nil
+	}
+	return bson.M{"$and": and}, nil
+}
+
+// exactFold matches s exactly, ignoring case.
+func exactFold(s string) primitive.Regex {
+	return primitive.Regex{Pattern: "^" + regexp.QuoteMeta(strings.TrimSpace(s)) + "$", Options: "i"}
+}
+
+func (r mongoVoters) CountByElection(ctx context.Context, electionAddr string) (int64, error) {
+	return r.c.CountDocuments(ctx, bson.M{"registrations.election_address": CanonicalAddress(electionAddr)})
+}
+
+func (r mongoVoters) Update(ctx context.Context, id primitive.ObjectID, set map[string]interface{}) error {
+	if email, ok := set["email"].(string); ok {
+		if err := r.emailTaken(ctx, email, id); err != nil {
+			return err
+		}
+	}
+	doc := bson.M{}
+	for k, v := range set {
+		doc[k] = v
+	}
+	if err := r.cipher.SealDoc(ctx, doc, VoterFields); err != nil {
+		return err
+	}
+	res, err := r.c.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": doc})
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r mongoVoters) AddRegistration(ctx context.Context, id primitive.ObjectID, reg VoterRegistration) (bool, error) {
+	reg.ElectionAddress = CanonicalAddress(reg.ElectionAddress)
+	res, err := r.c.UpdateOne(ctx,
+		bson.M{"_id": id, "registrations.election_address": bson.M{"$ne": reg.ElectionAddress}},
+		bson.M{"$push": bson.M{"registrations": reg}})
+	if err != nil {
+		return false, err
+	}
+	if res.MatchedCount > 0 {
+		return true, nil
+	}
+	n, err := r.c.CountDocuments(ctx, bson.M{"_id": id})
+	if err != nil {
+		return false, err
+	}
+	if n == 0 {
+		return false, ErrNotFound
+	}
+	return false, nil
+}
+
+func (r mongoVoters) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.c.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (r mongoVoters) Erase(ctx context.Context, id primitive.ObjectID, pseudonym string, keep []VoterRegistration) error {
+	now := time.Now().UTC()
+	stub, err := r.stored(ctx, &Voter{ID: id, Email: pseudonym, Registrations: keep, ErasedAt: &now})
+	if err != nil {
+		return err
+	}
+	res, err := r.c.ReplaceOne(ctx, bson.M{"_id": id}, stub)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r mongoVoters) RegionCounts(ctx context.Context, electionAddr string) ([]RegionCount, error) {
+	// Pipeline: Match Election in Registrations -> Group by Address -> Count
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "registrations.election_address", Value: CanonicalAddress(electionAddr)}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$address"},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}}}}, // highest first
+	}
+	cursor, err := r.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var out []RegionCount
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r mongoVoters) RemoveRegistrations(ctx context.Context, electionAddr string) (int64, error) {
+	addr := CanonicalAddress(electionAddr)
+	res, err := r.c.UpdateMany(ctx, bson.M{"registrations.election_address": addr},
+		bson.M{"$pull": bson.M{"registrations": bson.M{"election_address": addr}}})
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// ----------------------------
+// ELECTIONS
+// ----------------------------
+
+type mongoElections struct{ c *mongo.Collection }
+
+func (r mongoElections) Get(ctx context.Context, addr string) (*ElectionMetadata, error) {
+	var out ElectionMetadata
+	if err := findOne(ctx, r.c, bson.M{"election_address": CanonicalAddress(addr)}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r mongoElections) List(ctx context.Context, f ElectionFilter) ([]ElectionMetadata, error) {
+	var out []ElectionMetadata
+	err := findAll(ctx, r.c, electionFilter(f), &out, options.Find().SetSort(bson.M{"start_date": -1}))
+	return out, err
+}
+
+func (r mongoElections) Page(ctx context.Context, q ElectionQuery) (*ElectionPage, error) {
+	filter := electionFilter(q.ElectionFilter)
+	if term := strings.TrimSpace(q.Search); term != "" {
+		filter["election_name"] = primitive.Regex{Pattern: regexp.QuoteMeta(term), Options: "i"}
+	}
+	if start := timeRange(q.StartFrom, q.StartTo); start != nil {
+		filter["start_date"] = start
+	}
+	var docs []bson.M
+	info, err := mongoPage(ctx, r.c, filter, q.Sort, ElectionSortKeys, q.Cursor, q.Limit, &docs)
+	if err != nil {
+		return nil, err
+	}
+	page := &ElectionPage{Elections: []ElectionMetadata{}, PageInfo: *info}
+	for _, doc := range docs {
+		var m ElectionMetadata
+		if err := fromDoc(doc, &m); err != nil {
+			return nil, err
+		}
+		page.Elections = append(page.Elections, m)
+	}
+	return page, nil
+}
+
+func electionFilter(f ElectionFilter) bson.M {
+	filter := bson.M{}
+	switch {
+	case f.Status != "":
+		filter["status"] = f.Status
+	case f.ExcludeStatus != "":
+		filter["status"] = bson.M{"$ne": f.ExcludeStatus}
+	}
+	if f.MissingCodeHash {
+		filter["code_hash"] = bson.M{"$in": bson.A{nil, ""}}
+	}
+	if f.CompanyEmail != "" {
+		filter["company_email"] = strings.ToLower(strings.TrimSpace(f.CompanyEmail))
+	}
+	return filter
+}
+
+func (r mongoElections) Create(ctx context.Context, m *ElectionMetadata) error {
+	m.canonicalize()
+	res, err := r.c.InsertOne(ctx, m)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	if oid, ok := res.InsertedID.(primitive.ObjectID); ok {
+		m.ID = oid
+	}
+	return nil
+}
+
+func (r mongoElections) Update(ctx context.Context, addr string, u Update) error {
+	doc := u.doc()
+	if len(doc) == 0 {
+		return nil
+	}
+	_, err := r.c.UpdateOne(ctx, bson.M{"election_address": CanonicalAddress(addr)}, doc)
+	return err
+}
+
+func (r mongoElections) Upsert(ctx context.Context, addr string, u Update) error {
+	addr = CanonicalAddress(addr)
+	doc := u.doc()
+	doc["$setOnInsert"] = bson.M{"election_address": addr}
+	_, err := r.c.UpdateOne(ctx, bson.M{"election_address": addr}, doc, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r mongoElections) Delete(ctx context.Context, addr string) error {
+	_, err := r.c.DeleteOne(ctx, bson.M{"election_address": CanonicalAddress(addr)})
+	return err
+}
+
+// ----------------------------
+// CANDIDATES
+// ----------------------------
+
+type mongoCandidates struct{ c *mongo.Collection }
+
+func (r mongoCandidates) Create(ctx context.Context, c *CandidateDocument) error {
+	c.canonicalize()
+	_, err := r.c.InsertOne(ctx, c)
+	return err
+}
+
+func (r mongoCandidates) List(ctx context.Context, electionAddr string) ([]CandidateDocument, error) {
+	var out []CandidateDocument
+	err := findAll(ctx, r.c, bson.M{"electionAddress": CanonicalAddress(electionAddr)}, &out)
+	return out, err
+}
+
+func (r mongoCandidates) Manifestos(ctx context.Context, electionAddr string) (map[string]string, error) {
+	filter := bson.M{
+		"electionAddress": CanonicalAddress(electionAddr),
+		"manifestoUrl":    bson.M{"$nin": bson.A{nil, ""}},
+	}
+	var docs []CandidateDocument
+	if err := findAll(ctx, r.c, filter, &docs, options.Find().SetProjection(bson.M{"email": 1, "manifestoUrl": 1})); err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(docs))
+	for _, d := range docs {
+		out[strings.ToLower(d.Email)] = d.ManifestoUrl
+	}
+	return out, nil
+}
+
+func (r mongoCandidates) ElectionsByPrefix(ctx context.Context, prefix string, limit int) ([]string, error) {
+	filter := bson.M{"electionAddress": bson.M{"$regex": "^" + regexp.QuoteMeta(prefix), "$options": "i"}}
+	var docs []CandidateDocument
+	if err := findAll(ctx, r.c, filter, &docs, options.Find().SetLimit(int64(limit))); err != nil {
+		return nil, err
+	}
+	return distinctElections(docs), nil
+}
+
+func (r mongoCandidates) CountActive(ctx context.Context, electionAddr string) (int64, error) {
+	// Rows the reconciler proved never reached the chain are not candidates
+	return r.c.CountDocuments(ctx, bson.M{
+		"electionAddress": CanonicalAddress(electionAddr),
+		"status":          bson.M{"$nin": []string{"failed", "reverted", "orphaned"}},
+	})
+}
+
+func (r mongoCandidates) SetStatus(ctx context.Context, m CandidateMatch, status, txHash string) error {
+	filter := bson.M{"txId": m.TxID}
+	if m.TxID == "" {
+		filter = bson.M{"electionAddress": CanonicalAddress(m.ElectionAddress), "email": m.Email}
+		if m.Status != "" {
+			filter["status"] = m.Status
+		}
+	}
+	set := bson.M{"status": status, "updatedAt": time.Now().UTC()}
+	if txHash != "" {
+		set["txHash"] = txHash
+	}
+	_, err := r.c.UpdateMany(ctx, filter, bson.M{"$set": set})
+	return err
+}
+
+func (r mongoCandidates) DeleteByElection(ctx context.Context, electionAddr string) (int64, error) {
+	return deleteMany(ctx, r.c, bson.M{"electionAddress": CanonicalAddress(electionAddr)})
+}
+
+// distinctElections returns the non-empty election addresses of docs in first-seen order.
+func distinctElections(docs []CandidateDocument) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, d := range docs {
+		if d.ElectionAddress != "" && !seen[d.ElectionAddress] {
+			seen[d.ElectionAddress] = true
+			out = append(out, d.ElectionAddress)
+		}
+	}
+	return out
+}
+
+// ----------------------------
+// AUDIT LOGS
+// ----------------------------
+
+type mongoAudit struct{ c *mongo.Collection }
+
+func (r mongoAudit) Insert(ctx context.Context, e *AuditLog) error {
+	e.canonicalize()
+	res, err := r.c.InsertOne(ctx, e)
+	if err != nil {
+		return err
+	}
+	if oid, ok := res.InsertedID.(primitive.ObjectID); ok {
+		e.ID = oid
+	}
+	return nil
+}
+
+func (r mongoAudit) InsertEvent(ctx context.Context, e *AuditLog) error {
+	e.canonicalize()
+	_, err := r.c.UpdateOne(ctx, bson.M{"event_id": e.EventID}, bson.M{"$setOnInsert": e}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r mongoAudit) DeleteEvent(ctx context.Context, eventID string) error {
+	_, err := r.c.DeleteMany(ctx, bson.M{"event_id": eventID})
+	return err
+}
+
+func (r mongoAudit) List(ctx context.Context, electionAddr string) ([]AuditLog, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}}) // Oldest first for timeline
+	var out []AuditLog
+	err := findAll(ctx, r.c, bson.M{"election_address": CanonicalAddress(electionAddr)}, &out, opts)
+	return out, err
+}
+
+func (r mongoAudit) CountAction(ctx context.Context, electionAddr, action string) (int64, error) {
+	return r.c.CountDocuments(ctx, bson.M{"election_address": CanonicalAddress(electionAddr), "action": action})
+}
+
+// mentioning returns the entries matching filter that mention email, oldest first. The
+// query only narrows by substring; Mentions makes the final call.
+func (r mongoAudit) mentioning(ctx context.Context, filter bson.M, email string) ([]AuditLog, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil, nil
+	}
+	re := primitive.Regex{Pattern: regexp.QuoteMeta(email), Options: "i"}
+	filter["$or"] = []bson.M{{"actor": re}, {"details": re}}
+	var docs []AuditLog
+	if err := findAll(ctx, r.c, filter, &docs, options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})); err != nil {
+		return nil, err
+	}
+	out := docs[:0]
+	for i := range docs {
+		if docs[i].mentionsEmail(email) {
+			out = append(out, docs[i])
+		}
+	}
+	return out, nil
+}
+
+func (r mongoAudit) ListMentioning(ctx context.Context, email string) ([]AuditLog, error) {
+	return r.mentioning(ctx, bson.M{}, email)
+}
+
+func (r mongoAudit) Pseudonymize(ctx context.Context, electionAddr, email, pseudonym string) (int64, error) {
+	docs, err := r.mentioning(ctx, bson.M{"election_address": CanonicalAddress(electionAddr)}, email)
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	for _, e := range docs {
+		set := bson.M{"actor": scrubEmail(e.Actor, email, pseudonym), "details": scrubEmail(e.Details, email, pseudonym)}
+		if _, err := r.c.UpdateOne(ctx, bson.M{"_id": e.ID}, bson.M{"$set": set}); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+func (r mongoAudit) DeleteMentioning(ctx context.Context, electionAddr, email string) (int64, error) {
+	docs, err := r.mentioning(ctx, bson.M{"election_address": CanonicalAddress(electionAddr)}, email)
+	if err != nil || len(docs) == 0 {
+		return 0, err
+	}
+	ids := make([]primitive.ObjectID, len(docs))
+	for i, e := range docs {
+		ids[i] = e.ID
+	}
+	res, err := r.c.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+func (r mongoAudit) DeleteByElection(ctx context.Context, electionAddr string) (int64, error) {
+	return deleteMany(ctx, r.c, bson.M{"election_address": CanonicalAddress(electionAddr)})
+}
+
+// ----------------------------
+// OTPS
+// ----------------------------
+
+type mongoOTPs struct{ c *mongo.Collection }
+
+func (r mongoOTPs) Get(ctx context.Context, email string) (*OTP, error) {
+	var out OTP
+	if err := findOne(ctx, r.c, bson.M{"email": email}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r mongoOTPs) Replace(ctx context.Context, o *OTP) error {
+	o.canonicalize()
+	// remove older OTP entries
+	if _, err := r.c.DeleteMany(ctx, bson.M{"email": o.Email}); err != nil {
+		return err
+	}
+	_, err := r.c.InsertOne(ctx, o)
+	return err
+}
+
+func (r mongoOTPs) Delete(ctx context.Context, email string) error {
+	_, err := r.c.DeleteMany(ctx, bson.M{"email": email})
+	return err
+}
+
+func (r mongoOTPs) CountByElection(ctx context.Context, electionAddr string) (int64, error) {
+	return r.c.CountDocuments(ctx, bson.M{"election_address": CanonicalAddress(electionAddr)})
+}
+
+func (r mongoOTPs) DeleteByElection(ctx context.Context, electionAddr string) (int64, error) {
+	return deleteMany(ctx, r.c, bson.M{"election_address": CanonicalAddress(electionAddr)})
+}
+
+// ----------------------------
+// COMPANIES
+// ----------------------------
+
+type mongoCompanies struct{ c *mongo.Collection }
+
+func (r mongoCompanies) Create(ctx context.Context, c *Company) error {
+	res, err := r.c.InsertOne(ctx, c)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	if oid, ok := res.InsertedID.(primitive.ObjectID); ok {
+		c.ID = oid
+	}
+	return nil
+}
+
+// GetByEmail tries the exact (indexed) email first; accounts registered before emails
+// were lower-cased are found by the case-insensitive match.
+func (r mongoCompanies) GetByEmail(ctx context.Context, email string) (*Company, error) {
+	var out Company
+	err := findOne(ctx, r.c, bson.M{"email": email}, &out)
+	if errors.Is(err, ErrNotFound) {
+		err = findOne(ctx, r.c, bson.M{"email": exactFold(email)}, &out)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r mongoCompanies) List(ctx context.Context) ([]Company, error) {
+	var out []Company
+	err := findAll(ctx, r.c, bson.M{}, &out)
+	return out, err
+}
+
+func (r mongoCompanies) Update(ctx context.Context, email string, u Update) error {
+	c, err := r.GetByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	res, err := r.c.UpdateOne(ctx, bson.M{"_id": c.ID}, u.doc())
+	if err == nil && res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return err
+}
+
+// ----------------------------
+// ANCHOR JOBS
+// ----------------------------
+
+type mongoAnchorJobs struct{ c *mongo.Collection }
+
+func (r mongoAnchorJobs) Get(ctx context.Context, electionAddr string) (*AnchorJob, error) {
+	var out AnchorJob
+	if err := findOne(ctx, r.c, bson.M{"_id": CanonicalAddress(electionAddr)}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r mongoAnchorJobs) Queue(ctx context.Context, electionAddr, closeTxID, requestedBy string, requiredConfirmations uint64) error {
+	now := time.Now().UTC()
+	_, err := r.c.UpdateOne(ctx,
+		bson.M{"_id": CanonicalAddress(electionAddr)},
+		bson.M{
+			"$set": bson.M{
+				"status":                 AnchorQueued,
+				"attempts":               0,
+				"next_attempt_at":        now,
+				"requested_by":           requestedBy,
+				"close_tx_id":            closeTxID,
+				"confirmations":          uint64(0),
+				"required_confirmations": requiredConfirmations,
+				"updated_at":             now,
+			},
+			"$unset":       bson.M{"last_error": "", "tx_id": "", "tx_hash": "", "block_number": "", "submitted_at": "", "confirmed_at": ""},
+			"$setOnInsert": bson.M{"created_at": now},
+		},
+		options.Update().SetUpsert(true))
+	return err
+}
+
+func (r mongoAnchorJobs) Update(ctx context.Context, electionAddr string, u Update) error {
+	doc := u.doc()
+	if len(doc) == 0 {
+		return nil
+	}
+	_, err := r.c.UpdateOne(ctx, bson.M{"_id": CanonicalAddress(electionAddr)}, doc)
+	return err
+}
+
+func (r mongoAnchorJobs) Due(ctx context.Context, now time.Time) ([]AnchorJob, error) {
+	var out []AnchorJob
+	err := findAll(ctx, r.c, bson.M{"$or": []bson.M{
+		{"status": AnchorQueued, "next_attempt_at": bson.M{"$lte": now}},
+		{"status": AnchorSubmitted},
+	}}, &out)
+	return out, err
+}
+
+// ----------------------------
+// ROSTER IMPORTS
+// ----------------------------
+
+type mongoRosters struct {
+	imports, rows *mongo.Collection
+	cipher        *fieldcrypt.Cipher
+}
+
+func (r mongoRosters) Create(ctx context.Context, job *RosterImport, rows []RosterRow) error {
+	docs := make([]interface{}, 0, len(rows))
+	for i := range rows {
+		doc, err := toDoc(rows[i])
+		if err != nil {
+			return err
+		}
+		if err := r.cipher.SealDoc(ctx, doc, VoterFields); err != nil {
+			return err
+		}
+		docs = append(docs, doc)
+	}
+	for len(docs) > 0 {
+		n := len(docs)
+		if n > 1000 {
+			n = 1000
+		}
+		if _, err := r.rows.InsertMany(ctx, docs[:n]); err != nil {
+			return err
+		}
+		docs = docs[n:]
+	}
+	_, err := r.imports.InsertOne(ctx, job)
+	return err
+}
+
+func (r mongoRosters) Get(ctx context.Context, id primitive.ObjectID) (*RosterImport, error) {
+	var out RosterImport
+	if err := findOne(ctx, r.imports, bson.M{"_id": id}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r mongoRosters) Update(ctx context.Context, id primitive.ObjectID, u Update) error {
+	doc := u.doc()
+	if len(doc) == 0 {
+		return nil
+	}
+	_, err := r.imports.UpdateOne(ctx, bson.M{"_id": id}, doc)
+	return err
+}
+
+// transition moves the first import matching filter to status and returns it.
+func (r mongoRosters) transition(ctx context.Context, filter bson.M, set bson.M) (*RosterImport, error) {
+	var out RosterImport
+	err := r.imports.FindOneAndUpdate(ctx, filter, bson.M{"$set": set},
+		options.FindOneAndUpdate().SetSort(bson.M{"updated_at": 1}).SetReturnDocument(options.After)).Decode(&out)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r mongoRosters) Queue(ctx context.Context, id primitive.ObjectID, since time.Time) (*RosterImport, error) {
+	return r.transition(ctx,
+		bson.M{"_id": id, "status": RosterPreview, "created_at": bson.M{"$gt": since}},
+		bson.M{"status": RosterQueued, "updated_at": time.Now().UTC()})
+}
+
+func (r mongoRosters) Claim(ctx context.Context) (*RosterImport, error) {
+	now := time.Now().UTC()
+	return r.transition(ctx, bson.M{"status": RosterQueued},
+		bson.M{"status": RosterRunning, "started_at": now, "updated_at": now})
+}
+
+func (r mongoRosters) Requeue(ctx context.Context) (int64, error) {
+	res, err := r.imports.UpdateMany(ctx, bson.M{"status": RosterRunning}, bson.M{"$set": bson.M{"status": RosterQueued}})
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+func (r mongoRosters) Rows(ctx context.Context, q RosterRowQuery) ([]RosterRow, error) {
+	filter := bson.M{"import_id": q.ImportID}
+	if q.After > 0 {
+		filter["row"] = bson.M{"$gt": q.After}
+	}
+	if q.Pending {
+		filter["result"] = bson.M{"$exists": false}
+	}
+	if q.Problems {
+		filter["$or"] = []bson.M{{"errors.0": bson.M{"$exists": true}}, {"result": RosterRowFailed}}
+	}
+	opts := options.Find().SetSort(bson.M{"row": 1})
+	if q.Limit > 0 {
+		opts.SetLimit(int64(q.Limit))
+	}
+	var docs []bson.M
+	if err := findAll(ctx, r.rows, filter, &docs, opts); err != nil {
+		return nil, err
+	}
+	rows := make([]RosterRow, 0, len(docs))
+	for _, doc := range docs {
+		if err := r.cipher.OpenDoc(ctx, doc); err != nil {
+			return nil, err
+		}
+		var row RosterRow
+		if err := fromDoc(doc, &row); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (r mongoRosters) UpdateRow(ctx context.Context, id primitive.ObjectID, set map[string]interface{}) error {
+	_, err := r.rows.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+	return err
+}
+
+// ----------------------------
+// COMPANY RESETS
+// ----------------------------
+
+type mongoResets struct{ c *mongo.Collection }
+
+func (r mongoResets) SavePreview(ctx context.Context, rec *ResetRecord) error {
+	if _, err := r.c.DeleteMany(ctx, bson.M{"company": rec.Company, "status": ResetPreview}); err != nil {
+		return err
+	}
+	rec.ID = primitive.NewObjectID()
+	_, err := r.c.InsertOne(ctx, rec)
+	return err
+}
+
+func (r mongoResets) Start(ctx context.Context, company, tokenHash, remoteAddr string, now time.Time) (*ResetRecord, error) {
+	var out ResetRecord
+	err := r.c.FindOneAndUpdate(ctx,
+		bson.M{"company": company, "status": ResetPreview, "token_hash": tokenHash, "expires_at": bson.M{"$gt": now}},
+		bson.M{
+			"$set":   bson.M{"status": ResetStarted, "started_at": now, "remote_addr": remoteAddr},
+			"$unset": bson.M{"token_hash": "", "expires_at": ""},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r mongoResets) Update(ctx context.Context, id primitive.ObjectID, u Update) error {
+	doc := u.doc()
+	if len(doc) == 0 {
+		return nil
+	}
+	_, err := r.c.UpdateOne(ctx, bson.M{"_id": id}, doc)
+	return err
+}
+
+func (r mongoResets) List(ctx context.Context, company string, limit int) ([]ResetRecord, error) {
+	out := []ResetRecord{}
+	err := findAll(ctx, r.c, bson.M{"company": company, "status": bson.M{"$ne": ResetPreview}}, &out,
+		options.Find().SetSort(bson.D{{Key: "started_at", Value: -1}}).SetLimit(int64(limit)))
+	return out, err
+}
+
+// ----------------------------
+// STUDENTS
+// ----------------------------
+
+type mongoStudents struct {
+	c      *mongo.Collection
+	cipher *fieldcrypt.Cipher
+}
+
+func (r mongoStudents) GetByEmail(ctx context.Context, email string) (*Student, error) {
+	filter, err := r.cipher.Lookup(ctx, "email", email)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	if err := findOne(ctx, r.c, filter, &doc); err != nil {
+		return nil, err
+	}
+	if err := r.cipher.OpenDoc(ctx, doc); err != nil {
+		return nil, err
+	}
+	var s Student
+	if err := fromDoc(doc, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r mongoStudents) Create(ctx context.Context, s *Student) error {
+	// Sealed emails differ per encryption, so the unique index cannot catch a repeat
+	if r.cipher != nil {
+		if _, err := r.GetByEmail(ctx, s.Email); err == nil {
+			return ErrDuplicate
+		} else if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+	}
+	doc, err := toDoc(s)
+	if err != nil {
+		return err
+	}
+	if err := r.cipher.SealDoc(ctx, doc, StudentFields); err != nil {
+		return err
+	}
+	_, err = r.c.InsertOne(ctx, doc)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	s.ID, _ = doc["_id"].(primitive.ObjectID)
+	return nil
+}
+
+func (r mongoStudents) Update(ctx context.Context, email string, set map[string]interface{}) error {
+	filter, err := r.cipher.Lookup(ctx, "email", email)
+	if err != nil {
+		return err
+	}
+	doc := bson.M{}
+	for k, v := range set {
+		doc[k] = v
+	}
+	if err := r.cipher.SealDoc(ctx, doc, StudentFields); err != nil {
+		return err
+	}
+	res, err := r.c.UpdateOne(ctx, filter, bson.M{"$set": doc})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ----------------------------
+// BALLOTS
+// ----------------------------
+
+type mongoBallots struct{ c *mongo.Collection }
+
+func (q BallotQuery) filter() bson.M {
+	f := bson.M{}
+	if len(q.IDs) > 0 {
+		f["_id"] = bson.M{"$in": q.IDs}
+	}
+	if q.Election != "" {
+		f["election_address"] = q.Election
+	}
+	if q.Voter != nil {
+		f["voter_email"] = primitive.Regex{Pattern: q.Voter.String()}
+	}
+	if len(q.Statuses) > 0 {
+		f["status"] = bson.M{"$in": q.Statuses}
+	}
+	return f
+}
+
+func (r mongoBallots) Insert(ctx context.Context, bl *Ballot) error {
+	bl.ID = primitive.NewObjectID()
+	_, err := r.c.InsertOne(ctx, bl)
+	if mongo.IsDuplicateKeyError(err) {
+		bl.ID = primitive.NilObjectID
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r mongoBallots) Get(ctx context.Context, id primitive.ObjectID) (*Ballot, error) {
+	var bl Ballot
+	if err := findOne(ctx, r.c, bson.M{"_id": id}, &bl); err != nil {
+		return nil, err
+	}
+	return &bl, nil
+}
+
+func (r mongoBallots) Find(ctx context.Context, q BallotQuery) ([]Ballot, error) {
+	out := []Ballot{}
+	err := findAll(ctx, r.c, q.filter(), &out, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	return out, err
+}
+
+func (r mongoBallots) Count(ctx context.Context, q BallotQuery) (int64, error) {
+	return r.c.CountDocuments(ctx, q.filter())
+}
+
+func (r mongoBallots) Update(ctx context.Context, q BallotQuery, u Update) (int64, error) {
+	doc := u.doc()
+	if len(doc) == 0 {
+		return 0, nil
+	}
+	res, err := r.c.UpdateMany(ctx, q.filter(), doc)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+func (r mongoBallots) Delete(ctx context.Context, q BallotQuery) (int64, error) {
+	res, err := r.c.DeleteMany(ctx, q.filter())
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+// ----------------------------
+// CONSISTENCY REPORTS
+// ----------------------------
+
+type mongoReports struct{ c *mongo.Collection }
+
+func (r mongoReports) Get(ctx context.Context, electionAddr string) (*ConsistencyReport, error) {
+	var out ConsistencyReport
+	if err := findOne(ctx, r.c, bson.M{"_id": CanonicalAddress(electionAddr)}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r mongoReports) Save(ctx context.Context, rep *ConsistencyReport) error {
+	rep.ElectionAddress = CanonicalAddress(rep.ElectionAddress)
+	_, err := r.c.ReplaceOne(ctx, bson.M{"_id": rep.ElectionAddress}, rep, options.Replace().SetUpsert(true))
+	return err
+}
+
+// ----------------------------
+// OPERATOR KEYS
+// ----------------------------
+
+type mongoOperators struct{ c *mongo.Collection }
+
+func (r mongoOperators) Get(ctx context.Context, chain string) (*OperatorKey, error) {
+	var out OperatorKey
+	if err := findOne(ctx, r.c, bson.M{"_id": chain}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r mongoOperators) Save(ctx context.Context, k *OperatorKey) error {
+	_, err := r.c.ReplaceOne(ctx, bson.M{"_id": k.Chain}, k, options.Replace().SetUpsert(true))
+	return err
+}
+
+// ----------------------------
+// BUNDLE ARCHIVES
+// ----------------------------
+
+type mongoBundles struct{ c *mongo.Collection }
+
+func (r mongoBundles) Save(ctx context.Context, a *BundleArchive) error {
+	a.ElectionAddress = CanonicalAddress(a.ElectionAddress)
+	_, err := r.c.ReplaceOne(ctx, bson.M{"_id": a.ElectionAddress}, a, options.Replace().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,57 @@
+﻿package repository
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Mentions returns the [start, end) byte offsets of every occurrence of email in s,
+// ignoring case. An occurrence that is part of a longer address (bob@x.com inside
+// jbob@x.com or bob@x.com.au) does not count.
+func Mentions(s, email string) [][]int {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil
+	}
+	var out [][]int
+	for _, m := range regexp.MustCompile("(?i)"+regexp.QuoteMeta(email)).FindAllStringIndex(s, -1) {
+		if m[0] > 0 && isLocalChar(s[m[0]-1]) {
+			continue
+		}
+		if rest := s[m[1]:]; rest != "" && (isDomainChar(rest[0]) || rest[0] == '.' && len(rest) > 1 && isDomainChar(rest[1])) {
+			continue
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// scrubEmail replaces every mention of email in s with pseudonym.
+func scrubEmail(s, email, pseudonym string) string {
+	ms := Mentions(s, email)
+	if len(ms) == 0 {
+		return s
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range ms {
+		b.WriteString(s[last:m[0]])
+		b.WriteString(pseudonym)
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// mentionsEmail reports whether an audit entry names email in its actor or details.
+func (e *AuditLog) mentionsEmail(email string) bool {
+	return len(Mentions(e.Actor, email)) > 0 || len(Mentions(e.Details, email)) > 0
+}
+
+func isDomainChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-'
+}
+
+func isLocalChar(c byte) bool {
+	return isDomainChar(c) || strings.IndexByte("._%+", c) >= 0
+}
@@ -0,0 +1,250 @@
+﻿package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidQuery is returned by the Page methods for an unknown sort key, a cursor from
+// another query, or a filter the storage cannot evaluate (see VoterQuery).
+var ErrInvalidQuery = errors.New("repository: invalid query")
+
+// Page limits.
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 500
+)
+
+// Sort orders a page by one stored field; ties are broken by _id in the same direction.
+type Sort struct {
+	Field string // bson field name
+	Desc  bool
+}
+
+// PageInfo describes one page. Next is the cursor of the following page, "" on the last.
+type PageInfo struct {
+	Total int64  `json:"total"` // matches of the filter, across all pages
+	Count int    `json:"count"`
+	Limit int    `json:"limit"`
+	Next  string `json:"next_cursor,omitempty"`
+}
+
+// VoterQuery selects a page of voters for VoterRepo.Page. Registration filters (Status,
+// RegisteredFrom/To) apply to the ElectionAddress registration, or to any registration
+// when no election is given.
+//
+// With field encryption on, Search and Gender match the blind indexes: Search is an exact,
+// case-insensitive email or roll number, not a substring or a name. A Search with spaces,
+// or a sort by a sealed field, is an ErrInvalidQuery.
+type VoterQuery struct {
+	ElectionAddress string
+	Status          string
+	Year            string
+	Gender          string
+	RegisteredFrom  time.Time
+	RegisteredTo    time.Time
+	Search          string // email, full name or roll number
+	IncludeErased   bool   // also list the stubs left by erasures
+	Sort            Sort
+	Cursor          string
+	Limit           int
+}
+
+// VoterPage is one page of voters.
+type VoterPage struct {
+	Voters []Voter
+	PageInfo
+}
+
+// ElectionQuery selects a page of election metadata for ElectionRepo.Page.
+type ElectionQuery struct {
+	ElectionFilter
+	Search    string // election name, case-insensitive substring
+	StartFrom time.Time
+	StartTo   time.Time
+	Sort      Sort
+	Cursor    string
+	Limit     int
+}
+
+// ElectionPage is one page of elections.
+type ElectionPage struct {
+	Elections []ElectionMetadata
+	PageInfo
+}
+
+// Sort keys accepted by the API, mapped to stored fields.
+var (
+	VoterSortKeys    = map[string]string{"id": "_id", "email": "email", "full_name": "full_name", "roll_no": "roll_no", "year": "year"}
+	ElectionSortKeys = map[string]string{"start_date": "start_date", "end_date": "end_date", "election_name": "election_name", "status": "status"}
+)
+
+// ParseSort reads "key" or "-key" (descending) against keys; "" gives def.
+func ParseSort(s string, keys map[string]string, def Sort) (Sort, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return def, nil
+	}
+	desc := strings.HasPrefix(s, "-")
+	field, ok := keys[strings.TrimPrefix(s, "-")]
+	if !ok {
+		return Sort{}, fmt.Errorf("%w: unknown sort key %q", ErrInvalidQuery, strings.TrimPrefix(s, "-"))
+	}
+	return Sort{Field: field, Desc: desc}, nil
+}
+
+// validSort checks s against keys; the zero Sort means _id ascending.
+func validSort(s Sort, keys map[string]string) (Sort, error) {
+	if s.Field == "" {
+		return Sort{Field: "_id", Desc: s.Desc}, nil
+	}
+	if s.Field == "_id" {
+		return s, nil
+	}
+	for _, field := range keys {
+		if field == s.Field {
+			return s, nil
+		}
+	}
+	return Sort{}, fmt.Errorf("%w: cannot sort by %q", ErrInvalidQuery, s.Field)
+}
+
+// pageLimit clamps a requested page size.
+func pageLimit(n int) int {
+	switch {
+	case n <= 0:
+		return DefaultPageLimit
+	case n > MaxPageLimit:
+		return MaxPageLimit
+	}
+	return n
+}
+
+// pageCursor is the position after the last document of a page: its sort value and _id,
+// with the sort it belongs to so a cursor is not reused with another order.
+type pageCursor struct {
+	Field string             `bson:"f"`
+	Desc  bool               `bson:"d"`
+	Value interface{}        `bson:"v"`
+	ID    primitive.ObjectID `bson:"i"`
+}
+
+func encodeCursor(doc bson.M, s Sort) string {
+	id, _ := doc["_id"].(primitive.ObjectID)
+	raw, err := bson.Marshal(pageCursor{Field: s.Field, Desc: s.Desc, Value: doc[s.Field], ID: id})
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor returns nil for "" and ErrInvalidQuery for anything not made by
+// encodeCursor for the same sort.
+func decodeCursor(s string, sort Sort) (*pageCursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	var c pageCursor
+	if err == nil {
+		err = bson.Unmarshal(raw, &c)
+	}
+	if err != nil || c.Field != sort.Field || c.Desc != sort.Desc {
+		return nil, fmt.Errorf("%w: cursor does not belong to this query", ErrInvalidQuery)
+	}
+	return &c, nil
+}
+
+// filter matches the documents after c in Mongo's sort order, where a missing or null
+// value sorts before any other.
+func (c *pageCursor) filter() bson.M {
+	f := c.Field
+	if c.Field == "_id" {
+		if c.Desc {
+			return bson.M{"_id": bson.M{"$lt": c.ID}}
+		}
+		return bson.M{"_id": bson.M{"$gt": c.ID}}
+	}
+	idAfter := bson.M{"$gt": c.ID}
+	if c.Desc {
+		idAfter = bson.M{"$lt": c.ID}
+	}
+	if c.Value == nil {
+		if c.Desc {
+			return bson.M{f: nil, "_id": idAfter}
+		}
+		return bson.M{"$or": []bson.M{{f: nil, "_id": idAfter}, {f: bson.M{"$ne": nil}}}}
+	}
+	if c.Desc {
+		return bson.M{"$or": []bson.M{{f: bson.M{"$lt": c.Value}}, {f: c.Value, "_id": idAfter}, {f: nil}}}
+	}
+	return bson.M{"$or": []bson.M{{f: bson.M{"$gt": c.Value}}, {f: c.Value, "_id": idAfter}}}
+}
+
+// after reports whether doc comes after c; the in-memory twin of filter.
+func (c *pageCursor) after(doc bson.M) bool {
+	id, _ := doc["_id"].(primitive.ObjectID)
+	return compareSorted(doc[c.Field], id, c.Value, c.ID, c.Desc) > 0
+}
+
+// compareSorted orders (value, id) pairs the way a Mongo sort on the field then _id does.
+func compareSorted(av interface{}, aid primitive.ObjectID, bv interface{}, bid primitive.ObjectID, desc bool) int {
+	n := compareValues(av, bv)
+	if n == 0 {
+		n = strings.Compare(aid.Hex(), bid.Hex())
+	}
+	if desc {
+		return -n
+	}
+	return n
+}
+
+// compareValues covers the types sort fields hold: null < strings < ObjectIDs < dates.
+func compareValues(a, b interface{}) int {
+	rank := func(v interface{}) int {
+		switch v.(type) {
+		case nil:
+			return 0
+		case string:
+			return 2
+		case primitive.ObjectID:
+			return 3
+		case primitive.DateTime, time.Time:
+			return 4
+		}
+		return 1
+	}
+	ra, rb := rank(a), rank(b)
+	if ra != rb {
+		return ra - rb
+	}
+	switch x := a.(type) {
+	case string:
+		return strings.Compare(x, b.(string))
+	case primitive.ObjectID:
+		return strings.Compare(x.Hex(), b.(primitive.ObjectID).Hex())
+	case primitive.DateTime, time.Time:
+		ta, tb := asTime(a), asTime(b)
+		switch {
+		case ta.Before(tb):
+			return -1
+		case ta.After(tb):
+			return 1
+		}
+	}
+	return 0
+}
+
+func asTime(v interface{}) time.Time {
+	if dt, ok := v.(primitive.DateTime); ok {
+		return dt.Time()
+	}
+	t, _ := v.(time.Time)
+	return t
+}
@@ -0,0 +1,302 @@
+﻿// Package repository is the storage layer behind the HTTP API: typed repositories for
+// voters, election metadata, candidates, audit logs, OTPs, companies, anchor jobs, roster
+// imports, company resets, the students roster, ballots, consistency reports, operator
+// keys and imported bundles, with a MongoDB
+// implementation (NewMongo) and an in-memory one (NewMemory) for tests and the no-DB
+// demo mode.
+//
+// Election addresses are stored in one canonical form, EIP-55 checksummed (see
+// CanonicalAddress): every write converts them and every lookup converts the queried
+// address, so a checksummed and a lower-cased address find the same records through
+// exact-match indexes. Data written before this rule is rewritten by cmd/evote-migrate.
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// ErrNotFound is returned when the requested record does not exist.
+	ErrNotFound = errors.New("repository: not found")
+	// ErrDuplicate is returned when a record would violate a unique key (voter email,
+	// election address).
+	ErrDuplicate = errors.New("repository: duplicate key")
+)
+
+// Storage modes selected by STORAGE_BACKEND.
+const (
+	ModeMongo  = "mongo"
+	ModeMemory = "memory"
+)
+
+// Mode returns the configured storage mode; anything other than "memory" means MongoDB.
+func Mode() string {
+	if strings.EqualFold(strings.Trim(strings.TrimSpace(os.Getenv("STORAGE_BACKEND")), `"'`), ModeMemory) {
+		return ModeMemory
+	}
+	return ModeMongo
+}
+
+// Repositories bundles one implementation of every repository.
+type Repositories struct {
+	Voters     VoterRepo
+	Elections  ElectionRepo
+	Candidates CandidateRepo
+	Audit      AuditRepo
+	OTPs       OTPRepo
+	Companies  CompanyRepo
+	AnchorJobs AnchorJobRepo
+	Rosters    RosterImportRepo
+	Resets     ResetRepo
+	Students   StudentRepo
+	Ballots    BallotRepo
+	Reports    ReportRepo
+	Operators  OperatorKeyRepo
+	Bundles    BundleArchiveRepo
+}
+
+// Update is a partial update of one record, keyed by top-level bson field names.
+type Update struct {
+	Set   map[string]interface{}
+	Unset []string
+	Inc   map[string]int64
+	Push  map[string]interface{} // appended to the array field
+}
+
+// VoterRepo stores voter accounts and their election registrations.
+type VoterRepo interface {
+	// Create inserts v and sets its ID. ErrDuplicate when the email is taken.
+	Create(ctx context.Context, v *Voter) error
+	Get(ctx context.Context, id primitive.ObjectID) (*Voter, error)
+	// GetByEmail and GetByRollNo ignore case when PII is encrypted (blind index lookups).
+	GetByEmail(ctx context.Context, email string) (*Voter, error)
+	GetByRollNo(ctx context.Context, rollNo string) (*Voter, error)
+	// List returns the voters registered for an election, or every voter for "".
+	List(ctx context.Context, electionAddr string) ([]Voter, error)
+	// Page returns one sorted page of the voters matching q (see VoterQuery).
+	Page(ctx context.Context, q VoterQuery) (*VoterPage, error)
+	CountByElection(ctx context.Context, electionAddr string) (int64, error)
+	// Update sets fields on one voter. ErrNotFound when the voter does not exist.
+	Update(ctx context.Context, id primitive.ObjectID, set map[string]interface{}) error
+	// AddRegistration links a voter to reg's election. It reports false when the voter
+	// was already registered for it, and ErrNotFound when the voter does not exist.
+	AddRegistration(ctx context.Context, id primitive.ObjectID, reg VoterRegistration) (bool, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	// Erase replaces a voter with a stub that holds only pseudonym as its email, the
+	// registrations in keep and the erasure time. ErrNotFound when the voter does not exist.
+	Erase(ctx context.Context, id primitive.ObjectID, pseudonym string, keep []VoterRegistration) error
+	// RegionCounts groups an election's voters by their address field, largest first.
+	RegionCounts(ctx context.Context, electionAddr string) ([]RegionCount, error)
+	// RemoveRegistrations unlinks every voter from one election and reports how many changed.
+	RemoveRegistrations(ctx context.Context, electionAddr string) (int64, error)
+}
+
+// ElectionFilter selects election metadata for List. The zero value lists everything.
+type ElectionFilter struct {
+	Status          string // only elections with this status
+	ExcludeStatus   string // skip elections with this status
+	MissingCodeHash bool   // only elections whose contract build was never recorded
+	CompanyEmail    string // only elections owned by this company (compared lower-cased)
+}
+
+// ElectionRepo stores the off-chain metadata of each election, keyed by address.
+type ElectionRepo interface {
+	Get(ctx context.Context, addr string) (*ElectionMetadata, error)
+	// List returns the matching elections, latest start date first.
+	List(ctx context.Context, f ElectionFilter) ([]ElectionMetadata, error)
+	// Page returns one sorted page of the elections matching q.
+	Page(ctx context.Context, q ElectionQuery) (*ElectionPage, error)
+	// Create inserts m and sets its ID. ErrDuplicate when the address already has metadata.
+	Create(ctx context.Context, m *ElectionMetadata) error
+	// Update applies u to one election; updating an unknown election is a no-op.
+	Update(ctx context.Context, addr string, u Update) error
+	// Upsert applies u, creating the election's metadata first when there is none.
+	Upsert(ctx context.Context, addr string, u Update) error
+	Delete(ctx context.Context, addr string) error
+}
+
+// CandidateMatch selects candidate rows for SetStatus: by the managed tx that registered
+// them when TxID is set, else by election and email (and status, when given).
+type CandidateMatch struct {
+	TxID            string
+	ElectionAddress string
+	Email           string
+	Status          string
+}
+
+// CandidateRepo stores candidate registrations as submitted through the API.
+type CandidateRepo interface {
+	Create(ctx context.Context, c *CandidateDocument) error
+	List(ctx context.Context, electionAddr string) ([]CandidateDocument, error)
+	// Manifestos returns the election's manifesto links keyed by lower-cased email.
+	Manifestos(ctx context.Context, electionAddr string) (map[string]string, error)
+	// ElectionsByPrefix returns the distinct election addresses among the first limit
+	// candidates whose election address starts with prefix.
+	ElectionsByPrefix(ctx context.Context, prefix string, limit int) ([]string, error)
+	// CountActive counts the candidates that are not failed, reverted or orphaned.
+	CountActive(ctx context.Context, electionAddr string) (int64, error)
+	// SetStatus updates every matching row; txHash is left alone when empty.
+	SetStatus(ctx context.Context, m CandidateMatch, status, txHash string) error
+	DeleteByElection(ctx context.Context, electionAddr string) (int64, error)
+}
+
+// AuditRepo stores the audit trail of every election.
+type AuditRepo interface {
+	Insert(ctx context.Context, e *AuditLog) error
+	// InsertEvent writes an entry derived from a chain event once, keyed by e.EventID.
+	InsertEvent(ctx context.Context, e *AuditLog) error
+	// DeleteEvent drops the entries of an event whose block was reorged away.
+	DeleteEvent(ctx context.Context, eventID string) error
+	// List returns an election's entries, oldest first.
+	List(ctx context.Context, electionAddr string) ([]AuditLog, error)
+	CountAction(ctx context.Context, electionAddr, action string) (int64, error)
+	// ListMentioning returns the entries, across all elections, whose actor or details
+	// contain email (see Mentions), oldest first.
+	ListMentioning(ctx context.Context, email string) ([]AuditLog, error)
+	// Pseudonymize replaces email with pseudonym in one election's entries ("" for entries
+	// without an election) and reports how many changed.
+	Pseudonymize(ctx context.Context, electionAddr, email, pseudonym string) (int64, error)
+	// DeleteMentioning drops one election's entries that mention email.
+	DeleteMentioning(ctx context.Context, electionAddr, email string) (int64, error)
+	// DeleteByElection drops one election's entries; entries without an election stay.
+	DeleteByElection(ctx context.Context, electionAddr string) (int64, error)
+}
+
+// OTPRepo stores the one-time codes sent to voters, one per email.
+type OTPRepo interface {
+	Get(ctx context.Context, email string) (*OTP, error)
+	// Replace stores o, dropping any older code for the same email.
+	Replace(ctx context.Context, o *OTP) error
+	Delete(ctx context.Context, email string) error
+	CountByElection(ctx context.Context, electionAddr string) (int64, error)
+	DeleteByElection(ctx context.Context, electionAddr string) (int64, error)
+}
+
+// CompanyRepo stores company accounts, one per email. Lookups ignore case, since
+// elections and budgets refer to their company by the lower-cased email.
+type CompanyRepo interface {
+	// Create inserts c and sets its ID. ErrDuplicate when the email is taken.
+	Create(ctx context.Context, c *Company) error
+	GetByEmail(ctx context.Context, email string) (*Company, error)
+	List(ctx context.Context) ([]Company, error)
+	// Update applies u to one company. ErrNotFound when the company does not exist.
+	Update(ctx context.Context, email string, u Update) error
+}
+
+// AnchorJobRepo stores the L1 anchoring job of each election, keyed by address.
+type AnchorJobRepo interface {
+	Get(ctx context.Context, electionAddr string) (*AnchorJob, error)
+	// Queue creates or resets the election's job to AnchorQueued, due now, with no
+	// attempts and none of the previous submission's fields.
+	Queue(ctx context.Context, electionAddr, closeTxID, requestedBy string, requiredConfirmations uint64) error
+	Update(ctx context.Context, electionAddr string, u Update) error
+	// Due returns the queued jobs whose next attempt is at or before now, and every
+	// submitted job.
+	Due(ctx context.Context, now time.Time) ([]AnchorJob, error)
+}
+
+// RosterImportRepo stores roster imports and their rows. Rows hold voter PII and are
+// sealed like voters; they expire after RosterRowTTL.
+type RosterImportRepo interface {
+	// Create stores job and its rows.
+	Create(ctx context.Context, job *RosterImport, rows []RosterRow) error
+	Get(ctx context.Context, id primitive.ObjectID) (*RosterImport, error)
+	Update(ctx context.Context, id primitive.ObjectID, u Update) error
+	// Queue moves a RosterPreview import created after since to RosterQueued and returns
+	// it. ErrNotFound when there is no such import.
+	Queue(ctx context.Context, id primitive.ObjectID, since time.Time) (*RosterImport, error)
+	// Claim moves the longest-waiting queued import to RosterRunning and returns it.
+	// ErrNotFound when none is queued.
+	Claim(ctx context.Context) (*RosterImport, error)
+	// Requeue moves imports left RosterRunning (by a restart) back to RosterQueued.
+	Requeue(ctx context.Context) (int64, error)
+	// Rows returns the rows matching q in file order, opened.
+	Rows(ctx context.Context, q RosterRowQuery) ([]RosterRow, error)
+	UpdateRow(ctx context.Context, id primitive.ObjectID, set map[string]interface{}) error
+}
+
+// RosterRowQuery selects rows of one import.
+type RosterRowQuery struct {
+	ImportID primitive.ObjectID
+	After    int  // only rows numbered above this
+	Pending  bool // only rows without a result
+	Problems bool // only invalid or failed rows
+	Limit    int  // 0 for every row
+}
+
+// ResetRepo stores company resets (reset_history), from preview to outcome. Resets never
+// delete from it.
+type ResetRepo interface {
+	// SavePreview stores a ResetPreview record, dropping the company's earlier previews:
+	// a company has one open preview.
+	SavePreview(ctx context.Context, rec *ResetRecord) error
+	// Start moves the company's preview with tokenHash, if it has not expired by now, to
+	// ResetStarted and returns it. ErrNotFound when there is none; a token starts at most
+	// one reset.
+	Start(ctx context.Context, company, tokenHash, remoteAddr string, now time.Time) (*ResetRecord, error)
+	Update(ctx context.Context, id primitive.ObjectID, u Update) error
+	// List returns up to limit of a company's started resets, latest first.
+	List(ctx context.Context, company string, limit int) ([]ResetRecord, error)
+}
+
+// StudentRepo stores the students roster. Its PII is sealed like voters'.
+type StudentRepo interface {
+	// GetByEmail ignores case when PII is encrypted. ErrNotFound when there is no entry.
+	GetByEmail(ctx context.Context, email string) (*Student, error)
+	// Create inserts s and sets its ID. ErrDuplicate when the email is taken.
+	Create(ctx context.Context, s *Student) error
+	// Update sets fields on the student with email. ErrNotFound when there is none.
+	Update(ctx context.Context, email string, set map[string]interface{}) error
+}
+
+// BallotRepo stores the ballots of batched votes. A voter has at most one active ballot
+// (QUEUED, SUBMITTED or INCLUDED) per election.
+type BallotRepo interface {
+	// Insert stores bl and sets its ID. ErrDuplicate when bl is active and the voter
+	// already has an active ballot in the election.
+	Insert(ctx context.Context, bl *Ballot) error
+	Get(ctx context.Context, id primitive.ObjectID) (*Ballot, error)
+	// Find returns the ballots matching q, oldest first.
+	Find(ctx context.Context, q BallotQuery) ([]Ballot, error)
+	Count(ctx context.Context, q BallotQuery) (int64, error)
+	// Update applies u to every ballot matching q and reports how many changed.
+	Update(ctx context.Context, q BallotQuery, u Update) (int64, error)
+	Delete(ctx context.Context, q BallotQuery) (int64, error)
+}
+
+// BallotQuery selects ballots; zero fields match every ballot.
+type BallotQuery struct {
+	IDs      []primitive.ObjectID
+	Election string         // as stored, not canonicalized
+	Voter    *regexp.Regexp // matched against the stored voter_email
+	Statuses []string
+}
+
+// ReportRepo keeps the latest consistency report of each election.
+type ReportRepo interface {
+	// Get returns the election's report. ErrNotFound when it was never reconciled.
+	Get(ctx context.Context, electionAddr string) (*ConsistencyReport, error)
+	// Save replaces the election's report.
+	Save(ctx context.Context, r *ConsistencyReport) error
+}
+
+// OperatorKeyRepo remembers the operator account of each chain.
+type OperatorKeyRepo interface {
+	// Get returns the chain's last key. ErrNotFound when none was recorded.
+	Get(ctx context.Context, chain string) (*OperatorKey, error)
+	// Save replaces the chain's key.
+	Save(ctx context.Context, k *OperatorKey) error
+}
+
+// BundleArchiveRepo keeps the archive of every imported election bundle, keyed by election.
+type BundleArchiveRepo interface {
+	// Save replaces the election's archive.
+	Save(ctx context.Context, a *BundleArchive) error
+}
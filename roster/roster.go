@@ -0,0 +1,270 @@
+﻿// Package roster reads student and voter rosters from CSV or XLSX files and maps their
+// columns to roster fields. It does not validate values or touch storage.
+package roster
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// Roster fields, named like the stored Student and Voter fields.
+const (
+	Email    = "email"
+	FullName = "full_name"
+	RollNo   = "roll_no"
+	Mobile   = "mobile"
+	Gender   = "gender"
+	Year     = "year"
+	DOB      = "dob"
+)
+
+// Fields lists every roster field in display order.
+var Fields = []string{Email, FullName, RollNo, Mobile, Gender, Year, DOB}
+
+// aliases maps normalized header text to a field.
+var aliases = map[string]string{
+	"email": Email, "e mail": Email, "email address": Email, "email id": Email, "mail": Email,
+	"full name": FullName, "name": FullName, "student name": FullName, "voter name": FullName,
+	"roll no": RollNo, "roll number": RollNo, "roll": RollNo, "enrollment no": RollNo, "enrollment number": RollNo,
+	"mobile": Mobile, "mobile no": Mobile, "mobile number": Mobile, "phone": Mobile, "phone number": Mobile, "contact": Mobile,
+	"gender": Gender, "sex": Gender,
+	"year": Year, "class year": Year, "batch": Year,
+	"dob": DOB, "date of birth": DOB, "birth date": DOB, "birthdate": DOB,
+}
+
+// ErrFormat is returned for files that are neither CSV nor XLSX, or that cannot be read.
+var ErrFormat = errors.New("roster: unsupported or unreadable file")
+
+// ErrTooManyRows is returned as soon as a file has more data rows than Read allows.
+var ErrTooManyRows = errors.New("roster: too many rows")
+
+// Row is one data row of a file; Number is its line (CSV) or row (XLSX) number in the file.
+// Values holds the mapped fields, trimmed.
+type Row struct {
+	Number int
+	Values map[string]string
+}
+
+// Table is a file's header, its column mapping and its non-empty data rows.
+type Table struct {
+	Format  string // "csv" or "xlsx"
+	Header  []string
+	Mapping Mapping
+	Rows    []Row
+}
+
+// rowReader yields a file's rows one at a time as column index to cell text, or io.EOF.
+// A non-nil wanted limits which columns are kept.
+type rowReader interface {
+	next(wanted map[int]bool) (int, map[int]string, error)
+}
+
+// Read parses data as XLSX when it is a zip archive (or name ends in .xlsx), else as CSV.
+// The first non-empty row is the header and is mapped with Map(header, columns); only the
+// mapped columns of later rows are kept. Reading stops with ErrTooManyRows once there are
+// more than maxRows data rows (0 = no limit).
+func Read(name string, data []byte, columns map[string]string, maxRows int) (*Table, error) {
+	var (
+		src rowReader
+		t   = &Table{}
+		err error
+	)
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) || strings.EqualFold(filepath.Ext(name), ".xlsx") {
+		t.Format = "xlsx"
+		src, err = newXLSXReader(data)
+	} else {
+		t.Format = "csv"
+		src = newCSVReader(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var wanted map[int]bool
+	for {
+		n, cells, err := src.next(wanted)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if blank(cells) {
+			continue
+		}
+		if t.Mapping == nil {
+			t.Header = headerCells(cells)
+			if t.Mapping, err = Map(t.Header, columns); err != nil {
+				return nil, err
+			}
+			wanted = make(map[int]bool, len(t.Mapping))
+			for _, i := range t.Mapping {
+				wanted[i] = true
+			}
+			continue
+		}
+		if maxRows > 0 && len(t.Rows) == maxRows {
+			return nil, fmt.Errorf("%w: the limit is %d per file", ErrTooManyRows, maxRows)
+		}
+		t.Rows = append(t.Rows, Row{Number: n, Values: t.Mapping.record(cells)})
+	}
+	if t.Mapping == nil {
+		return nil, fmt.Errorf("%w: no header row", ErrFormat)
+	}
+	return t, nil
+}
+
+type csvReader struct {
+	r *csv.Reader
+}
+
+func newCSVReader(data []byte) *csvReader {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // spreadsheet exports often carry a BOM
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	r.ReuseRecord = true
+	return &csvReader{r: r}
+}
+
+func (c *csvReader) next(wanted map[int]bool) (int, map[int]string, error) {
+	rec, err := c.r.Read()
+	if err == io.EOF {
+		return 0, nil, io.EOF
+	}
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: %v", ErrFormat, err)
+	}
+	line, _ := c.r.FieldPos(0)
+	cells := make(map[int]string, len(rec))
+	for i, v := range rec {
+		if wanted == nil || wanted[i] {
+			cells[i] = v
+		}
+	}
+	return line, cells, nil
+}
+
+// headerCells lays the header row out as a slice, one entry per column up to the last
+// non-empty one.
+func headerCells(cells map[int]string) []string {
+	last := -1
+	for i, v := range cells {
+		if i > last && strings.TrimSpace(v) != "" {
+			last = i
+		}
+	}
+	header := make([]string, last+1)
+	for i, v := range cells {
+		if i <= last {
+			header[i] = v
+		}
+	}
+	return header
+}
+
+func blank(cells map[int]string) bool {
+	for _, c := range cells {
+		if strings.TrimSpace(c) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Mapping is the column index of each mapped field.
+type Mapping map[string]int
+
+// Map matches header columns to fields by common names ("Roll Number", "E-mail", ...).
+// columns overrides it per field with the exact header text. The email column is required.
+func Map(header []string, columns map[string]string) (Mapping, error) {
+	m := Mapping{}
+	for i, h := range header {
+		if f, ok := aliases[normalize(h)]; ok {
+			if _, taken := m[f]; !taken {
+				m[f] = i
+			}
+		}
+	}
+	for field, col := range columns {
+		if !known(field) {
+			return nil, fmt.Errorf("roster: unknown field %q", field)
+		}
+		i := indexOf(header, col)
+		if i < 0 {
+			return nil, fmt.Errorf("roster: no column %q for %s", col, field)
+		}
+		m[field] = i
+	}
+	if _, ok := m[Email]; !ok {
+		return nil, errors.New("roster: no email column")
+	}
+	return m, nil
+}
+
+// Columns is the header text of each mapped field, for reporting the mapping back.
+func (m Mapping) Columns(header []string) map[string]string {
+	out := make(map[string]string, len(m))
+	for f, i := range m {
+		out[f] = header[i]
+	}
+	return out
+}
+
+// record returns a row's mapped values, trimmed, keyed by field.
+func (m Mapping) record(cells map[int]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for f, i := range m {
+		if v, ok := cells[i]; ok {
+			out[f] = strings.TrimSpace(v)
+		}
+	}
+	return out
+}
+
+func known(field string) bool {
+	for _, f := range Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func indexOf(header []string, col string) int {
+	for i, h := range header {
+		if strings.TrimSpace(h) == strings.TrimSpace(col) {
+			return i
+		}
+	}
+	for i, h := range header {
+		if normalize(h) == normalize(col) {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalize lower-cases s and turns every run of non-alphanumerics into one space.
+func normalize(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+		} else {
+			space = true
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,237 @@
+﻿package roster
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// maxXLSXPart caps how much of one archive member is decompressed.
+const maxXLSXPart = 64 << 20
+
+// The parts of SpreadsheetML that a roster needs: the first sheet's cells and the shared
+// string table. Styles are ignored, so dates come back as Excel serial numbers.
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRels struct {
+	Rels []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+type xlsxRow struct {
+	R     int `xml:"r,attr"`
+	Cells []struct {
+		R      string   `xml:"r,attr"`
+		T      string   `xml:"t,attr"`
+		V      string   `xml:"v"`
+		Inline xlsxText `xml:"is"`
+	} `xml:"c"`
+}
+
+// xlsxReader streams the first sheet one <row> at a time, so a sheet is never decoded
+// past the row where Read stops.
+type xlsxReader struct {
+	dec    *xml.Decoder
+	shared []string
+	row    int // number of the previous row, for rows without an r attribute
+}
+
+func newXLSXReader(data []byte) (*xlsxReader, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFormat, err)
+	}
+	parts := map[string]*zip.File{}
+	for _, f := range zr.File {
+		parts[strings.TrimPrefix(f.Name, "/")] = f
+	}
+
+	sheetPath, err := firstSheet(parts)
+	if err != nil {
+		return nil, err
+	}
+	x := &xlsxReader{}
+	if f, ok := parts["xl/sharedStrings.xml"]; ok {
+		var sst struct {
+			Items []xlsxText `xml:"si"`
+		}
+		if err := decodePart(f, &sst); err != nil {
+			return nil, err
+		}
+		for _, si := range sst.Items {
+			x.shared = append(x.shared, si.String())
+		}
+	}
+	raw, err := readPart(parts[sheetPath])
+	if err != nil {
+		return nil, err
+	}
+	x.dec = xml.NewDecoder(bytes.NewReader(raw))
+	return x, nil
+}
+
+func (x *xlsxReader) next(wanted map[int]bool) (int, map[int]string, error) {
+	for {
+		tok, err := x.dec.Token()
+		if err == io.EOF {
+			return 0, nil, io.EOF
+		}
+		if err != nil {
+			return 0, nil, fmt.Errorf("%w: %v", ErrFormat, err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+		var xr xlsxRow
+		if err := x.dec.DecodeElement(&xr, &start); err != nil {
+			return 0, nil, fmt.Errorf("%w: %v", ErrFormat, err)
+		}
+		n := xr.R
+		if n <= 0 {
+			n = x.row + 1
+		}
+		x.row = n
+
+		cells := map[int]string{}
+		col := -1
+		for _, c := range xr.Cells {
+			col++
+			if c.R != "" {
+				i, ok := columnIndex(c.R)
+				if !ok {
+					return 0, nil, fmt.Errorf("%w: bad cell reference %q", ErrFormat, c.R)
+				}
+				col = i
+			}
+			if wanted != nil && !wanted[col] {
+				continue
+			}
+			switch c.T {
+			case "s":
+				i, err := strconv.Atoi(strings.TrimSpace(c.V))
+				if err != nil || i < 0 || i >= len(x.shared) {
+					return 0, nil, fmt.Errorf("%w: bad shared string in cell %s", ErrFormat, c.R)
+				}
+				cells[col] = x.shared[i]
+			case "inlineStr":
+				cells[col] = c.Inline.String()
+			case "b":
+				cells[col] = map[string]string{"1": "TRUE", "0": "FALSE"}[c.V]
+			default:
+				cells[col] = c.V
+			}
+		}
+		return n, cells, nil
+	}
+}
+
+// firstSheet resolves the archive path of the workbook's first sheet.
+func firstSheet(parts map[string]*zip.File) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+	wb, ok := parts["xl/workbook.xml"]
+	rels, hasRels := parts["xl/_rels/workbook.xml.rels"]
+	if ok && hasRels {
+		var w xlsxWorkbook
+		var r xlsxRels
+		if err := decodePart(wb, &w); err != nil {
+			return "", err
+		}
+		if err := decodePart(rels, &r); err != nil {
+			return "", err
+		}
+		if len(w.Sheets) > 0 {
+			for _, rel := range r.Rels {
+				if rel.ID != w.Sheets[0].RID {
+					continue
+				}
+				p := strings.TrimPrefix(rel.Target, "/")
+				if !strings.HasPrefix(p, "xl/") {
+					p = path.Join("xl", p)
+				}
+				if _, ok := parts[p]; ok {
+					return p, nil
+				}
+			}
+		}
+	}
+	if _, ok := parts[fallback]; ok {
+		return fallback, nil
+	}
+	return "", fmt.Errorf("%w: no worksheet", ErrFormat)
+}
+
+func decodePart(f *zip.File, v interface{}) error {
+	raw, err := readPart(f)
+	if err != nil {
+		return err
+	}
+	if err := xml.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrFormat, f.Name, err)
+	}
+	return nil
+}
+
+// readPart decompresses one archive member, up to maxXLSXPart.
+func readPart(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFormat, err)
+	}
+	defer rc.Close()
+	raw, err := io.ReadAll(io.LimitReader(rc, maxXLSXPart+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrFormat, f.Name, err)
+	}
+	if len(raw) > maxXLSXPart {
+		return nil, fmt.Errorf("%w: %s is too large", ErrFormat, f.Name)
+	}
+	return raw, nil
+}
+
+// columnIndex turns a cell reference ("C7") into a zero-based column index, up to
+// Excel's last column (XFD).
+func columnIndex(ref string) (int, bool) {
+	n := 0
+	i := 0
+	for ; i < len(ref) && ref[i] >= 'A' && ref[i] <= 'Z'; i++ {
+		n = n*26 + int(ref[i]-'A'+1)
+		if n > 16384 {
+			return 0, false
+		}
+	}
+	if i == 0 {
+		return 0, false
+	}
+	return n - 1, true
+}
@@ -23,7 +23,8 @@ func corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-func SetupRoutes() *mux.Router {
+// SetupRoutes registers the API; handlers that read or write storage are methods on h.
+func SetupRoutes(h *controllers.Handlers) *mux.Router {
 	router := mux.NewRouter()
 	api := router.PathPrefix("/api/").Subrouter()
 	api.Use(middleware.RecoveryMiddleware) // Global panic recovery
@@ -33,50 +34,80 @@ func SetupRoutes() *mux.Router {
 	// ----------------------------
 	// COMPANY ROUTES
 	// ----------------------------
-	api.HandleFunc("/admin/clear-database", controllers.ClearDatabase).Methods(http.MethodPost, http.MethodOptions) // NEW
-	api.HandleFunc("/company/register", controllers.CreateCompany).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/company/register", h.CreateCompany).Methods(http.MethodPost, http.MethodOptions)
 
-	api.HandleFunc("/company/authenticate", controllers.AuthenticateCompany).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/company/authenticate", h.AuthenticateCompany).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/company/{email}/elections", h.ListCompanyElections).Methods(http.MethodGet, http.MethodOptions)
+	api.HandleFunc("/company/{email}/gas", h.GetCompanyGasSpend).Methods(http.MethodGet, http.MethodOptions)
+	api.HandleFunc("/company/{email}/gas/budget", h.SetCompanyGasBudget).Methods(http.MethodPut, http.MethodOptions)
+	api.HandleFunc("/company/{email}/retention", h.SetCompanyRetention).Methods(http.MethodPut, http.MethodOptions)
+	api.HandleFunc("/company/{email}/reset/preview", h.PreviewCompanyReset).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/company/{email}/reset", h.ResetCompany).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/company/{email}/resets", h.ListCompanyResets).Methods(http.MethodGet, http.MethodOptions)
 
 	// ----------------------------
 	// ELECTION ROUTES
 	// ----------------------------
-	api.HandleFunc("/elections/create", controllers.CreateElection).Methods(http.MethodPost, http.MethodOptions)
-	api.HandleFunc("/elections/{address}/details", controllers.GetElectionInfo).Methods(http.MethodGet, http.MethodOptions)
-	api.HandleFunc("/elections/{address}/candidates", controllers.GetElectionCandidates).Methods(http.MethodGet, http.MethodOptions)
-	api.HandleFunc("/elections/{address}/vote", controllers.VoteCandidate).Methods(http.MethodPost, http.MethodOptions)
-	api.HandleFunc("/elections/{address}/voters", controllers.GetElectionVoters).Methods(http.MethodGet, http.MethodOptions)
-	api.HandleFunc("/elections/dates", controllers.SetElectionDates).Methods(http.MethodPost, http.MethodOptions)
-	api.HandleFunc("/elections/{address}/metadata", controllers.GetElectionMetadata).Methods(http.MethodGet, http.MethodOptions)
-	api.HandleFunc("/elections/{address}/analytics/geo", controllers.GetVoterAnalytics).Methods(http.MethodGet, http.MethodOptions)
-	api.HandleFunc("/elections/{address}/end", controllers.EndElection).Methods(http.MethodPost, http.MethodOptions)  // NEW
-	api.HandleFunc("/elections", controllers.GetAllElections).Methods(http.MethodGet, http.MethodOptions)             // NEW
-	api.HandleFunc("/elections/archives", controllers.GetArchivedResults).Methods(http.MethodGet, http.MethodOptions) // L1 Archives
+	api.HandleFunc("/elections/create", h.CreateElection).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/elections/by-id/{id}", h.GetElectionByID).Methods(http.MethodGet, http.MethodOptions)
+	api.HandleFunc("/elections/{address}/details", h.GetElectionInfo).Methods(http.MethodGet, http.MethodOptions)
+	api.HandleFunc("/elections/{address}/candidates", h.GetElectionCandidates).Methods(http.MethodGet, http.MethodOptions)
+	api.HandleFunc("/elections/{address}/vote", h.VoteCandidate).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/elections/{address}/voters", h.GetElectionVoters).Methods(http.MethodGet, http.MethodOptions)
+	api.HandleFunc("/elections/dates", h.SetElectionDates).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/elections/{address}/metadata", h.GetElectionMetadata).Methods(http.MethodGet, http.MethodOptions)
+	api.HandleFunc("/elections/{address}/analytics/geo", h.GetVoterAnalytics).Methods(http.MethodGet, http.MethodOptions)
+	api.HandleFunc("/elections/{address}/end", h.EndElection).Methods(http.MethodPost, http.MethodOptions) // NEW
+	api.HandleFunc("/elections/{address}/pause", h.PauseElection).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/elections/{address}/resume", h.ResumeElection).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/elections/{address}/approvers", h.SetElectionApprovers).Methods(http.MethodPut, http.MethodOptions)
+	api.HandleFunc("/elections/{address}/approvals", h.GetElectionApprovals).Methods(http.MethodGet, http.MethodOptions)
+	api.HandleFunc("/elections/{address}/approvals", h.ApproveElectionAction).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/elections", h.GetAllElections).Methods(http.MethodGet, http.MethodOptions)             // NEW
+	api.HandleFunc("/elections/archives", h.GetArchivedResults).Methods(http.MethodGet, http.MethodOptions) // L1 Archives
+	api.HandleFunc("/elections/{address}/consistency", h.GetConsistencyReport).Methods(http.MethodGet, http.MethodOptions)
+	api.HandleFunc("/elections/{address}/anchor", h.GetAnchorStatus).Methods(http.MethodGet, http.MethodOptions)
+	api.HandleFunc("/admin/elections/{address}/anchor", h.ReAnchorElection).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/admin/elections/migrate", h.MigrateElectionVersions).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/admin/elections/{address}/bundle", h.ExportElectionBundle).Methods(http.MethodGet, http.MethodOptions)
+	api.HandleFunc("/admin/elections/import", h.ImportElectionBundle).Methods(http.MethodPost, http.MethodOptions)
+
+	// ----------------------------
+	// TRANSACTION ROUTES
+	// ----------------------------
+	api.HandleFunc("/transactions", h.ListTransactions).Methods(http.MethodGet, http.MethodOptions)
+	api.HandleFunc("/transactions/{id}", h.GetTransactionStatus).Methods(http.MethodGet, http.MethodOptions)
+	api.HandleFunc("/ballots/{id}", h.GetBallotStatus).Methods(http.MethodGet, http.MethodOptions)
 
 	// ----------------------------
 	// CANDIDATE ROUTES
 	// ----------------------------
-	api.HandleFunc("/candidate/register", controllers.RegisterCandidate).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/candidate/register", h.RegisterCandidate).Methods(http.MethodPost, http.MethodOptions)
 
 	// ----------------------------
 	// VOTER ROUTES
 	// ----------------------------
-	api.HandleFunc("/voters/register", controllers.RegisterVoter).Methods(http.MethodPost, http.MethodOptions)
-	api.HandleFunc("/voters/send-otp", controllers.SendOTP).Methods(http.MethodPost, http.MethodOptions)
-	api.HandleFunc("/voters/verify-otp-register", controllers.VerifyOTPAndRegister).Methods(http.MethodPost, http.MethodOptions)
-	api.HandleFunc("/voters/me/elections", controllers.GetVoterElections).Methods(http.MethodGet, http.MethodOptions) // NEW
-	api.HandleFunc("/voters/forgot-password", controllers.ForgotPassword).Methods(http.MethodPost, http.MethodOptions)
-	api.HandleFunc("/voter/authenticate", controllers.AuthenticateVoter).Methods(http.MethodPost, http.MethodOptions)
-	api.HandleFunc("/voters", controllers.GetAllVoters).Methods(http.MethodGet, http.MethodPost, http.MethodOptions)
-	api.HandleFunc("/voters/{voterId}/card", controllers.GenerateVoterID).Methods(http.MethodGet, http.MethodOptions)
-	api.HandleFunc("/voters/{voterId}/card/email", controllers.EmailVoterID).Methods(http.MethodPost, http.MethodOptions)
-	api.HandleFunc("/voters/{voterId}", controllers.UpdateVoter).Methods(http.MethodPut, http.MethodOptions)
-	api.HandleFunc("/voters/{voterId}", controllers.DeleteVoter).Methods(http.MethodDelete, http.MethodOptions)
-	api.HandleFunc("/voters/{voterId}/approve", controllers.ApproveVoter).Methods(http.MethodPost, http.MethodOptions)                              // NEW
-	api.HandleFunc("/voters/{voterId}/reset-password", controllers.AdminResetVoterPassword).Methods(http.MethodPost, http.MethodOptions)            // ADMIN RESET
-	api.HandleFunc("/elections/{address}/voters/add", controllers.AddVotersToElection).Methods(http.MethodPost, http.MethodOptions)                 // NEW BULK IMPORT
-	api.HandleFunc("/elections/{address}/voters/reset-passwords", controllers.BulkResetVoterPasswords).Methods(http.MethodPost, http.MethodOptions) // BULK SEND PASSWORDS
-	api.HandleFunc("/voter/resultMail", controllers.ResultMail).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/voters/register", h.RegisterVoter).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/voters/send-otp", h.SendOTP).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/voters/verify-otp-register", h.VerifyOTPAndRegister).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/voters/me/elections", h.GetVoterElections).Methods(http.MethodGet, http.MethodOptions) // NEW
+	api.HandleFunc("/voters/forgot-password", h.ForgotPassword).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/voter/authenticate", h.AuthenticateVoter).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/voters", h.GetAllVoters).Methods(http.MethodGet, http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/voters/{voterId}/card", h.GenerateVoterID).Methods(http.MethodGet, http.MethodOptions)
+	api.HandleFunc("/voters/{voterId}/card/email", h.EmailVoterID).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/voters/{voterId}", h.UpdateVoter).Methods(http.MethodPut, http.MethodOptions)
+	api.HandleFunc("/voters/{voterId}", h.DeleteVoter).Methods(http.MethodDelete, http.MethodOptions) // ERASURE
+	api.HandleFunc("/voters/{voterId}/export", h.ExportVoterData).Methods(http.MethodGet, http.MethodOptions)
+	api.HandleFunc("/voters/{voterId}/approve", h.ApproveVoter).Methods(http.MethodPost, http.MethodOptions)                              // NEW
+	api.HandleFunc("/voters/{voterId}/reset-password", h.AdminResetVoterPassword).Methods(http.MethodPost, http.MethodOptions)            // ADMIN RESET
+	api.HandleFunc("/elections/{address}/voters/add", h.AddVotersToElection).Methods(http.MethodPost, http.MethodOptions)                 // NEW BULK IMPORT
+	api.HandleFunc("/elections/{address}/voters/reset-passwords", h.BulkResetVoterPasswords).Methods(http.MethodPost, http.MethodOptions) // BULK SEND PASSWORDS
+	api.HandleFunc("/voter/resultMail", h.ResultMail).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/admin/roster/preview", h.PreviewRosterImport).Methods(http.MethodPost, http.MethodOptions) // CSV/XLSX ROSTER DRY RUN
+	api.HandleFunc("/admin/roster/imports/{id}/apply", h.ApplyRosterImport).Methods(http.MethodPost, http.MethodOptions)
+	api.HandleFunc("/admin/roster/imports/{id}", h.GetRosterImport).Methods(http.MethodGet, http.MethodOptions)
+	api.HandleFunc("/admin/roster/imports/{id}/report.csv", h.GetRosterImportReport).Methods(http.MethodGet, http.MethodOptions)
 	// ----------------------------
 	// UPLOAD ROUTES
 	// ----------------------------
@@ -85,6 +116,9 @@ func SetupRoutes() *mux.Router {
 	api.HandleFunc("/upload/s3", controllers.UnifiedUploadHandler).Methods(http.MethodPost, http.MethodOptions)
 	api.HandleFunc("/upload/gdrive", controllers.UnifiedUploadHandler).Methods(http.MethodPost, http.MethodOptions)
 
+	// RPC endpoint health per layer (outside /api so probes skip rate limiting)
+	router.HandleFunc("/health/chain", h.GetChainHealth).Methods(http.MethodGet)
+
 	// ----------------------------
 	// STATIC FILE SERVING
 	// ----------------------------
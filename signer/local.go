@@ -0,0 +1,58 @@
+﻿package signer
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// keySigner holds a decrypted key in memory. Both the raw-key and keystore modes end up here.
+type keySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+	kind    string
+}
+
+// NewKeySigner signs with an in-memory key.
+func NewKeySigner(key *ecdsa.PrivateKey) Signer {
+	return &keySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey), kind: "key"}
+}
+
+// NewKeySignerFromHex parses a hex key (see ParsePrivateKey).
+func NewKeySignerFromHex(hexKey string) (Signer, error) {
+	key, err := ParsePrivateKey(hexKey)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeySigner(key), nil
+}
+
+// NewKeystoreSigner decrypts a geth keystore (V3 JSON) file once at startup.
+func NewKeystoreSigner(path, passphrase string) (Signer, error) {
+	if path == "" {
+		return nil, fmt.Errorf("keystore file not configured")
+	}
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore %s: %w", path, err)
+	}
+	k, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore %s: %w", path, err)
+	}
+	return &keySigner{key: k.PrivateKey, address: k.Address, kind: "keystore"}, nil
+}
+
+func (s *keySigner) Address() common.Address { return s.address }
+
+func (s *keySigner) Kind() string { return s.kind }
+
+func (s *keySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
@@ -0,0 +1,68 @@
+﻿package signer
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// remoteSigner forwards every signature to an external signer speaking clef's
+// account_signTransaction API (clef, or anything compatible). The key never
+// enters this process.
+type remoteSigner struct {
+	ext     *external.ExternalSigner
+	account accounts.Account
+}
+
+// NewRemoteSigner connects to the signer at endpoint. With a zero account the signer
+// must expose exactly one account, which is then used.
+func NewRemoteSigner(endpoint string, account common.Address) (Signer, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("remote signer URL not configured")
+	}
+	ext, err := external.NewExternalSigner(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach remote signer %s: %w", endpoint, err)
+	}
+
+	accts := ext.Accounts()
+	if account == (common.Address{}) {
+		if len(accts) != 1 {
+			return nil, fmt.Errorf("remote signer %s exposes %d accounts; set SIGNER_ADDRESS", endpoint, len(accts))
+		}
+		return &remoteSigner{ext: ext, account: accts[0]}, nil
+	}
+	for _, a := range accts {
+		if a.Address == account {
+			return &remoteSigner{ext: ext, account: a}, nil
+		}
+	}
+	return nil, fmt.Errorf("remote signer %s does not manage %s", endpoint, account.Hex())
+}
+
+func (s *remoteSigner) Address() common.Address { return s.account.Address }
+
+func (s *remoteSigner) Kind() string { return "remote" }
+
+// SignTx asks the remote signer and checks the signature really is from our account.
+func (s *remoteSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signed, err := s.ext.SignTx(s.account, tx, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer: %w", err)
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer returned an invalid signature: %w", err)
+	}
+	if from != s.account.Address {
+		return nil, fmt.Errorf("remote signer signed as %s, expected %s", from.Hex(), s.account.Address.Hex())
+	}
+	if signed.Nonce() != tx.Nonce() || signed.Gas() != tx.Gas() {
+		return nil, fmt.Errorf("remote signer altered nonce or gas")
+	}
+	return signed, nil
+}
@@ -0,0 +1,121 @@
+﻿package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer signs transactions for one operator account. Implementations never expose
+// the key itself, so callers work the same whether it lives in memory, in an
+// encrypted keystore or behind a remote signer.
+type Signer interface {
+	Address() common.Address
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	// Kind is "key", "keystore" or "remote"; used in logs and the audit trail.
+	Kind() string
+}
+
+// SignerFn adapts s to bind.SignerFn for the transaction manager and generated bindings.
+func SignerFn(s Signer, chainID *big.Int) bind.SignerFn {
+	return func(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if from != s.Address() {
+			return nil, bind.ErrNotAuthorized
+		}
+		return s.SignTx(tx, chainID)
+	}
+}
+
+// TransactOpts returns bind options that sign with s (used for deployments).
+func TransactOpts(ctx context.Context, s Signer, chainID *big.Int) *bind.TransactOpts {
+	return &bind.TransactOpts{From: s.Address(), Signer: SignerFn(s, chainID), Context: ctx}
+}
+
+// FromEnv builds the signer for one layer ("L1" or "L2") from <LAYER>_SIGNER:
+//
+//	key      <LAYER>_PRIVATE_KEY, falling back to the shared EVM_PRIVATE_KEY
+//	keystore <LAYER>_KEYSTORE_FILE + <LAYER>_KEYSTORE_PASSWORD_FILE or <LAYER>_KEYSTORE_PASSWORD
+//	remote   <LAYER>_REMOTE_SIGNER_URL (clef-compatible) + optional <LAYER>_SIGNER_ADDRESS
+//
+// When <LAYER>_SIGNER is unset the mode is inferred from which of those variables exist.
+func FromEnv(layer string) (Signer, error) {
+	p := layer + "_"
+	mode := strings.ToLower(env(p + "SIGNER"))
+	if mode == "" {
+		switch {
+		case env(p+"REMOTE_SIGNER_URL") != "":
+			mode = "remote"
+		case env(p+"KEYSTORE_FILE") != "":
+			mode = "keystore"
+		default:
+			mode = "key"
+		}
+	}
+
+	switch mode {
+	case "key":
+		hexKey := env(p + "PRIVATE_KEY")
+		if hexKey == "" {
+			hexKey = env("EVM_PRIVATE_KEY")
+		}
+		if hexKey == "" {
+			return nil, fmt.Errorf("%s signer: neither %sPRIVATE_KEY nor EVM_PRIVATE_KEY is set", layer, p)
+		}
+		return NewKeySignerFromHex(hexKey)
+	case "keystore":
+		passphrase, err := keystorePassphrase(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s signer: %w", layer, err)
+		}
+		return NewKeystoreSigner(env(p+"KEYSTORE_FILE"), passphrase)
+	case "remote":
+		var account common.Address
+		if a := env(p + "SIGNER_ADDRESS"); a != "" {
+			if !common.IsHexAddress(a) {
+				return nil, fmt.Errorf("%s signer: invalid %sSIGNER_ADDRESS %q", layer, p, a)
+			}
+			account = common.HexToAddress(a)
+		}
+		return NewRemoteSigner(env(p+"REMOTE_SIGNER_URL"), account)
+	default:
+		return nil, fmt.Errorf("%s signer: unknown %sSIGNER %q (want key, keystore or remote)", layer, p, mode)
+	}
+}
+
+// ParsePrivateKey is the one place a hex key is parsed: surrounding space/quotes and
+// an optional 0x prefix are accepted.
+func ParsePrivateKey(hexKey string) (*ecdsa.PrivateKey, error) {
+	s := strings.Trim(strings.TrimSpace(hexKey), `"'`)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	key, err := crypto.HexToECDSA(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return key, nil
+}
+
+func env(name string) string {
+	return strings.Trim(strings.TrimSpace(os.Getenv(name)), `"'`)
+}
+
+func keystorePassphrase(prefix string) (string, error) {
+	if file := env(prefix + "KEYSTORE_PASSWORD_FILE"); file != "" {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %sKEYSTORE_PASSWORD_FILE: %w", prefix, err)
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	}
+	if pass, ok := os.LookupEnv(prefix + "KEYSTORE_PASSWORD"); ok {
+		return pass, nil
+	}
+	return "", fmt.Errorf("%sKEYSTORE_PASSWORD_FILE or %sKEYSTORE_PASSWORD is required", prefix, prefix)
+}
@@ -8,6 +8,27 @@ const UI = {
         try { return await resp.json(); } catch (err) { console.error("JSON Parse Error:", err); return null; }
     },
 
+    // Fetch every page of a paginated list endpoint by following page.next_cursor.
+    // Resolves to { data, total }; rejects on the first failed page.
+    fetchAll: async (url, options = {}) => {
+        const data = [];
+        let total = 0;
+        let cursor = '';
+        do {
+            const sep = url.includes('?') ? '&' : '?';
+            const pageUrl = cursor ? `${url}${sep}cursor=${encodeURIComponent(cursor)}` : url;
+            const resp = await fetch(pageUrl, options);
+            const json = await UI.safeJson(resp);
+            if (!resp.ok || !json || json.status === 'error') {
+                throw new Error((json && json.message) || `Request failed (${resp.status})`);
+            }
+            data.push(...(json.data || []));
+            total = json.page ? json.page.total : data.length;
+            cursor = json.page ? json.page.next_cursor : '';
+        } while (cursor);
+        return { data, total };
+    },
+
     // Show Toast Notification
     toast: (message, type = 'info') => {
         let container = document.getElementById('toast-container');
@@ -0,0 +1,329 @@
+﻿package txmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrBudgetExceeded is returned by Send when a company's remaining gas budget cannot
+// cover the worst-case cost of the new transaction.
+var ErrBudgetExceeded = errors.New("gas budget exceeded")
+
+// UnattributedCompany is the bucket charged for sends that name no company, so operator
+// overhead is still counted and capped by the default budget.
+const UnattributedCompany = "unattributed"
+
+// Budget caps what one company may spend on one chain, in wei of that chain's native token.
+type Budget struct {
+	Company   string               `bson:"company" json:"company"`
+	Chain     string               `bson:"chain" json:"chain"`
+	Limit     primitive.Decimal128 `bson:"limit_wei" json:"limit_wei"`
+	UpdatedAt time.Time            `bson:"updated_at" json:"updated_at"`
+}
+
+// SpendLine is one row of a spend breakdown (per election or per purpose).
+type SpendLine struct {
+	Key          string `json:"key"`
+	Transactions int64  `json:"transactions"`
+	GasUsed      int64  `json:"gas_used"`
+	SpentWei     string `json:"spent_wei"`
+	ReservedWei  string `json:"reserved_wei"`
+}
+
+// Spend summarises a company's gas usage on one chain. Spent is the real cost from
+// receipts (reverted txs still pay); Reserved is the worst-case cost of PENDING txs.
+type Spend struct {
+	Company      string      `json:"company"`
+	Chain        string      `json:"chain"`
+	LimitWei     string      `json:"limit_wei,omitempty"` // empty = unlimited
+	SpentWei     string      `json:"spent_wei"`
+	ReservedWei  string      `json:"reserved_wei"`
+	RemainingWei string      `json:"remaining_wei,omitempty"`
+	Exhausted    bool        `json:"exhausted"`
+	Transactions int64       `json:"transactions"`
+	GasUsed      int64       `json:"gas_used"`
+	ByElection   []SpendLine `json:"by_election"`
+	ByPurpose    []SpendLine `json:"by_purpose"`
+
+	spent, reserved, limit *big.Int
+}
+
+// NormalizeCompany is the key budgets and records are stored under.
+func NormalizeCompany(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func (s *mongoStore) SetBudget(ctx context.Context, company, chain string, limit *big.Int) error {
+	company = NormalizeCompany(company)
+	filter := bson.M{"company": company, "chain": chain}
+	if limit == nil {
+		_, err := s.budgets.DeleteOne(ctx, filter)
+		return err
+	}
+	if limit.Sign() < 0 {
+		return errors.New("budget must not be negative")
+	}
+	d, err := toDecimal(limit)
+	if err != nil {
+		return err
+	}
+	_, err = s.budgets.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"limit_wei": d, "updated_at": time.Now().UTC()}}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (s *mongoStore) budget(ctx context.Context, company, chain string) (*big.Int, error) {
+	var b Budget
+	err := s.budgets.FindOne(ctx, bson.M{"company": NormalizeCompany(company), "chain": chain}).Decode(&b)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decimalToBig(b.Limit), nil
+}
+
+func (s *mongoStore) Spend(ctx context.Context, company, chain string, defaultLimit *big.Int) (*Spend, error) {
+	company = NormalizeCompany(company)
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"company": company, "chain": chain}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      bson.M{"election": "$election", "purpose": "$purpose", "status": "$status"},
+			"txs":      bson.M{"$sum": 1},
+			"gas_used": bson.M{"$sum": "$gas_used"},
+			"cost":     bson.M{"$sum": "$cost_wei"},
+			"max_cost": bson.M{"$sum": "$max_cost_wei"},
+		}}},
+	}
+	cursor, err := s.txs.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		ID struct {
+			Election string `bson:"election"`
+			Purpose  string `bson:"purpose"`
+			Status   string `bson:"status"`
+		} `bson:"_id"`
+		Txs     int64       `bson:"txs"`
+		GasUsed int64       `bson:"gas_used"`
+		Cost    interface{} `bson:"cost"`
+		MaxCost interface{} `bson:"max_cost"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	groups := make([]spendGroup, 0, len(rows))
+	for _, r := range rows {
+		groups = append(groups, spendGroup{
+			Election: r.ID.Election, Purpose: r.ID.Purpose, Status: r.ID.Status,
+			Txs: r.Txs, GasUsed: r.GasUsed, Cost: anyToBig(r.Cost), MaxCost: anyToBig(r.MaxCost),
+		})
+	}
+	return summarizeSpend(ctx, s, company, chain, groups, defaultLimit)
+}
+
+// spendGroup totals a company's records on one chain that share election, purpose and status.
+type spendGroup struct {
+	Election, Purpose, Status string
+	Txs, GasUsed              int64
+	Cost, MaxCost             *big.Int
+}
+
+// summarizeSpend builds a Spend from the groups and the company's budget (defaultLimit
+// when it has none).
+func summarizeSpend(ctx context.Context, s Store, company, chain string, groups []spendGroup, defaultLimit *big.Int) (*Spend, error) {
+	out := &Spend{Company: company, Chain: chain, spent: new(big.Int), reserved: new(big.Int)}
+	byElection := map[string]*spendAcc{}
+	byPurpose := map[string]*spendAcc{}
+	for _, g := range groups {
+		spent, reserved := new(big.Int), new(big.Int)
+		switch g.Status {
+		case StatusConfirmed, StatusReverted:
+			spent = g.Cost
+		case StatusPending:
+			reserved = g.MaxCost
+		}
+		out.Transactions += g.Txs
+		out.GasUsed += g.GasUsed
+		out.spent.Add(out.spent, spent)
+		out.reserved.Add(out.reserved, reserved)
+		accumulate(byElection, g.Election, g.Txs, g.GasUsed, spent, reserved)
+		accumulate(byPurpose, g.Purpose, g.Txs, g.GasUsed, spent, reserved)
+	}
+	out.ByElection = spendLines(byElection)
+	out.ByPurpose = spendLines(byPurpose)
+	out.SpentWei = out.spent.String()
+	out.ReservedWei = out.reserved.String()
+
+	limit, err := s.budget(ctx, company, chain)
+	if err != nil {
+		return nil, err
+	}
+	if limit == nil {
+		limit = defaultLimit
+	}
+	if limit != nil {
+		out.limit = limit
+		out.LimitWei = limit.String()
+		remaining := new(big.Int).Sub(limit, out.spent)
+		remaining.Sub(remaining, out.reserved)
+		if remaining.Sign() <= 0 {
+			remaining.SetInt64(0)
+			out.Exhausted = true
+		}
+		out.RemainingWei = remaining.String()
+	}
+	return out, nil
+}
+
+// spendLedger keeps each company's committed wei (spent plus reserved) in memory, so a
+// send costs one budget lookup instead of an aggregation over every record. A company is
+// loaded from the store the first time it sends on this manager; after that reservations,
+// bumps, receipts and failures move its total.
+type spendLedger struct {
+	mu        sync.Mutex
+	committed map[string]*big.Int
+}
+
+// reserve adds cost to the company's total, or fails with ErrBudgetExceeded when the
+// total would pass its cap.
+func (m *Manager) reserve(ctx context.Context, company string, cost *big.Int) error {
+	l := &m.ledger
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	total, ok := l.committed[company]
+	if !ok {
+		spend, err := m.cfg.Store.Spend(ctx, company, m.cfg.Chain, nil)
+		if err != nil {
+			return fmt.Errorf("failed to read gas spend: %w", err)
+		}
+		total = new(big.Int).Add(spend.spent, spend.reserved)
+		l.committed[company] = total
+	}
+
+	limit, err := m.cfg.Store.budget(ctx, company, m.cfg.Chain)
+	if err != nil {
+		return fmt.Errorf("failed to read gas budget: %w", err)
+	}
+	if limit == nil {
+		limit = m.cfg.DefaultBudget
+	}
+	if limit != nil {
+		after := new(big.Int).Add(total, cost)
+		if after.Cmp(limit) > 0 {
+			remaining := new(big.Int).Sub(limit, total)
+			if remaining.Sign() < 0 {
+				remaining.SetInt64(0)
+			}
+			return fmt.Errorf("%w: %s on %s has %s wei left, transaction may cost %s wei", ErrBudgetExceeded, company, m.cfg.Chain, remaining, cost)
+		}
+	}
+	total.Add(total, cost)
+	return nil
+}
+
+// settle runs a store update and, when it succeeds, moves the company's total by delta.
+// Both happen under the ledger lock so a concurrent first load cannot count the change
+// twice. A nil update only adjusts the total.
+func (m *Manager) settle(company string, delta *big.Int, update func() error) error {
+	l := &m.ledger
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if update != nil {
+		if err := update(); err != nil {
+			return err
+		}
+	}
+	if total, ok := l.committed[company]; ok && delta.Sign() != 0 {
+		total.Add(total, delta)
+	}
+	return nil
+}
+
+type spendAcc struct {
+	txs, gas        int64
+	spent, reserved *big.Int
+}
+
+func accumulate(m map[string]*spendAcc, key string, txs, gas int64, spent, reserved *big.Int) {
+	a, ok := m[key]
+	if !ok {
+		a = &spendAcc{spent: new(big.Int), reserved: new(big.Int)}
+		m[key] = a
+	}
+	a.txs += txs
+	a.gas += gas
+	a.spent.Add(a.spent, spent)
+	a.reserved.Add(a.reserved, reserved)
+}
+
+// spendLines flattens an accumulator map, biggest spender first.
+func spendLines(m map[string]*spendAcc) []SpendLine {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if c := m[keys[i]].spent.Cmp(m[keys[j]].spent); c != 0 {
+			return c > 0
+		}
+		return keys[i] < keys[j]
+	})
+	lines := make([]SpendLine, 0, len(keys))
+	for _, k := range keys {
+		a := m[k]
+		lines = append(lines, SpendLine{Key: k, Transactions: a.txs, GasUsed: a.gas, SpentWei: a.spent.String(), ReservedWei: a.reserved.String()})
+	}
+	return lines
+}
+
+// Wei amounts are stored as Decimal128 so MongoDB can $sum them without int64 overflow.
+func toDecimal(v *big.Int) (primitive.Decimal128, error) {
+	d, ok := primitive.ParseDecimal128FromBigInt(v, 0)
+	if !ok {
+		return primitive.Decimal128{}, fmt.Errorf("wei amount %s out of Decimal128 range", v)
+	}
+	return d, nil
+}
+
+func decimalToBig(d primitive.Decimal128) *big.Int {
+	v, exp, err := d.BigInt()
+	if err != nil {
+		return new(big.Int)
+	}
+	if exp > 0 {
+		v.Mul(v, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil))
+	} else if exp < 0 {
+		v.Div(v, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-exp)), nil))
+	}
+	return v
+}
+
+func anyToBig(v interface{}) *big.Int {
+	switch n := v.(type) {
+	case primitive.Decimal128:
+		return decimalToBig(n)
+	case int32:
+		return big.NewInt(int64(n))
+	case int64:
+		return big.NewInt(n)
+	default:
+		return new(big.Int)
+	}
+}
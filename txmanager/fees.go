@@ -0,0 +1,71 @@
+﻿package txmanager
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// FeeStrategy scales the node's fee suggestion. Percentages keep the arithmetic in big.Int.
+type FeeStrategy struct {
+	Name           string
+	TipPercent     int64 // applied to the suggested priority fee (or legacy gas price)
+	BaseFeePercent int64 // fee cap headroom over the current base fee
+}
+
+// Built-in strategies. "standard" matches the fee policy used before strategies existed.
+var (
+	StrategyEconomy  = FeeStrategy{Name: "economy", TipPercent: 100, BaseFeePercent: 125}
+	StrategyStandard = FeeStrategy{Name: "standard", TipPercent: 100, BaseFeePercent: 200}
+	StrategyFast     = FeeStrategy{Name: "fast", TipPercent: 150, BaseFeePercent: 300}
+)
+
+var feeStrategies = map[string]FeeStrategy{
+	StrategyEconomy.Name:  StrategyEconomy,
+	StrategyStandard.Name: StrategyStandard,
+	StrategyFast.Name:     StrategyFast,
+}
+
+// ParseFeeStrategy resolves a strategy by name. An empty name is "standard".
+func ParseFeeStrategy(name string) (FeeStrategy, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return StrategyStandard, nil
+	}
+	s, ok := feeStrategies[name]
+	if !ok {
+		return FeeStrategy{}, fmt.Errorf("unknown fee strategy %q (want economy, standard or fast)", name)
+	}
+	return s, nil
+}
+
+func (s FeeStrategy) tip(suggested *big.Int) *big.Int {
+	return percentOf(suggested, s.TipPercent)
+}
+
+// feeCap is baseFee * BaseFeePercent/100 + tip: the most the tx will ever pay per gas.
+func (s FeeStrategy) feeCap(baseFee, tip *big.Int) *big.Int {
+	return new(big.Int).Add(percentOf(baseFee, s.BaseFeePercent), tip)
+}
+
+func percentOf(v *big.Int, pct int64) *big.Int {
+	out := new(big.Int).Mul(v, big.NewInt(pct))
+	return out.Div(out, big.NewInt(100))
+}
+
+// maxCost is the worst-case wei a record can spend: gas limit at its fee cap plus value.
+func maxCost(rec *Record) *big.Int {
+	price := rec.GasFeeCap
+	if rec.GasPrice != "" {
+		price = rec.GasPrice
+	}
+	p, _ := new(big.Int).SetString(price, 10)
+	if p == nil {
+		p = new(big.Int)
+	}
+	out := new(big.Int).Mul(p, new(big.Int).SetUint64(rec.GasLimit))
+	if v, ok := new(big.Int).SetString(rec.Value, 10); ok {
+		out.Add(out, v)
+	}
+	return out
+}
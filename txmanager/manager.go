@@ -0,0 +1,584 @@
+﻿package txmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Backend is the subset of an Ethereum client the manager needs. *ethclient.Client satisfies it.
+type Backend interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// Hook runs once a record reaches a final state (CONFIRMED, REVERTED or FAILED).
+// receipt is nil for FAILED records.
+type Hook func(rec *Record, receipt *types.Receipt)
+
+// Config wires a manager to one chain and one sending account.
+type Config struct {
+	Chain   string // label stored on every record, e.g. "L2"
+	Backend Backend
+	ChainID *big.Int
+	From    common.Address
+	Signer  bind.SignerFn
+	Store   Store
+
+	GasLimit    uint64      // optional fixed gas limit (GAS_LIMIT)
+	GasPrice    *big.Int    // optional fixed legacy gas price (GAS_PRICE); disables EIP-1559
+	FeeStrategy FeeStrategy // default for requests that do not pick one (GAS_FEE_STRATEGY)
+
+	DefaultBudget *big.Int // cap for companies without an explicit budget (nil = unlimited)
+
+	PollInterval time.Duration // how often pending records are checked
+	BumpInterval time.Duration // how long a broadcast may sit unmined before a fee bump
+	MaxAttempts  int           // broadcasts per nonce before the record is marked FAILED
+	MaxFeeCap    *big.Int      // hard ceiling for bumped fees (nil = unlimited)
+}
+
+// Request describes a contract call the caller wants mined.
+type Request struct {
+	Purpose string
+	To      common.Address
+	Data    []byte
+	Value   *big.Int
+	Payload map[string]interface{} // context the confirmation hooks need (election, actor, ...)
+
+	Company     string // company charged for the gas; empty = UnattributedCompany
+	Election    string // election the tx belongs to, for the spend breakdown
+	FeeStrategy string // "economy", "standard" or "fast"; empty = Config.FeeStrategy
+}
+
+// Manager owns the nonce sequence of one account on one chain. Every outbound
+// transaction is persisted before broadcast, monitored until mined, and
+// re-signed with bumped EIP-1559 fees when it stalls.
+type Manager struct {
+	cfg Config
+
+	mu        sync.Mutex // serialises nonce allocation + broadcast
+	nonceKey  string
+	hooksMu   sync.RWMutex
+	hooks     map[string][]Hook
+	startOnce sync.Once
+	ledger    spendLedger
+}
+
+// bumpNumerator/bumpDenominator give the 12.5% replacement bump; geth requires at least 10%.
+const (
+	bumpNumerator   = 1125
+	bumpDenominator = 1000
+)
+
+// New validates cfg, fills defaults and returns an idle manager. Call Start to begin monitoring.
+func New(cfg Config) (*Manager, error) {
+	if cfg.Backend == nil || cfg.Store == nil || cfg.Signer == nil {
+		return nil, errors.New("txmanager: backend, store and signer are required")
+	}
+	if cfg.ChainID == nil || cfg.ChainID.Sign() == 0 {
+		return nil, errors.New("txmanager: chain id is required")
+	}
+	if cfg.Chain == "" {
+		cfg.Chain = "L2"
+	}
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	if cfg.BumpInterval == 0 {
+		cfg.BumpInterval = 45 * time.Second
+	}
+	if cfg.MaxAttempts == 0 {
+		cfg.MaxAttempts = 8
+	}
+	if cfg.FeeStrategy.Name == "" {
+		cfg.FeeStrategy = StrategyStandard
+	}
+	return &Manager{
+		cfg:      cfg,
+		nonceKey: cfg.Chain + ":" + strings.ToLower(cfg.From.Hex()),
+		hooks:    make(map[string][]Hook),
+		ledger:   spendLedger{committed: make(map[string]*big.Int)},
+	}, nil
+}
+
+// From returns the sending account.
+func (m *Manager) From() common.Address { return m.cfg.From }
+
+// Chain returns the chain label.
+func (m *Manager) Chain() string { return m.cfg.Chain }
+
+// Store exposes the persistence layer for status endpoints.
+func (m *Manager) Store() Store { return m.cfg.Store }
+
+// Spend reports a company's gas usage on this manager's chain against its budget.
+func (m *Manager) Spend(ctx context.Context, company string) (*Spend, error) {
+	return m.cfg.Store.Spend(ctx, company, m.cfg.Chain, m.cfg.DefaultBudget)
+}
+
+// OnFinal registers a hook for every record with the given purpose. Hooks must be
+// registered before Start so that records recovered after a restart still trigger them.
+func (m *Manager) OnFinal(purpose string, fn Hook) {
+	m.hooksMu.Lock()
+	defer m.hooksMu.Unlock()
+	m.hooks[purpose] = append(m.hooks[purpose], fn)
+}
+
+// Start launches the monitor loop. Pending records left over from a previous
+// process are picked up on the first tick.
+func (m *Manager) Start(ctx context.Context) {
+	m.startOnce.Do(func() {
+		go m.loop(ctx)
+		log.Printf("[TXM] %s manager started for %s", m.cfg.Chain, m.cfg.From.Hex())
+	})
+}
+
+// Send estimates, signs, persists and broadcasts a transaction. The returned record
+// is already PENDING; the caller does not need to wait for mining. Requests charged to
+// a company fail with ErrBudgetExceeded when its remaining budget cannot cover them.
+func (m *Manager) Send(ctx context.Context, req Request) (*Record, error) {
+	value := req.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	strategy := m.cfg.FeeStrategy
+	if req.FeeStrategy != "" {
+		var err error
+		if strategy, err = ParseFeeStrategy(req.FeeStrategy); err != nil {
+			return nil, err
+		}
+	}
+
+	gasLimit := m.cfg.GasLimit
+	if gasLimit == 0 {
+		est, err := m.cfg.Backend.EstimateGas(ctx, ethereum.CallMsg{From: m.cfg.From, To: &req.To, Data: req.Data, Value: value})
+		if err != nil {
+			return nil, fmt.Errorf("gas estimation failed: %w", err)
+		}
+		gasLimit = est + est/5 // 20% headroom
+	}
+
+	now := time.Now().UTC()
+	rec := &Record{
+		Chain:     m.cfg.Chain,
+		Purpose:   req.Purpose,
+		Payload:   req.Payload,
+		Company:   NormalizeCompany(req.Company),
+		Election:  req.Election,
+		From:      m.cfg.From.Hex(),
+		To:        req.To.Hex(),
+		Data:      common.Bytes2Hex(req.Data),
+		Value:     value.String(),
+		GasLimit:  gasLimit,
+		Status:    StatusPending,
+		TxHashes:  []string{},
+		CreatedAt: now,
+		UpdatedAt: now,
+
+		FeeStrategy: strategy.Name,
+	}
+	if err := m.initialFees(ctx, rec, strategy); err != nil {
+		return nil, err
+	}
+	worst := maxCost(rec)
+	reserved, err := toDecimal(worst)
+	if err != nil {
+		return nil, err
+	}
+	rec.MaxCost = reserved
+	if rec.Company == "" {
+		rec.Company = UnattributedCompany
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.reserve(ctx, rec.Company, worst); err != nil {
+		return nil, err
+	}
+	release := new(big.Int).Neg(worst)
+
+	nonce, err := m.allocateNonce(ctx)
+	if err != nil {
+		_ = m.settle(rec.Company, release, nil)
+		return nil, err
+	}
+	rec.Nonce = nonce
+
+	tx, err := m.sign(rec)
+	if err != nil {
+		_ = m.settle(rec.Company, release, nil)
+		return nil, err
+	}
+	rec.TxHash = tx.Hash().Hex()
+	rec.TxHashes = append(rec.TxHashes, rec.TxHash)
+	rec.Attempts = 1
+	rec.LastBroadcastAt = now
+
+	// Persist first: if the process dies right after broadcast, the monitor still owns the tx.
+	if err := m.cfg.Store.insert(ctx, rec); err != nil {
+		_ = m.settle(rec.Company, release, nil)
+		return nil, fmt.Errorf("failed to persist transaction: %w", err)
+	}
+
+	// A refused broadcast does not prove the node dropped the tx (a timeout may hide an
+	// accepted one), so the record stays PENDING and keeps its nonce and reservation. The
+	// monitor finds its receipt, rebroadcasts it, or fails it once the nonce is taken.
+	if err := m.cfg.Backend.SendTransaction(ctx, tx); err != nil && !isAlreadyKnown(err) {
+		rec.Error = err.Error()
+		_ = m.cfg.Store.update(context.Background(), rec.ID, bson.M{"error": rec.Error})
+		log.Printf("[TXM WARN] %s %s broadcast nonce=%d tx=%s failed, left pending: %v", m.cfg.Chain, rec.Purpose, rec.Nonce, rec.TxHash, err)
+	} else {
+		log.Printf("[TXM] %s %s broadcast nonce=%d tx=%s", m.cfg.Chain, rec.Purpose, rec.Nonce, rec.TxHash)
+	}
+
+	if err := m.cfg.Store.saveNonce(ctx, m.nonceKey, nonce+1); err != nil {
+		log.Printf("[TXM WARN] %s failed to persist nonce %d: %v", m.cfg.Chain, nonce+1, err)
+	}
+	return rec, nil
+}
+
+// allocateNonce takes the larger of the persisted cursor and the node's pending nonce,
+// so a restart never reuses a nonce and externally-sent txs are skipped over.
+func (m *Manager) allocateNonce(ctx context.Context) (uint64, error) {
+	stored, err := m.cfg.Store.nextNonce(ctx, m.nonceKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read nonce cursor: %w", err)
+	}
+	chainNonce, err := m.cfg.Backend.PendingNonceAt(ctx, m.cfg.From)
+	if err != nil {
+		log.Printf("[TXM WARN] %s PendingNonceAt failed, using stored cursor %d: %v", m.cfg.Chain, stored, err)
+		return stored, nil
+	}
+	if chainNonce > stored {
+		return chainNonce, nil
+	}
+	return stored, nil
+}
+
+// initialFees fills either a legacy gas price or EIP-1559 tip/fee caps, scaled by the strategy.
+func (m *Manager) initialFees(ctx context.Context, rec *Record, strategy FeeStrategy) error {
+	if m.cfg.GasPrice != nil && m.cfg.GasPrice.Sign() > 0 {
+		rec.GasPrice = m.cfg.GasPrice.String()
+		return nil
+	}
+
+	head, err := m.cfg.Backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to fetch latest header: %w", err)
+	}
+	if head.BaseFee == nil {
+		price, err := m.cfg.Backend.SuggestGasPrice(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to suggest gas price: %w", err)
+		}
+		rec.GasPrice = m.capFee(strategy.tip(price)).String()
+		return nil
+	}
+
+	tip, err := m.cfg.Backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to suggest gas tip: %w", err)
+	}
+	tip = strategy.tip(tip)
+	feeCap := m.capFee(strategy.feeCap(head.BaseFee, tip))
+	if tip.Cmp(feeCap) > 0 {
+		tip = new(big.Int).Set(feeCap)
+	}
+	rec.GasTipCap = tip.String()
+	rec.GasFeeCap = feeCap.String()
+	return nil
+}
+
+func (m *Manager) capFee(v *big.Int) *big.Int {
+	if m.cfg.MaxFeeCap != nil && v.Cmp(m.cfg.MaxFeeCap) > 0 {
+		return new(big.Int).Set(m.cfg.MaxFeeCap)
+	}
+	return v
+}
+
+// sign builds the transaction described by rec and signs it with the configured signer.
+func (m *Manager) sign(rec *Record) (*types.Transaction, error) {
+	to := common.HexToAddress(rec.To)
+	value, _ := new(big.Int).SetString(rec.Value, 10)
+	data := common.FromHex(rec.Data)
+
+	var inner types.TxData
+	if rec.GasPrice != "" {
+		price, _ := new(big.Int).SetString(rec.GasPrice, 10)
+		inner = &types.LegacyTx{Nonce: rec.Nonce, GasPrice: price, Gas: rec.GasLimit, To: &to, Value: value, Data: data}
+	} else {
+		tip, _ := new(big.Int).SetString(rec.GasTipCap, 10)
+		feeCap, _ := new(big.Int).SetString(rec.GasFeeCap, 10)
+		inner = &types.DynamicFeeTx{ChainID: m.cfg.ChainID, Nonce: rec.Nonce, GasTipCap: tip, GasFeeCap: feeCap, Gas: rec.GasLimit, To: &to, Value: value, Data: data}
+	}
+
+	signed, err := m.cfg.Signer(m.cfg.From, types.NewTx(inner))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return signed, nil
+}
+
+func (m *Manager) loop(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		m.checkPending(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkPending walks every PENDING record: finalise the mined ones, bump the stalled ones.
+func (m *Manager) checkPending(ctx context.Context) {
+	listCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	recs, err := m.cfg.Store.pending(listCtx, m.cfg.Chain, m.cfg.From.Hex())
+	cancel()
+	if err != nil {
+		log.Printf("[TXM ERROR] %s failed to load pending transactions: %v", m.cfg.Chain, err)
+		return
+	}
+
+	for i := range recs {
+		rec := &recs[i]
+		recCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+		m.checkOne(recCtx, rec)
+		cancel()
+	}
+}
+
+func (m *Manager) checkOne(ctx context.Context, rec *Record) {
+	// Any of the hashes we broadcast for this nonce may be the one that got mined.
+	unknown := false
+	for i := len(rec.TxHashes) - 1; i >= 0; i-- {
+		receipt, err := m.cfg.Backend.TransactionReceipt(ctx, common.HexToHash(rec.TxHashes[i]))
+		if err == nil && receipt != nil {
+			m.finalize(rec, rec.TxHashes[i], receipt)
+			return
+		}
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			unknown = true
+		}
+	}
+	if unknown {
+		// The node could not say whether a hash was mined; failing or bumping now would be a
+		// guess, so wait for the next tick.
+		log.Printf("[TXM WARN] %s receipt lookup for nonce=%d failed; retrying", m.cfg.Chain, rec.Nonce)
+		return
+	}
+
+	// No receipt. If the account nonce already moved past ours, some other tx took the slot.
+	mined, err := m.cfg.Backend.NonceAt(ctx, m.cfg.From, nil)
+	if err == nil && mined > rec.Nonce && time.Since(rec.LastBroadcastAt) > m.cfg.BumpInterval {
+		m.fail(rec, fmt.Sprintf("nonce %d was consumed by another transaction", rec.Nonce))
+		return
+	}
+
+	if time.Since(rec.LastBroadcastAt) < m.cfg.BumpInterval {
+		return
+	}
+	if rec.Attempts >= m.cfg.MaxAttempts {
+		m.fail(rec, fmt.Sprintf("not mined after %d broadcasts", rec.Attempts))
+		return
+	}
+	m.bump(ctx, rec)
+}
+
+// bump re-signs the record at the same nonce with fees raised by 12.5% and rebroadcasts it.
+func (m *Manager) bump(ctx context.Context, rec *Record) {
+	if rec.GasPrice != "" {
+		price, _ := new(big.Int).SetString(rec.GasPrice, 10)
+		rec.GasPrice = m.capFee(bumpFee(price)).String()
+	} else {
+		tip, _ := new(big.Int).SetString(rec.GasTipCap, 10)
+		feeCap, _ := new(big.Int).SetString(rec.GasFeeCap, 10)
+		newTip := bumpFee(tip)
+		newFeeCap := bumpFee(feeCap)
+
+		// Keep up with a rising base fee, not just the 12.5% minimum.
+		if head, err := m.cfg.Backend.HeaderByNumber(ctx, nil); err == nil && head.BaseFee != nil {
+			strategy, err := ParseFeeStrategy(rec.FeeStrategy)
+			if err != nil {
+				strategy = m.cfg.FeeStrategy
+			}
+			floor := strategy.feeCap(head.BaseFee, newTip)
+			if floor.Cmp(newFeeCap) > 0 {
+				newFeeCap = floor
+			}
+		}
+		newFeeCap = m.capFee(newFeeCap)
+		if newTip.Cmp(newFeeCap) > 0 {
+			newTip = new(big.Int).Set(newFeeCap)
+		}
+		rec.GasTipCap = newTip.String()
+		rec.GasFeeCap = newFeeCap.String()
+	}
+
+	tx, err := m.sign(rec)
+	if err != nil {
+		log.Printf("[TXM ERROR] %s re-sign failed for nonce %d: %v", m.cfg.Chain, rec.Nonce, err)
+		return
+	}
+
+	if err := m.cfg.Backend.SendTransaction(ctx, tx); err != nil && !isAlreadyKnown(err) {
+		// "nonce too low" means one of our earlier hashes got mined; the next tick will find it.
+		// A refused rebroadcast is not a broadcast, so it does not count toward MaxAttempts;
+		// the record fails once another transaction takes its nonce.
+		log.Printf("[TXM WARN] %s rebroadcast nonce=%d attempt=%d failed: %v", m.cfg.Chain, rec.Nonce, rec.Attempts, err)
+		_ = m.cfg.Store.update(context.Background(), rec.ID, bson.M{"error": err.Error(), "last_broadcast_at": time.Now().UTC()})
+		return
+	}
+
+	hash := tx.Hash().Hex()
+	rec.Attempts++
+	// Bumps are never refused for budget reasons, but the larger reservation is recorded.
+	oldMax := decimalToBig(rec.MaxCost)
+	newMax := maxCost(rec)
+	rec.MaxCost, _ = toDecimal(newMax)
+	log.Printf("[TXM] %s bumped %s nonce=%d attempt=%d tx=%s", m.cfg.Chain, rec.Purpose, rec.Nonce, rec.Attempts, hash)
+	update := bson.M{
+		"tx_hash":           hash,
+		"tx_hashes":         append(rec.TxHashes, hash),
+		"attempts":          rec.Attempts,
+		"gas_price":         rec.GasPrice,
+		"gas_tip_cap":       rec.GasTipCap,
+		"gas_fee_cap":       rec.GasFeeCap,
+		"max_cost_wei":      rec.MaxCost,
+		"last_broadcast_at": time.Now().UTC(),
+		"error":             "",
+	}
+	_ = m.settle(rec.Company, newMax.Sub(newMax, oldMax), func() error {
+		return m.cfg.Store.update(context.Background(), rec.ID, update)
+	})
+}
+
+func (m *Manager) finalize(rec *Record, hash string, receipt *types.Receipt) {
+	status := StatusConfirmed
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		status = StatusReverted
+	}
+	now := time.Now().UTC()
+
+	set := bson.M{
+		"status":       status,
+		"tx_hash":      hash,
+		"block_number": receipt.BlockNumber.Uint64(),
+		"gas_used":     receipt.GasUsed,
+		"confirmed_at": now,
+	}
+	// The reservation is replaced by the real cost (zero when the node reports no price).
+	delta := new(big.Int).Neg(decimalToBig(rec.MaxCost))
+	if receipt.EffectiveGasPrice != nil {
+		set["effective_gas_price"] = receipt.EffectiveGasPrice.String()
+		rec.EffectiveGasPrice = receipt.EffectiveGasPrice.String()
+
+		cost := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+		if d, err := toDecimal(cost); err == nil {
+			set["cost_wei"] = d
+			rec.Cost = d
+			delta.Add(delta, cost)
+		}
+	}
+	if receipt.ContractAddress != (common.Address{}) {
+		set["contract_address"] = receipt.ContractAddress.Hex()
+		rec.ContractAddress = receipt.ContractAddress.Hex()
+	}
+	if err := m.settle(rec.Company, delta, func() error {
+		return m.cfg.Store.update(context.Background(), rec.ID, set)
+	}); err != nil {
+		log.Printf("[TXM ERROR] %s failed to persist receipt for %s: %v", m.cfg.Chain, hash, err)
+		return // retry on next tick rather than firing hooks for an unsaved state
+	}
+
+	rec.Status = status
+	rec.TxHash = hash
+	rec.BlockNumber = receipt.BlockNumber.Uint64()
+	rec.GasUsed = receipt.GasUsed
+	rec.ConfirmedAt = &now
+
+	log.Printf("[TXM] %s %s %s in block %d (tx %s)", m.cfg.Chain, rec.Purpose, status, rec.BlockNumber, hash)
+	m.runHooks(rec, receipt)
+}
+
+func (m *Manager) fail(rec *Record, reason string) {
+	release := new(big.Int).Neg(decimalToBig(rec.MaxCost))
+	if err := m.settle(rec.Company, release, func() error {
+		return m.cfg.Store.update(context.Background(), rec.ID, bson.M{"status": StatusFailed, "error": reason})
+	}); err != nil {
+		log.Printf("[TXM ERROR] %s failed to mark record %s failed: %v", m.cfg.Chain, rec.ID.Hex(), err)
+		return
+	}
+	rec.Status = StatusFailed
+	rec.Error = reason
+	log.Printf("[TXM ERROR] %s %s nonce=%d failed: %s", m.cfg.Chain, rec.Purpose, rec.Nonce, reason)
+	m.runHooks(rec, nil)
+}
+
+func (m *Manager) runHooks(rec *Record, receipt *types.Receipt) {
+	m.hooksMu.RLock()
+	hooks := m.hooks[rec.Purpose]
+	m.hooksMu.RUnlock()
+
+	for _, h := range hooks {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("[TXM ERROR] hook for %s panicked: %v", rec.Purpose, r)
+				}
+			}()
+			h(rec, receipt)
+		}()
+	}
+}
+
+// Wait blocks until the record reaches a final state or ctx expires.
+func (m *Manager) Wait(ctx context.Context, id string) (*Record, error) {
+	ticker := time.NewTicker(m.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		rec, err := m.cfg.Store.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if rec.Status != StatusPending {
+			return rec, nil
+		}
+		select {
+		case <-ctx.Done():
+			return rec, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func bumpFee(v *big.Int) *big.Int {
+	out := new(big.Int).Mul(v, big.NewInt(bumpNumerator))
+	out.Add(out, big.NewInt(bumpDenominator-1)) // round up so tiny fees still move
+	return out.Div(out, big.NewInt(bumpDenominator))
+}
+
+func isAlreadyKnown(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction")
+}
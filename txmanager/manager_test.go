@@ -0,0 +1,284 @@
+﻿package txmanager
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"MAJOR-PROJECT/chain"
+	"MAJOR-PROJECT/signer"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	testChainID = big.NewInt(1337)
+	testTo      = common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	gwei        = big.NewInt(1_000_000_000)
+)
+
+// testBackend is a simulated chain whose next sends can be refused (an error, nothing
+// reaches the node) or dropped (reported as sent, nothing reaches the node).
+type testBackend struct {
+	chain.ChainBackend
+	refuse, drop int
+}
+
+func (b *testBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if b.refuse > 0 {
+		b.refuse--
+		return errors.New("connection reset by peer")
+	}
+	if b.drop > 0 {
+		b.drop--
+		return nil
+	}
+	return b.ChainBackend.SendTransaction(ctx, tx)
+}
+
+type testEnv struct {
+	backend *testBackend
+	store   Store
+	signer  signer.Signer
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := signer.NewKeySigner(key)
+	b := chain.NewSimulated("L2", testChainID, s.Address(), crypto.PubkeyToAddress(other.PublicKey))
+
+	// The node indexes no transactions until it has a block, so mine one from another
+	// account and leave the operator's nonce at zero.
+	warmup, err := types.SignTx(types.NewTx(&types.LegacyTx{GasPrice: new(big.Int).Mul(gwei, big.NewInt(10)), Gas: 21000, To: &testTo}), types.LatestSignerForChainID(testChainID), other)
+	if err == nil {
+		err = b.SendTransaction(context.Background(), warmup)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(b.Close)
+	return &testEnv{backend: &testBackend{ChainBackend: b}, store: NewMemoryStore(), signer: s}
+}
+
+// manager returns a manager over the env's chain and store; edit adjusts its config.
+// Every test drives the monitor with checkPending instead of Start.
+func (e *testEnv) manager(t *testing.T, edit func(*Config)) *Manager {
+	t.Helper()
+	cfg := Config{
+		Chain:        "L2",
+		Backend:      e.backend,
+		ChainID:      testChainID,
+		From:         e.signer.Address(),
+		Signer:       signer.SignerFn(e.signer, testChainID),
+		Store:        e.store,
+		GasLimit:     30000,
+		BumpInterval: time.Nanosecond,
+	}
+	if edit != nil {
+		edit(&cfg)
+	}
+	m, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func send(t *testing.T, m *Manager, company string) *Record {
+	t.Helper()
+	rec, err := m.Send(context.Background(), Request{Purpose: "TEST", To: testTo, Company: company})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	return rec
+}
+
+func load(t *testing.T, s Store, rec *Record) *Record {
+	t.Helper()
+	got, err := s.Get(context.Background(), rec.ID.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+// poll runs the monitor until done reports true. The simulated node answers receipt
+// lookups with "indexing in progress" for a moment after its first blocks, which the
+// monitor treats as unknown and retries.
+func poll(t *testing.T, m *Manager, done func() bool) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		m.checkPending(context.Background())
+		if done() {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("monitor did not reach the expected state")
+}
+
+func TestNonceAllocation(t *testing.T) {
+	env := newTestEnv(t)
+	m := env.manager(t, nil)
+	ctx := context.Background()
+
+	for want := uint64(0); want < 3; want++ {
+		if rec := send(t, m, ""); rec.Nonce != want {
+			t.Fatalf("nonce = %d, want %d", rec.Nonce, want)
+		}
+	}
+
+	// A tx sent outside the manager moves the node's nonce past the cursor.
+	raw := types.NewTx(&types.LegacyTx{Nonce: 3, GasPrice: new(big.Int).Mul(gwei, big.NewInt(10)), Gas: 21000, To: &testTo})
+	tx, err := env.signer.SignTx(raw, testChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.backend.ChainBackend.SendTransaction(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	if rec := send(t, m, ""); rec.Nonce != 4 {
+		t.Errorf("nonce after an external tx = %d, want 4", rec.Nonce)
+	}
+
+	// A refused broadcast still holds its nonce, so the next send does not reuse it.
+	env.backend.refuse = 1
+	refused := send(t, m, "")
+	if got := load(t, env.store, refused); got.Status != StatusPending || got.Error == "" {
+		t.Errorf("refused broadcast: status %s, error %q; want PENDING with the error", got.Status, got.Error)
+	}
+	if rec := send(t, m, ""); rec.Nonce != refused.Nonce+1 {
+		t.Errorf("nonce after a refused broadcast = %d, want %d", rec.Nonce, refused.Nonce+1)
+	}
+}
+
+func TestFeeBump(t *testing.T) {
+	env := newTestEnv(t)
+	m := env.manager(t, nil)
+
+	env.backend.drop = 1
+	rec := send(t, m, "")
+	// No receipt: re-signed with higher fees and sent again.
+	poll(t, m, func() bool { return len(load(t, env.store, rec).TxHashes) > 1 })
+
+	got := load(t, env.store, rec)
+	if len(got.TxHashes) != 2 || got.Attempts != 2 {
+		t.Fatalf("after bump: %d hashes, %d attempts; want 2 and 2", len(got.TxHashes), got.Attempts)
+	}
+	for _, fee := range [][2]string{{rec.GasTipCap, got.GasTipCap}, {rec.GasFeeCap, got.GasFeeCap}} {
+		before, _ := new(big.Int).SetString(fee[0], 10)
+		after, _ := new(big.Int).SetString(fee[1], 10)
+		if after.Cmp(bumpFee(before)) < 0 {
+			t.Errorf("fee %s bumped to %s, want at least %s", before, after, bumpFee(before))
+		}
+	}
+
+	poll(t, m, func() bool { return load(t, env.store, rec).Status != StatusPending })
+	got = load(t, env.store, rec)
+	if got.Status != StatusConfirmed || got.TxHash != got.TxHashes[1] {
+		t.Errorf("status %s with tx %s, want CONFIRMED with the bumped tx %s", got.Status, got.TxHash, got.TxHashes[1])
+	}
+}
+
+func TestRefusedRebroadcast(t *testing.T) {
+	env := newTestEnv(t)
+	m := env.manager(t, nil)
+
+	env.backend.drop = 1
+	rec := send(t, m, "")
+	env.backend.refuse = 1
+	poll(t, m, func() bool { return env.backend.refuse == 0 })
+
+	got := load(t, env.store, rec)
+	if got.Status != StatusPending || got.Attempts != 1 || len(got.TxHashes) != 1 {
+		t.Fatalf("after a refused rebroadcast: %s, %d attempts, %d hashes; want PENDING, 1, 1", got.Status, got.Attempts, len(got.TxHashes))
+	}
+
+	poll(t, m, func() bool { return load(t, env.store, rec).Status != StatusPending })
+	if got := load(t, env.store, rec); got.Status != StatusConfirmed {
+		t.Errorf("status %s, want CONFIRMED", got.Status)
+	}
+}
+
+func TestRestartRecovery(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	// The first process broadcasts and exits before the monitor sees the receipt.
+	rec := send(t, env.manager(t, nil), "")
+
+	m := env.manager(t, nil)
+	var final []*Record
+	m.OnFinal("TEST", func(r *Record, receipt *types.Receipt) {
+		if receipt == nil {
+			t.Errorf("hook for %s ran without a receipt", r.Status)
+		}
+		final = append(final, r)
+	})
+	poll(t, m, func() bool { return len(final) > 0 })
+	m.checkPending(ctx)
+
+	if len(final) != 1 || final[0].ID != rec.ID || final[0].Status != StatusConfirmed {
+		t.Fatalf("hooks after restart: %+v, want one CONFIRMED run for %s", final, rec.ID.Hex())
+	}
+	if next := send(t, m, ""); next.Nonce != rec.Nonce+1 {
+		t.Errorf("nonce after restart = %d, want %d", next.Nonce, rec.Nonce+1)
+	}
+}
+
+func TestBudgetReserveAndSettle(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	price := new(big.Int).Mul(gwei, big.NewInt(10))
+	m := env.manager(t, func(cfg *Config) { cfg.GasPrice = price })
+
+	// A send reserves gas limit x price; the receipt settles it at gas used x price.
+	worst := new(big.Int).Mul(big.NewInt(30000), price)
+	spent := new(big.Int).Mul(big.NewInt(21000), price)
+	limit := new(big.Int).Div(new(big.Int).Mul(worst, big.NewInt(9)), big.NewInt(5))
+	if err := env.store.SetBudget(ctx, "Acme@Example.com", "L2", limit); err != nil {
+		t.Fatal(err)
+	}
+
+	spend := func() *Spend {
+		s, err := m.Spend(ctx, "acme@example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	send(t, m, "acme@example.com")
+	if s := spend(); s.ReservedWei != worst.String() || s.SpentWei != "0" {
+		t.Errorf("after send: reserved %s, spent %s; want %s and 0", s.ReservedWei, s.SpentWei, worst)
+	}
+	if _, err := m.Send(ctx, Request{Purpose: "TEST", To: testTo, Company: "acme@example.com"}); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("second send over budget: got %v, want ErrBudgetExceeded", err)
+	}
+	if _, err := m.Send(ctx, Request{Purpose: "TEST", To: testTo, Company: "other@example.com"}); err != nil {
+		t.Errorf("a company without a budget was refused: %v", err)
+	}
+
+	poll(t, m, func() bool { return spend().ReservedWei == "0" })
+	if s := spend(); s.ReservedWei != "0" || s.SpentWei != spent.String() {
+		t.Errorf("after settle: reserved %s, spent %s; want 0 and %s", s.ReservedWei, s.SpentWei, spent)
+	}
+	// The unused part of the reservation is released, so one more send fits.
+	send(t, m, "acme@example.com")
+	if _, err := m.Send(ctx, Request{Purpose: "TEST", To: testTo, Company: "acme@example.com"}); err == nil || !strings.Contains(err.Error(), "wei left") {
+		t.Errorf("third send: got %v, want a budget error", err)
+	}
+}
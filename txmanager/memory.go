@@ -0,0 +1,292 @@
+﻿package txmanager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryStore keeps records, nonce cursors and budgets in process memory. Records are
+// held in their bson form, so updates keyed by bson field paths apply the way they do
+// in MongoDB and a reloaded payload has the same types.
+type memoryStore struct {
+	mu      sync.Mutex
+	txs     []bson.M
+	nonces  map[string]uint64
+	budgets map[string]*big.Int // company + " " + chain
+}
+
+// NewMemoryStore returns an empty in-memory store. Nothing is persisted, so a restart
+// forgets every transaction.
+func NewMemoryStore() Store {
+	return &memoryStore{nonces: map[string]uint64{}, budgets: map[string]*big.Int{}}
+}
+
+func toDoc(rec *Record) (bson.M, error) {
+	raw, err := bson.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	err = bson.Unmarshal(raw, &doc)
+	return doc, err
+}
+
+func fromDoc(doc bson.M) (*Record, error) {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var rec Record
+	err = bson.Unmarshal(raw, &rec)
+	return &rec, err
+}
+
+// setPath sets a dotted bson path, creating the embedded documents on the way.
+func setPath(doc bson.M, path string, v interface{}) {
+	keys := strings.Split(path, ".")
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := doc[k].(bson.M)
+		if !ok {
+			next = bson.M{}
+			doc[k] = next
+		}
+		doc = next
+	}
+	doc[keys[len(keys)-1]] = v
+}
+
+func unsetPath(doc bson.M, path string) {
+	keys := strings.Split(path, ".")
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := doc[k].(bson.M)
+		if !ok {
+			return
+		}
+		doc = next
+	}
+	delete(doc, keys[len(keys)-1])
+}
+
+// patch applies set and unset to a copy of doc, normalised back to the stored form.
+func patch(doc bson.M, set bson.M, unset []string) (bson.M, error) {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var next bson.M
+	if err := bson.Unmarshal(raw, &next); err != nil {
+		return nil, err
+	}
+	for k, v := range set {
+		setPath(next, k, v)
+	}
+	for _, k := range unset {
+		unsetPath(next, k)
+	}
+	rec, err := fromDoc(next)
+	if err != nil {
+		return nil, err
+	}
+	return toDoc(rec)
+}
+
+// index returns the position of the record with id, or -1. Callers hold s.mu.
+func (s *memoryStore) index(id primitive.ObjectID) int {
+	for i, doc := range s.txs {
+		if doc["_id"] == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// records decodes every stored record, in insertion order.
+func (s *memoryStore) records() ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Record, 0, len(s.txs))
+	for _, doc := range s.txs {
+		rec, err := fromDoc(doc)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *rec)
+	}
+	return out, nil
+}
+
+func (s *memoryStore) insert(ctx context.Context, rec *Record) error {
+	if rec.ID.IsZero() {
+		rec.ID = primitive.NewObjectID()
+	}
+	doc, err := toDoc(rec)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.txs = append(s.txs, doc)
+	return nil
+}
+
+func (s *memoryStore) update(ctx context.Context, id primitive.ObjectID, set bson.M) error {
+	set["updated_at"] = time.Now().UTC()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.index(id)
+	if i < 0 {
+		return nil
+	}
+	next, err := patch(s.txs[i], set, nil)
+	if err != nil {
+		return err
+	}
+	s.txs[i] = next
+	return nil
+}
+
+func (s *memoryStore) pending(ctx context.Context, chain, from string) ([]Record, error) {
+	recs, err := s.records()
+	if err != nil {
+		return nil, err
+	}
+	var out []Record
+	for _, rec := range recs {
+		if rec.Chain == chain && rec.From == from && rec.Status == StatusPending {
+			out = append(out, rec)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Nonce < out[j].Nonce })
+	return out, nil
+}
+
+func (s *memoryStore) Get(ctx context.Context, idOrHash string) (*Record, error) {
+	recs, err := s.records()
+	if err != nil {
+		return nil, err
+	}
+	oid, idErr := primitive.ObjectIDFromHex(idOrHash)
+	for i := range recs {
+		if idErr == nil {
+			if recs[i].ID == oid {
+				return &recs[i], nil
+			}
+			continue
+		}
+		if contains(recs[i].TxHashes, idOrHash) {
+			return &recs[i], nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *memoryStore) List(ctx context.Context, f Filter, limit int64) ([]Record, error) {
+	recs, err := s.records()
+	if err != nil {
+		return nil, err
+	}
+	out := []Record{}
+	for i := len(recs) - 1; i >= 0; i-- {
+		if f.match(&recs[i]) {
+			out = append(out, recs[i])
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	if limit > 0 && int64(len(out)) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (s *memoryStore) Redact(ctx context.Context, id primitive.ObjectID, set bson.M, unset []string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.index(id)
+	if i < 0 || s.txs[i]["status"] == StatusPending {
+		return false, nil
+	}
+	next, err := patch(s.txs[i], set, unset)
+	if err != nil {
+		return false, err
+	}
+	before, _ := bson.Marshal(s.txs[i])
+	after, _ := bson.Marshal(next)
+	s.txs[i] = next
+	return !bytes.Equal(before, after), nil
+}
+
+func (s *memoryStore) nextNonce(ctx context.Context, key string) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.nonces[key], nil
+}
+
+func (s *memoryStore) saveNonce(ctx context.Context, key string, next uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if next > s.nonces[key] {
+		s.nonces[key] = next
+	}
+	return nil
+}
+
+func (s *memoryStore) SetBudget(ctx context.Context, company, chain string, limit *big.Int) error {
+	key := NormalizeCompany(company) + " " + chain
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if limit == nil {
+		delete(s.budgets, key)
+		return nil
+	}
+	if limit.Sign() < 0 {
+		return errors.New("budget must not be negative")
+	}
+	s.budgets[key] = new(big.Int).Set(limit)
+	return nil
+}
+
+func (s *memoryStore) budget(ctx context.Context, company, chain string) (*big.Int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if limit, ok := s.budgets[NormalizeCompany(company)+" "+chain]; ok {
+		return new(big.Int).Set(limit), nil
+	}
+	return nil, nil
+}
+
+func (s *memoryStore) Spend(ctx context.Context, company, chain string, defaultLimit *big.Int) (*Spend, error) {
+	company = NormalizeCompany(company)
+	recs, err := s.records()
+	if err != nil {
+		return nil, err
+	}
+	var groups []spendGroup
+	at := map[[3]string]int{}
+	for _, rec := range recs {
+		if rec.Company != company || rec.Chain != chain {
+			continue
+		}
+		key := [3]string{rec.Election, rec.Purpose, rec.Status}
+		i, ok := at[key]
+		if !ok {
+			i = len(groups)
+			at[key] = i
+			groups = append(groups, spendGroup{Election: rec.Election, Purpose: rec.Purpose, Status: rec.Status, Cost: new(big.Int), MaxCost: new(big.Int)})
+		}
+		g := &groups[i]
+		g.Txs++
+		g.GasUsed += int64(rec.GasUsed)
+		g.Cost.Add(g.Cost, decimalToBig(rec.Cost))
+		g.MaxCost.Add(g.MaxCost, decimalToBig(rec.MaxCost))
+	}
+	return summarizeSpend(ctx, s, company, chain, groups, defaultLimit)
+}
@@ -0,0 +1,326 @@
+﻿package txmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Transaction statuses persisted in the transactions collection.
+const (
+	StatusPending   = "PENDING"   // signed and broadcast, waiting for a receipt
+	StatusConfirmed = "CONFIRMED" // mined with receipt status 1
+	StatusReverted  = "REVERTED"  // mined but the EVM reverted it
+	StatusFailed    = "FAILED"    // never broadcast, dropped, or gave up after max attempts
+)
+
+// Record is one outbound transaction owned by the manager. A record keeps the same
+// nonce for its whole life; every fee bump re-signs it and appends a new hash.
+type Record struct {
+	ID      primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	Chain   string                 `bson:"chain" json:"chain"` // "L1" or "L2"
+	Purpose string                 `bson:"purpose" json:"purpose"`
+	Payload map[string]interface{} `bson:"payload,omitempty" json:"payload,omitempty"`
+
+	// Spend attribution: the company whose budget pays for this tx and the election it serves.
+	Company  string `bson:"company,omitempty" json:"company,omitempty"`
+	Election string `bson:"election,omitempty" json:"election,omitempty"`
+
+	From  string `bson:"from" json:"from"`
+	To    string `bson:"to" json:"to"`
+	Data  string `bson:"data" json:"-"`                // hex calldata, needed to re-sign on bump
+	Value string `bson:"value,omitempty" json:"value"` // decimal wei
+
+	Nonce     uint64 `bson:"nonce" json:"nonce"`
+	GasLimit  uint64 `bson:"gas_limit" json:"gas_limit"`
+	GasPrice  string `bson:"gas_price,omitempty" json:"gas_price,omitempty"`     // legacy txs only
+	GasTipCap string `bson:"gas_tip_cap,omitempty" json:"gas_tip_cap,omitempty"` // EIP-1559
+	GasFeeCap string `bson:"gas_fee_cap,omitempty" json:"gas_fee_cap,omitempty"` // EIP-1559
+
+	FeeStrategy string               `bson:"fee_strategy,omitempty" json:"fee_strategy,omitempty"`
+	MaxCost     primitive.Decimal128 `bson:"max_cost_wei,omitempty" json:"max_cost_wei"` // gas limit x fee cap, reserved against the budget
+	Cost        primitive.Decimal128 `bson:"cost_wei,omitempty" json:"cost_wei"`         // gas used x effective price, from the receipt
+
+	TxHash   string   `bson:"tx_hash" json:"tx_hash"`     // latest broadcast hash (or the mined one)
+	TxHashes []string `bson:"tx_hashes" json:"tx_hashes"` // every hash ever broadcast for this nonce
+	Attempts int      `bson:"attempts" json:"attempts"`
+
+	Status            string `bson:"status" json:"status"`
+	Error             string `bson:"error,omitempty" json:"error,omitempty"`
+	BlockNumber       uint64 `bson:"block_number,omitempty" json:"block_number,omitempty"`
+	GasUsed           uint64 `bson:"gas_used,omitempty" json:"gas_used,omitempty"`
+	EffectiveGasPrice string `bson:"effective_gas_price,omitempty" json:"effective_gas_price,omitempty"`
+	ContractAddress   string `bson:"contract_address,omitempty" json:"contract_address,omitempty"`
+
+	CreatedAt       time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time  `bson:"updated_at" json:"updated_at"`
+	LastBroadcastAt time.Time  `bson:"last_broadcast_at" json:"last_broadcast_at"`
+	ConfirmedAt     *time.Time `bson:"confirmed_at,omitempty" json:"confirmed_at,omitempty"`
+}
+
+// ErrNotFound is returned by Store.Get when no record has the id or hash.
+var ErrNotFound = errors.New("txmanager: transaction not found")
+
+// Store persists records, the per-account nonce cursors and the gas budgets.
+// NewMongoStore keeps them in MongoDB; NewMemoryStore keeps them in process memory for
+// tests and the STORAGE_BACKEND=memory demo mode.
+type Store interface {
+	// Get looks a record up by its ObjectID hex or by any hash it was broadcast under.
+	// ErrNotFound when there is none.
+	Get(ctx context.Context, idOrHash string) (*Record, error)
+	// List returns the most recent records matching f; a limit of 0 returns them all.
+	List(ctx context.Context, f Filter, limit int64) ([]Record, error)
+	// Redact applies set and unset (bson field paths) to one record that is no longer
+	// pending, e.g. to pseudonymize a voter named in its payload and calldata. Pending
+	// records are left alone: their calldata is re-signed on every fee bump. Reports
+	// whether the record changed.
+	Redact(ctx context.Context, id primitive.ObjectID, set bson.M, unset []string) (bool, error)
+	// SetBudget creates or replaces a company's cap on one chain. A nil limit removes the cap.
+	SetBudget(ctx context.Context, company, chain string, limit *big.Int) error
+	// Spend aggregates every record attributed to the company on one chain. defaultLimit
+	// applies when the company has no explicit budget (nil = unlimited).
+	Spend(ctx context.Context, company, chain string, defaultLimit *big.Int) (*Spend, error)
+
+	insert(ctx context.Context, rec *Record) error
+	update(ctx context.Context, id primitive.ObjectID, set bson.M) error
+	// pending returns every PENDING record for one chain/account, oldest nonce first.
+	pending(ctx context.Context, chain, from string) ([]Record, error)
+	// nextNonce returns the persisted nonce cursor for an account (0 if never used).
+	nextNonce(ctx context.Context, key string) (uint64, error)
+	// saveNonce moves the cursor forward, never back.
+	saveNonce(ctx context.Context, key string, next uint64) error
+	// budget returns the company's explicit cap, or nil when none is stored.
+	budget(ctx context.Context, company, chain string) (*big.Int, error)
+}
+
+// Filter selects records for List. Zero fields match every record.
+type Filter struct {
+	Chain    string
+	Company  string
+	Election string // any case: older records keep the address as the client sent it
+	Purposes []string
+	Status   string
+	Settled  bool // only records that are no longer PENDING, when Status is empty
+
+	// Voter matches the voter_email in the payload. IDs alone selects those records; with
+	// Voter it adds them whatever their payload (the batches a voter's ballots went out in).
+	Voter *regexp.Regexp
+	IDs   []primitive.ObjectID
+}
+
+// query is the MongoDB filter for f.
+func (f Filter) query() bson.M {
+	q := bson.M{}
+	if f.Chain != "" {
+		q["chain"] = f.Chain
+	}
+	if f.Company != "" {
+		q["company"] = f.Company
+	}
+	if f.Election != "" {
+		q["election"] = primitive.Regex{Pattern: "^" + regexp.QuoteMeta(f.Election) + "$", Options: "i"}
+	}
+	if len(f.Purposes) > 0 {
+		q["purpose"] = bson.M{"$in": f.Purposes}
+	}
+	switch {
+	case f.Status != "":
+		q["status"] = f.Status
+	case f.Settled:
+		q["status"] = bson.M{"$ne": StatusPending}
+	}
+	switch {
+	case f.Voter != nil && len(f.IDs) > 0:
+		q["$or"] = bson.A{
+			bson.M{"payload.voter_email": primitive.Regex{Pattern: f.Voter.String()}},
+			bson.M{"_id": bson.M{"$in": f.IDs}},
+		}
+	case f.Voter != nil:
+		q["payload.voter_email"] = primitive.Regex{Pattern: f.Voter.String()}
+	case len(f.IDs) > 0:
+		q["_id"] = bson.M{"$in": f.IDs}
+	}
+	return q
+}
+
+// match is the in-memory twin of query.
+func (f Filter) match(rec *Record) bool {
+	switch {
+	case f.Chain != "" && rec.Chain != f.Chain,
+		f.Company != "" && rec.Company != f.Company,
+		f.Election != "" && !strings.EqualFold(rec.Election, f.Election),
+		len(f.Purposes) > 0 && !contains(f.Purposes, rec.Purpose),
+		f.Status != "" && rec.Status != f.Status,
+		f.Status == "" && f.Settled && rec.Status == StatusPending:
+		return false
+	}
+	listed := false
+	for _, id := range f.IDs {
+		listed = listed || id == rec.ID
+	}
+	if f.Voter != nil {
+		email, _ := rec.Payload["voter_email"].(string)
+		return listed || f.Voter.MatchString(email)
+	}
+	return len(f.IDs) == 0 || listed
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// mongoStore keeps records in transactions, nonce cursors in tx_nonces and budgets in
+// gas_budgets.
+type mongoStore struct {
+	txs     *mongo.Collection
+	nonces  *mongo.Collection
+	budgets *mongo.Collection
+}
+
+// NewMongoStore binds the transactions, tx_nonces and gas_budgets collections and creates their indexes.
+func NewMongoStore(client *mongo.Client, dbName string) Store {
+	db := client.Database(dbName)
+	s := &mongoStore{
+		txs:     db.Collection("transactions"),
+		nonces:  db.Collection("tx_nonces"),
+		budgets: db.Collection("gas_budgets"),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, _ = s.txs.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "chain", Value: 1}, {Key: "from", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "tx_hashes", Value: 1}}},
+		{Keys: bson.D{{Key: "company", Value: 1}, {Key: "chain", Value: 1}, {Key: "status", Value: 1}}},
+	})
+	_, _ = s.budgets.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "company", Value: 1}, {Key: "chain", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	fmt.Println("[OK] Initialized transactions collection with indexes")
+	return s
+}
+
+func (s *mongoStore) insert(ctx context.Context, rec *Record) error {
+	res, err := s.txs.InsertOne(ctx, rec)
+	if err != nil {
+		return err
+	}
+	if oid, ok := res.InsertedID.(primitive.ObjectID); ok {
+		rec.ID = oid
+	}
+	return nil
+}
+
+func (s *mongoStore) update(ctx context.Context, id primitive.ObjectID, set bson.M) error {
+	set["updated_at"] = time.Now().UTC()
+	_, err := s.txs.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+	return err
+}
+
+func (s *mongoStore) pending(ctx context.Context, chain, from string) ([]Record, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "nonce", Value: 1}})
+	cursor, err := s.txs.Find(ctx, bson.M{"chain": chain, "from": from, "status": StatusPending}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var recs []Record
+	if err := cursor.All(ctx, &recs); err != nil {
+		return nil, err
+	}
+	return recs, nil
+}
+
+func (s *mongoStore) Get(ctx context.Context, idOrHash string) (*Record, error) {
+	filter := bson.M{"tx_hashes": idOrHash}
+	if oid, err := primitive.ObjectIDFromHex(idOrHash); err == nil {
+		filter = bson.M{"_id": oid}
+	}
+	var rec Record
+	err := s.txs.FindOne(ctx, filter).Decode(&rec)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (s *mongoStore) List(ctx context.Context, f Filter, limit int64) ([]Record, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := s.txs.Find(ctx, f.query(), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	recs := []Record{}
+	if err := cursor.All(ctx, &recs); err != nil {
+		return nil, err
+	}
+	return recs, nil
+}
+
+func (s *mongoStore) Redact(ctx context.Context, id primitive.ObjectID, set bson.M, unset []string) (bool, error) {
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		fields := bson.M{}
+		for _, f := range unset {
+			fields[f] = ""
+		}
+		update["$unset"] = fields
+	}
+	if len(update) == 0 {
+		return false, nil
+	}
+	res, err := s.txs.UpdateOne(ctx, bson.M{"_id": id, "status": bson.M{"$ne": StatusPending}}, update)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+func (s *mongoStore) nextNonce(ctx context.Context, key string) (uint64, error) {
+	var doc struct {
+		Next int64 `bson:"next"`
+	}
+	err := s.nonces.FindOne(ctx, bson.M{"_id": key}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return uint64(doc.Next), nil
+}
+
+// saveNonce uses $max, which makes it safe against stale writers.
+func (s *mongoStore) saveNonce(ctx context.Context, key string, next uint64) error {
+	_, err := s.nonces.UpdateOne(ctx,
+		bson.M{"_id": key},
+		bson.M{"$max": bson.M{"next": int64(next)}, "$set": bson.M{"updated_at": time.Now().UTC()}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
@@ -3,8 +3,11 @@
 import (
 	"context"
 	"fmt"
+	"io"
 	"mime/multipart"
+	"net/url"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -86,3 +89,58 @@ func DeleteFromS3(objectKey string) error {
 	})
 	return err
 }
+
+// S3KeyFromURL returns the object key of a URL produced by UploadToS3 for the configured
+// bucket, and false for any other URL.
+func S3KeyFromURL(rawURL string) (string, bool) {
+	bucket := os.Getenv("AWS_S3_BUCKET")
+	region := os.Getenv("AWS_REGION")
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || bucket == "" || region == "" {
+		return "", false
+	}
+	if u.Scheme != "https" || !strings.EqualFold(u.Host, fmt.Sprintf("%s.s3.%s.amazonaws.com", bucket, region)) {
+		return "", false
+	}
+	key := strings.TrimPrefix(u.Path, "/")
+	return key, key != ""
+}
+
+// DownloadFromS3 reads a file from S3 by its key and returns its bytes and content type.
+func DownloadFromS3(objectKey string) ([]byte, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	accessKey := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+	region := os.Getenv("AWS_REGION")
+	bucket := os.Getenv("AWS_S3_BUCKET")
+
+	if accessKey == "" || secretKey == "" || region == "" || bucket == "" {
+		return nil, "", fmt.Errorf("S3 configuration missing")
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")),
+	)
+	if err != nil {
+		return nil, "", err
+	}
+
+	client := s3.NewFromConfig(cfg)
+
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	defer out.Body.Close()
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, aws.ToString(out.ContentType), nil
+}